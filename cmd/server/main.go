@@ -3,23 +3,29 @@ package main
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"scheduler-service/internal/app"
 	"scheduler-service/internal/config"
+	"scheduler-service/internal/repository/postgres"
 	"scheduler-service/internal/router"
 	"scheduler-service/internal/server"
+	"scheduler-service/internal/service"
 )
 
 func main() {
-    ctx := context.Background()
+	ctx := context.Background()
 
-    cfg, _ := config.Load()
-    dbURL := cfg.DatabaseURL
-    if dbURL == "" {
-        log.Fatal("DATABASE_URL required")
-    }
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+	dbURL := cfg.DatabaseURL
+	if dbURL == "" {
+		log.Fatal("DATABASE_URL required")
+	}
 
 	pool, err := pgxpool.New(ctx, dbURL)
 	if err != nil {
@@ -27,8 +33,18 @@ func main() {
 	}
 	defer pool.Close()
 
-    appInstance := &app.App{DB: pool}
+	appInstance := &app.App{DB: pool, EnforceOwnership: cfg.EnforceUserOwnership}
+
+	if cfg.CancelledBookingRetentionDays > 0 {
+		interval := time.Duration(cfg.CancelledBookingPurgeIntervalMinutes) * time.Minute
+		if interval <= 0 {
+			interval = time.Hour
+		}
+		purger := service.NewBookingService(pool, postgres.NewBookingRepo(), nil)
+		purger.Audit = postgres.NewBookingAuditRepo()
+		go purger.RunCancelledPurge(ctx, time.Duration(cfg.CancelledBookingRetentionDays)*24*time.Hour, interval)
+	}
 
-    r := router.Build(appInstance, cfg)
-    server.Run(r)
+	r := router.Build(appInstance, cfg)
+	server.Run(r)
 }
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminTokenMiddleware guards admin routes with a shared token sent in the
+// X-Admin-Token header. With an empty token the admin API is disabled and
+// every request gets 404.
+func AdminTokenMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "admin API not enabled"})
+			return
+		}
+		got := c.GetHeader("X-Admin-Token")
+		if got == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "admin token required in X-Admin-Token header"})
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "invalid admin token"})
+			return
+		}
+		c.Next()
+	}
+}
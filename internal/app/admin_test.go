@@ -0,0 +1,37 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminTokenMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		name       string
+		configured string
+		header     string
+		want       int
+	}{
+		{"valid token", "s3cret", "s3cret", http.StatusOK},
+		{"wrong token", "s3cret", "guess", http.StatusForbidden},
+		{"missing token", "s3cret", "", http.StatusUnauthorized},
+		{"admin API disabled", "", "s3cret", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		r := gin.New()
+		r.GET("/admin/bookings", AdminTokenMiddleware(tc.configured), func(c *gin.Context) { c.Status(http.StatusOK) })
+		req := httptest.NewRequest(http.MethodGet, "/admin/bookings", nil)
+		if tc.header != "" {
+			req.Header.Set("X-Admin-Token", tc.header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.want)
+		}
+	}
+}
@@ -4,4 +4,8 @@ import "github.com/jackc/pgx/v5/pgxpool"
 
 type App struct {
 	DB *pgxpool.Pool
+
+	// EnforceOwnership limits routes that look a booking up by id to the
+	// booking's own user, as ResolveUserMiddleware does for /users/:id.
+	EnforceOwnership bool
 }
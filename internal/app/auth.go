@@ -3,6 +3,7 @@ package app
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"net/http"
 	"os"
 	"strings"
@@ -44,9 +45,13 @@ func AuthMiddlewareFromEnv() gin.HandlerFunc {
 				if pool, ok := db.(*pgxpool.Pool); ok {
 					apiKeyRepo := postgres.NewAPIKeyRepo()
 					apiKeyService := service.NewAPIKeyService(pool, apiKeyRepo)
-					
+
 					apiKeyRecord, err := apiKeyService.ValidateAPIKey(c.Request.Context(), apiKey)
 					if err == nil && apiKeyRecord != nil {
+						if !service.IPAllowed(apiKeyRecord.AllowedIPs, c.ClientIP()) {
+							c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key not allowed from this IP address"})
+							return
+						}
 						// Store email in context for later use
 						c.Set("user_email", apiKeyRecord.Email)
 						c.Next()
@@ -123,8 +128,14 @@ func AuthMiddlewareWithDB(db *pgxpool.Pool) gin.HandlerFunc {
 		// Validate the API key
 		apiKeyRepo := postgres.NewAPIKeyRepo()
 		apiKeyService := service.NewAPIKeyService(db, apiKeyRepo)
-		
+
 		apiKeyRecord, err := apiKeyService.ValidateAPIKey(c.Request.Context(), apiKey)
+		if errors.Is(err, service.ErrAPIKeyExpired) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 		if err != nil || apiKeyRecord == nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": "invalid API key",
@@ -132,8 +143,20 @@ func AuthMiddlewareWithDB(db *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
-		// Store email in context for later use
+		// The client IP honours the engine's trusted proxies
+		if !service.IPAllowed(apiKeyRecord.AllowedIPs, c.ClientIP()) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "API key not allowed from this IP address",
+			})
+			return
+		}
+
+		// Store email, key id and scopes in context for later use
 		c.Set("user_email", apiKeyRecord.Email)
+		c.Set("api_key_id", apiKeyRecord.ID)
+		c.Set(apiKeyScopesKey, apiKeyRecord.Scopes)
+		// Services attribute changes (e.g. the booking audit log) to the caller
+		c.Request = c.Request.WithContext(service.WithActor(c.Request.Context(), apiKeyRecord.Email))
 		c.Next()
 	}
 }
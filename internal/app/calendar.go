@@ -3,9 +3,11 @@ package app
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -78,8 +80,18 @@ func (a *App) GoogleAuthHandler(c *gin.Context) {
 		return
 	}
 
-	// Generate state parameter for security
-	state := fmt.Sprintf("user_%s_%d", c.Query("user_id"), time.Now().Unix())
+	// Signed state binds the callback to this request and browser, expires
+	// quickly and is recorded so the callback can use it only once
+	state, nonce, err := issueOAuthState(c.Request.Context(), a.DB, postgres.NewOAuthStateRepo(), c.Query("user_id"), time.Now())
+	if errors.Is(err, errOAuthStateSecret) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate state"})
+		return
+	}
+	setOAuthStateCookie(c, nonce, int(oauthStateTTL/time.Second))
 
 	url := calendarConfig.Config.AuthCodeURL(state, oauth2.AccessTypeOffline)
 	c.JSON(http.StatusOK, gin.H{
@@ -104,6 +116,20 @@ func (a *App) GoogleOAuth2CallbackHandler(c *gin.Context) {
 		return
 	}
 
+	// Reject forged, stale, replayed or foreign state before spending the
+	// authorization code
+	cookieNonce, _ := c.Cookie(oauthStateCookie)
+	setOAuthStateCookie(c, "", -1)
+	userID, err := consumeOAuthState(c.Request.Context(), a.DB, postgres.NewOAuthStateRepo(), state, cookieNonce, time.Now())
+	if errors.Is(err, errOAuthStateSecret) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or expired state"})
+		return
+	}
+
 	// Exchange code for token
 	token, err := calendarConfig.Config.Exchange(context.Background(), code)
 	if err != nil {
@@ -117,6 +143,7 @@ func (a *App) GoogleOAuth2CallbackHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Authorization successful",
 		"state":   state,
+		"user_id": userID,
 		"token":   string(tokenJSON), // In production, don't return token directly
 	})
 }
@@ -153,11 +180,19 @@ func (a *App) GetGoogleCalendarEvents(c *gin.Context) {
 	}
 
 	// Parse query parameters
-	calendarID := c.DefaultQuery("calendar_id", "primary")
 	timeMin := c.Query("time_min") // RFC3339 format
 	timeMax := c.Query("time_max") // RFC3339 format
 	userID := c.Query("user_id")   // target user to create availability/booking for
-	maxResults := int64(250)
+	calendarID, err := a.resolveCalendarID(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	maxResults, err := parseMaxResults(c, defaultEventsMaxResults, maxEventsMaxResults)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Build the events call
 	eventsCall := srv.Events.List(calendarID).
@@ -173,9 +208,11 @@ func (a *App) GetGoogleCalendarEvents(c *gin.Context) {
 	}
 
 	// Execute the call
-	events, err := eventsCall.Do()
+	events, err := withGoogleBackoff(c.Request.Context(), func() (*calendar.Events, error) {
+		return eventsCall.Context(c.Request.Context()).Do()
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to retrieve events: %v", err)})
+		writeGoogleError(c, "retrieve events", err)
 		return
 	}
 
@@ -212,61 +249,8 @@ func (a *App) GetGoogleCalendarEvents(c *gin.Context) {
 			event.Creator = item.Creator.Email
 		}
 
-		// Extract meeting link (Google Meet link)
-		if item.HangoutLink != "" {
-			event.MeetingLink = item.HangoutLink
-		}
-
-		// Extract detailed conference data
-		if item.ConferenceData != nil && len(item.ConferenceData.EntryPoints) > 0 {
-			conferenceInfo := &ConferenceInfo{}
-
-			// Get conference type
-			if item.ConferenceData.ConferenceSolution != nil {
-				conferenceInfo.Type = item.ConferenceData.ConferenceSolution.Name
-			}
-
-			// Get meeting ID
-			if item.ConferenceData.ConferenceId != "" {
-				conferenceInfo.ID = item.ConferenceData.ConferenceId
-			}
-
-			// Extract entry points (URLs and phone numbers)
-			var phoneNumbers []string
-			for _, entryPoint := range item.ConferenceData.EntryPoints {
-				switch entryPoint.EntryPointType {
-				case "video":
-					if conferenceInfo.URL == "" && entryPoint.Uri != "" {
-						conferenceInfo.URL = entryPoint.Uri
-						// If no HangoutLink, use this as meeting link
-						if event.MeetingLink == "" {
-							event.MeetingLink = entryPoint.Uri
-						}
-					}
-				case "phone":
-					if entryPoint.Uri != "" {
-						phoneNumbers = append(phoneNumbers, entryPoint.Uri)
-					}
-				case "more":
-					// Additional meeting details
-					if entryPoint.Uri != "" && conferenceInfo.URL == "" {
-						conferenceInfo.URL = entryPoint.Uri
-						if event.MeetingLink == "" {
-							event.MeetingLink = entryPoint.Uri
-						}
-					}
-				}
-			}
-
-			if len(phoneNumbers) > 0 {
-				conferenceInfo.PhoneNumbers = phoneNumbers
-			}
-
-			// Only include conference data if we have meaningful info
-			if conferenceInfo.URL != "" || conferenceInfo.ID != "" || len(conferenceInfo.PhoneNumbers) > 0 {
-				event.ConferenceData = conferenceInfo
-			}
-		}
+		// Extract the meeting link and detailed conference data
+		event.ConferenceData, event.MeetingLink = parseConferenceData(item)
 
 		// Parse start time
 		if item.Start.DateTime != "" {
@@ -319,7 +303,7 @@ func (a *App) GetGoogleCalendarEvents(c *gin.Context) {
 					Available:      true,
 				}
 				fmt.Printf("Creating availability rule: %+v\n", rule)
-				availResult, availErr := availSvc.SetAvailability(c.Request.Context(), userID, []models.AvailabilityRule{rule})
+				availResult, availErr := availSvc.UpsertAvailability(c.Request.Context(), userID, []models.AvailabilityRule{rule})
 				if availErr != nil {
 					fmt.Printf("Error creating availability: %v\n", availErr)
 				} else {
@@ -335,6 +319,7 @@ func (a *App) GetGoogleCalendarEvents(c *gin.Context) {
 					Type:           "google_meet",
 					Description:    event.MeetingLink,
 					Title:          event.Summary,
+					GoogleEventID:  event.ID,
 				}
 				fmt.Printf("Creating booking: %+v\n", bookingParams)
 				bookingResult, bookingErr := bookingSvc.CreateBooking(c.Request.Context(), userID, bookingParams)
@@ -356,6 +341,53 @@ func (a *App) GetGoogleCalendarEvents(c *gin.Context) {
 	})
 }
 
+// Bounds for the max_results query parameter. Google caps events.list at 2500
+// and calendarList.list at 250 entries per page.
+const (
+	defaultEventsMaxResults       = 250
+	maxEventsMaxResults           = 2500
+	defaultCalendarListMaxResults = 100
+	maxCalendarListMaxResults     = 250
+)
+
+func attendeeEmails(attendees []*calendar.EventAttendee) []string {
+	out := make([]string, 0, len(attendees))
+	for _, a := range attendees {
+		out = append(out, a.Email)
+	}
+	return out
+}
+
+// resolveCalendarID picks the calendar for a request: the calendar_id query
+// param, else the user's stored default, else "primary".
+func (a *App) resolveCalendarID(c *gin.Context, userID string) (string, error) {
+	if a.DB == nil {
+		return c.DefaultQuery("calendar_id", "primary"), nil
+	}
+	settingsSvc := service.NewUserSettingsService(a.DB, postgres.NewUserSettingsRepo())
+	return settingsSvc.CalendarID(c.Request.Context(), userID, c.Query("calendar_id"))
+}
+
+// parseMaxResults reads the max_results query parameter, falling back to def
+// when absent and clamping it into [1, max].
+func parseMaxResults(c *gin.Context, def, max int64) (int64, error) {
+	raw := c.Query("max_results")
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid max_results: %s", raw)
+	}
+	if n < 1 {
+		return 1, nil
+	}
+	if n > max {
+		return max, nil
+	}
+	return n, nil
+}
+
 // isGoogleMeetEvent determines whether an event is a Google Meet
 func isGoogleMeetEvent(e *CalendarEvent) bool {
 	if e == nil {
@@ -404,8 +436,14 @@ func (a *App) GetGoogleCalendarList(c *gin.Context) {
 		return
 	}
 
+	maxResults, err := parseMaxResults(c, defaultCalendarListMaxResults, maxCalendarListMaxResults)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Get calendar list
-	calendarList, err := srv.CalendarList.List().Do()
+	calendarList, err := srv.CalendarList.List().MaxResults(maxResults).Do()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to retrieve calendars: %v", err)})
 		return
@@ -452,11 +490,79 @@ func (a *App) CreateInterviewEvent(c *gin.Context) {
 		return
 	}
 
+	interviewEvent, ok := bindInterviewEvent(c)
+	if !ok {
+		return
+	}
+
+	calendarConfig := InitGoogleCalendarConfig()
+	if calendarConfig == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Google Calendar not configured"})
+		return
+	}
+
+	// Create HTTP client with token
+	client := calendarConfig.Config.Client(context.Background(), &token)
+
+	// Create Calendar service
+	srv, err := calendar.NewService(context.Background(), option.WithHTTPClient(client))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create calendar service"})
+		return
+	}
+
+	event := buildInterviewEvent(&interviewEvent)
+
+	// Create the event, in the interviewer's default calendar when user_id is given
+	calendarID, err := a.resolveCalendarID(c, c.Query("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	createdEvent, err := withGoogleBackoff(c.Request.Context(), func() (*calendar.Event, error) {
+		return srv.Events.Insert(calendarID, event).ConferenceDataVersion(1).Context(c.Request.Context()).Do()
+	})
+	if err != nil {
+		writeGoogleError(c, "create event", err)
+		return
+	}
+
+	// Extract meeting link if available
+	_, meetingLink := parseConferenceData(createdEvent)
+
+	// Return success response
+	response := gin.H{
+		"message":      "Interview event created successfully",
+		"event_id":     createdEvent.Id,
+		"event_title":  createdEvent.Summary,
+		"start_time":   createdEvent.Start.DateTime,
+		"end_time":     createdEvent.End.DateTime,
+		"meeting_link": meetingLink,
+		"attendees":    attendeeEmails(event.Attendees),
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// PreviewInterviewEvent returns the Google Calendar event CreateInterviewEvent
+// would create for the same body, without calling Google. No Google token is
+// needed.
+func (a *App) PreviewInterviewEvent(c *gin.Context) {
+	interviewEvent, ok := bindInterviewEvent(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, buildInterviewEvent(&interviewEvent))
+}
+
+// bindInterviewEvent parses and validates an InterviewEvent request body and
+// fills in defaults, writing a 400 response and returning ok=false on error.
+func bindInterviewEvent(c *gin.Context) (InterviewEvent, bool) {
 	// Parse interview event from request body
 	var interviewEvent InterviewEvent
 	if err := c.ShouldBindJSON(&interviewEvent); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+		return interviewEvent, false
 	}
 
 	// Validate required fields
@@ -464,7 +570,7 @@ func (a *App) CreateInterviewEvent(c *gin.Context) {
 		interviewEvent.Position == "" || interviewEvent.Stage == "" ||
 		interviewEvent.Mode == "" || interviewEvent.InterviewerEmail == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "missing required fields"})
-		return
+		return interviewEvent, false
 	}
 
 	// Set default values
@@ -474,23 +580,14 @@ func (a *App) CreateInterviewEvent(c *gin.Context) {
 	if interviewEvent.Duration == 0 {
 		interviewEvent.Duration = 60 // Default 1 hour
 	}
+	return interviewEvent, true
+}
 
-	calendarConfig := InitGoogleCalendarConfig()
-	if calendarConfig == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Google Calendar not configured"})
-		return
-	}
-
-	// Create HTTP client with token
-	client := calendarConfig.Config.Client(context.Background(), &token)
-
-	// Create Calendar service
-	srv, err := calendar.NewService(context.Background(), option.WithHTTPClient(client))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create calendar service"})
-		return
-	}
-
+// buildInterviewEvent constructs the Google Calendar event for an interview:
+// title, description, attendees, conference request and location. It is shared
+// by CreateInterviewEvent and PreviewInterviewEvent so both produce the same
+// body.
+func buildInterviewEvent(interviewEvent *InterviewEvent) *calendar.Event {
 	// Prepare event details
 	startTime := interviewEvent.DateTime
 	endTime := startTime.Add(time.Duration(interviewEvent.Duration) * time.Minute)
@@ -530,11 +627,16 @@ Status: %s`,
 			{Email: interviewEvent.CandidateEmail, DisplayName: interviewEvent.CandidateName},
 			{Email: interviewEvent.InterviewerEmail},
 		},
-		Reminders: &calendar.EventReminders{  // ← Move this INSIDE the struct
+		Reminders: &calendar.EventReminders{ // ← Move this INSIDE the struct
 			UseDefault: true,
 		},
 	}
 
+	// Invite any additional panel attendees
+	for _, att := range interviewEvent.Attendees {
+		event.Attendees = append(event.Attendees, &calendar.EventAttendee{Email: att.Email, Optional: att.Optional})
+	}
+
 	// Add Google Meet conference if mode is "google"
 	if interviewEvent.Mode == "google" {
 		event.ConferenceData = &calendar.ConferenceData{
@@ -554,39 +656,61 @@ Status: %s`,
 		event.Location = "Google Meet"
 	}
 
-	// Create the event
-	calendarID := c.DefaultQuery("calendar_id", "primary")
-	createdEvent, err := srv.Events.Insert(calendarID, event).ConferenceDataVersion(1).Do()
+	return event
+}
+
+// ValidateGoogleToken checks that a Google token is still usable by making the
+// cheapest authenticated call available. An expired access token is refreshed
+// transparently when the token carries a refresh token; the new token is then
+// returned so the client can replace its copy.
+func (a *App) ValidateGoogleToken(c *gin.Context) {
+	tokenStr := c.GetHeader("X-Google-Token")
+	if tokenStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Google token required in X-Google-Token header"})
+		return
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal([]byte(tokenStr), &token); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token format"})
+		return
+	}
+
+	calendarConfig := InitGoogleCalendarConfig()
+	if calendarConfig == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Google Calendar not configured"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tokenSource := calendarConfig.Config.TokenSource(ctx, &token)
+	srv, err := calendar.NewService(ctx, option.WithHTTPClient(oauth2.NewClient(ctx, tokenSource)))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to create event: %v", err)})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create calendar service"})
 		return
 	}
 
-	// Extract meeting link if available
-	meetingLink := ""
-	if createdEvent.HangoutLink != "" {
-		meetingLink = createdEvent.HangoutLink
-	} else if createdEvent.ConferenceData != nil && len(createdEvent.ConferenceData.EntryPoints) > 0 {
-		for _, entryPoint := range createdEvent.ConferenceData.EntryPoints {
-			if entryPoint.EntryPointType == "video" && entryPoint.Uri != "" {
-				meetingLink = entryPoint.Uri
-				break
-			}
-		}
+	if _, err := srv.CalendarList.List().MaxResults(1).Do(); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"valid": false, "error": fmt.Sprintf("token rejected: %v", err)})
+		return
 	}
 
-	// Return success response
-	response := gin.H{
-		"message":      "Interview event created successfully",
-		"event_id":     createdEvent.Id,
-		"event_title":  createdEvent.Summary,
-		"start_time":   createdEvent.Start.DateTime,
-		"end_time":     createdEvent.End.DateTime,
-		"meeting_link": meetingLink,
-		"attendees":    []string{interviewEvent.CandidateEmail, interviewEvent.InterviewerEmail},
+	current, err := tokenSource.Token()
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"valid": false, "error": "failed to read token"})
+		return
 	}
 
-	c.JSON(http.StatusCreated, response)
+	response := gin.H{
+		"valid":      true,
+		"expires_at": current.Expiry.UTC(),
+		"refreshed":  current.AccessToken != token.AccessToken,
+	}
+	if current.AccessToken != token.AccessToken {
+		tokenJSON, _ := json.Marshal(current)
+		response["token"] = string(tokenJSON)
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // RefreshGoogleToken refreshes an expired Google OAuth token
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseMaxResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		name     string
+		query    string
+		def, max int64
+		want     int64
+		wantErr  bool
+	}{
+		{"events default", "", defaultEventsMaxResults, maxEventsMaxResults, 250, false},
+		{"events in range", "?max_results=40", defaultEventsMaxResults, maxEventsMaxResults, 40, false},
+		{"events at the cap", "?max_results=2500", defaultEventsMaxResults, maxEventsMaxResults, 2500, false},
+		{"events above the cap", "?max_results=10000", defaultEventsMaxResults, maxEventsMaxResults, 2500, false},
+		{"zero clamps to one", "?max_results=0", defaultEventsMaxResults, maxEventsMaxResults, 1, false},
+		{"negative clamps to one", "?max_results=-3", defaultEventsMaxResults, maxEventsMaxResults, 1, false},
+		{"calendar list default", "", defaultCalendarListMaxResults, maxCalendarListMaxResults, 100, false},
+		{"calendar list above the cap", "?max_results=300", defaultCalendarListMaxResults, maxCalendarListMaxResults, 250, false},
+		{"not a number", "?max_results=lots", defaultEventsMaxResults, maxEventsMaxResults, 0, true},
+	}
+	for _, tc := range cases {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/api/calendar/events"+tc.query, nil)
+		got, err := parseMaxResults(c, tc.def, tc.max)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: err = %v, want error %v", tc.name, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: max_results = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
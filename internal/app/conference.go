@@ -0,0 +1,159 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+
+	"scheduler-service/internal/repository/postgres"
+	"scheduler-service/internal/service"
+)
+
+// parseConferenceData extracts conference details from a Google event, along
+// with the best meeting link: the Hangouts link, else the first video or
+// "more" entry point. It returns nil info when the event carries nothing
+// useful.
+func parseConferenceData(item *calendar.Event) (*ConferenceInfo, string) {
+	meetingLink := item.HangoutLink
+	if item.ConferenceData == nil || len(item.ConferenceData.EntryPoints) == 0 {
+		return nil, meetingLink
+	}
+	info := &ConferenceInfo{ID: item.ConferenceData.ConferenceId}
+	if item.ConferenceData.ConferenceSolution != nil {
+		info.Type = item.ConferenceData.ConferenceSolution.Name
+	}
+	for _, entryPoint := range item.ConferenceData.EntryPoints {
+		switch entryPoint.EntryPointType {
+		case "video", "more":
+			if info.URL == "" && entryPoint.Uri != "" {
+				info.URL = entryPoint.Uri
+			}
+		case "phone":
+			if entryPoint.Uri != "" {
+				info.PhoneNumbers = append(info.PhoneNumbers, entryPoint.Uri)
+			}
+		}
+	}
+	if meetingLink == "" {
+		meetingLink = info.URL
+	}
+	// Only include conference data if we have meaningful info
+	if info.URL == "" && info.ID == "" && len(info.PhoneNumbers) == 0 {
+		return nil, meetingLink
+	}
+	return info, meetingLink
+}
+
+var (
+	linkPattern     = regexp.MustCompile(`https?://[^\s<>"']+`)
+	meetCodePattern = regexp.MustCompile(`^/([a-z]{3}-[a-z]{4}-[a-z]{3})`)
+	zoomIDPattern   = regexp.MustCompile(`^/[jw]/(\d+)`)
+)
+
+// conferenceFromText finds the first meeting URL in free text, such as a
+// booking description holding an imported meeting link, and infers the
+// provider and meeting id from it. It returns nil when there is no URL.
+func conferenceFromText(text string) *ConferenceInfo {
+	raw := linkPattern.FindString(text)
+	if raw == "" {
+		return nil
+	}
+	info := &ConferenceInfo{URL: raw}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return info
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "meet.google.com":
+		info.Type = "Google Meet"
+		if m := meetCodePattern.FindStringSubmatch(u.Path); m != nil {
+			info.ID = m[1]
+		}
+	case host == "zoom.us" || strings.HasSuffix(host, ".zoom.us"):
+		info.Type = "Zoom"
+		if m := zoomIDPattern.FindStringSubmatch(u.Path); m != nil {
+			info.ID = m[1]
+		}
+	case host == "teams.microsoft.com" || host == "teams.live.com":
+		info.Type = "Microsoft Teams"
+	}
+	return info
+}
+
+// GetBookingConference returns structured conference details for a booking.
+// When the booking is linked to a Google event and an X-Google-Token header
+// is sent, the event is fetched and its conference data parsed; otherwise the
+// meeting link stored in the booking's description is parsed.
+func (a *App) GetBookingConference(c *gin.Context) {
+	bookingSvc := service.NewBookingService(a.DB, postgres.NewBookingRepo(), nil)
+	booking, err := bookingSvc.GetBooking(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if a.EnforceOwnership && booking.UserID != ResolvedUserFrom(c).CallerKeyID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to access this booking"})
+		return
+	}
+
+	tokenStr := c.GetHeader("X-Google-Token")
+	if booking.GoogleEventID == "" || tokenStr == "" {
+		info := conferenceFromText(booking.Description)
+		if info == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "booking has no conference details"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"booking_id": booking.ID, "source": "description", "conference": info})
+		return
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal([]byte(tokenStr), &token); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token format"})
+		return
+	}
+	calendarConfig := InitGoogleCalendarConfig()
+	if calendarConfig == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Google Calendar not configured"})
+		return
+	}
+	ctx := c.Request.Context()
+	srv, err := calendar.NewService(ctx, option.WithHTTPClient(calendarConfig.Config.Client(ctx, &token)))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create calendar service"})
+		return
+	}
+	calendarID, err := a.resolveCalendarID(c, booking.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	event, err := withGoogleBackoff(ctx, func() (*calendar.Event, error) {
+		return srv.Events.Get(calendarID, booking.GoogleEventID).Context(ctx).Do()
+	})
+	if err != nil {
+		writeGoogleError(c, "retrieve event", err)
+		return
+	}
+	info, meetingLink := parseConferenceData(event)
+	if info == nil && meetingLink != "" {
+		info = conferenceFromText(meetingLink)
+	}
+	if info == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "event has no conference details"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"booking_id": booking.ID, "source": "google_event", "conference": info})
+}
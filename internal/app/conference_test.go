@@ -0,0 +1,54 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestParseConferenceDataFromEvent(t *testing.T) {
+	event := &calendar.Event{
+		ConferenceData: &calendar.ConferenceData{
+			ConferenceId:       "abc-defg-hij",
+			ConferenceSolution: &calendar.ConferenceSolution{Name: "Google Meet"},
+			EntryPoints: []*calendar.EntryPoint{
+				{EntryPointType: "phone", Uri: "tel:+1-555-0100"},
+				{EntryPointType: "video", Uri: "https://meet.google.com/abc-defg-hij"},
+				{EntryPointType: "more", Uri: "https://tel.meet/abc-defg-hij"},
+			},
+		},
+	}
+	info, link := parseConferenceData(event)
+	want := &ConferenceInfo{ID: "abc-defg-hij", Type: "Google Meet", URL: "https://meet.google.com/abc-defg-hij", PhoneNumbers: []string{"tel:+1-555-0100"}}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("info = %+v, want %+v", info, want)
+	}
+	if link != want.URL {
+		t.Errorf("link = %q, want the video entry point", link)
+	}
+
+	// An event with only a Hangouts link yields no info, just the link
+	info, link = parseConferenceData(&calendar.Event{HangoutLink: "https://meet.google.com/xyz-abcd-efg"})
+	if info != nil || link != "https://meet.google.com/xyz-abcd-efg" {
+		t.Errorf("hangout-only event: info %+v, link %q", info, link)
+	}
+}
+
+func TestConferenceFromDescription(t *testing.T) {
+	cases := []struct {
+		text string
+		want *ConferenceInfo
+	}{
+		{"Join: https://meet.google.com/abc-defg-hij please", &ConferenceInfo{URL: "https://meet.google.com/abc-defg-hij", Type: "Google Meet", ID: "abc-defg-hij"}},
+		{"Zoom https://acme.zoom.us/j/123456789?pwd=x", &ConferenceInfo{URL: "https://acme.zoom.us/j/123456789?pwd=x", Type: "Zoom", ID: "123456789"}},
+		{"<a href=\"https://teams.microsoft.com/l/meetup-join/1\">join</a>", &ConferenceInfo{URL: "https://teams.microsoft.com/l/meetup-join/1", Type: "Microsoft Teams"}},
+		{"https://example.com/room/7", &ConferenceInfo{URL: "https://example.com/room/7"}},
+		{"no link here", nil},
+	}
+	for _, tc := range cases {
+		if got := conferenceFromText(tc.text); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("conferenceFromText(%q) = %+v, want %+v", tc.text, got, tc.want)
+		}
+	}
+}
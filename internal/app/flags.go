@@ -0,0 +1,65 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/service"
+)
+
+// flagSources names the query parameter and header each flag is read from.
+var flagSources = []struct {
+	query, header string
+	field         func(*service.Flags) *bool
+}{
+	{"strict_utc", "X-Flag-Strict-UTC", func(f *service.Flags) *bool { return &f.StrictUTC }},
+	{"include_held", "X-Flag-Include-Held", func(f *service.Flags) *bool { return &f.IncludeHeld }},
+	{"include_past", "X-Flag-Include-Past", func(f *service.Flags) *bool { return &f.IncludePast }},
+}
+
+// FeatureFlagsMiddleware resolves the request's service.Flags once and stores
+// them in the request context. Each flag comes from its query parameter, then
+// its header, then defaults; values that do not parse as booleans are ignored.
+// The locale comes from the locale query parameter, then the first
+// Accept-Language tag.
+func FeatureFlagsMiddleware(defaults service.Flags) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(service.WithFlags(c.Request.Context(), ResolveFlags(c, defaults)))
+		c.Next()
+	}
+}
+
+// ResolveFlags applies the request's query parameters and headers on top of
+// defaults, with query taking precedence over header.
+func ResolveFlags(c *gin.Context, defaults service.Flags) service.Flags {
+	flags := defaults
+	for _, src := range flagSources {
+		if v, ok := parseFlag(c.GetHeader(src.header)); ok {
+			*src.field(&flags) = v
+		}
+		if v, ok := parseFlag(c.Query(src.query)); ok {
+			*src.field(&flags) = v
+		}
+	}
+	if lang := c.GetHeader("Accept-Language"); lang != "" {
+		tag, _, _ := strings.Cut(lang, ",")
+		tag, _, _ = strings.Cut(tag, ";")
+		if tag = strings.TrimSpace(tag); tag != "*" {
+			flags.Locale = tag
+		}
+	}
+	if v := c.Query("locale"); v != "" {
+		flags.Locale = v
+	}
+	return flags
+}
+
+func parseFlag(raw string) (bool, bool) {
+	if raw == "" {
+		return false, false
+	}
+	v, err := strconv.ParseBool(raw)
+	return v, err == nil
+}
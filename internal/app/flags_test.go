@@ -0,0 +1,47 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/service"
+)
+
+func TestFeatureFlagsPrecedence(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		name     string
+		defaults service.Flags
+		query    string
+		headers  map[string]string
+		want     service.Flags
+	}{
+		{"defaults only", service.Flags{StrictUTC: true}, "", nil, service.Flags{StrictUTC: true}},
+		{"header over default", service.Flags{StrictUTC: true}, "", map[string]string{"X-Flag-Strict-UTC": "false", "X-Flag-Include-Held": "1"}, service.Flags{IncludeHeld: true}},
+		{"query over header", service.Flags{}, "strict_utc=false&include_past=true", map[string]string{"X-Flag-Strict-UTC": "true", "X-Flag-Include-Past": "false"}, service.Flags{IncludePast: true}},
+		{"query over default", service.Flags{StrictUTC: true}, "strict_utc=0", nil, service.Flags{}},
+		{"unparsable values ignored", service.Flags{StrictUTC: true}, "strict_utc=maybe", map[string]string{"X-Flag-Strict-UTC": "nope"}, service.Flags{StrictUTC: true}},
+		{"locale from Accept-Language", service.Flags{}, "", map[string]string{"Accept-Language": "pt-BR,pt;q=0.9,en;q=0.8"}, service.Flags{Locale: "pt-BR"}},
+		{"locale query over Accept-Language", service.Flags{}, "locale=de", map[string]string{"Accept-Language": "fr"}, service.Flags{Locale: "de"}},
+		{"wildcard Accept-Language", service.Flags{}, "", map[string]string{"Accept-Language": "*"}, service.Flags{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got service.Flags
+			r := gin.New()
+			r.Use(FeatureFlagsMiddleware(tc.defaults))
+			r.GET("/slots", func(c *gin.Context) { got = service.FlagsFrom(c.Request.Context()) })
+			req := httptest.NewRequest(http.MethodGet, "/slots?"+tc.query, nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+			if got != tc.want {
+				t.Errorf("flags = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
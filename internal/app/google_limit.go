@@ -0,0 +1,37 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GoogleConcurrencyMiddleware bounds how many requests may be calling the
+// Google APIs at once. A request waits up to wait (or until its context is
+// done) for a free slot and otherwise gets 503. A non-positive max disables
+// the limit.
+func GoogleConcurrencyMiddleware(max int, wait time.Duration) gin.HandlerFunc {
+	if max <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	sem := make(chan struct{}, max)
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if wait > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, wait)
+			defer cancel()
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "too many concurrent Google Calendar requests"})
+			return
+		}
+		defer func() { <-sem }()
+		c.Next()
+	}
+}
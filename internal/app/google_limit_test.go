@@ -0,0 +1,80 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGoogleConcurrencyMiddlewareCapsCalls(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cases := []struct {
+		name        string
+		max         int
+		wait        time.Duration
+		requests    int
+		wantMaxPeak int // 0: no cap
+		want503     bool
+	}{
+		{"capped at two", 2, 5 * time.Second, 8, 2, false},
+		{"capped at one", 1, 5 * time.Second, 4, 1, false},
+		{"wait times out", 1, 10 * time.Millisecond, 3, 1, true},
+		{"disabled", 0, 0, 4, 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var inFlight, peak int32
+			r := gin.New()
+			r.GET("/calendar", GoogleConcurrencyMiddleware(tc.max, tc.wait), func(c *gin.Context) {
+				n := atomic.AddInt32(&inFlight, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(30 * time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				c.Status(http.StatusOK)
+			})
+
+			codes := make([]*httptest.ResponseRecorder, tc.requests)
+			var wg sync.WaitGroup
+			for i := range codes {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					w := httptest.NewRecorder()
+					r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar", nil))
+					codes[i] = w
+				}(i)
+			}
+			wg.Wait()
+
+			if tc.wantMaxPeak > 0 && int(peak) > tc.wantMaxPeak {
+				t.Errorf("peak concurrency = %d, want at most %d", peak, tc.wantMaxPeak)
+			}
+			got503 := false
+			for _, w := range codes {
+				switch w.Code {
+				case http.StatusOK:
+				case http.StatusServiceUnavailable:
+					got503 = true
+					if w.Header().Get("Retry-After") == "" {
+						t.Error("503 without Retry-After")
+					}
+				default:
+					t.Errorf("status = %d, want 200 or 503", w.Code)
+				}
+			}
+			if got503 != tc.want503 {
+				t.Errorf("got a 503 = %v, want %v", got503, tc.want503)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/api/googleapi"
+)
+
+// Retry policy for Google rate-limit responses: up to googleMaxRetries
+// retries, doubling from googleBaseBackoff and capped at googleMaxBackoff,
+// with full jitter.
+const (
+	googleMaxRetries  = 4
+	googleBaseBackoff = 500 * time.Millisecond
+	googleMaxBackoff  = 8 * time.Second
+)
+
+// isGoogleRateLimit reports whether err is Google throttling the caller: a 429,
+// or a 403 whose reason is one of the rate-limit reasons.
+func isGoogleRateLimit(err error) bool {
+	gerr, ok := err.(*googleapi.Error)
+	if !ok {
+		return false
+	}
+	if gerr.Code == http.StatusTooManyRequests {
+		return true
+	}
+	if gerr.Code != http.StatusForbidden {
+		return false
+	}
+	for _, e := range gerr.Errors {
+		switch e.Reason {
+		case "rateLimitExceeded", "userRateLimitExceeded", "quotaExceeded":
+			return true
+		}
+	}
+	return false
+}
+
+// googleBackoff returns the jittered delay before retry attempt (0-based).
+func googleBackoff(attempt int) time.Duration {
+	d := googleBaseBackoff << attempt
+	if d > googleMaxBackoff {
+		d = googleMaxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(d)) + 1)
+}
+
+// withGoogleBackoff runs call, retrying with exponential backoff while Google
+// answers with a rate-limit error. It gives up early when ctx is done.
+func withGoogleBackoff[T any](ctx context.Context, call func() (T, error)) (T, error) {
+	var (
+		res T
+		err error
+	)
+	for attempt := 0; ; attempt++ {
+		res, err = call()
+		if err == nil || !isGoogleRateLimit(err) || attempt >= googleMaxRetries {
+			return res, err
+		}
+		select {
+		case <-time.After(googleBackoff(attempt)):
+		case <-ctx.Done():
+			return res, err
+		}
+	}
+}
+
+// writeGoogleError answers a failed Google call: 503 with Retry-After when
+// Google is still throttling us, 500 otherwise.
+func writeGoogleError(c *gin.Context, action string, err error) {
+	if isGoogleRateLimit(err) {
+		c.Header("Retry-After", strconv.Itoa(int(googleMaxBackoff.Seconds())))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": fmt.Sprintf("Google Calendar rate limit exceeded while trying to %s", action)})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to %s: %v", action, err)})
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// scriptedGoogle answers event inserts with the scripted responses in turn,
+// repeating the last one, and counts the calls.
+type scriptedGoogle struct {
+	responses []googleResponse
+	calls     int32
+}
+
+type googleResponse struct {
+	code int
+	body string
+}
+
+func (g *scriptedGoogle) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := int(atomic.AddInt32(&g.calls, 1))
+	if n > len(g.responses) {
+		n = len(g.responses)
+	}
+	r := g.responses[n-1]
+	return &http.Response{StatusCode: r.code, Header: http.Header{"Content-Type": {"application/json"}}, Body: io.NopCloser(strings.NewReader(r.body)), Request: req}, nil
+}
+
+var (
+	googleOK          = googleResponse{http.StatusOK, `{"id":"evt1","summary":"Interview","start":{"dateTime":"2026-03-02T09:00:00Z"},"end":{"dateTime":"2026-03-02T10:00:00Z"}}`}
+	googleTooMany     = googleResponse{http.StatusTooManyRequests, `{"error":{"code":429,"message":"Rate Limit Exceeded","errors":[{"reason":"rateLimitExceeded"}]}}`}
+	googleRateLimited = googleResponse{http.StatusForbidden, `{"error":{"code":403,"message":"Rate Limit Exceeded","errors":[{"reason":"userRateLimitExceeded"}]}}`}
+	googleForbidden   = googleResponse{http.StatusForbidden, `{"error":{"code":403,"message":"Forbidden","errors":[{"reason":"forbidden"}]}}`}
+)
+
+func TestCreateInterviewEventRetriesRateLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("GOOGLE_CLIENT_ID", "client")
+	t.Setenv("GOOGLE_CLIENT_SECRET", "secret")
+	t.Setenv("GOOGLE_REDIRECT_URL", "http://localhost/oauth2callback")
+	orig := http.DefaultTransport
+	t.Cleanup(func() { http.DefaultTransport = orig })
+
+	cases := []struct {
+		name           string
+		responses      []googleResponse
+		timeout        time.Duration // bounds the request, and so the retries
+		wantCode       int
+		wantCalls      int32 // 0: at least one
+		wantRetryAfter string
+	}{
+		{"429 then success", []googleResponse{googleTooMany, googleOK}, 0, http.StatusCreated, 2, ""},
+		{"403 rate limit then success", []googleResponse{googleRateLimited, googleOK}, 0, http.StatusCreated, 2, ""},
+		{"403 without a rate-limit reason", []googleResponse{googleForbidden}, 0, http.StatusInternalServerError, 1, ""},
+		{"still throttled", []googleResponse{googleTooMany}, 50 * time.Millisecond, http.StatusServiceUnavailable, 0, "8"},
+	}
+	a := &App{}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			google := &scriptedGoogle{responses: tc.responses}
+			http.DefaultTransport = google
+
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+			body := `{"candidate_name":"Ada","candidate_email":"ada@example.com","position":"Engineer","stage":"Onsite","date_time":"2026-03-02T09:00:00Z","mode":"zoom","interviewer_email":"grace@example.com"}`
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/calendar/interview", strings.NewReader(body)).WithContext(ctx)
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Request.Header.Set("X-Google-Token", `{"access_token":"good","token_type":"Bearer","expiry":"2999-01-01T00:00:00Z"}`)
+			a.CreateInterviewEvent(c)
+
+			if w.Code != tc.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tc.wantCode, w.Body)
+			}
+			if calls := atomic.LoadInt32(&google.calls); tc.wantCalls > 0 && calls != tc.wantCalls {
+				t.Errorf("Google called %d times, want %d", calls, tc.wantCalls)
+			}
+			if got := w.Header().Get("Retry-After"); got != tc.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tc.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestGoogleBackoffIsCapped(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		limit := googleBaseBackoff << attempt
+		if limit > googleMaxBackoff || limit <= 0 {
+			limit = googleMaxBackoff
+		}
+		for i := 0; i < 20; i++ {
+			if d := googleBackoff(attempt); d <= 0 || d > limit {
+				t.Fatalf("googleBackoff(%d) = %s, want within (0, %s]", attempt, d, limit)
+			}
+		}
+	}
+}
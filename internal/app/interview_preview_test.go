@@ -0,0 +1,112 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/api/calendar/v3"
+)
+
+// recordingGoogle answers event inserts by echoing the event back with an id,
+// keeping the last body it was sent.
+type recordingGoogle struct {
+	inserted []byte
+}
+
+func (g *recordingGoogle) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	g.inserted = body
+	var ev map[string]any
+	if err := json.Unmarshal(body, &ev); err != nil {
+		return nil, err
+	}
+	ev["id"] = "evt1"
+	out, _ := json.Marshal(ev)
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"application/json"}}, Body: io.NopCloser(bytes.NewReader(out)), Request: req}, nil
+}
+
+// decodeEvent decodes an event body, dropping the conference request id,
+// which embeds the current time.
+func decodeEvent(t *testing.T, body []byte) calendar.Event {
+	t.Helper()
+	var ev calendar.Event
+	if err := json.Unmarshal(body, &ev); err != nil {
+		t.Fatalf("decode event %s: %v", body, err)
+	}
+	if ev.ConferenceData != nil && ev.ConferenceData.CreateRequest != nil {
+		ev.ConferenceData.CreateRequest.RequestId = ""
+	}
+	return ev
+}
+
+func TestPreviewInterviewEventMatchesCreate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("GOOGLE_CLIENT_ID", "client")
+	t.Setenv("GOOGLE_CLIENT_SECRET", "secret")
+	t.Setenv("GOOGLE_REDIRECT_URL", "http://localhost/oauth2callback")
+	google := &recordingGoogle{}
+	orig := http.DefaultTransport
+	http.DefaultTransport = google
+	t.Cleanup(func() { http.DefaultTransport = orig })
+
+	const base = `"candidate_name":"Ada","candidate_email":"ada@example.com","position":"Engineer","stage":"Onsite","date_time":"2026-03-02T09:00:00Z","interviewer_email":"grace@example.com"`
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"google meet", `{` + base + `,"mode":"google"}`},
+		{"zoom with location and notes", `{` + base + `,"mode":"zoom","location":"https://zoom.example/j/1","description":"Bring a laptop","duration_minutes":45}`},
+		{"panel attendees", `{` + base + `,"mode":"teams","attendees":[{"email":"lin@example.com","role":"shadow","optional":true}]}`},
+	}
+	a := &App{}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			call := func(handler gin.HandlerFunc, path string) *httptest.ResponseRecorder {
+				w := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(w)
+				c.Request = httptest.NewRequest(http.MethodPost, path, strings.NewReader(tc.body))
+				c.Request.Header.Set("Content-Type", "application/json")
+				c.Request.Header.Set("X-Google-Token", `{"access_token":"good","token_type":"Bearer","expiry":"2999-01-01T00:00:00Z"}`)
+				handler(c)
+				return w
+			}
+
+			preview := call(a.PreviewInterviewEvent, "/api/calendar/interview/preview")
+			if preview.Code != http.StatusOK {
+				t.Fatalf("preview status = %d: %s", preview.Code, preview.Body)
+			}
+			google.inserted = nil
+			created := call(a.CreateInterviewEvent, "/api/calendar/interview")
+			if created.Code != http.StatusCreated {
+				t.Fatalf("create status = %d: %s", created.Code, created.Body)
+			}
+
+			got, want := decodeEvent(t, preview.Body.Bytes()), decodeEvent(t, google.inserted)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("preview body differs from the created one:\npreview %s\ncreated %s", preview.Body, google.inserted)
+			}
+		})
+	}
+}
+
+func TestPreviewInterviewEventRejectsMissingFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/calendar/interview/preview", strings.NewReader(`{"candidate_name":"Ada"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	(&App{}).PreviewInterviewEvent(c)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
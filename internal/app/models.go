@@ -432,4 +432,12 @@ type InterviewEvent struct {
 	Duration        int       `json:"duration_minutes"` // Duration in minutes, defaults to 60
 	Description     string    `json:"description,omitempty"`
 	Location        string    `json:"location,omitempty"`
+	Attendees       []InterviewAttendee `json:"attendees,omitempty" binding:"omitempty,dive"` // extra panel members beyond candidate and interviewer
+}
+
+// InterviewAttendee is an additional participant invited to an interview event
+type InterviewAttendee struct {
+	Email    string `json:"email" binding:"required,email"`
+	Role     string `json:"role,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
 }
\ No newline at end of file
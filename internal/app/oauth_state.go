@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/repository"
+)
+
+// oauthStateTTL bounds how long a user has to complete the Google consent screen.
+const oauthStateTTL = 10 * time.Minute
+
+// oauthStateCookie holds the state's nonce in the browser that started the
+// flow, so the callback only accepts the state in that browser.
+const oauthStateCookie = "oauth_state"
+
+// errOAuthStateSecret is returned when no key is configured to sign states.
+var errOAuthStateSecret = errors.New("oauth state secret not configured")
+
+type oauthStatePayload struct {
+	UserID    string `json:"u"`
+	Nonce     string `json:"n"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// oauthStateSecret returns the key used to sign OAuth state values. It falls
+// back to the Google client secret so existing deployments keep working, and
+// fails when neither is set rather than sign with an empty key.
+func oauthStateSecret() ([]byte, error) {
+	if secret := os.Getenv("OAUTH_STATE_SECRET"); secret != "" {
+		return []byte(secret), nil
+	}
+	if secret := os.Getenv("GOOGLE_CLIENT_SECRET"); secret != "" {
+		return []byte(secret), nil
+	}
+	return nil, errOAuthStateSecret
+}
+
+// newOAuthState builds a state value of the form payload.signature, where the
+// payload carries the user id, a random nonce and an expiry, and the signature
+// is an HMAC-SHA256 over the encoded payload. The nonce is returned too.
+func newOAuthState(userID string, now time.Time) (state, nonce string, err error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	nonce = hex.EncodeToString(buf)
+	raw, err := json.Marshal(oauthStatePayload{
+		UserID:    userID,
+		Nonce:     nonce,
+		ExpiresAt: now.Add(oauthStateTTL).Unix(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	sig, err := signOAuthState(payload)
+	if err != nil {
+		return "", "", err
+	}
+	return payload + "." + sig, nonce, nil
+}
+
+// verifyOAuthState checks the signature and expiry of a state produced by
+// newOAuthState and returns its payload.
+func verifyOAuthState(state string, now time.Time) (*oauthStatePayload, error) {
+	payload, sig, ok := strings.Cut(state, ".")
+	if !ok {
+		return nil, errors.New("malformed state")
+	}
+	want, err := signOAuthState(payload)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(sig), []byte(want)) {
+		return nil, errors.New("state signature mismatch")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errors.New("malformed state")
+	}
+	var p oauthStatePayload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, errors.New("malformed state")
+	}
+	if now.Unix() > p.ExpiresAt {
+		return nil, errors.New("state expired")
+	}
+	return &p, nil
+}
+
+func signOAuthState(payload string) (string, error) {
+	secret, err := oauthStateSecret()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// issueOAuthState creates a state for userID, records it as pending in store
+// and returns it along with its nonce for the browser cookie.
+func issueOAuthState(ctx context.Context, q repository.Querier, store repository.OAuthStateRepository, userID string, now time.Time) (state, nonce string, err error) {
+	state, nonce, err = newOAuthState(userID, now)
+	if err != nil {
+		return "", "", err
+	}
+	if err := store.InsertOAuthState(ctx, q, nonce, userID, now.Add(oauthStateTTL).UTC()); err != nil {
+		return "", "", err
+	}
+	return state, nonce, nil
+}
+
+// consumeOAuthState accepts a state returned to the callback: it must verify,
+// carry the nonce of the browser's cookie, and still be pending in store,
+// which it is removed from so it cannot be replayed. It returns the user id
+// the state was issued for.
+func consumeOAuthState(ctx context.Context, q repository.Querier, store repository.OAuthStateRepository, state, cookieNonce string, now time.Time) (string, error) {
+	p, err := verifyOAuthState(state, now)
+	if err != nil {
+		return "", err
+	}
+	if cookieNonce == "" || !hmac.Equal([]byte(cookieNonce), []byte(p.Nonce)) {
+		return "", errors.New("state issued to another browser")
+	}
+	userID, err := store.ConsumeOAuthState(ctx, q, p.Nonce, now.UTC())
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", errors.New("state already used")
+	}
+	if err != nil {
+		return "", err
+	}
+	return userID, nil
+}
+
+// setOAuthStateCookie stores nonce in the browser for the callback; an
+// empty nonce with maxAge -1 clears it. Lax keeps the cookie on the
+// top-level redirect back from Google.
+func setOAuthStateCookie(c *gin.Context, nonce string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, nonce, maxAge, "/", "", c.Request.TLS != nil, true)
+}
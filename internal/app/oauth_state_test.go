@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/repository"
+)
+
+// memOAuthStates is an in-memory OAuthStateRepository.
+type memOAuthStates struct {
+	mu      sync.Mutex
+	pending map[string]memOAuthState
+}
+
+type memOAuthState struct {
+	userID    string
+	expiresAt time.Time
+}
+
+func (m *memOAuthStates) InsertOAuthState(ctx context.Context, q repository.Querier, nonce, userID string, expiresAt repository.AppTime) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pending == nil {
+		m.pending = map[string]memOAuthState{}
+	}
+	m.pending[nonce] = memOAuthState{userID: userID, expiresAt: expiresAt.(time.Time)}
+	return nil
+}
+
+func (m *memOAuthStates) ConsumeOAuthState(ctx context.Context, q repository.Querier, nonce string, now repository.AppTime) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st, ok := m.pending[nonce]
+	if !ok || st.expiresAt.Before(now.(time.Time)) {
+		return "", pgx.ErrNoRows
+	}
+	delete(m.pending, nonce)
+	return st.userID, nil
+}
+
+func TestOAuthState(t *testing.T) {
+	t.Setenv("OAUTH_STATE_SECRET", "test-secret")
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	issue := func(t *testing.T, store *memOAuthStates) (string, string) {
+		t.Helper()
+		state, nonce, err := issueOAuthState(ctx, nil, store, "user-1", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return state, nonce
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		store := &memOAuthStates{}
+		state, nonce := issue(t, store)
+		userID, err := consumeOAuthState(ctx, nil, store, state, nonce, now.Add(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if userID != "user-1" {
+			t.Errorf("user id %q, want user-1", userID)
+		}
+	})
+
+	t.Run("replayed", func(t *testing.T) {
+		store := &memOAuthStates{}
+		state, nonce := issue(t, store)
+		if _, err := consumeOAuthState(ctx, nil, store, state, nonce, now); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := consumeOAuthState(ctx, nil, store, state, nonce, now); err == nil {
+			t.Error("second use accepted")
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		store := &memOAuthStates{}
+		state, nonce := issue(t, store)
+		if _, err := consumeOAuthState(ctx, nil, store, state, nonce, now.Add(oauthStateTTL+time.Second)); err == nil {
+			t.Error("expired state accepted")
+		}
+	})
+
+	t.Run("forged", func(t *testing.T) {
+		store := &memOAuthStates{}
+		state, nonce := issue(t, store)
+		payload, sig, _ := strings.Cut(state, ".")
+		forged := []string{
+			payload + "." + sig[:len(sig)-2] + "xx",
+			strings.ToUpper(payload[:4]) + payload[4:] + "." + sig,
+			payload,
+			"",
+		}
+		for _, f := range forged {
+			if _, err := consumeOAuthState(ctx, nil, store, f, nonce, now); err == nil {
+				t.Errorf("forged state %q accepted", f)
+			}
+		}
+
+		t.Setenv("OAUTH_STATE_SECRET", "other-secret")
+		if _, err := consumeOAuthState(ctx, nil, store, state, nonce, now); err == nil {
+			t.Error("state signed with another secret accepted")
+		}
+	})
+
+	t.Run("other browser", func(t *testing.T) {
+		store := &memOAuthStates{}
+		state, _ := issue(t, store)
+		_, otherNonce := issue(t, store)
+		for _, cookie := range []string{"", otherNonce} {
+			if _, err := consumeOAuthState(ctx, nil, store, state, cookie, now); err == nil {
+				t.Errorf("state accepted with cookie %q", cookie)
+			}
+		}
+	})
+
+	t.Run("unrecorded", func(t *testing.T) {
+		state, nonce, err := newOAuthState("user-1", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := consumeOAuthState(ctx, nil, &memOAuthStates{}, state, nonce, now); err == nil {
+			t.Error("state never issued through the store accepted")
+		}
+	})
+}
+
+func TestOAuthStateFailsClosedWithoutSecret(t *testing.T) {
+	t.Setenv("OAUTH_STATE_SECRET", "")
+	t.Setenv("GOOGLE_CLIENT_SECRET", "")
+	if _, _, err := issueOAuthState(context.Background(), nil, &memOAuthStates{}, "user-1", time.Now()); !errors.Is(err, errOAuthStateSecret) {
+		t.Errorf("issue: err = %v, want errOAuthStateSecret", err)
+	}
+	if _, err := verifyOAuthState("e30.c2ln", time.Now()); !errors.Is(err, errOAuthStateSecret) {
+		t.Errorf("verify: err = %v, want errOAuthStateSecret", err)
+	}
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userRateLimiter is a fixed-window limiter keyed by the authenticated user,
+// or by whatever key a RateLimitMiddleware derives.
+type userRateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	buckets map[string]*rateBucket
+}
+
+type rateBucket struct {
+	start time.Time
+	count int
+}
+
+// allow records a request for key and reports whether it fits in the current
+// window, along with how long until the window resets.
+func (l *userRateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok || now.Sub(b.start) >= l.window {
+		if len(l.buckets) > 10000 {
+			l.evictExpired(now)
+		}
+		b = &rateBucket{start: now}
+		l.buckets[key] = b
+	}
+	if b.count >= l.limit {
+		return false, b.start.Add(l.window).Sub(now)
+	}
+	b.count++
+	return true, 0
+}
+
+func (l *userRateLimiter) evictExpired(now time.Time) {
+	for k, b := range l.buckets {
+		if now.Sub(b.start) >= l.window {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// UserRateLimitMiddleware limits each authenticated user (user_email set by the
+// auth middleware) to perMinute requests per minute, answering 429 once the
+// budget is spent. A non-positive perMinute disables limiting.
+func UserRateLimitMiddleware(perMinute int) gin.HandlerFunc {
+	return RateLimitMiddleware(perMinute, func(c *gin.Context) string { return c.GetString("user_email") })
+}
+
+// RateLimitMiddleware limits requests sharing a key to perMinute per minute,
+// answering 429 once the budget is spent. Requests key maps to "" are not
+// limited. A non-positive perMinute disables limiting.
+func RateLimitMiddleware(perMinute int, key func(c *gin.Context) string) gin.HandlerFunc {
+	if perMinute <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	limiter := &userRateLimiter{limit: perMinute, window: time.Minute, buckets: map[string]*rateBucket{}}
+	return func(c *gin.Context) {
+		key := key(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+		ok, retryAfter := limiter.allow(key, time.Now())
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUserRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	type call struct {
+		user string
+		want int
+	}
+	cases := []struct {
+		name      string
+		perMinute int
+		calls     []call
+	}{
+		{"one user exhausts the budget, another is unaffected", 2, []call{
+			{"a@example.com", 201}, {"a@example.com", 201}, {"a@example.com", 429},
+			{"b@example.com", 201}, {"b@example.com", 201}, {"a@example.com", 429},
+			{"b@example.com", 429},
+		}},
+		{"disabled", 0, []call{{"a@example.com", 201}, {"a@example.com", 201}, {"a@example.com", 201}}},
+		{"unauthenticated requests pass", 1, []call{{"", 201}, {"", 201}}},
+	}
+	for _, tc := range cases {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			if u := c.GetHeader("X-Test-User"); u != "" {
+				c.Set("user_email", u)
+			}
+		})
+		r.POST("/users/:id/bookings", UserRateLimitMiddleware(tc.perMinute), func(c *gin.Context) { c.Status(http.StatusCreated) })
+		for i, cl := range tc.calls {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/users/u1/bookings", nil)
+			req.Header.Set("X-Test-User", cl.user)
+			r.ServeHTTP(w, req)
+			if w.Code != cl.want {
+				t.Errorf("%s: call %d by %q = %d, want %d", tc.name, i, cl.user, w.Code, cl.want)
+			}
+			if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+				t.Errorf("%s: call %d: 429 without Retry-After", tc.name, i)
+			}
+		}
+	}
+}
+
+func TestUserRateLimiterWindowResets(t *testing.T) {
+	l := &userRateLimiter{limit: 1, window: time.Minute, buckets: map[string]*rateBucket{}}
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		at        time.Duration
+		wantOK    bool
+		wantRetry time.Duration
+	}{
+		{0, true, 0},
+		{20 * time.Second, false, 40 * time.Second},
+		{59 * time.Second, false, time.Second},
+		{time.Minute, true, 0},
+	}
+	for _, tc := range cases {
+		ok, retry := l.allow("a@example.com", start.Add(tc.at))
+		if ok != tc.wantOK || retry != tc.wantRetry {
+			t.Errorf("at +%s: allow = %v, retry %s, want %v, %s", tc.at, ok, retry, tc.wantOK, tc.wantRetry)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+
+	"scheduler-service/internal/service"
+)
+
+// googleEventSource reads events from one Google calendar for
+// service.ReconcileWithCalendar.
+type googleEventSource struct {
+	srv        *calendar.Service
+	calendarID string
+}
+
+func (g googleEventSource) GetEvent(ctx context.Context, eventID string) (*service.CalendarEvent, error) {
+	ev, err := withGoogleBackoff(ctx, func() (*calendar.Event, error) {
+		return g.srv.Events.Get(g.calendarID, eventID).Context(ctx).Do()
+	})
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) && (gerr.Code == http.StatusNotFound || gerr.Code == http.StatusGone) {
+		return nil, service.ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	start, err := parseEventTime(ev.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseEventTime(ev.End)
+	if err != nil {
+		return nil, err
+	}
+	return &service.CalendarEvent{StartUTC: start, EndUTC: end, Cancelled: ev.Status == "cancelled"}, nil
+}
+
+// parseEventTime reads a Google event boundary; all-day dates map to UTC
+// midnight.
+func parseEventTime(t *calendar.EventDateTime) (time.Time, error) {
+	if t == nil {
+		return time.Time{}, errors.New("event has no time")
+	}
+	if t.DateTime != "" {
+		v, err := time.Parse(time.RFC3339, t.DateTime)
+		return v.UTC(), err
+	}
+	return time.Parse("2006-01-02", t.Date)
+}
+
+// ReconcileCalendarHandler serves POST /users/:id/calendar/reconcile[?apply=true].
+// It checks the user's upcoming bookings against their Google Calendar events
+// (token in X-Google-Token) and returns a service.ReconcileReport, applying
+// the fixes when apply is true.
+func (a *App) ReconcileCalendarHandler(bookingSvc *service.BookingService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := ResolvedUserFrom(c).ID
+		src, ok := a.googleEventSourceFor(c, userID)
+		if !ok {
+			return
+		}
+
+		report, err := bookingSvc.ReconcileWithCalendar(c.Request.Context(), userID, src, c.Query("apply") == "true")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// SyncCalendarHandler serves POST /users/:id/calendar/sync. It applies the
+// user's Google Calendar (token in X-Google-Token) to their upcoming bookings
+// right away and returns a service.CalendarSyncReport. Concurrent requests
+// for the same user share one sync.
+func (a *App) SyncCalendarHandler(bookingSvc *service.BookingService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := ResolvedUserFrom(c).ID
+		src, ok := a.googleEventSourceFor(c, userID)
+		if !ok {
+			return
+		}
+		report, err := bookingSvc.SyncCalendar(c.Request.Context(), userID, src)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// googleEventSourceFor builds the event source for the user's calendar from
+// the X-Google-Token header, answering the request itself on failure.
+func (a *App) googleEventSourceFor(c *gin.Context, userID string) (googleEventSource, bool) {
+	tokenStr := c.GetHeader("X-Google-Token")
+	if tokenStr == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-Google-Token header required"})
+		return googleEventSource{}, false
+	}
+	var token oauth2.Token
+	if err := json.Unmarshal([]byte(tokenStr), &token); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token format"})
+		return googleEventSource{}, false
+	}
+	calendarConfig := InitGoogleCalendarConfig()
+	if calendarConfig == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Google Calendar not configured"})
+		return googleEventSource{}, false
+	}
+	ctx := c.Request.Context()
+	srv, err := calendar.NewService(ctx, option.WithHTTPClient(calendarConfig.Config.Client(ctx, &token)))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create calendar service"})
+		return googleEventSource{}, false
+	}
+	calendarID, err := a.resolveCalendarID(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return googleEventSource{}, false
+	}
+	return googleEventSource{srv: srv, calendarID: calendarID}, true
+}
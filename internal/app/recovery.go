@@ -0,0 +1,45 @@
+package app
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDMiddleware tags every request with an id, reusing an incoming
+// X-Request-ID when present. The id is echoed in the response header and
+// stored in the context as "request_id".
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader("X-Request-ID")
+		if id == "" {
+			id = uuid.New().String()
+		}
+		c.Set("request_id", id)
+		c.Header("X-Request-ID", id)
+		c.Next()
+	}
+}
+
+// RecoveryMiddleware turns a handler panic into a JSON 500 carrying the request
+// id, logging the panic value and stack so the failure can be traced.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				requestID := c.GetString("request_id")
+				log.Printf("panic recovered request_id=%s method=%s path=%s: %v\n%s",
+					requestID, c.Request.Method, c.Request.URL.Path, rec, debug.Stack())
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"error":      "internal server error",
+					"code":       "INTERNAL",
+					"request_id": requestID,
+				})
+			}
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+	orig := log.Writer()
+	log.SetOutput(&logs)
+	defer log.SetOutput(orig)
+
+	r := gin.New()
+	r.Use(RequestIDMiddleware(), RecoveryMiddleware())
+	r.GET("/nil-map", func(c *gin.Context) {
+		var m map[string]int
+		m["boom"]++ // assignment to a nil map panics
+	})
+	r.GET("/ok", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
+
+	cases := []struct {
+		name          string
+		path          string
+		requestID     string
+		wantCode      int
+		wantRequestID string
+	}{
+		{"panic with incoming id", "/nil-map", "req-123", http.StatusInternalServerError, "req-123"},
+		{"panic with generated id", "/nil-map", "", http.StatusInternalServerError, ""},
+		{"no panic", "/ok", "req-456", http.StatusOK, "req-456"},
+	}
+	for _, tc := range cases {
+		logs.Reset()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.requestID != "" {
+			req.Header.Set("X-Request-ID", tc.requestID)
+		}
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.wantCode {
+			t.Fatalf("%s: status %d, want %d", tc.name, w.Code, tc.wantCode)
+		}
+		echoed := w.Header().Get("X-Request-ID")
+		if echoed == "" || tc.wantRequestID != "" && echoed != tc.wantRequestID {
+			t.Errorf("%s: X-Request-ID = %q, want %q", tc.name, echoed, tc.wantRequestID)
+		}
+		if tc.wantCode != http.StatusInternalServerError {
+			if logs.Len() != 0 {
+				t.Errorf("%s: logged %q", tc.name, logs.String())
+			}
+			continue
+		}
+		var body map[string]string
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: body %q is not JSON: %v", tc.name, w.Body, err)
+		}
+		if body["code"] != "INTERNAL" || body["request_id"] != echoed || body["error"] == "" {
+			t.Errorf("%s: body = %v, want code INTERNAL and request_id %q", tc.name, body, echoed)
+		}
+		if !strings.Contains(logs.String(), "request_id="+echoed) || !strings.Contains(logs.String(), "goroutine") {
+			t.Errorf("%s: log %q lacks the request id or stack", tc.name, logs.String())
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"scheduler-service/internal/repository/postgres"
+	"scheduler-service/internal/service"
+)
+
+// ResolvedUser is the target user of a /users/:id request, resolved once by
+// ResolveUserMiddleware.
+type ResolvedUser struct {
+	ID          string
+	CallerEmail string // email of the API key making the request
+	CallerKeyID string // id of the API key making the request
+}
+
+const resolvedUserKey = "resolved_user"
+
+// ResolveUserMiddleware validates the :id path parameter and stores a
+// *ResolvedUser in the context. When enforceOwnership is set, the id must
+// belong to an existing API key (404 otherwise) and match the caller's own key
+// (403 otherwise). It must run after AuthMiddlewareWithDB.
+func ResolveUserMiddleware(db *pgxpool.Pool, enforceOwnership bool) gin.HandlerFunc {
+	return resolveUser(service.NewAPIKeyService(db, postgres.NewAPIKeyRepo()), enforceOwnership)
+}
+
+func resolveUser(apiKeyService *service.APIKeyService, enforceOwnership bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		if _, err := uuid.Parse(id); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+		user := &ResolvedUser{ID: id, CallerEmail: c.GetString("user_email"), CallerKeyID: c.GetString("api_key_id")}
+
+		if enforceOwnership {
+			owner, err := apiKeyService.GetAPIKeyByID(c.Request.Context(), id)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			if owner == nil {
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "user not found"})
+				return
+			}
+			if owner.ID != user.CallerKeyID {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "not allowed to access this user"})
+				return
+			}
+		}
+
+		c.Set(resolvedUserKey, user)
+		c.Next()
+	}
+}
+
+// ResolvedUserFrom returns the user stored by ResolveUserMiddleware. Routes
+// mounted without the middleware get an unvalidated user built from :id.
+func ResolvedUserFrom(c *gin.Context) *ResolvedUser {
+	if v, ok := c.Get(resolvedUserKey); ok {
+		if u, ok := v.(*ResolvedUser); ok {
+			return u
+		}
+	}
+	return &ResolvedUser{ID: c.Param("id"), CallerEmail: c.GetString("user_email"), CallerKeyID: c.GetString("api_key_id")}
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+	"scheduler-service/internal/service"
+)
+
+// keyByIDRepo serves API keys by id; unknown ids return nil, as the postgres
+// repo does.
+type keyByIDRepo struct {
+	repository.APIKeyRepository
+	keys map[string]models.APIKey
+}
+
+func (r keyByIDRepo) GetAPIKeyByID(ctx context.Context, q repository.Querier, id string) (*models.APIKey, error) {
+	k, ok := r.keys[id]
+	if !ok {
+		return nil, nil
+	}
+	return &k, nil
+}
+
+func TestResolveUserMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const (
+		own   = "11111111-1111-1111-1111-111111111111"
+		other = "22222222-2222-2222-2222-222222222222"
+		none  = "33333333-3333-3333-3333-333333333333"
+	)
+	keys := service.NewAPIKeyService(nil, keyByIDRepo{keys: map[string]models.APIKey{
+		own:   {ID: own, Email: "own@example.com"},
+		other: {ID: other, Email: "other@example.com"},
+	}})
+	cases := []struct {
+		name       string
+		enforce    bool
+		id         string
+		wantStatus int
+	}{
+		{"valid user", true, own, http.StatusOK},
+		{"nonexistent user", true, none, http.StatusNotFound},
+		{"another caller's user", true, other, http.StatusForbidden},
+		{"malformed id", true, "not-a-uuid", http.StatusBadRequest},
+		{"ownership not enforced", false, other, http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				c.Set("api_key_id", own)
+				c.Set("user_email", "own@example.com")
+			})
+			r.GET("/users/:id", resolveUser(keys, tc.enforce), func(c *gin.Context) {
+				c.JSON(http.StatusOK, ResolvedUserFrom(c))
+			})
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/"+tc.id, nil))
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tc.wantStatus, w.Body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var got ResolvedUser
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			want := ResolvedUser{ID: tc.id, CallerEmail: "own@example.com", CallerKeyID: own}
+			if got != want {
+				t.Errorf("resolved user = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
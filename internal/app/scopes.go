@@ -0,0 +1,25 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/service"
+)
+
+const apiKeyScopesKey = "api_key_scopes"
+
+// RequireScope rejects with 403 a request whose API key lacks scope. It must
+// run after AuthMiddlewareWithDB.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted, _ := c.Get(apiKeyScopesKey)
+		scopes, _ := granted.([]string)
+		if !service.HasScope(scopes, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key lacks scope " + scope})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/service"
+)
+
+func TestRequireScopePerRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	routes := []struct {
+		method, path, scope string
+	}{
+		{http.MethodGet, "/users/:id/availability", service.ScopeAvailabilityRead},
+		{http.MethodPost, "/users/:id/availability", service.ScopeAvailabilityWrite},
+		{http.MethodGet, "/users/:id/bookings", service.ScopeBookingsRead},
+		{http.MethodPost, "/users/:id/bookings", service.ScopeBookingsWrite},
+		{http.MethodGet, "/users/:id/settings", service.ScopeSettingsRead},
+		{http.MethodPut, "/users/:id/settings", service.ScopeSettingsWrite},
+	}
+	cases := []struct {
+		name    string
+		granted []string
+		allowed map[string]bool // "METHOD path" of the routes the key may call
+	}{
+		{"full access", []string{service.ScopeAll}, map[string]bool{
+			"GET /users/:id/availability": true, "POST /users/:id/availability": true,
+			"GET /users/:id/bookings": true, "POST /users/:id/bookings": true,
+			"GET /users/:id/settings": true, "PUT /users/:id/settings": true,
+		}},
+		{"bookings read-only", []string{service.ScopeBookingsRead}, map[string]bool{
+			"GET /users/:id/bookings": true,
+		}},
+		{"bookings write", []string{service.ScopeBookingsWrite}, map[string]bool{
+			"GET /users/:id/bookings": true, "POST /users/:id/bookings": true,
+		}},
+		{"availability and settings read", []string{service.ScopeAvailabilityRead, service.ScopeSettingsRead}, map[string]bool{
+			"GET /users/:id/availability": true, "GET /users/:id/settings": true,
+		}},
+		{"no scopes", nil, map[string]bool{}},
+	}
+	for _, tc := range cases {
+		r := gin.New()
+		r.Use(func(c *gin.Context) { c.Set(apiKeyScopesKey, tc.granted) })
+		for _, rt := range routes {
+			r.Handle(rt.method, "/users/:id/"+rt.path[len("/users/:id/"):], RequireScope(rt.scope), func(c *gin.Context) { c.Status(http.StatusOK) })
+		}
+		for _, rt := range routes {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(rt.method, strings.Replace(rt.path, ":id", "u1", 1), nil))
+			want := http.StatusForbidden
+			if tc.allowed[rt.method+" "+rt.path] {
+				want = http.StatusOK
+			}
+			if w.Code != want {
+				t.Errorf("%s: %s %s = %d, want %d", tc.name, rt.method, rt.path, w.Code, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+)
+
+// fakeGoogle answers the calendar list and token endpoints: "good" is the
+// only access token Google accepts, and a refresh always yields it.
+type fakeGoogle struct{}
+
+func (fakeGoogle) RoundTrip(req *http.Request) (*http.Response, error) {
+	respond := func(code int, body string) (*http.Response, error) {
+		return &http.Response{StatusCode: code, Header: http.Header{"Content-Type": {"application/json"}}, Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+	}
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/token"):
+		return respond(http.StatusOK, `{"access_token":"good","token_type":"Bearer","expires_in":3600}`)
+	case req.Header.Get("Authorization") == "Bearer good":
+		return respond(http.StatusOK, `{"items":[]}`)
+	default:
+		return respond(http.StatusUnauthorized, `{"error":{"code":401,"message":"Invalid Credentials"}}`)
+	}
+}
+
+func TestValidateGoogleToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("GOOGLE_CLIENT_ID", "client")
+	t.Setenv("GOOGLE_CLIENT_SECRET", "secret")
+	t.Setenv("GOOGLE_REDIRECT_URL", "http://localhost/oauth2callback")
+	future, past := time.Now().Add(time.Hour), time.Now().Add(-time.Hour)
+	token := func(tok oauth2.Token) string {
+		b, _ := json.Marshal(tok)
+		return string(b)
+	}
+	cases := []struct {
+		name          string
+		header        string
+		wantCode      int
+		wantValid     bool
+		wantRefreshed bool
+	}{
+		{"valid token", token(oauth2.Token{AccessToken: "good", TokenType: "Bearer", Expiry: future}), http.StatusOK, true, false},
+		{"expired, refreshed", token(oauth2.Token{AccessToken: "stale", TokenType: "Bearer", RefreshToken: "r", Expiry: past}), http.StatusOK, true, true},
+		{"expired, no refresh token", token(oauth2.Token{AccessToken: "stale", TokenType: "Bearer", Expiry: past}), http.StatusUnauthorized, false, false},
+		{"revoked", token(oauth2.Token{AccessToken: "revoked", TokenType: "Bearer", Expiry: future}), http.StatusUnauthorized, false, false},
+		{"malformed", "not json", http.StatusBadRequest, false, false},
+		{"missing", "", http.StatusBadRequest, false, false},
+	}
+	a := &App{}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: fakeGoogle{}})
+			req := httptest.NewRequest(http.MethodPost, "/api/calendar/validate-token", nil).WithContext(ctx)
+			if tc.header != "" {
+				req.Header.Set("X-Google-Token", tc.header)
+			}
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			a.ValidateGoogleToken(c)
+
+			if w.Code != tc.wantCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tc.wantCode, w.Body)
+			}
+			var body struct {
+				Valid     bool   `json:"valid"`
+				Refreshed bool   `json:"refreshed"`
+				Token     string `json:"token"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Valid != tc.wantValid || body.Refreshed != tc.wantRefreshed {
+				t.Errorf("valid = %v, refreshed = %v, want %v, %v", body.Valid, body.Refreshed, tc.wantValid, tc.wantRefreshed)
+			}
+			if tc.wantRefreshed && !strings.Contains(body.Token, `"access_token":"good"`) {
+				t.Errorf("refreshed token %q not returned", body.Token)
+			}
+		})
+	}
+}
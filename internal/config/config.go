@@ -1,7 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -13,6 +16,130 @@ type Config struct {
 	GoogleClientID string
 	GoogleSecret   string
 	GoogleRedirect string
+
+	// BlockCandidateDoubleBooking rejects bookings whose window overlaps another
+	// confirmed booking for the same candidate with any interviewer.
+	BlockCandidateDoubleBooking bool
+
+	// MinCandidateGapMins is the least time, in minutes, required between a
+	// candidate's confirmed bookings across all interviewers. Zero disables it.
+	MinCandidateGapMins int
+
+	// SlotBatchConcurrency bounds parallel per-user slot generation in the
+	// batch slots endpoint.
+	SlotBatchConcurrency int
+
+	// BookingRateLimitPerMinute caps booking creations per authenticated user
+	// and per candidate self-service token. Zero disables the limit.
+	BookingRateLimitPerMinute int
+
+	// BookingStreamBatchSize is how many bookings the NDJSON export reads per query.
+	BookingStreamBatchSize int
+
+	// AllowedEmailDomains restricts candidate emails to these domains when
+	// non-empty (comma-separated ALLOWED_EMAIL_DOMAINS).
+	AllowedEmailDomains []string
+
+	// SlotHoldTTLSeconds is how long a tentative slot hold blocks the slot.
+	SlotHoldTTLSeconds int
+
+	// MaxRulesPerUser bounds the number of availability rules per user.
+	MaxRulesPerUser int
+
+	// BookingTxIsolation is the isolation level of the booking transaction:
+	// "read committed" (the server default when empty), "repeatable read" or
+	// "serializable". Underscores are accepted in place of spaces.
+	BookingTxIsolation string
+
+	// EnforceUserOwnership restricts /users/:id routes to the user whose API
+	// key id matches :id; other callers get 403 and unknown ids 404.
+	EnforceUserOwnership bool
+
+	// SlowQueryMS logs database calls slower than this many milliseconds.
+	// Zero disables slow query logging.
+	SlowQueryMS int
+
+	// GoogleMaxConcurrency caps in-flight requests to the Google APIs across
+	// the calendar handlers. Zero disables the cap.
+	GoogleMaxConcurrency int
+
+	// GoogleConcurrencyWaitMS is how long a request waits for a free Google
+	// slot before answering 503.
+	GoogleConcurrencyWaitMS int
+
+	// StrictUTCTimestamps rejects booking and hold timestamps in *_utc fields
+	// that carry an offset instead of a Z suffix. It is the default for the
+	// per-request strict_utc flag.
+	StrictUTCTimestamps bool
+
+	// AdminToken is the shared secret for /api/admin routes, sent in the
+	// X-Admin-Token header. Empty disables the admin API.
+	AdminToken string
+
+	// CancelledBookingRetentionDays enables a background job deleting
+	// cancelled bookings that ended more than this many days ago. Zero keeps
+	// them forever.
+	CancelledBookingRetentionDays int
+
+	// CancelledBookingPurgeIntervalMinutes is how often the purge job runs.
+	CancelledBookingPurgeIntervalMinutes int
+
+	// CandidateTokenSecret signs candidate self-service tokens. Empty disables
+	// candidate tokens and the /public/candidate routes.
+	CandidateTokenSecret string
+
+	// EnforceCandidateTokenEmail rejects, with 403, self-service bookings
+	// made with a candidate token under an email other than the token's.
+	EnforceCandidateTokenEmail bool
+
+	// FeedTokenSecret signs team ICS feed tokens. Empty disables the
+	// /calendar/feed.ics route.
+	FeedTokenSecret string
+
+	// BusinessHours is an org-wide bound on bookable time such as
+	// "Mon-Fri 08:00-18:00", in BusinessHoursTZ (UTC when empty). Slots and
+	// bookings outside it are rejected even when a rule allows them. Empty
+	// disables the policy.
+	BusinessHours   string
+	BusinessHoursTZ string
+
+	// SeedDefaultAvailability gives a brand-new user a Mon-Fri 09:00-17:00
+	// template of 30 minute slots when their first API key is created.
+	SeedDefaultAvailability bool
+
+	// SkipPastSlots stops slot listings from returning slots that have
+	// already ended, skipping past days without planning them.
+	SkipPastSlots bool
+
+	// ReadCacheMaxAgeSeconds is the max-age sent with ETagged slot and
+	// availability reads. 0 still sends an ETag, so clients revalidate.
+	ReadCacheMaxAgeSeconds int
+
+	// TrustedProxies lists the proxy addresses or CIDRs (comma-separated
+	// TRUSTED_PROXIES) whose X-Forwarded-For is believed when resolving the
+	// client IP, e.g. for API key IP allowlists. Empty trusts no proxy and
+	// uses the connection's address.
+	TrustedProxies []string
+
+	// ConfirmationCodeLength is how many characters new bookings'
+	// confirmation codes have, between 6 and 32.
+	ConfirmationCodeLength int
+
+	// RequireBookingApproval creates bookings as pending until they are
+	// approved through POST /bookings/:id/approve.
+	RequireBookingApproval bool
+
+	// ApprovalTTLMins expires bookings left pending longer than this many
+	// minutes, freeing their slots. Zero keeps them pending indefinitely.
+	ApprovalTTLMins int
+
+	// ApprovalExpiryIntervalMinutes is how often the expiry job runs.
+	ApprovalExpiryIntervalMinutes int
+
+	// DSTRepeatedHour is "both" (the default) to offer slots in both passes
+	// of the hour repeated when clocks go back, or "first" to offer them once.
+	// Times skipped when clocks go forward are never offered.
+	DSTRepeatedHour string
 }
 
 func Load() (*Config, error) {
@@ -25,6 +152,84 @@ func Load() (*Config, error) {
 		GoogleClientID: os.Getenv("GOOGLE_CLIENT_ID"),
 		GoogleSecret:   os.Getenv("GOOGLE_CLIENT_SECRET"),
 		GoogleRedirect: os.Getenv("GOOGLE_REDIRECT_URL"),
+		AdminToken:     os.Getenv("ADMIN_TOKEN"),
+
+		CandidateTokenSecret: os.Getenv("CANDIDATE_TOKEN_SECRET"),
+		FeedTokenSecret:      os.Getenv("FEED_TOKEN_SECRET"),
+		BusinessHours:        strings.TrimSpace(os.Getenv("BUSINESS_HOURS")),
+		BusinessHoursTZ:      strings.TrimSpace(os.Getenv("BUSINESS_HOURS_TZ")),
+
+		BlockCandidateDoubleBooking: getEnvBool("BLOCK_CANDIDATE_DOUBLE_BOOKING", false),
+		MinCandidateGapMins:         getEnvInt("MIN_CANDIDATE_GAP_MINS", 0),
+		SlotBatchConcurrency:        getEnvInt("SLOT_BATCH_CONCURRENCY", 4),
+		BookingRateLimitPerMinute:   getEnvInt("BOOKING_RATE_LIMIT_PER_MINUTE", 30),
+		BookingStreamBatchSize:      getEnvInt("BOOKING_STREAM_BATCH_SIZE", 500),
+		AllowedEmailDomains:         getEnvList("ALLOWED_EMAIL_DOMAINS"),
+		SlotHoldTTLSeconds:          getEnvInt("SLOT_HOLD_TTL_SECONDS", 300),
+		MaxRulesPerUser:             getEnvInt("MAX_RULES_PER_USER", 500),
+		EnforceUserOwnership:        getEnvBool("ENFORCE_USER_OWNERSHIP", false),
+		SlowQueryMS:                 getEnvInt("SLOW_QUERY_MS", 0),
+		GoogleMaxConcurrency:        getEnvInt("GOOGLE_MAX_CONCURRENCY", 16),
+		GoogleConcurrencyWaitMS:     getEnvInt("GOOGLE_CONCURRENCY_WAIT_MS", 5000),
+		StrictUTCTimestamps:         getEnvBool("STRICT_UTC_TIMESTAMPS", false),
+		SeedDefaultAvailability:     getEnvBool("SEED_DEFAULT_AVAILABILITY", false),
+		SkipPastSlots:               getEnvBool("SKIP_PAST_SLOTS", false),
+		ReadCacheMaxAgeSeconds:      getEnvInt("READ_CACHE_MAX_AGE_SECONDS", 0),
+		TrustedProxies:              getEnvList("TRUSTED_PROXIES"),
+		ConfirmationCodeLength:      getEnvInt("CONFIRMATION_CODE_LENGTH", 8),
+
+		CancelledBookingRetentionDays:        getEnvInt("CANCELLED_BOOKING_RETENTION_DAYS", 0),
+		CancelledBookingPurgeIntervalMinutes: getEnvInt("CANCELLED_BOOKING_PURGE_INTERVAL_MINUTES", 60),
+
+		EnforceCandidateTokenEmail: getEnvBool("ENFORCE_CANDIDATE_TOKEN_EMAIL", true),
+
+		RequireBookingApproval:        getEnvBool("REQUIRE_BOOKING_APPROVAL", false),
+		ApprovalTTLMins:               getEnvInt("APPROVAL_TTL_MINS", 0),
+		ApprovalExpiryIntervalMinutes: getEnvInt("APPROVAL_EXPIRY_INTERVAL_MINUTES", 5),
+	}
+
+	iso := strings.ToLower(strings.TrimSpace(strings.ReplaceAll(os.Getenv("BOOKING_TX_ISOLATION"), "_", " ")))
+	switch iso {
+	case "", "read committed", "repeatable read", "serializable":
+		cfg.BookingTxIsolation = iso
+	default:
+		return nil, fmt.Errorf("invalid BOOKING_TX_ISOLATION %q", os.Getenv("BOOKING_TX_ISOLATION"))
+	}
+	switch cfg.DSTRepeatedHour = strings.ToLower(strings.TrimSpace(os.Getenv("DST_REPEATED_HOUR"))); cfg.DSTRepeatedHour {
+	case "":
+		cfg.DSTRepeatedHour = "both"
+	case "both", "first":
+	default:
+		return nil, fmt.Errorf("invalid DST_REPEATED_HOUR %q: must be both or first", os.Getenv("DST_REPEATED_HOUR"))
+	}
+	if cfg.ConfirmationCodeLength < 6 || cfg.ConfirmationCodeLength > 32 {
+		return nil, fmt.Errorf("invalid CONFIRMATION_CODE_LENGTH %d: must be between 6 and 32", cfg.ConfirmationCodeLength)
 	}
 	return cfg, nil
 }
+
+func getEnvList(key string) []string {
+	var out []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func getEnvInt(key string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func getEnvBool(key string, def bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/service"
+)
+
+type AdminHandler struct {
+	BookSv *service.BookingService
+}
+
+// GET /admin/bookings[?from=ISO&to=ISO&status=confirmed|pending|cancelled|expired&limit=N&cursor=...]
+// Lists bookings across all users in start order, paginated like
+// /users/:id/bookings/upcoming.
+func (h *AdminHandler) ListBookings(c *gin.Context) {
+	var filter service.AdminBookingFilter
+	if c.Query("from") != "" || c.Query("to") != "" {
+		from, to, ok := parseTimeRange(c)
+		if !ok {
+			return
+		}
+		filter.From, filter.To = &from, &to
+	}
+	filter.Status = c.Query("status")
+
+	limit := defaultUpcomingLimit
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		if n > maxUpcomingLimit {
+			n = maxUpcomingLimit
+		}
+		limit = n
+	}
+	var cursor *service.BookingCursor
+	if v := c.Query("cursor"); v != "" {
+		cur, err := decodeBookingCursor(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+		cursor = cur
+	}
+
+	bookings, next, err := h.BookSv.ListAllBookings(c.Request.Context(), filter, cursor, limit)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "status must be") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if bookings == nil {
+		bookings = []models.Booking{}
+	}
+	resp := gin.H{"bookings": bookings}
+	if next != nil {
+		resp["next_cursor"] = encodeBookingCursor(next)
+	}
+	c.JSON(http.StatusOK, resp)
+}
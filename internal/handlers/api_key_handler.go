@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -13,12 +18,20 @@ type APIKeyHandler struct {
 }
 
 // GenerateAPIKey handles POST /api/auth/key
-// Request body: { "email": "user@example.com", "password": "password123" }
-// Response: { "api_key": "sk_...", "email": "user@example.com", "created_at_utc": "..." }
+// Request body: { "email": "user@example.com", "password": "password123", "scopes": ["bookings:read"], "allowed_ips": ["203.0.113.0/24"], "ttl_seconds": 86400 }
+// Omitting scopes grants full access; omitting allowed_ips allows any source address;
+// omitting ttl_seconds creates a key that never expires. Regenerating an
+// existing key keeps its scopes and allowed_ips unless narrower ones are
+// requested; widening either is 403. Its expiry is kept too, and a later
+// one is capped at it.
+// Response: { "api_key": "sk_...", "email": "user@example.com", "scopes": [...], "allowed_ips": [...], "expires_at_utc": "...", "created_at_utc": "..." }
 func (h *APIKeyHandler) GenerateAPIKey(c *gin.Context) {
 	var req struct {
-		Email    string `json:"email" binding:"required,email"`
-		Password string `json:"password" binding:"required"`
+		Email      string   `json:"email" binding:"required,email"`
+		Password   string   `json:"password" binding:"required"`
+		Scopes     []string `json:"scopes"`
+		AllowedIPs []string `json:"allowed_ips"`
+		TTLSeconds *int     `json:"ttl_seconds"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -26,16 +39,108 @@ func (h *APIKeyHandler) GenerateAPIKey(c *gin.Context) {
 		return
 	}
 
-	apiKey, apiKeyRecord, err := h.Service.GenerateAPIKey(c.Request.Context(), req.Email, req.Password)
+	var ttl time.Duration
+	if req.TTLSeconds != nil {
+		if *req.TTLSeconds <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ttl_seconds must be positive"})
+			return
+		}
+		ttl = time.Duration(*req.TTLSeconds) * time.Second
+	}
+
+	apiKey, apiKeyRecord, err := h.Service.GenerateAPIKey(c.Request.Context(), req.Email, req.Password, req.Scopes, req.AllowedIPs, ttl)
+	if errors.Is(err, service.ErrAPIKeyConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrAPIKeyWiden) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil && (strings.HasPrefix(err.Error(), "unknown scope") || strings.HasPrefix(err.Error(), "invalid allowed IP")) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	var expiresAt *time.Time
+	if apiKeyRecord.ExpiresAt != nil {
+		utc := apiKeyRecord.ExpiresAt.UTC()
+		expiresAt = &utc
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"api_key":        apiKey,
 		"email":          apiKeyRecord.Email,
+		"scopes":         apiKeyRecord.Scopes,
+		"allowed_ips":    apiKeyRecord.AllowedIPs,
+		"expires_at_utc": expiresAt,
 		"created_at_utc": apiKeyRecord.CreatedAt.UTC(),
 		"uuid":           apiKeyRecord.ID,
 	})
 }
+
+// maxBulkAPIKeys caps how many emails one bulk key request may name.
+const maxBulkAPIKeys = 200
+
+// GenerateAPIKeysBulk handles POST /api/admin/keys/bulk
+// Request body: { "emails": ["a@example.com", "b@example.com"], "skip_existing": true }
+// Keys are created in one transaction and returned in plaintext only here.
+// Without skip_existing an email that already has a key fails the batch.
+func (h *APIKeyHandler) GenerateAPIKeysBulk(c *gin.Context) {
+	var req struct {
+		Emails       []string `json:"emails" binding:"required,min=1,dive,required,email"`
+		SkipExisting bool     `json:"skip_existing"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Emails) > maxBulkAPIKeys {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d emails allowed", maxBulkAPIKeys)})
+		return
+	}
+
+	keys, err := h.Service.GenerateAPIKeysBulk(c.Request.Context(), req.Emails, req.SkipExisting)
+	if errors.Is(err, service.ErrAPIKeyConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, gin.H{"keys": keys})
+}
+
+// Window of ListExpiringAPIKeys when within_days is omitted, and the longest
+// allowed.
+const (
+	defaultExpiringKeysDays = 7
+	maxExpiringKeysDays     = 365
+)
+
+// ListExpiringAPIKeys handles GET /api/admin/keys/expiring?within_days=N
+// Lists keys whose expiry falls within the next N days (default 7), soonest
+// first, for renewal reminders.
+// Response: { "within_days": 7, "keys": [{ "id": "...", "email": "...", "expires_at_utc": "...", ... }] }
+func (h *APIKeyHandler) ListExpiringAPIKeys(c *gin.Context) {
+	days := defaultExpiringKeysDays
+	if v := c.Query("within_days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxExpiringKeysDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("within_days must be between 1 and %d", maxExpiringKeysDays)})
+			return
+		}
+		days = n
+	}
+	keys, err := h.Service.ExpiringAPIKeys(c.Request.Context(), time.Duration(days)*24*time.Hour)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"within_days": days, "keys": keys})
+}
@@ -1,14 +1,27 @@
 package handlers
 
 import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"scheduler-service/internal/app"
+	"scheduler-service/internal/ics"
 	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
 	"scheduler-service/internal/service"
 )
 
@@ -16,45 +29,86 @@ type AvailabilityHandlers struct {
 	DB      *pgxpool.Pool
 	AvailSv *service.AvailabilityService
 	BookSv  *service.BookingService
+
+	// StreamBatchSize is the default page size for StreamBookings.
+	StreamBatchSize int
+
+	// CacheMaxAge is the Cache-Control max-age of ETagged reads (slots,
+	// availability, slot matrix).
+	CacheMaxAge time.Duration
+
+	// EnforceOwnership mirrors ResolveUserMiddleware for routes naming a
+	// second user, which must then also belong to the caller.
+	EnforceOwnership bool
 }
 
-// POST /users/:id/availability
+// POST /users/:id/availability[?upsert=true]
 func (h *AvailabilityHandlers) SetAvailability(c *gin.Context) {
-	userID := c.Param("id")
+	userID := app.ResolvedUserFrom(c).ID
 	var payload []models.AvailabilityRule
 	if err := c.BindJSON(&payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	saved, err := h.AvailSv.SetAvailability(c.Request.Context(), userID, payload)
+	save := h.AvailSv.SetAvailability
+	if c.Query("upsert") == "true" {
+		save = h.AvailSv.UpsertAvailability
+	}
+	saved, err := save(c.Request.Context(), userID, payload)
 	if err != nil {
+		if err.Error() == "availability rule already exists" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "availability rule limit exceeded" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "limit": h.AvailSv.MaxRulesPerUser})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "unknown timezone") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	// Only include created_at_utc in response
 	type CreatedAvailability struct {
-		ID             string    `json:"id"`
-		UserID         string    `json:"user_id"`
-		DayOfWeek      int       `json:"day_of_week"`
-		StartTime      string    `json:"start_time"`
-		EndTime        string    `json:"end_time"`
-		SlotLengthMins int       `json:"slot_length_minutes"`
-		Title          string    `json:"title,omitempty"`
-		Available      bool      `json:"available"`
-		CreatedAtUTC   time.Time `json:"created_at_utc"`
+		ID                string              `json:"id"`
+		UserID            string              `json:"user_id"`
+		DayOfWeek         int                 `json:"day_of_week"`
+		StartTime         string              `json:"start_time"`
+		EndTime           string              `json:"end_time"`
+		SlotLengthMins    int                 `json:"slot_length_minutes"`
+		StartOffsetMins   int                 `json:"start_offset_minutes,omitempty"`
+		BufferMins        int                 `json:"buffer_minutes,omitempty"`
+		Title             string              `json:"title,omitempty"`
+		TitleTranslations map[string]string   `json:"title_translations,omitempty"`
+		Timezone          string              `json:"timezone,omitempty"`
+		Tags              []string            `json:"tags,omitempty"`
+		Windows           []models.TimeWindow `json:"windows,omitempty"`
+		Available         bool                `json:"available"`
+		CreatedAtUTC      time.Time           `json:"created_at_utc"`
+		Warnings          []string            `json:"warnings,omitempty"`
 	}
 	var filtered []CreatedAvailability
 	for _, rule := range saved {
 		filtered = append(filtered, CreatedAvailability{
-			ID:             rule.ID,
-			UserID:         rule.UserID,
-			DayOfWeek:      rule.DayOfWeek,
-			StartTime:      rule.StartTime,
-			EndTime:        rule.EndTime,
-			SlotLengthMins: rule.SlotLengthMins,
-			Title:          rule.Title,
-			Available:      rule.Available,
-			CreatedAtUTC:   rule.CreatedAt,
+			ID:                rule.ID,
+			UserID:            rule.UserID,
+			DayOfWeek:         rule.DayOfWeek,
+			StartTime:         rule.StartTime,
+			EndTime:           rule.EndTime,
+			SlotLengthMins:    rule.SlotLengthMins,
+			StartOffsetMins:   rule.StartOffsetMins,
+			BufferMins:        rule.BufferMins,
+			Title:             rule.Title,
+			TitleTranslations: rule.TitleTranslations,
+			Timezone:          rule.Timezone,
+			Tags:              rule.Tags,
+			Windows:           rule.Windows,
+			Available:         rule.Available,
+			CreatedAtUTC:      rule.CreatedAt,
+			Warnings:          service.AvailabilityRuleWarnings(rule),
 		})
 	}
 	c.JSON(http.StatusCreated, filtered)
@@ -62,7 +116,7 @@ func (h *AvailabilityHandlers) SetAvailability(c *gin.Context) {
 
 // PUT /users/:id/availability/:rule_id
 func (h *AvailabilityHandlers) UpdateAvailability(c *gin.Context) {
-	userID := c.Param("id")
+	userID := app.ResolvedUserFrom(c).ID
 	ruleID := c.Param("rule_id")
 
 	var payload models.AvailabilityRule
@@ -75,201 +129,374 @@ func (h *AvailabilityHandlers) UpdateAvailability(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "availability not found"})
 		return
 	}
+	if err != nil && err.Error() == "availability rule already exists" {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil && strings.HasPrefix(err.Error(), "unknown timezone") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	// Only include updated_at_utc in response
 	type UpdatedAvailability struct {
-		ID             string    `json:"id"`
-		UserID         string    `json:"user_id"`
-		DayOfWeek      int       `json:"day_of_week"`
-		StartTime      string    `json:"start_time"`
-		EndTime        string    `json:"end_time"`
-		SlotLengthMins int       `json:"slot_length_minutes"`
-		Title          string    `json:"title,omitempty"`
-		Available      bool      `json:"available"`
-		UpdatedAtUTC   time.Time `json:"updated_at_utc"`
+		ID                string              `json:"id"`
+		UserID            string              `json:"user_id"`
+		DayOfWeek         int                 `json:"day_of_week"`
+		StartTime         string              `json:"start_time"`
+		EndTime           string              `json:"end_time"`
+		SlotLengthMins    int                 `json:"slot_length_minutes"`
+		StartOffsetMins   int                 `json:"start_offset_minutes,omitempty"`
+		BufferMins        int                 `json:"buffer_minutes,omitempty"`
+		Title             string              `json:"title,omitempty"`
+		TitleTranslations map[string]string   `json:"title_translations,omitempty"`
+		Timezone          string              `json:"timezone,omitempty"`
+		Tags              []string            `json:"tags,omitempty"`
+		Windows           []models.TimeWindow `json:"windows,omitempty"`
+		Available         bool                `json:"available"`
+		UpdatedAtUTC      time.Time           `json:"updated_at_utc"`
 	}
 	filtered := UpdatedAvailability{
-		ID:             res.ID,
-		UserID:         res.UserID,
-		DayOfWeek:      res.DayOfWeek,
-		StartTime:      res.StartTime,
-		EndTime:        res.EndTime,
-		SlotLengthMins: res.SlotLengthMins,
-		Title:          res.Title,
-		Available:      res.Available,
-		UpdatedAtUTC:   res.UpdatedAt,
+		ID:                res.ID,
+		UserID:            res.UserID,
+		DayOfWeek:         res.DayOfWeek,
+		StartTime:         res.StartTime,
+		EndTime:           res.EndTime,
+		SlotLengthMins:    res.SlotLengthMins,
+		StartOffsetMins:   res.StartOffsetMins,
+		BufferMins:        res.BufferMins,
+		Title:             res.Title,
+		TitleTranslations: res.TitleTranslations,
+		Timezone:          res.Timezone,
+		Tags:              res.Tags,
+		Windows:           res.Windows,
+		Available:         res.Available,
+		UpdatedAtUTC:      res.UpdatedAt,
 	}
 	c.JSON(http.StatusOK, filtered)
 }
 
 // GET /users/:id/availability
 func (h *AvailabilityHandlers) ListAvailability(c *gin.Context) {
-	userID := c.Param("id")
+	userID := app.ResolvedUserFrom(c).ID
 	rules, err := h.AvailSv.ListAvailability(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, rules)
+	writeCachedJSON(c, rules, h.CacheMaxAge)
 }
 
-// GET /users/:id/slots?from=ISO&to=ISO
-func (h *AvailabilityHandlers) GetSlots(c *gin.Context) {
-	userID := c.Param("id")
-	fromStr := c.Query("from")
-	toStr := c.Query("to")
-	if fromStr == "" || toStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to required (ISO8601)"})
-		return
-	}
-	from, err := time.Parse(time.RFC3339, fromStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
-		return
-	}
-	to, err := time.Parse(time.RFC3339, toStr)
+// GET /users/:id/availability/export
+func (h *AvailabilityHandlers) ExportAvailability(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	doc, err := h.AvailSv.ExportAvailability(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if !from.Before(to) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="availability-%s.json"`, userID))
+	c.JSON(http.StatusOK, doc)
+}
+
+// POST /users/:id/availability/import
+func (h *AvailabilityHandlers) ImportAvailability(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	var doc models.AvailabilityDocument
+	if err := c.BindJSON(&doc); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	slots, err := h.AvailSv.GenerateAvailableSlots(c.Request.Context(), userID, from.UTC(), to.UTC())
+	saved, exceptions, err := h.AvailSv.ImportAvailability(c.Request.Context(), userID, &doc)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidAvailabilityDocument) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err.Error() == "availability rule limit exceeded" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "limit": h.AvailSv.MaxRulesPerUser})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, slots)
-}
-
-type createBookingReq struct {
-	UserID         string `json:"user_id"`
-	CandidateEmail string `json:"candidate_email" binding:"required,email"`
-	StartAtUTCStr  string `json:"start_at_utc" binding:"required"`
-	EndAtUTCStr    string `json:"end_at_utc" binding:"required"`
-	Source         string `json:"source,omitempty"`
-	Type           string `json:"type,omitempty"`
-	Description    string `json:"description,omitempty"`
-	Title          string `json:"title,omitempty"`
+	c.JSON(http.StatusOK, gin.H{"imported": len(saved), "rules": saved, "exceptions": exceptions})
 }
 
-// GET /users/:id/bookings
-func (h *AvailabilityHandlers) ListBookings(c *gin.Context) {
-	userID := c.Param("id")
-	fromStr := c.Query("from")
-	toStr := c.Query("to")
-
-	ctx := c.Request.Context()
+// Limits for ImportICS: the upload size and the span of days imported.
+const (
+	maxICSBytes     = 1 << 20
+	maxICSRangeDays = 366
+)
 
-	var (
-		from time.Time
-		to   time.Time
-		err  error
-	)
+// POST /users/:id/availability/import-ics?from=YYYY-MM-DD&to=YYYY-MM-DD
+// Accepts an iCalendar file, either as the raw body or as the "file" field of
+// a multipart form, and creates blackout overrides for the busy time of its
+// events (recurrences expanded) on the dates from..to inclusive. Dates and
+// times are in the timezone the user's rules share, UTC otherwise, matching
+// how overrides are read.
+func (h *AvailabilityHandlers) ImportICS(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be YYYY-MM-DD"})
+		return
+	}
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be YYYY-MM-DD"})
+		return
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return
+	}
+	if to.Sub(from) >= maxICSRangeDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("range must not exceed %d days", maxICSRangeDays)})
+		return
+	}
 
-	if fromStr != "" && toStr != "" {
-		from, err = time.Parse(time.RFC3339, fromStr)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxICSBytes)
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "multipart upload needs a file field"})
 			return
 		}
-		to, err = time.Parse(time.RFC3339, toStr)
+		f, err := fh.Open()
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		if !from.Before(to) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		defer f.Close()
+		body = f
+	}
+	events, err := ics.Parse(body)
+	if err != nil {
+		var tooBig *http.MaxBytesError
+		if errors.As(err, &tooBig) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("ics file exceeds %d bytes", maxICSBytes)})
 			return
 		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ics file: " + err.Error()})
+		return
 	}
 
-	bookings, err := h.BookSv.ListBookings(ctx, userID, from, to, fromStr != "" && toStr != "")
+	created, err := h.AvailSv.ImportICSBlackouts(c.Request.Context(), userID, events, from, to.AddDate(0, 0, 1))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if err.Error() == "schedule overrides not enabled" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, bookings)
+	c.JSON(http.StatusOK, gin.H{"imported": len(created), "overrides": created})
 }
 
-// POST /users/:id/bookings
-func (h *AvailabilityHandlers) CreateBooking(c *gin.Context) {
-	userID := c.Param("id")
-	var req createBookingReq
-	if err := c.BindJSON(&req); err != nil {
+// POST /users/:id/overrides
+// Request body: { "type": "blackout", "start_date": "2025-12-24", "end_date": "2025-12-26" }
+// or { "type": "custom_hours", "start_date": "...", "start_time": "13:00", "end_time": "17:00", "slot_length_minutes": 30 }
+func (h *AvailabilityHandlers) CreateOverride(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	var payload models.ScheduleOverride
+	if err := c.BindJSON(&payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	start, err := time.Parse(time.RFC3339, req.StartAtUTCStr)
+	o, err := h.AvailSv.CreateOverride(c.Request.Context(), userID, &payload)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_at_utc"})
+		if err.Error() == "schedule overrides not enabled" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	end, err := time.Parse(time.RFC3339, req.EndAtUTCStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_at_utc"})
+	c.JSON(http.StatusCreated, o)
+}
+
+type oneOffAvailabilityReq struct {
+	Date           string `json:"date" binding:"required"`
+	StartTime      string `json:"start_time" binding:"required"`
+	EndTime        string `json:"end_time" binding:"required"`
+	SlotLengthMins int    `json:"slot_length_minutes" binding:"required"`
+	Title          string `json:"title"`
+}
+
+// POST /users/:id/availability/one-off
+// Request body: { "date": "2025-06-14", "start_time": "10:00", "end_time": "12:00", "slot_length_minutes": 30 }
+// Adds availability on one date only, even a day with no weekly rule. It is
+// listed and deleted like any other override.
+func (h *AvailabilityHandlers) CreateOneOffAvailability(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	var req oneOffAvailabilityReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if !start.Before(end) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "start must be before end"})
+	o, err := h.AvailSv.CreateOneOffAvailability(c.Request.Context(), userID, req.Date, req.StartTime, req.EndTime, req.SlotLengthMins, req.Title)
+	if err != nil {
+		if err.Error() == "schedule overrides not enabled" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusCreated, o)
+}
 
-	booking, err := h.BookSv.CreateBooking(c.Request.Context(), userID, serviceCreateReq(req, start, end))
-	if err != nil {
-		if err.Error() == "slot already booked" {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+// GET /users/:id/overrides?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *AvailabilityHandlers) ListOverrides(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" {
+		from = time.Now().UTC().Format("2006-01-02")
+	}
+	if to == "" {
+		to = "9999-12-31"
+	}
+	for _, d := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be YYYY-MM-DD"})
 			return
 		}
-		if err.Error() == "slot not available" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+	overrides, err := h.AvailSv.ListOverrides(c.Request.Context(), userID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if overrides == nil {
+		overrides = []models.ScheduleOverride{}
+	}
+	c.JSON(http.StatusOK, overrides)
+}
+
+// DELETE /users/:id/overrides/:override_id
+func (h *AvailabilityHandlers) DeleteOverride(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	if err := h.AvailSv.DeleteOverride(c.Request.Context(), userID, c.Param("override_id")); err != nil {
+		if err.Error() == "override not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
 
-	response := gin.H{
-		"id":              booking.ID,
-		"user_id":         booking.UserID,
-		"candidate_email": booking.CandidateEmail,
-		"status":          booking.Status,
-		"start_at_utc":    booking.StartAtUTC,
-		"end_at_utc":      booking.EndAtUTC,
-		"created_at":      booking.CreatedAt,
+// POST /users/:id/availability/clone-to/:to_user_id[?replace=true&exceptions=true]
+// Copies the user's weekly rules to another user, replacing the target's
+// rules when replace is true and adding to them otherwise. exceptions also
+// copies upcoming availability exceptions.
+func (h *AvailabilityHandlers) CloneAvailability(c *gin.Context) {
+	user := app.ResolvedUserFrom(c)
+	toUserID := c.Param("to_user_id")
+	if _, err := uuid.Parse(toUserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid target user id"})
+		return
 	}
-	if req.Source != "" {
-		response["source"] = req.Source
+	if !h.ownsUsers(c, toUserID) {
+		return
 	}
-	if req.Type != "" {
-		response["type"] = req.Type
+	res, err := h.AvailSv.CloneAvailability(c.Request.Context(), user.ID, toUserID, c.Query("replace") == "true", c.Query("exceptions") == "true")
+	if err != nil {
+		switch err.Error() {
+		case "cannot clone availability to the same user":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "availability rule limit exceeded":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "limit": h.AvailSv.MaxRulesPerUser})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
 	}
-	if req.Description != "" {
-		response["description"] = req.Description
+	c.JSON(http.StatusOK, res)
+}
+
+// POST /users/:id/availability/retimezone
+// Request body: { "timezone": "Europe/Berlin", "mode": "relabel" } keeps the
+// rules' wall-clock times in the new zone; "mode": "shift" keeps their UTC
+// times instead, moving the hours (and days) they are written in.
+func (h *AvailabilityHandlers) RetimezoneAvailability(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	var payload struct {
+		Timezone string `json:"timezone"`
+		Mode     string `json:"mode"`
 	}
-	if req.Title != "" {
-		response["title"] = req.Title
+	if err := c.BindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rules, err := h.AvailSv.RetimezoneAvailability(c.Request.Context(), userID, payload.Timezone, payload.Mode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
+	c.JSON(http.StatusOK, gin.H{"timezone": payload.Timezone, "mode": payload.Mode, "rules": rules})
+}
 
-	c.JSON(http.StatusCreated, response)
+// POST /users/:id/availability/exceptions
+// Request body: { "date": "2025-12-25", "blocked": true } for a day off, or
+// { "date": "...", "start_time": "13:00", "end_time": "17:00", "blocked": true }
+// for part of a day; with "blocked": false the window (and
+// slot_length_minutes) adds availability on that date instead.
+func (h *AvailabilityHandlers) CreateException(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	var payload models.AvailabilityException
+	if err := c.BindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	e, err := h.AvailSv.CreateException(c.Request.Context(), userID, &payload)
+	if err != nil {
+		if err.Error() == "availability exceptions not enabled" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, e)
 }
 
-// DELETE /bookings/:id
-func (h *AvailabilityHandlers) CancelBooking(c *gin.Context) {
-	id := c.Param("id")
-	if err := h.BookSv.CancelBooking(c.Request.Context(), id); err != nil {
-		if err == pgx.ErrNoRows || err.Error() == "booking not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+// GET /users/:id/availability/exceptions?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *AvailabilityHandlers) ListExceptions(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" {
+		from = time.Now().UTC().Format("2006-01-02")
+	}
+	if to == "" {
+		to = "9999-12-31"
+	}
+	for _, d := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be YYYY-MM-DD"})
 			return
 		}
-		if err.Error() == "already cancelled" {
-			c.JSON(http.StatusConflict, gin.H{"error": "booking not found"})
+	}
+	exceptions, err := h.AvailSv.ListExceptions(c.Request.Context(), userID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, exceptions)
+}
+
+// DELETE /users/:id/availability/exceptions/:exception_id
+func (h *AvailabilityHandlers) DeleteException(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	if err := h.AvailSv.DeleteException(c.Request.Context(), userID, c.Param("exception_id")); err != nil {
+		if err.Error() == "exception not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -278,32 +505,1347 @@ func (h *AvailabilityHandlers) CancelBooking(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"ok": true})
 }
 
-func validateAvailabilityRule(rule *models.AvailabilityRule) error {
-	return serviceValidateAvailabilityRule(rule)
+// GET /users/:id/availability/effective?from=ISO&to=ISO[&tz=Area/City]
+func (h *AvailabilityHandlers) GetEffectiveAvailability(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	from, to, ok := parseTimeRange(c)
+	if !ok {
+		return
+	}
+	loc, ok := parseTimezone(c)
+	if !ok {
+		return
+	}
+	days, err := h.AvailSv.EffectiveAvailability(c.Request.Context(), userID, from.UTC(), to.UTC(), loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, days)
 }
-func serviceValidateAvailabilityRule(rule *models.AvailabilityRule) error {
-	startTime, err := time.Parse("15:04", rule.StartTime)
+
+// GET /users/:id/availability/resolve?date=YYYY-MM-DD[&tz=Area/City]
+// Explains one date: the rules and overrides (including holidays) that apply
+// to it and the net windows and blocks, for debugging a slot's availability.
+func (h *AvailabilityHandlers) ResolveAvailability(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	loc, ok := parseTimezone(c)
+	if !ok {
+		return
+	}
+	date := c.Query("date")
+	if date == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date required"})
+		return
+	}
+	res, err := h.AvailSv.ResolveDate(c.Request.Context(), userID, date, loc)
 	if err != nil {
-		return err
+		if err.Error() == "date must be YYYY-MM-DD" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Slot result limits for GetSlots. Without ?limit= the default applies and the
+// response stays a bare array; with it the response is an envelope carrying
+// truncated and next_from.
+const (
+	defaultSlotLimit = 2000
+	maxSlotLimit     = 10000
+)
+
+// GET /users/:id/slots?from=ISO&to=ISO[&tag=screen][&limit=N][&group_by=day&tz=Area/City][&exclude_booking=ID][&min_duration=90]
+// exclude_booking generates slots as if that booking did not exist, so its
+// slot can be offered as a reschedule target. min_duration (minutes) returns
+// contiguous free windows of at least that length, merged from adjacent
+// slots, instead of the slots themselves.
+func (h *AvailabilityHandlers) GetSlots(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	from, to, ok := parseTimeRange(c)
+	if !ok {
+		return
+	}
+	groupBy := c.Query("group_by")
+	if groupBy != "" && groupBy != "day" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_by must be day"})
+		return
+	}
+	loc, ok := parseTimezone(c)
+	if !ok {
+		return
+	}
+	limit := defaultSlotLimit
+	limitStr, explicitLimit := c.GetQuery("limit")
+	if explicitLimit {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		if n > maxSlotLimit {
+			n = maxSlotLimit
+		}
+		limit = n
+	}
+	var minDuration time.Duration
+	if v := c.Query("min_duration"); v != "" {
+		mins, err := strconv.Atoi(v)
+		if err != nil || mins <= 0 || mins > 24*60 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "min_duration must be minutes between 1 and 1440"})
+			return
+		}
+		minDuration = time.Duration(mins) * time.Minute
+	}
+	var slots []service.Slot
+	var err error
+	if excludeID := c.Query("exclude_booking"); excludeID != "" {
+		if _, perr := uuid.Parse(excludeID); perr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "exclude_booking must be a UUID"})
+			return
+		}
+		slots, err = h.AvailSv.GenerateAvailableSlotsExcluding(c.Request.Context(), userID, from.UTC(), to.UTC(), excludeID)
+	} else {
+		slots, err = h.AvailSv.GenerateAvailableSlots(c.Request.Context(), userID, from.UTC(), to.UTC())
 	}
-	endTime, err := time.Parse("15:04", rule.EndTime)
 	if err != nil {
-		return err
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
-	if !endTime.After(startTime) {
-		return pgx.ErrNoRows
+	if tag := c.Query("tag"); tag != "" {
+		slots = service.FilterSlotsByTag(slots, tag)
 	}
-	return nil
+	if minDuration > 0 {
+		slots = service.MergeFreeWindows(slots, minDuration)
+	}
+	slots, nextFrom := service.TruncateSlots(slots, limit)
+	if nextFrom != nil {
+		// Also signalled in headers so callers of the bare-array form can notice
+		c.Header("X-Slots-Truncated", "true")
+		c.Header("X-Slots-Next-From", nextFrom.Format(time.RFC3339))
+	}
+
+	var body interface{} = slots
+	if groupBy == "day" {
+		body = service.GroupSlotsByDay(slots, loc)
+	}
+	if !explicitLimit {
+		writeCachedJSON(c, body, h.CacheMaxAge)
+		return
+	}
+	resp := gin.H{"slots": body, "truncated": nextFrom != nil}
+	if nextFrom != nil {
+		resp["next_from"] = nextFrom
+	}
+	writeCachedJSON(c, resp, h.CacheMaxAge)
 }
 
-func serviceCreateReq(req createBookingReq, start, end time.Time) service.CreateBookingParams {
-	return service.CreateBookingParams{
-		CandidateEmail: req.CandidateEmail,
-		Start:          start,
-		End:            end,
-		Source:         req.Source,
-		Type:           req.Type,
-		Description:    req.Description,
-		Title:          req.Title,
+// GET /users/:id/slots/report?from=ISO&to=ISO[&format=csv|json]
+// Downloads the open slots in the range as a CSV (start_utc, end_utc,
+// duration_mins, title) or JSON array, written row by row.
+func (h *AvailabilityHandlers) SlotsReport(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	from, to, ok := parseTimeRange(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return
+	}
+	// Slots are written a day at a time as they are generated; the response
+	// starts with the first day, so setup errors can still be reported.
+	started := false
+	var csvW *csv.Writer
+	var enc *json.Encoder
+	written := 0
+	begin := func() {
+		started = true
+		filename := fmt.Sprintf("slots-%s-%s-%s.%s", userID, from.UTC().Format("20060102"), to.UTC().Format("20060102"), format)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		if format == "csv" {
+			c.Header("Content-Type", "text/csv; charset=utf-8")
+		} else {
+			c.Header("Content-Type", "application/json; charset=utf-8")
+		}
+		c.Status(http.StatusOK)
+		if format == "csv" {
+			csvW = csv.NewWriter(c.Writer)
+			_ = csvW.Write([]string{"start_utc", "end_utc", "duration_mins", "title"})
+			return
+		}
+		enc = json.NewEncoder(c.Writer)
+		_, _ = c.Writer.WriteString("[")
+	}
+	err := h.AvailSv.StreamAvailableSlots(c.Request.Context(), userID, from.UTC(), to.UTC(), func(day []service.Slot) error {
+		if !started {
+			begin()
+		}
+		for _, sl := range day {
+			if csvW != nil {
+				_ = csvW.Write([]string{
+					sl.StartUTC.UTC().Format(time.RFC3339),
+					sl.EndUTC.UTC().Format(time.RFC3339),
+					strconv.Itoa(int(sl.EndUTC.Sub(sl.StartUTC).Minutes())),
+					sl.Title,
+				})
+			} else {
+				if written > 0 {
+					_, _ = c.Writer.WriteString(",")
+				}
+				if err := enc.Encode(sl); err != nil {
+					return err
+				}
+			}
+			written++
+		}
+		if csvW != nil {
+			csvW.Flush()
+			if err := csvW.Error(); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil && !started {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		// Headers are gone; the truncated body is all the client can get
+		log.Printf("slots report for user %s: %v", userID, err)
+		return
+	}
+	if !started {
+		begin()
+	}
+	if csvW != nil {
+		csvW.Flush()
+		return
+	}
+	_, _ = c.Writer.WriteString("]\n")
+}
+
+// maxSlotChecks caps how many slots one check-batch request may carry.
+const maxSlotChecks = 500
+
+// POST /users/:id/slots/check-batch
+// Request body: [{ "start": ISO, "end": ISO }, ...]
+// Response: [{ "available": bool, "reason": "booked" }, ...] in request order
+func (h *AvailabilityHandlers) CheckSlotsBatch(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	var checks []service.SlotCheck
+	if err := c.ShouldBindJSON(&checks); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(checks) > maxSlotChecks {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d slots allowed", maxSlotChecks)})
+		return
+	}
+	results, err := h.AvailSv.CheckSlots(c.Request.Context(), userID, checks)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// maxBatchUsers caps how many users a single batch slots request may name.
+const maxBatchUsers = 50
+
+// GET /slots/batch?user_ids=a,b,c&from=ISO&to=ISO
+func (h *AvailabilityHandlers) GetBatchSlots(c *gin.Context) {
+	userIDs := splitUserIDs(c.Query("user_ids"))
+	if len(userIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_ids required"})
+		return
+	}
+	if len(userIDs) > maxBatchUsers {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d user_ids allowed", maxBatchUsers)})
+		return
+	}
+	if !h.ownsUsers(c, userIDs...) {
+		return
+	}
+	from, to, ok := parseTimeRange(c)
+	if !ok {
+		return
+	}
+	results := h.AvailSv.GenerateAvailableSlotsBatch(c.Request.Context(), userIDs, from.UTC(), to.UTC())
+	c.JSON(http.StatusOK, results)
+}
+
+// Search window for GetPanelSlot when to is omitted, and the longest allowed.
+const (
+	defaultPanelHorizon = 14 * 24 * time.Hour
+	maxPanelHorizon     = 90 * 24 * time.Hour
+)
+
+// GET /slots/panel?user_ids=a,b,c&duration=60[&from=ISO&to=ISO]
+// Returns the earliest start at which every listed user is free for duration
+// minutes, searching from (default now) up to to (default 14 days later).
+func (h *AvailabilityHandlers) GetPanelSlot(c *gin.Context) {
+	userIDs := splitUserIDs(c.Query("user_ids"))
+	if len(userIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_ids required"})
+		return
+	}
+	if len(userIDs) > maxBatchUsers {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d user_ids allowed", maxBatchUsers)})
+		return
+	}
+	if !h.ownsUsers(c, userIDs...) {
+		return
+	}
+	mins, err := strconv.Atoi(c.Query("duration"))
+	if err != nil || mins <= 0 || mins > 24*60 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "duration must be minutes between 1 and 1440"})
+		return
+	}
+	from := time.Now().UTC()
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+			return
+		}
+	}
+	to := from.Add(defaultPanelHorizon)
+	if v := c.Query("to"); v != "" {
+		if to, err = time.Parse(time.RFC3339, v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+			return
+		}
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+	if to.Sub(from) > maxPanelHorizon {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "search window must not exceed 90 days"})
+		return
+	}
+
+	slot, err := h.AvailSv.EarliestCommonSlot(c.Request.Context(), userIDs, time.Duration(mins)*time.Minute, from.UTC(), to.UTC())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if slot == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no common slot in the search window"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"user_ids": userIDs, "start_utc": slot.StartUTC, "end_utc": slot.EndUTC})
+}
+
+// Limits for GetSlotMatrix: the grid step defaults to 30 minutes, and the
+// user count, window and cell count are capped to keep the grid small.
+const (
+	defaultMatrixStepMins = 30
+	maxMatrixUsers        = 20
+	maxMatrixWindow       = 14 * 24 * time.Hour
+	maxMatrixCells        = 2000
+)
+
+// GET /slots/matrix?user_ids=a,b,c&from=ISO&to=ISO[&tz=Zone&step=30]
+// Returns a grid of step-minute cells aligned in tz, marking which of the
+// listed users are free for each whole cell.
+func (h *AvailabilityHandlers) GetSlotMatrix(c *gin.Context) {
+	userIDs := splitUserIDs(c.Query("user_ids"))
+	if len(userIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_ids required"})
+		return
+	}
+	if len(userIDs) > maxMatrixUsers {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d user_ids allowed", maxMatrixUsers)})
+		return
+	}
+	if !h.ownsUsers(c, userIDs...) {
+		return
+	}
+	from, to, ok := parseTimeRange(c)
+	if !ok {
+		return
+	}
+	loc, ok := parseTimezone(c)
+	if !ok {
+		return
+	}
+	stepMins := defaultMatrixStepMins
+	if v := c.Query("step"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 5 || n > 24*60 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "step must be minutes between 5 and 1440"})
+			return
+		}
+		stepMins = n
+	}
+	step := time.Duration(stepMins) * time.Minute
+	if to.Sub(from) > maxMatrixWindow {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "window must not exceed 14 days"})
+		return
+	}
+	if to.Sub(from)/step > maxMatrixCells {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("window holds more than %d cells; use a larger step", maxMatrixCells)})
+		return
+	}
+
+	matrix, err := h.AvailSv.AvailabilityMatrix(c.Request.Context(), userIDs, from.UTC(), to.UTC(), step, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	writeCachedJSON(c, matrix, h.CacheMaxAge)
+}
+
+// parseTimeRange reads the required from/to RFC3339 query parameters, writing
+// a 400 response and returning ok=false when they are missing or invalid.
+func parseTimeRange(c *gin.Context) (from, to time.Time, ok bool) {
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+	if fromStr == "" || toStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to required (ISO8601)"})
+		return from, to, false
+	}
+	from, err := time.Parse(time.RFC3339, fromStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+		return from, to, false
+	}
+	to, err = time.Parse(time.RFC3339, toStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+		return from, to, false
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// parseTimezone reads the optional tz query parameter as an IANA zone name,
+// defaulting to UTC. It writes a 400 response and returns ok=false when the
+// zone is unknown.
+func parseTimezone(c *gin.Context) (*time.Location, bool) {
+	tz := c.Query("tz")
+	if tz == "" {
+		return time.UTC, true
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tz"})
+		return nil, false
+	}
+	return loc, true
+}
+
+// splitUserIDs parses a comma-separated user id list, dropping blanks and duplicates.
+func splitUserIDs(raw string) []string {
+	var ids []string
+	seen := map[string]bool{}
+	for _, id := range strings.Split(raw, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// ownsUsers reports whether the caller may act on every one of userIDs. With
+// EnforceOwnership a user belongs only to the API key of the same id, as in
+// ResolveUserMiddleware; otherwise it writes a 403 and returns false.
+func (h *AvailabilityHandlers) ownsUsers(c *gin.Context, userIDs ...string) bool {
+	if !h.EnforceOwnership {
+		return true
+	}
+	caller := app.ResolvedUserFrom(c).CallerKeyID
+	for _, id := range userIDs {
+		if id != caller {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to access this user", "user_id": id})
+			return false
+		}
+	}
+	return true
+}
+
+// GET /users/:id/slots/count?from=ISO&to=ISO
+func (h *AvailabilityHandlers) CountSlots(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	from, to, ok := parseTimeRange(c)
+	if !ok {
+		return
+	}
+	slots, err := h.AvailSv.GenerateAvailableSlots(c.Request.Context(), userID, from.UTC(), to.UTC())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"count": len(slots)})
+}
+
+type createBookingReq struct {
+	UserID         string `json:"user_id"`
+	CandidateEmail string `json:"candidate_email" binding:"required,email"`
+	StartAtUTCStr  string `json:"start_at_utc" binding:"required"`
+	EndAtUTCStr    string `json:"end_at_utc" binding:"required"`
+	Source         string `json:"source,omitempty"`
+	Type           string `json:"type,omitempty"`
+	Description    string `json:"description,omitempty"`
+	Title          string `json:"title,omitempty"`
+	// Attendees lists further participants; candidate_email stays the primary
+	Attendees []bookingAttendeeReq `json:"attendees,omitempty" binding:"omitempty,dive"`
+	// AmountCents and Currency (ISO 4217) optionally price a paid booking
+	AmountCents *int64 `json:"amount_cents,omitempty"`
+	Currency    string `json:"currency,omitempty"`
+	// RecurrenceGroupID groups the occurrences of a recurring booking
+	RecurrenceGroupID string `json:"recurrence_group_id,omitempty" binding:"omitempty,uuid"`
+}
+
+type bookingAttendeeReq struct {
+	Email    string `json:"email" binding:"required,email"`
+	Role     string `json:"role,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// Page sizes for ListBookings.
+const (
+	defaultBookingsLimit = 50
+	maxBookingsLimit     = 500
+)
+
+// GET /users/:id/bookings[?from=ISO&to=ISO|?upcoming=true][&limit=N&offset=N]
+// Returns { "items": [...], "total": N, "next_offset": N }; next_offset is
+// omitted on the last page. limit defaults to 50 and is capped at 500.
+// upcoming=true lists the bookings starting from now on, like from=now with
+// no end.
+func (h *AvailabilityHandlers) ListBookings(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+
+	ctx := c.Request.Context()
+
+	var (
+		from     time.Time
+		to       time.Time
+		filtered bool
+		err      error
+	)
+
+	if c.Query("upcoming") == "true" {
+		if fromStr != "" || toStr != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "upcoming cannot be combined with from or to"})
+			return
+		}
+		from, to, filtered = time.Now().UTC(), time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), true
+	} else if fromStr != "" && toStr != "" {
+		filtered = true
+		from, err = time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+			return
+		}
+		to, err = time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+			return
+		}
+		if !from.Before(to) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+			return
+		}
+	}
+
+	limit := defaultBookingsLimit
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		if n > maxBookingsLimit {
+			n = maxBookingsLimit
+		}
+		limit = n
+	}
+	offset := 0
+	if v := c.Query("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+			return
+		}
+		offset = n
+	}
+
+	bookings, total, err := h.BookSv.ListBookings(ctx, userID, from, to, filtered, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if bookings == nil {
+		bookings = []models.Booking{}
+	}
+	resp := gin.H{"items": bookings, "total": total}
+	if next := offset + len(bookings); len(bookings) > 0 && next < total {
+		resp["next_offset"] = next
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Page sizes for ListUpcomingBookings.
+const (
+	defaultUpcomingLimit = 50
+	maxUpcomingLimit     = 500
+)
+
+// GET /users/:id/bookings/upcoming[?limit=N&cursor=...]
+// Returns { "bookings": [...], "next_cursor": "..." }; next_cursor is omitted
+// on the last page. GET /users/:id/bookings?upcoming=true lists the same
+// bookings in that endpoint's offset-paginated shape.
+func (h *AvailabilityHandlers) ListUpcomingBookings(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	limit := defaultUpcomingLimit
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		if n > maxUpcomingLimit {
+			n = maxUpcomingLimit
+		}
+		limit = n
+	}
+	var cursor *service.BookingCursor
+	if v := c.Query("cursor"); v != "" {
+		cur, err := decodeBookingCursor(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+		cursor = cur
+	}
+
+	bookings, next, err := h.BookSv.ListUpcomingBookings(c.Request.Context(), userID, time.Now(), cursor, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if bookings == nil {
+		bookings = []models.Booking{}
+	}
+	resp := gin.H{"bookings": bookings}
+	if next != nil {
+		resp["next_cursor"] = encodeBookingCursor(next)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// encodeBookingCursor packs a cursor as URL-safe base64 of "start|id".
+func encodeBookingCursor(cur *service.BookingCursor) string {
+	raw := cur.StartAtUTC.UTC().Format(time.RFC3339Nano) + "|" + cur.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeBookingCursor(s string) (*service.BookingCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	startStr, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, errors.New("malformed cursor")
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, err
+	}
+	start, err := time.Parse(time.RFC3339Nano, startStr)
+	if err != nil {
+		return nil, err
+	}
+	return &service.BookingCursor{StartAtUTC: start, ID: id}, nil
+}
+
+// GET /users/:id/digest?period=day|week[&tz=Area/City][&start=YYYY-MM-DD]
+// Summarises confirmed bookings per local day, starting today (or on start)
+// in tz.
+func (h *AvailabilityHandlers) GetDigest(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	days := 7
+	switch c.DefaultQuery("period", "week") {
+	case "day":
+		days = 1
+	case "week":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "period must be day or week"})
+		return
+	}
+	loc, ok := parseTimezone(c)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	if v := c.Query("start"); v != "" {
+		d, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start must be YYYY-MM-DD"})
+			return
+		}
+		start = d
+	}
+	digest, err := h.BookSv.Digest(c.Request.Context(), userID, start, days, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, digest)
+}
+
+// defaultConflictWindow is how far ahead GetScheduleConflicts checks bookings
+// when no range is given.
+const defaultConflictWindow = 30 * 24 * time.Hour
+
+// GET /users/:id/schedule/conflicts[?from=ISO&to=ISO]
+// Diagnoses the user's schedule: overlapping rules, rules without slots, and
+// confirmed bookings (default: the next 30 days) outside availability or
+// overlapping each other.
+func (h *AvailabilityHandlers) GetScheduleConflicts(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	from := time.Now().UTC()
+	to := from.Add(defaultConflictWindow)
+	if c.Query("from") != "" || c.Query("to") != "" {
+		var ok bool
+		if from, to, ok = parseTimeRange(c); !ok {
+			return
+		}
+	}
+	report, err := h.AvailSv.ScheduleConflicts(c.Request.Context(), userID, from.UTC(), to.UTC())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GET /users/:id/stats?from=ISO&to=ISO
+// Returns booking counts, average duration, busiest weekday and slot
+// utilization for bookings starting in the range.
+func (h *AvailabilityHandlers) GetStats(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	from, to, ok := parseTimeRange(c)
+	if !ok {
+		return
+	}
+	stats, err := h.BookSv.Stats(c.Request.Context(), userID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// defaultForecastWindow is the forecast period when from and to are omitted.
+const defaultForecastWindow = 7 * 24 * time.Hour
+
+// GET /users/:id/forecast[?from=ISO&to=ISO]
+// Reports total, booked and remaining slots and the utilization percentage
+// for the period, by default the next 7 days.
+func (h *AvailabilityHandlers) GetForecast(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	from := time.Now().UTC()
+	to := from.Add(defaultForecastWindow)
+	if c.Query("from") != "" || c.Query("to") != "" {
+		var ok bool
+		if from, to, ok = parseTimeRange(c); !ok {
+			return
+		}
+	}
+	forecast, err := h.BookSv.Forecast(c.Request.Context(), userID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, forecast)
+}
+
+// Week counts for GetWeeks: the default, and the cap on one request.
+const (
+	defaultWeekCount = 4
+	maxWeekCount     = 53
+)
+
+// GET /users/:id/weeks[?from=YYYY-MM-DD&count=N&tz=Area/City]
+// Summarises count ISO weeks (default 4) starting with the week containing
+// from (default today) in tz: each week's year-week label, Monday and Sunday
+// dates, open slots and confirmed bookings.
+func (h *AvailabilityHandlers) GetWeeks(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	loc, ok := parseTimezone(c)
+	if !ok {
+		return
+	}
+	from := time.Now().In(loc)
+	if raw := c.Query("from"); raw != "" {
+		d, err := time.ParseInLocation("2006-01-02", raw, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be YYYY-MM-DD"})
+			return
+		}
+		from = d
+	}
+	count := defaultWeekCount
+	if raw := c.Query("count"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxWeekCount {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("count must be between 1 and %d", maxWeekCount)})
+			return
+		}
+		count = n
+	}
+	weeks, err := h.AvailSv.WeekSummaries(c.Request.Context(), userID, from, count, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tz": loc.String(), "weeks": weeks})
+}
+
+// maxStreamBatchSize caps the batch_size a caller may request from StreamBookings.
+const maxStreamBatchSize = 5000
+
+// GET /users/:id/bookings/stream[?batch_size=N]
+// Writes the user's full booking history as newline-delimited JSON, one
+// booking per line, flushing after every batch read from the database.
+func (h *AvailabilityHandlers) StreamBookings(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	batchSize := h.StreamBatchSize
+	if raw := c.Query("batch_size"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid batch_size"})
+			return
+		}
+		batchSize = n
+	}
+	if batchSize < 1 {
+		batchSize = 500
+	}
+	if batchSize > maxStreamBatchSize {
+		batchSize = maxStreamBatchSize
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+	enc := json.NewEncoder(c.Writer)
+	err := h.BookSv.StreamBookings(c.Request.Context(), userID, batchSize, func(batch []models.Booking) error {
+		for _, b := range batch {
+			if err := enc.Encode(b); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		// Headers are already sent, so the failure can only be logged.
+		log.Printf("booking stream for user %s aborted: %v", userID, err)
+	}
+}
+
+// parseUTCField parses an RFC3339 body field named for UTC. Under the strict_utc
+// flag the value must carry a Z suffix; otherwise any offset is accepted and
+// converted. It writes a 400 response and returns ok=false on failure.
+func (h *AvailabilityHandlers) parseUTCField(c *gin.Context, name, value string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return t, false
+	}
+	if service.FlagsFrom(c.Request.Context()).StrictUTC && !strings.HasSuffix(value, "Z") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be in UTC with a Z suffix, e.g. 2024-01-02T15:04:05Z"})
+		return t, false
+	}
+	return t, true
+}
+
+type holdSlotReq struct {
+	StartAtUTCStr string `json:"start_at_utc" binding:"required"`
+	EndAtUTCStr   string `json:"end_at_utc" binding:"required"`
+}
+
+// POST /users/:id/slots/hold
+func (h *AvailabilityHandlers) HoldSlot(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	var req holdSlotReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	start, ok := h.parseUTCField(c, "start_at_utc", req.StartAtUTCStr)
+	if !ok {
+		return
+	}
+	end, ok := h.parseUTCField(c, "end_at_utc", req.EndAtUTCStr)
+	if !ok {
+		return
+	}
+	if !start.Before(end) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start must be before end"})
+		return
+	}
+
+	hold, err := h.BookSv.HoldSlot(c.Request.Context(), userID, start, end)
+	if err != nil {
+		switch err.Error() {
+		case "slot is held", "slot already booked":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case "slot not available":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, hold)
+}
+
+// POST /users/:id/bookings[?hold=token]
+func (h *AvailabilityHandlers) CreateBooking(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	var req createBookingReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	start, ok := h.parseUTCField(c, "start_at_utc", req.StartAtUTCStr)
+	if !ok {
+		return
+	}
+	end, ok := h.parseUTCField(c, "end_at_utc", req.EndAtUTCStr)
+	if !ok {
+		return
+	}
+	if !start.Before(end) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start must be before end"})
+		return
+	}
+
+	params := serviceCreateReq(req, start, end)
+	params.HoldToken = c.Query("hold")
+	booking, err := h.BookSv.CreateBooking(c.Request.Context(), userID, params)
+	if err != nil {
+		writeCreateBookingError(c, err)
+		return
+	}
+
+	response := gin.H{
+		"id":              booking.ID,
+		"user_id":         booking.UserID,
+		"candidate_email": booking.CandidateEmail,
+		"status":          booking.Status,
+		"start_at_utc":    booking.StartAtUTC,
+		"end_at_utc":      booking.EndAtUTC,
+		"created_at":      booking.CreatedAt,
+	}
+	if booking.ConfirmationCode != "" {
+		response["confirmation_code"] = booking.ConfirmationCode
+	}
+	if req.Source != "" {
+		response["source"] = req.Source
+	}
+	if req.Type != "" {
+		response["type"] = req.Type
+	}
+	if req.Description != "" {
+		response["description"] = req.Description
+	}
+	if req.Title != "" {
+		response["title"] = req.Title
+	}
+	if len(booking.Attendees) > 0 {
+		response["attendees"] = booking.Attendees
+	}
+	if booking.AmountCents != nil {
+		response["amount_cents"] = *booking.AmountCents
+		response["currency"] = booking.Currency
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// writeCreateBookingError answers a failed CreateBooking with the status for
+// its error.
+func writeCreateBookingError(c *gin.Context, err error) {
+	if err.Error() == "slot already booked" || err.Error() == "candidate already booked" || err.Error() == "candidate has another booking too close" || err.Error() == "slot is held" {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err.Error() == "invalid or expired hold" {
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	}
+	if err.Error() == "candidate email domain not allowed" {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err.Error() == "start too far in the future" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_at_utc is too far in the future"})
+		return
+	}
+	if err.Error() == "slot not available" || err.Error() == "outside business hours" || err.Error() == "user is unavailable" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err.Error() == "amount_cents and currency must be given together" || err.Error() == "amount_cents must not be negative" || err.Error() == "currency must be an ISO 4217 code" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrInvalidBookingWindow) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		// Lost a race with a concurrent booking of the same start
+		c.JSON(http.StatusConflict, gin.H{"error": "slot already booked"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// POST /users/:id/bookings/transfer
+// Request body: { "to_user_id": "...", "from": ISO }; from defaults to now.
+func (h *AvailabilityHandlers) TransferBookings(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	var req struct {
+		ToUserID string     `json:"to_user_id" binding:"required"`
+		From     *time.Time `json:"from"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := uuid.Parse(req.ToUserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid target user id"})
+		return
+	}
+	if !h.ownsUsers(c, req.ToUserID) {
+		return
+	}
+	since := time.Now().UTC()
+	if req.From != nil {
+		since = *req.From
+	}
+	result, err := h.BookSv.TransferBookings(c.Request.Context(), userID, req.ToUserID, since)
+	if err != nil {
+		if err.Error() == "cannot transfer bookings to the same user" || err.Error() == "to_user_id required" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+type rescheduleBookingReq struct {
+	StartAtUTCStr string `json:"start_at_utc" binding:"required"`
+	EndAtUTCStr   string `json:"end_at_utc" binding:"required"`
+}
+
+// PUT /users/:id/bookings/:booking_id
+// Request body: { "start_at_utc": ISO, "end_at_utc": ISO }
+// Moves a confirmed or pending booking to another open slot, keeping its id.
+func (h *AvailabilityHandlers) RescheduleBooking(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	bookingID := c.Param("booking_id")
+	if _, err := uuid.Parse(bookingID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+		return
+	}
+	var req rescheduleBookingReq
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	start, ok := h.parseUTCField(c, "start_at_utc", req.StartAtUTCStr)
+	if !ok {
+		return
+	}
+	end, ok := h.parseUTCField(c, "end_at_utc", req.EndAtUTCStr)
+	if !ok {
+		return
+	}
+	if !start.Before(end) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start must be before end"})
+		return
+	}
+
+	booking, err := h.BookSv.RescheduleBooking(c.Request.Context(), userID, bookingID, start, end)
+	if err != nil {
+		switch {
+		case err.Error() == "booking not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case err.Error() == "slot already booked", err.Error() == "slot is held", err.Error() == "slot not available",
+			err.Error() == "candidate already booked", err.Error() == "candidate has another booking too close":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case err.Error() == "start too far in the future":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "start_at_utc is too far in the future"})
+		case err.Error() == "outside business hours", err.Error() == "user is unavailable", errors.Is(err, repository.ErrInvalidBookingWindow):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, booking)
+}
+
+// POST /users/:id/bookings/shift
+// Request body: { "from": ISO, "offset_mins": 30 }; from defaults to now and a
+// negative offset moves bookings earlier.
+func (h *AvailabilityHandlers) ShiftBookings(c *gin.Context) {
+	userID := app.ResolvedUserFrom(c).ID
+	var req struct {
+		From       *time.Time `json:"from"`
+		OffsetMins int        `json:"offset_mins" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	since := time.Now().UTC()
+	if req.From != nil {
+		since = *req.From
+	}
+	result, err := h.BookSv.ShiftBookings(c.Request.Context(), userID, since, time.Duration(req.OffsetMins)*time.Minute)
+	if err != nil {
+		switch err.Error() {
+		case "offset_mins must not be zero", "offset_mins must be within a day":
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "bookings changed concurrently":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// DELETE /bookings/:id?scope=single|following|all
+// Optional request body: { "reason": "candidate no-show" }
+func (h *AvailabilityHandlers) CancelBooking(c *gin.Context) {
+	id := c.Param("id")
+	scope := c.Query("scope")
+	var payload struct {
+		Reason string `json:"reason"`
+	}
+	// The body is optional; an empty one reads as io.EOF
+	if err := c.ShouldBindJSON(&payload); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if h.EnforceOwnership {
+		// A series is cancelled through any of its occurrences, so the one
+		// named must belong to the caller
+		booking, err := h.BookSv.GetBooking(c.Request.Context(), id)
+		if err != nil {
+			if err.Error() == "booking not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if booking.UserID != app.ResolvedUserFrom(c).CallerKeyID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to access this booking"})
+			return
+		}
+	}
+	cancelled, err := h.BookSv.CancelBookingScope(c.Request.Context(), id, scope, payload.Reason)
+	if err != nil {
+		if err.Error() == "invalid scope" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "scope must be single, following or all"})
+			return
+		}
+		if strings.HasPrefix(err.Error(), "reason must be") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err == pgx.ErrNoRows || err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+			return
+		}
+		if err.Error() == "already cancelled" {
+			// Cancelling is idempotent so clients can safely retry
+			c.JSON(http.StatusOK, gin.H{"ok": true, "already_cancelled": true, "cancelled": 0})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "cancelled": cancelled})
+}
+
+// POST /bookings/:id/approve
+// Confirms a booking awaiting approval.
+func (h *AvailabilityHandlers) ApproveBooking(c *gin.Context) {
+	id := c.Param("id")
+	booking, err := h.BookSv.GetBooking(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if h.EnforceOwnership && booking.UserID != app.ResolvedUserFrom(c).CallerKeyID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to access this booking"})
+		return
+	}
+	if err := h.BookSv.ApproveBooking(c.Request.Context(), id); err != nil {
+		switch err.Error() {
+		case "booking not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "booking not pending":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "status": booking.Status})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "id": id, "status": "confirmed"})
+}
+
+// DELETE /calendar/bookings/by-event/:event_id
+func (h *AvailabilityHandlers) CancelBookingByGoogleEvent(c *gin.Context) {
+	eventID := c.Param("event_id")
+	if h.EnforceOwnership {
+		linked, err := h.BookSv.GetBookingByGoogleEventID(c.Request.Context(), eventID)
+		if err != nil {
+			if err.Error() == "booking not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "no booking linked to event"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if linked.UserID != app.ResolvedUserFrom(c).CallerKeyID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to access this booking"})
+			return
+		}
+	}
+	booking, err := h.BookSv.CancelBookingByGoogleEventID(c.Request.Context(), eventID)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no booking linked to event"})
+			return
+		}
+		if err.Error() == "already cancelled" {
+			c.JSON(http.StatusOK, gin.H{"ok": true, "already_cancelled": true, "booking_id": booking.ID})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "booking_id": booking.ID})
+}
+
+// GET /bookings/:id/state
+func (h *AvailabilityHandlers) GetBookingState(c *gin.Context) {
+	id := c.Param("id")
+	booking, err := h.BookSv.GetBooking(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if h.EnforceOwnership && booking.UserID != app.ResolvedUserFrom(c).CallerKeyID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to access this booking"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"id":                 booking.ID,
+		"status":             booking.Status,
+		"confirmation_state": booking.ConfirmationState,
+	})
+}
+
+// GET /bookings/by-code/:code
+// Looks a booking up by the confirmation code candidates quote; case,
+// spaces and dashes in the code are ignored.
+func (h *AvailabilityHandlers) GetBookingByCode(c *gin.Context) {
+	booking, err := h.BookSv.GetBookingByConfirmationCode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if h.EnforceOwnership && booking.UserID != app.ResolvedUserFrom(c).CallerKeyID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to access this booking"})
+		return
+	}
+	c.JSON(http.StatusOK, booking)
+}
+
+func validateAvailabilityRule(rule *models.AvailabilityRule) error {
+	return serviceValidateAvailabilityRule(rule)
+}
+func serviceValidateAvailabilityRule(rule *models.AvailabilityRule) error {
+	startTime, err := time.Parse("15:04", rule.StartTime)
+	if err != nil {
+		return err
+	}
+	endTime, err := time.Parse("15:04", rule.EndTime)
+	if err != nil {
+		return err
+	}
+	if !endTime.After(startTime) {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func serviceCreateReq(req createBookingReq, start, end time.Time) service.CreateBookingParams {
+	return service.CreateBookingParams{
+		CandidateEmail:    req.CandidateEmail,
+		Start:             start,
+		End:               end,
+		Source:            req.Source,
+		Type:              req.Type,
+		Description:       req.Description,
+		Title:             req.Title,
+		Attendees:         attendeesFromReq(req.Attendees),
+		AmountCents:       req.AmountCents,
+		Currency:          req.Currency,
+		RecurrenceGroupID: req.RecurrenceGroupID,
+	}
+}
+
+func attendeesFromReq(in []bookingAttendeeReq) []models.BookingAttendee {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]models.BookingAttendee, 0, len(in))
+	for _, a := range in {
+		out = append(out, models.BookingAttendee{Email: strings.TrimSpace(a.Email), Role: strings.TrimSpace(a.Role), Optional: a.Optional})
 	}
+	return out
 }
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"scheduler-service/internal/app"
+	"scheduler-service/internal/service"
+)
+
+type BookingHistoryHandler struct {
+	Bookings *service.BookingService
+	Audit    *service.BookingAuditLog
+	// EnforceOwnership limits history to the booking's own user, as
+	// ENFORCE_USER_OWNERSHIP does for /users/:id routes.
+	EnforceOwnership bool
+}
+
+// GET /bookings/:id/history
+// Lists the booking's recorded changes (created, rescheduled, state_changed,
+// cancelled), oldest first, with the actor and time of each.
+func (h *BookingHistoryHandler) GetHistory(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid booking id"})
+		return
+	}
+	booking, err := h.Bookings.GetBooking(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "booking not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if h.EnforceOwnership && booking.UserID != app.ResolvedUserFrom(c).CallerKeyID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "not allowed to access this booking"})
+		return
+	}
+	entries, err := h.Audit.History(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"booking_id": id, "history": entries})
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+	"scheduler-service/internal/service"
+)
+
+// pageBookingRepo pages through bookings the way the SQL listing does and
+// records the last limit asked for.
+type pageBookingRepo struct {
+	repository.BookingRepository
+	bookings  []models.Booking // ordered by start
+	lastLimit int
+}
+
+func (r *pageBookingRepo) matching(from, to repository.AppTime, filtered bool) []models.Booking {
+	var out []models.Booking
+	for _, b := range r.bookings {
+		if filtered && (b.StartAtUTC.Before(from.(time.Time)) || !b.StartAtUTC.Before(to.(time.Time))) {
+			continue
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+func (r *pageBookingRepo) CountBookings(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime, filtered bool) (int, error) {
+	return len(r.matching(from, to, filtered)), nil
+}
+
+func (r *pageBookingRepo) ListBookings(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime, filtered bool, limit, offset int) ([]models.Booking, error) {
+	r.lastLimit = limit
+	out := r.matching(from, to, filtered)
+	if offset > len(out) {
+		offset = len(out)
+	}
+	out = out[offset:]
+	if limit > 0 && limit < len(out) {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func TestListBookingsPagesInOneShape(t *testing.T) {
+	now := time.Now().UTC()
+	repo := &pageBookingRepo{}
+	for _, d := range []int{-3, -2, -1, 1, 2, 3} {
+		repo.bookings = append(repo.bookings, models.Booking{ID: fmt.Sprint(d), UserID: ownUserID, StartAtUTC: now.Add(time.Duration(d) * time.Hour)})
+	}
+	h := &AvailabilityHandlers{BookSv: service.NewBookingService(nil, repo, nil)}
+
+	cases := []struct {
+		target      string
+		items       int
+		total       int
+		nextOffset  any
+		wantedLimit int
+	}{
+		{"/bookings?limit=2", 2, 6, float64(2), 2},
+		{"/bookings?limit=2&offset=4", 2, 6, nil, 2},
+		{"/bookings?upcoming=true&limit=2", 2, 3, float64(2), 2},
+		{"/bookings?upcoming=true&offset=2", 1, 3, nil, defaultBookingsLimit},
+		{"/bookings?limit=100000", 6, 6, nil, maxBookingsLimit},
+	}
+	for _, tc := range cases {
+		w := callAs(h.ListBookings, tc.target)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", tc.target, w.Code, w.Body)
+		}
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range []string{"bookings", "next_cursor"} {
+			if _, ok := body[key]; ok {
+				t.Errorf("%s: unexpected %q in response", tc.target, key)
+			}
+		}
+		if items, _ := body["items"].([]any); len(items) != tc.items {
+			t.Errorf("%s: %d items, want %d", tc.target, len(items), tc.items)
+		}
+		if body["total"] != float64(tc.total) {
+			t.Errorf("%s: total %v, want %d", tc.target, body["total"], tc.total)
+		}
+		if body["next_offset"] != tc.nextOffset {
+			t.Errorf("%s: next_offset %v, want %v", tc.target, body["next_offset"], tc.nextOffset)
+		}
+		if repo.lastLimit != tc.wantedLimit {
+			t.Errorf("%s: queried limit %d, want %d", tc.target, repo.lastLimit, tc.wantedLimit)
+		}
+	}
+
+	if w := callAs(h.ListBookings, "/bookings?upcoming=true&from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z"); w.Code != http.StatusBadRequest {
+		t.Errorf("upcoming with a range: status %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+	"scheduler-service/internal/service"
+)
+
+// keysetBookingRepo pages through bookings already sorted by (start, id) and
+// records the limit of each page read.
+type keysetBookingRepo struct {
+	repository.BookingRepository
+	bookings []models.Booking
+	limits   []int
+}
+
+func (r *keysetBookingRepo) ListBookingsAfter(ctx context.Context, q repository.Querier, userID string, afterStart repository.AppTime, afterID string, limit int) ([]models.Booking, error) {
+	r.limits = append(r.limits, limit)
+	var out []models.Booking
+	for _, b := range r.bookings {
+		if after, ok := afterStart.(time.Time); ok && (b.StartAtUTC.Before(after) || b.StartAtUTC.Equal(after) && b.ID <= afterID) {
+			continue
+		}
+		if len(out) == limit {
+			break
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func TestStreamBookingsNDJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	var bookings []models.Booking
+	var wantIDs []string
+	for i, id := range []string{"b1", "b2", "b3", "b4", "b5"} {
+		// b1/b2 and b3/b4 share a start, so the id breaks the tie
+		at := start.Add(time.Duration(i/2*2) * time.Hour)
+		bookings = append(bookings, models.Booking{ID: id, UserID: ownUserID, Status: "confirmed", StartAtUTC: at, EndAtUTC: at.Add(30 * time.Minute)})
+		wantIDs = append(wantIDs, id)
+	}
+	cases := []struct {
+		name       string
+		query      string
+		defaultSz  int
+		wantCode   int
+		wantLimits []int
+	}{
+		{"batches of two", "?batch_size=2", 500, http.StatusOK, []int{2, 2, 2}},
+		{"batch size dividing the total", "?batch_size=5", 500, http.StatusOK, []int{5, 5}},
+		{"configured default", "", 3, http.StatusOK, []int{3, 3}},
+		{"capped batch size", "?batch_size=999999", 500, http.StatusOK, []int{maxStreamBatchSize}},
+		{"invalid batch size", "?batch_size=0", 500, http.StatusBadRequest, nil},
+	}
+	for _, tc := range cases {
+		repo := &keysetBookingRepo{bookings: bookings}
+		h := &AvailabilityHandlers{BookSv: service.NewBookingService(nil, repo, nil), StreamBatchSize: tc.defaultSz}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/users/"+ownUserID+"/bookings/stream"+tc.query, nil)
+		c.Params = gin.Params{{Key: "id", Value: ownUserID}}
+		h.StreamBookings(c)
+
+		if w.Code != tc.wantCode {
+			t.Fatalf("%s: status %d, want %d", tc.name, w.Code, tc.wantCode)
+		}
+		if tc.wantCode != http.StatusOK {
+			continue
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("%s: Content-Type = %q", tc.name, ct)
+		}
+		body := w.Body.String()
+		if !strings.HasSuffix(body, "\n") {
+			t.Errorf("%s: body does not end in a newline", tc.name)
+		}
+		var gotIDs []string
+		for _, line := range strings.Split(strings.TrimSuffix(body, "\n"), "\n") {
+			var b models.Booking
+			if err := json.Unmarshal([]byte(line), &b); err != nil {
+				t.Fatalf("%s: line %q is not one JSON object: %v", tc.name, line, err)
+			}
+			gotIDs = append(gotIDs, b.ID)
+		}
+		if !reflect.DeepEqual(gotIDs, wantIDs) {
+			t.Errorf("%s: ids = %v, want %v", tc.name, gotIDs, wantIDs)
+		}
+		if !reflect.DeepEqual(repo.limits, tc.wantLimits) {
+			t.Errorf("%s: read pages of %v, want %v", tc.name, repo.limits, tc.wantLimits)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/service"
+)
+
+func TestGetSlotsETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	rules := stubRuleRepo{rules: []models.AvailabilityRule{
+		{ID: "r1", DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "12:00", SlotLengthMins: 30, Available: true},
+	}}
+	h := &AvailabilityHandlers{
+		AvailSv:     &service.AvailabilityService{Avail: rules, Book: rangeBookingRepo{}, Clock: service.FixedClock(monday)},
+		CacheMaxAge: 30 * time.Second,
+	}
+	// Served through an engine, which writes the bare 304 status
+	r := gin.New()
+	r.GET("/users/:id/slots", h.GetSlots)
+	const day = "?from=2026-03-02T00:00:00Z&to=2026-03-03T00:00:00Z"
+	get := func(query, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/users/"+ownUserID+"/slots"+query, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get(day, "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || len(etag) != 34 || etag[0] != '"' {
+		t.Fatalf("first request: %d with ETag %q", first.Code, etag)
+	}
+	if got := first.Header().Get("Cache-Control"); got != "private, max-age=30" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+	if again := get(day, "").Header().Get("ETag"); again != etag {
+		t.Errorf("same response got ETag %s, then %s", etag, again)
+	}
+	if other := get("?from=2026-03-09T00:00:00Z&to=2026-03-10T00:00:00Z&tag=none", "").Header().Get("ETag"); other == etag {
+		t.Error("different response bodies share an ETag")
+	}
+
+	cases := []struct {
+		name        string
+		ifNoneMatch string
+		want        int
+	}{
+		{"matching ETag", etag, http.StatusNotModified},
+		{"weak form", "W/" + etag, http.StatusNotModified},
+		{"one of several", `"stale", ` + etag, http.StatusNotModified},
+		{"wildcard", "*", http.StatusNotModified},
+		{"stale ETag", `"stale"`, http.StatusOK},
+	}
+	for _, tc := range cases {
+		w := get(day, tc.ifNoneMatch)
+		if w.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.want)
+		}
+		if tc.want == http.StatusNotModified && w.Body.Len() != 0 {
+			t.Errorf("%s: 304 carries a body: %s", tc.name, w.Body)
+		}
+		if w.Header().Get("ETag") != etag {
+			t.Errorf("%s: ETag = %q, want %q", tc.name, w.Header().Get("ETag"), etag)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/service"
+)
+
+func TestCancelBookingIsIdempotent(t *testing.T) {
+	id := "33333333-3333-3333-3333-333333333333"
+	repo := eventBookingRepo{byEvent: map[string]*models.Booking{
+		"evt-1": {ID: id, UserID: ownUserID, Status: "confirmed"},
+	}}
+	h := &AvailabilityHandlers{BookSv: service.NewBookingService(nil, repo, nil)}
+	// The cases run in order against the same booking
+	cases := []struct {
+		name string
+		id   string
+		want int
+		body string
+	}{
+		{"first cancel", id, http.StatusOK, `{"cancelled":1,"ok":true}`},
+		{"repeat cancel", id, http.StatusOK, `{"already_cancelled":true,"cancelled":0,"ok":true}`},
+		{"nonexistent booking", "55555555-5555-5555-5555-555555555555", http.StatusNotFound, `{"error":"booking not found"}`},
+	}
+	for _, tc := range cases {
+		w := callAs(func(c *gin.Context) {
+			c.Request = httptest.NewRequest(http.MethodDelete, "/bookings/"+tc.id, nil)
+			c.Params = gin.Params{{Key: "id", Value: tc.id}}
+			h.CancelBooking(c)
+		}, "/")
+		if w.Code != tc.want || w.Body.String() != tc.body {
+			t.Errorf("%s: %d %s, want %d %s", tc.name, w.Code, w.Body.String(), tc.want, tc.body)
+		}
+	}
+	if got := repo.byEvent["evt-1"].Status; got != "cancelled" {
+		t.Errorf("booking status = %s, want cancelled", got)
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/service"
+)
+
+func TestCancelBookingReasonRoundTrip(t *testing.T) {
+	id := "33333333-3333-3333-3333-333333333333"
+	repo := eventBookingRepo{byEvent: map[string]*models.Booking{
+		"evt-1": {ID: id, UserID: ownUserID, Status: "confirmed", ConfirmationCode: "K7M2Q9XA"},
+	}}
+	h := &AvailabilityHandlers{BookSv: service.NewBookingService(nil, repo, nil)}
+	cancel := func(body string) *httptest.ResponseRecorder {
+		return callAs(func(c *gin.Context) {
+			c.Request = httptest.NewRequest(http.MethodDelete, "/bookings/"+id, strings.NewReader(body))
+			c.Request = c.Request.WithContext(service.WithActor(c.Request.Context(), "ops@example.com"))
+			c.Params = gin.Params{{Key: "id", Value: id}}
+			h.CancelBooking(c)
+		}, "/")
+	}
+
+	if w := cancel(`{"reason":"` + strings.Repeat("r", 501) + `"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("over-long reason: status %d, want 400", w.Code)
+	}
+	if w := cancel(`{"reason":"candidate no-show"}`); w.Code != http.StatusOK {
+		t.Fatalf("cancel: %d %s", w.Code, w.Body.String())
+	}
+
+	w := callAs(func(c *gin.Context) {
+		c.Params = gin.Params{{Key: "code", Value: "K7M2Q9XA"}}
+		h.GetBookingByCode(c)
+	}, "/bookings/by-code/K7M2Q9XA")
+	if w.Code != http.StatusOK {
+		t.Fatalf("read: %d %s", w.Code, w.Body.String())
+	}
+	var got map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["status"] != "cancelled" || got["cancellation_reason"] != "candidate no-show" || got["cancelled_by"] != "ops@example.com" {
+		t.Errorf("booking = %s, want cancelled with cancellation_reason and cancelled_by", w.Body.String())
+	}
+}
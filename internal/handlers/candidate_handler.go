@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"scheduler-service/internal/service"
+)
+
+type CandidateHandler struct {
+	Service *service.CandidateTokenService
+}
+
+// GET /public/candidate/:token/bookings
+// Lists the token holder's bookings with every interviewer, read-only.
+func (h *CandidateHandler) ListBookings(c *gin.Context) {
+	email, bookings, err := h.Service.ListBookings(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		if err.Error() == "invalid token" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"candidate_email": email, "bookings": bookings})
+}
+
+// RateLimitKey keys booking rate limits by the id of the token in the path,
+// so one token holder cannot flood an interviewer's calendar. Tokens whose
+// signature does not verify get "" and are rejected by the handler anyway.
+func (h *CandidateHandler) RateLimitKey(c *gin.Context) string {
+	id, err := h.Service.TokenID(c.Param("token"))
+	if err != nil {
+		return ""
+	}
+	return "candidate-token:" + id
+}
+
+// POST /public/candidate/:token/bookings
+// Request body: { "user_id": "...", "start_at_utc": ISO, "end_at_utc": ISO,
+// "candidate_email": "..." }; candidate_email defaults to the token's.
+// Books the token holder with an interviewer.
+func (h *CandidateHandler) CreateBooking(c *gin.Context) {
+	var req struct {
+		UserID         string    `json:"user_id" binding:"required"`
+		CandidateEmail string    `json:"candidate_email"`
+		StartAtUTC     time.Time `json:"start_at_utc" binding:"required"`
+		EndAtUTC       time.Time `json:"end_at_utc" binding:"required"`
+		Title          string    `json:"title"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := uuid.Parse(req.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	if !req.StartAtUTC.Before(req.EndAtUTC) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start must be before end"})
+		return
+	}
+	booking, err := h.Service.Book(c.Request.Context(), c.Param("token"), req.UserID, service.CreateBookingParams{
+		CandidateEmail: req.CandidateEmail,
+		Start:          req.StartAtUTC.UTC(),
+		End:            req.EndAtUTC.UTC(),
+		Title:          req.Title,
+	})
+	if err != nil {
+		switch err.Error() {
+		case "invalid token":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "candidate email does not match token":
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			writeCreateBookingError(c, err)
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"id":                booking.ID,
+		"user_id":           booking.UserID,
+		"candidate_email":   booking.CandidateEmail,
+		"start_at_utc":      booking.StartAtUTC.UTC(),
+		"end_at_utc":        booking.EndAtUTC.UTC(),
+		"status":            booking.Status,
+		"confirmation_code": booking.ConfirmationCode,
+	})
+}
+
+// POST /admin/candidate-tokens
+// Request body: { "email": "candidate@example.com" }
+// Returns the candidate's live token, creating one if needed.
+func (h *CandidateHandler) IssueToken(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	token, rec, created, err := h.Service.Issue(c.Request.Context(), req.Email)
+	if err != nil {
+		if err.Error() == "valid email required" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{"id": rec.ID, "email": rec.Email, "token": token, "created_at_utc": rec.CreatedAt.UTC()})
+}
+
+// DELETE /admin/candidate-tokens/:token_id
+func (h *CandidateHandler) RevokeToken(c *gin.Context) {
+	if err := h.Service.Revoke(c.Request.Context(), c.Param("token_id")); err != nil {
+		if err.Error() == "token not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+	"scheduler-service/internal/service"
+)
+
+// stubCandidateTokenRepo serves one live token.
+type stubCandidateTokenRepo struct {
+	repository.CandidateTokenRepository
+	token models.CandidateToken
+}
+
+func (r stubCandidateTokenRepo) EnsureCandidateToken(ctx context.Context, q repository.Querier, email string) (*models.CandidateToken, bool, error) {
+	t := r.token
+	return &t, true, nil
+}
+
+func (r stubCandidateTokenRepo) GetCandidateToken(ctx context.Context, q repository.Querier, id string) (*models.CandidateToken, error) {
+	if id != r.token.ID {
+		return nil, pgx.ErrNoRows
+	}
+	t := r.token
+	return &t, nil
+}
+
+func TestCandidateBookingRejectsOtherEmail(t *testing.T) {
+	svc := service.NewCandidateTokenService(nil, stubCandidateTokenRepo{token: models.CandidateToken{ID: "t1", Email: "cand@example.com"}}, nil, []byte("secret"))
+	// The booking service has no repository, so only a rejected request
+	// answers without panicking
+	svc.Booker, svc.EnforceEmail = service.NewBookingService(nil, nil, nil), true
+	token, _, _, err := svc.Issue(context.Background(), "cand@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &CandidateHandler{Service: svc}
+	cases := []struct {
+		name, token, email string
+		want               int
+	}{
+		{"mismatched email", token, "other@example.com", http.StatusForbidden},
+		{"invalid token", "x.y", "cand@example.com", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		body := `{"user_id":"` + ownUserID + `","candidate_email":"` + tc.email + `","start_at_utc":"2026-03-02T09:00:00Z","end_at_utc":"2026-03-02T09:30:00Z"}`
+		c.Request = httptest.NewRequest(http.MethodPost, "/public/candidate/"+tc.token+"/bookings", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "token", Value: tc.token}}
+		h.CreateBooking(c)
+		if w.Code != tc.want {
+			t.Errorf("%s: status %d, want %d (%s)", tc.name, w.Code, tc.want, w.Body.String())
+		}
+	}
+}
+
+func TestCandidateBookingRejectsDisallowedDomain(t *testing.T) {
+	svc := service.NewCandidateTokenService(nil, stubCandidateTokenRepo{token: models.CandidateToken{ID: "t1", Email: "cand@example.com"}}, nil, []byte("secret"))
+	svc.Booker, svc.EnforceEmail = service.NewBookingService(nil, nil, nil), true
+	svc.Booker.AllowedEmailDomains = []string{"partner.com"}
+	token, _, _, err := svc.Issue(context.Background(), "cand@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := `{"user_id":"` + ownUserID + `","candidate_email":"cand@example.com","start_at_utc":"2026-03-02T09:00:00Z","end_at_utc":"2026-03-02T09:30:00Z"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/public/candidate/"+token+"/bookings", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "token", Value: token}}
+	(&CandidateHandler{Service: svc}).CreateBooking(c)
+	if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), "candidate email domain not allowed") {
+		t.Errorf("status %d (%s), want 403 for a domain off the allowlist", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+	"scheduler-service/internal/service"
+)
+
+const (
+	ownUserID   = "11111111-1111-1111-1111-111111111111"
+	otherUserID = "22222222-2222-2222-2222-222222222222"
+)
+
+// callAs runs handler for target as the API key ownUserID. AvailSv is nil, so
+// a request that gets past the ownership check panics instead of answering.
+func callAs(handler gin.HandlerFunc, target string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	c.Set("api_key_id", ownUserID)
+	handler(c)
+	return w
+}
+
+func TestMultiUserSlotsRejectForeignUsers(t *testing.T) {
+	h := &AvailabilityHandlers{EnforceOwnership: true}
+	routes := map[string]gin.HandlerFunc{
+		"/slots/batch?from=2026-03-02T00:00:00Z&to=2026-03-03T00:00:00Z&user_ids=":  h.GetBatchSlots,
+		"/slots/panel?duration=30&user_ids=":                                        h.GetPanelSlot,
+		"/slots/matrix?from=2026-03-02T00:00:00Z&to=2026-03-03T00:00:00Z&user_ids=": h.GetSlotMatrix,
+	}
+	for target, handler := range routes {
+		w := callAs(handler, target+ownUserID+","+otherUserID)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: status %d, want 403", target, w.Code)
+		}
+	}
+}
+
+func TestOwnsUsers(t *testing.T) {
+	cases := []struct {
+		enforce bool
+		ids     []string
+		want    bool
+	}{
+		{false, []string{otherUserID}, true},
+		{true, []string{ownUserID}, true},
+		{true, []string{ownUserID, otherUserID}, false},
+		{true, []string{otherUserID}, false},
+	}
+	for _, tc := range cases {
+		h := &AvailabilityHandlers{EnforceOwnership: tc.enforce}
+		var got bool
+		w := callAs(func(c *gin.Context) { got = h.ownsUsers(c, tc.ids...) }, "/")
+		if got != tc.want {
+			t.Errorf("enforce=%v ids=%v: got %v, want %v", tc.enforce, tc.ids, got, tc.want)
+		}
+		if !got && w.Code != http.StatusForbidden {
+			t.Errorf("enforce=%v ids=%v: status %d, want 403", tc.enforce, tc.ids, w.Code)
+		}
+	}
+}
+
+func TestTransferBookingsRejectsForeignTarget(t *testing.T) {
+	h := &AvailabilityHandlers{EnforceOwnership: true}
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/users/"+ownUserID+"/bookings/transfer", strings.NewReader(`{"to_user_id":"`+otherUserID+`"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("api_key_id", ownUserID)
+	h.TransferBookings(c)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status %d, want 403", w.Code)
+	}
+}
+
+// stubBookingRepo serves a single booking, whatever id or code is asked for.
+type stubBookingRepo struct {
+	repository.BookingRepository
+	booking models.Booking
+}
+
+func (r stubBookingRepo) GetBooking(ctx context.Context, q repository.Querier, id string) (*models.Booking, error) {
+	b := r.booking
+	return &b, nil
+}
+
+func (r stubBookingRepo) GetBookingByConfirmationCode(ctx context.Context, q repository.Querier, code string) (*models.Booking, error) {
+	b := r.booking
+	return &b, nil
+}
+
+func TestBookingLookupsCheckOwner(t *testing.T) {
+	repo := stubBookingRepo{booking: models.Booking{ID: "33333333-3333-3333-3333-333333333333", UserID: otherUserID, ConfirmationCode: "AB3D7FGH"}}
+	for _, enforce := range []bool{true, false} {
+		h := &AvailabilityHandlers{BookSv: service.NewBookingService(nil, repo, nil), EnforceOwnership: enforce}
+		want := http.StatusOK
+		if enforce {
+			want = http.StatusForbidden
+		}
+		routes := map[string]gin.HandlerFunc{
+			"state": h.GetBookingState,
+			"by-code": func(c *gin.Context) {
+				c.Params = gin.Params{{Key: "code", Value: "AB3D7FGH"}}
+				h.GetBookingByCode(c)
+			},
+		}
+		for name, handler := range routes {
+			w := callAs(handler, "/bookings/x")
+			if w.Code != want {
+				t.Errorf("%s with enforce=%v: status %d, want %d", name, enforce, w.Code, want)
+			}
+		}
+	}
+}
+
+func TestBookingHistoryChecksOwner(t *testing.T) {
+	id := "33333333-3333-3333-3333-333333333333"
+	repo := stubBookingRepo{booking: models.Booking{ID: id, UserID: otherUserID}}
+	// Audit is nil, so only a rejected request answers without panicking
+	h := &BookingHistoryHandler{Bookings: service.NewBookingService(nil, repo, nil), EnforceOwnership: true}
+	w := callAs(func(c *gin.Context) {
+		c.Params = gin.Params{{Key: "id", Value: id}}
+		h.GetHistory(c)
+	}, "/bookings/"+id+"/history")
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status %d, want 403", w.Code)
+	}
+}
+
+func TestCancelBookingChecksOwner(t *testing.T) {
+	id := "33333333-3333-3333-3333-333333333333"
+	repo := stubBookingRepo{booking: models.Booking{ID: id, UserID: otherUserID, RecurrenceGroupID: "44444444-4444-4444-4444-444444444444"}}
+	// The service has no DB, so only a rejected request answers without failing
+	h := &AvailabilityHandlers{BookSv: service.NewBookingService(nil, repo, nil), EnforceOwnership: true}
+	for _, scope := range []string{"single", "following", "all"} {
+		w := callAs(func(c *gin.Context) {
+			c.Request = httptest.NewRequest(http.MethodDelete, "/bookings/"+id+"?scope="+scope, nil)
+			c.Params = gin.Params{{Key: "id", Value: id}}
+			h.CancelBooking(c)
+		}, "/")
+		if w.Code != http.StatusForbidden {
+			t.Errorf("scope %s: status %d, want 403", scope, w.Code)
+		}
+	}
+}
+
+// eventBookingRepo links bookings to Google event ids and cancels them.
+type eventBookingRepo struct {
+	repository.BookingRepository
+	byEvent map[string]*models.Booking
+}
+
+func (r eventBookingRepo) GetBookingByGoogleEventID(ctx context.Context, q repository.Querier, eventID string) (*models.Booking, error) {
+	b, ok := r.byEvent[eventID]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	cp := *b
+	return &cp, nil
+}
+
+func (r eventBookingRepo) GetBookingStatus(ctx context.Context, q repository.Querier, id string) (string, error) {
+	for _, b := range r.byEvent {
+		if b.ID == id {
+			return b.Status, nil
+		}
+	}
+	return "", pgx.ErrNoRows
+}
+
+func (r eventBookingRepo) CancelBooking(ctx context.Context, q repository.Querier, id, reason, cancelledBy string) (int64, error) {
+	for _, b := range r.byEvent {
+		if b.ID == id {
+			b.Status = "cancelled"
+			b.CancellationReason, b.CancelledBy = reason, cancelledBy
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (r eventBookingRepo) GetBookingByConfirmationCode(ctx context.Context, q repository.Querier, code string) (*models.Booking, error) {
+	for _, b := range r.byEvent {
+		if b.ConfirmationCode == code {
+			cp := *b
+			return &cp, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func TestCancelBookingByGoogleEventChecksOwner(t *testing.T) {
+	repo := eventBookingRepo{byEvent: map[string]*models.Booking{
+		"evt-own":   {ID: "33333333-3333-3333-3333-333333333333", UserID: ownUserID, Status: "confirmed"},
+		"evt-other": {ID: "44444444-4444-4444-4444-444444444444", UserID: otherUserID, Status: "confirmed"},
+	}}
+	h := &AvailabilityHandlers{BookSv: service.NewBookingService(nil, repo, nil), EnforceOwnership: true}
+	cases := []struct {
+		eventID string
+		want    int
+		status  string
+	}{
+		{"evt-own", http.StatusOK, "cancelled"},
+		{"evt-other", http.StatusForbidden, "confirmed"},
+		{"evt-unlinked", http.StatusNotFound, ""},
+	}
+	for _, tc := range cases {
+		w := callAs(func(c *gin.Context) {
+			c.Params = gin.Params{{Key: "event_id", Value: tc.eventID}}
+			h.CancelBookingByGoogleEvent(c)
+		}, "/calendar/bookings/by-event/"+tc.eventID)
+		if w.Code != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.eventID, w.Code, tc.want)
+		}
+		if b, ok := repo.byEvent[tc.eventID]; ok && b.Status != tc.status {
+			t.Errorf("%s: booking %s, want %s", tc.eventID, b.Status, tc.status)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+	"scheduler-service/internal/service"
+)
+
+// stubRuleRepo serves a fixed set of availability rules to every user.
+type stubRuleRepo struct {
+	repository.AvailabilityRepository
+	rules []models.AvailabilityRule
+}
+
+func (r stubRuleRepo) ListAvailabilityRules(ctx context.Context, q repository.Querier, userID string) ([]models.AvailabilityRule, error) {
+	return r.rules, nil
+}
+
+// rangeBookingRepo serves a fixed set of bookings for any range.
+type rangeBookingRepo struct {
+	repository.BookingRepository
+	bookings []models.Booking
+}
+
+func (r rangeBookingRepo) ListBookingsInRange(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime) ([]models.Booking, error) {
+	return r.bookings, nil
+}
+
+func TestCountSlotsMatchesListedSlots(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	rules := stubRuleRepo{rules: []models.AvailabilityRule{
+		{ID: "r1", DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "12:00", SlotLengthMins: 30, Available: true},
+		{ID: "r2", DayOfWeek: int(time.Wednesday), StartTime: "13:00", EndTime: "15:00", SlotLengthMins: 60, Available: true},
+	}}
+	cases := []struct {
+		name     string
+		query    string
+		bookings []models.Booking
+		want     int
+	}{
+		{"one day", "?from=2026-03-02T00:00:00Z&to=2026-03-03T00:00:00Z", nil, 6},
+		{"one week", "?from=2026-03-02T00:00:00Z&to=2026-03-09T00:00:00Z", nil, 8},
+		{"a month", "?from=2026-03-01T00:00:00Z&to=2026-04-01T00:00:00Z", nil, 38},
+		{"booked slot excluded", "?from=2026-03-02T00:00:00Z&to=2026-03-03T00:00:00Z", []models.Booking{{ID: "b1", Status: "confirmed", StartAtUTC: monday.Add(9 * time.Hour), EndAtUTC: monday.Add(9*time.Hour + 30*time.Minute)}}, 5},
+		{"nothing open", "?from=2026-03-03T00:00:00Z&to=2026-03-04T00:00:00Z", nil, 0},
+	}
+	for _, tc := range cases {
+		avail := &service.AvailabilityService{Avail: rules, Book: rangeBookingRepo{bookings: tc.bookings}, Clock: service.FixedClock(monday)}
+		h := &AvailabilityHandlers{AvailSv: avail}
+		get := func(handler gin.HandlerFunc, path string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, path+tc.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: ownUserID}}
+			handler(c)
+			return w
+		}
+
+		list := get(h.GetSlots, "/users/"+ownUserID+"/slots")
+		count := get(h.CountSlots, "/users/"+ownUserID+"/slots/count")
+		if list.Code != http.StatusOK || count.Code != http.StatusOK {
+			t.Fatalf("%s: status %d and %d, want 200: %s %s", tc.name, list.Code, count.Code, list.Body, count.Body)
+		}
+		var slots []service.Slot
+		if err := json.Unmarshal(list.Body.Bytes(), &slots); err != nil {
+			t.Fatal(err)
+		}
+		var body struct {
+			Count int `json:"count"`
+		}
+		if err := json.Unmarshal(count.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Count != len(slots) || body.Count != tc.want {
+			t.Errorf("%s: count = %d, listed %d slots, want %d", tc.name, body.Count, len(slots), tc.want)
+		}
+	}
+}
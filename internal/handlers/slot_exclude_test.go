@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/service"
+)
+
+func TestGetSlotsExcludingBooking(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	const moved, kept = "33333333-3333-3333-3333-333333333333", "44444444-4444-4444-4444-444444444444"
+	rules := stubRuleRepo{rules: []models.AvailabilityRule{
+		{ID: "r1", DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "11:00", SlotLengthMins: 30, Available: true},
+	}}
+	bookings := rangeBookingRepo{bookings: []models.Booking{
+		{ID: moved, Status: "confirmed", StartAtUTC: monday.Add(9 * time.Hour), EndAtUTC: monday.Add(9*time.Hour + 30*time.Minute)},
+		{ID: kept, Status: "confirmed", StartAtUTC: monday.Add(10 * time.Hour), EndAtUTC: monday.Add(10*time.Hour + 30*time.Minute)},
+	}}
+	h := &AvailabilityHandlers{AvailSv: &service.AvailabilityService{Avail: rules, Book: bookings, Clock: service.FixedClock(monday)}}
+	const day = "?from=2026-03-02T00:00:00Z&to=2026-03-03T00:00:00Z"
+
+	cases := []struct {
+		name       string
+		query      string
+		wantStatus int
+		want       []string
+	}{
+		{"no exclusion", day, http.StatusOK, []string{"09:30", "10:30"}},
+		{"excluded booking's slot reappears", day + "&exclude_booking=" + moved, http.StatusOK, []string{"09:00", "09:30", "10:30"}},
+		{"unknown booking changes nothing", day + "&exclude_booking=55555555-5555-5555-5555-555555555555", http.StatusOK, []string{"09:30", "10:30"}},
+		{"not a UUID", day + "&exclude_booking=b1", http.StatusBadRequest, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/users/"+ownUserID+"/slots"+tc.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: ownUserID}}
+			h.GetSlots(c)
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tc.wantStatus, w.Body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var slots []service.Slot
+			if err := json.Unmarshal(w.Body.Bytes(), &slots); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, sl := range slots {
+				got = append(got, sl.StartUTC.Format("15:04"))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("slots = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
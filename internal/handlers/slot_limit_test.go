@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/service"
+)
+
+func TestGetSlotsLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	rules := stubRuleRepo{rules: []models.AvailabilityRule{
+		{ID: "r1", DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "12:00", SlotLengthMins: 30, Available: true},
+	}}
+	avail := &service.AvailabilityService{Avail: rules, Book: rangeBookingRepo{}, Clock: service.FixedClock(monday)}
+	h := &AvailabilityHandlers{AvailSv: avail}
+	const day = "?from=2026-03-02T00:00:00Z&to=2026-03-03T00:00:00Z"
+
+	cases := []struct {
+		name          string
+		query         string
+		wantStatus    int
+		wantSlots     int
+		wantTruncated bool
+		wantNextFrom  string
+	}{
+		{"truncated with a continuation hint", day + "&limit=4", http.StatusOK, 4, true, "2026-03-02T11:00:00Z"},
+		{"limit equal to the slot count", day + "&limit=6", http.StatusOK, 6, false, ""},
+		{"limit above the cap", day + "&limit=20000", http.StatusOK, 6, false, ""},
+		{"zero limit", day + "&limit=0", http.StatusBadRequest, 0, false, ""},
+		{"non-numeric limit", day + "&limit=many", http.StatusBadRequest, 0, false, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/users/"+ownUserID+"/slots"+tc.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: ownUserID}}
+			h.GetSlots(c)
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tc.wantStatus, w.Body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				Slots     []service.Slot `json:"slots"`
+				Truncated bool           `json:"truncated"`
+				NextFrom  *time.Time     `json:"next_from"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if len(body.Slots) != tc.wantSlots || body.Truncated != tc.wantTruncated {
+				t.Errorf("got %d slots, truncated %v; want %d, %v", len(body.Slots), body.Truncated, tc.wantSlots, tc.wantTruncated)
+			}
+			var gotNext string
+			if body.NextFrom != nil {
+				gotNext = body.NextFrom.UTC().Format(time.RFC3339)
+			}
+			if gotNext != tc.wantNextFrom || w.Header().Get("X-Slots-Next-From") != tc.wantNextFrom {
+				t.Errorf("next_from = %q, header %q; want %q", gotNext, w.Header().Get("X-Slots-Next-From"), tc.wantNextFrom)
+			}
+			if got := w.Header().Get("X-Slots-Truncated") == "true"; got != tc.wantTruncated {
+				t.Errorf("X-Slots-Truncated set = %v, want %v", got, tc.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestGetSlotsWithoutLimitStaysBareArray(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	rules := stubRuleRepo{rules: []models.AvailabilityRule{
+		{ID: "r1", DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "12:00", SlotLengthMins: 30, Available: true},
+	}}
+	h := &AvailabilityHandlers{AvailSv: &service.AvailabilityService{Avail: rules, Book: rangeBookingRepo{}, Clock: service.FixedClock(monday)}}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/"+ownUserID+"/slots?from=2026-03-02T00:00:00Z&to=2026-03-03T00:00:00Z", nil)
+	c.Params = gin.Params{{Key: "id", Value: ownUserID}}
+	h.GetSlots(c)
+
+	var slots []service.Slot
+	if err := json.Unmarshal(w.Body.Bytes(), &slots); err != nil {
+		t.Fatalf("body is not a bare array: %v: %s", err, w.Body)
+	}
+	if len(slots) != 6 || w.Header().Get("X-Slots-Truncated") != "" {
+		t.Errorf("got %d slots, X-Slots-Truncated %q; want 6 and unset", len(slots), w.Header().Get("X-Slots-Truncated"))
+	}
+}
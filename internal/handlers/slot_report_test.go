@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/service"
+)
+
+func TestSlotsReportFormats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	rules := stubRuleRepo{rules: []models.AvailabilityRule{
+		{ID: "r1", DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "10:00", SlotLengthMins: 30, Title: "Screen", Available: true},
+		{ID: "r2", DayOfWeek: int(time.Wednesday), StartTime: "13:00", EndTime: "14:00", SlotLengthMins: 60, Available: true},
+	}}
+	h := &AvailabilityHandlers{AvailSv: &service.AvailabilityService{Avail: rules, Book: rangeBookingRepo{}, Clock: service.FixedClock(monday)}}
+	const week = "from=2026-03-02T00:00:00Z&to=2026-03-05T00:00:00Z"
+
+	cases := []struct {
+		name            string
+		query           string
+		wantStatus      int
+		wantType        string
+		wantDisposition string
+		wantCSV         string
+		wantJSONStarts  []string
+	}{
+		{"csv", "?" + week + "&format=csv", http.StatusOK, "text/csv; charset=utf-8", `attachment; filename="slots-` + ownUserID + `-20260302-20260305.csv"`,
+			"start_utc,end_utc,duration_mins,title\n" +
+				"2026-03-02T09:00:00Z,2026-03-02T09:30:00Z,30,Screen\n" +
+				"2026-03-02T09:30:00Z,2026-03-02T10:00:00Z,30,Screen\n" +
+				"2026-03-04T13:00:00Z,2026-03-04T14:00:00Z,60,\n", nil},
+		{"csv by default", "?" + week, http.StatusOK, "text/csv; charset=utf-8", `attachment; filename="slots-` + ownUserID + `-20260302-20260305.csv"`,
+			"start_utc,end_utc,duration_mins,title\n" +
+				"2026-03-02T09:00:00Z,2026-03-02T09:30:00Z,30,Screen\n" +
+				"2026-03-02T09:30:00Z,2026-03-02T10:00:00Z,30,Screen\n" +
+				"2026-03-04T13:00:00Z,2026-03-04T14:00:00Z,60,\n", nil},
+		{"json", "?" + week + "&format=json", http.StatusOK, "application/json; charset=utf-8", `attachment; filename="slots-` + ownUserID + `-20260302-20260305.json"`,
+			"", []string{"2026-03-02T09:00:00Z", "2026-03-02T09:30:00Z", "2026-03-04T13:00:00Z"}},
+		{"csv without slots", "?from=2026-03-03T00:00:00Z&to=2026-03-04T00:00:00Z&format=csv", http.StatusOK, "text/csv; charset=utf-8", `attachment; filename="slots-` + ownUserID + `-20260303-20260304.csv"`,
+			"start_utc,end_utc,duration_mins,title\n", nil},
+		{"json without slots", "?from=2026-03-03T00:00:00Z&to=2026-03-04T00:00:00Z&format=json", http.StatusOK, "application/json; charset=utf-8", `attachment; filename="slots-` + ownUserID + `-20260303-20260304.json"`,
+			"", []string{}},
+		{"unknown format", "?" + week + "&format=xlsx", http.StatusBadRequest, "", "", "", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/users/"+ownUserID+"/slots/report"+tc.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: ownUserID}}
+			h.SlotsReport(c)
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tc.wantStatus, w.Body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			if got := w.Header().Get("Content-Type"); got != tc.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tc.wantType)
+			}
+			if got := w.Header().Get("Content-Disposition"); got != tc.wantDisposition {
+				t.Errorf("Content-Disposition = %q, want %q", got, tc.wantDisposition)
+			}
+			if tc.wantJSONStarts == nil {
+				if w.Body.String() != tc.wantCSV {
+					t.Errorf("body = %q, want %q", w.Body, tc.wantCSV)
+				}
+				return
+			}
+			var slots []service.Slot
+			if err := json.Unmarshal(w.Body.Bytes(), &slots); err != nil {
+				t.Fatalf("body is not a JSON array: %v: %s", err, w.Body)
+			}
+			starts := []string{}
+			for _, sl := range slots {
+				starts = append(starts, sl.StartUTC.Format(time.RFC3339))
+			}
+			if !reflect.DeepEqual(starts, tc.wantJSONStarts) {
+				t.Errorf("slot starts = %v, want %v", starts, tc.wantJSONStarts)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/service"
+)
+
+func TestParseUTCFieldStrictMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	nine := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		strict bool
+		value  string
+		want   time.Time
+		wantOK bool
+	}{
+		{"lenient, Z", false, "2026-03-02T09:00:00Z", nine, true},
+		{"lenient, +05:30 converted", false, "2026-03-02T14:30:00+05:30", nine, true},
+		{"lenient, malformed", false, "2026-03-02 09:00", time.Time{}, false},
+		{"strict, Z", true, "2026-03-02T09:00:00Z", nine, true},
+		{"strict, +05:30", true, "2026-03-02T14:30:00+05:30", time.Time{}, false},
+		{"strict, +00:00", true, "2026-03-02T09:00:00+00:00", time.Time{}, false},
+		{"strict, malformed", true, "2026-03-02 09:00", time.Time{}, false},
+	}
+	h := &AvailabilityHandlers{}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			req := httptest.NewRequest(http.MethodPost, "/users/"+ownUserID+"/bookings", nil)
+			c.Request = req.WithContext(service.WithFlags(req.Context(), service.Flags{StrictUTC: tc.strict}))
+
+			got, ok := h.parseUTCField(c, "start_at_utc", tc.value)
+			if ok != tc.wantOK {
+				t.Fatalf("ok = %v, want %v: %s", ok, tc.wantOK, w.Body)
+			}
+			if !ok {
+				if w.Code != http.StatusBadRequest {
+					t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+				}
+				return
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("parsed %s, want %s", got.UTC(), tc.want)
+			}
+		})
+	}
+}
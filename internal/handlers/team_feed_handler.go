@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/service"
+)
+
+type TeamFeedHandler struct {
+	Service *service.TeamFeedService
+}
+
+// GET /calendar/feed.ics?user_ids=a,b,c&token=...
+// Serves the team's upcoming bookings as a subscribable ICS calendar. The
+// token must have been issued for exactly this set of users.
+func (h *TeamFeedHandler) GetFeed(c *gin.Context) {
+	userIDs := splitUserIDs(c.Query("user_ids"))
+	if len(userIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_ids required"})
+		return
+	}
+	if len(userIDs) > maxBatchUsers {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d user_ids allowed", maxBatchUsers)})
+		return
+	}
+	if !h.Service.ValidToken(userIDs, c.Query("token")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "invalid feed token"})
+		return
+	}
+	body, err := h.Service.Feed(c.Request.Context(), userIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=60")
+	c.Header("Content-Disposition", `inline; filename="team.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
+
+// POST /admin/feed-tokens
+// Request body: { "user_ids": ["...", "..."] }
+// Returns the feed token for the set and the feed path to subscribe to.
+func (h *TeamFeedHandler) IssueToken(c *gin.Context) {
+	var req struct {
+		UserIDs []string `json:"user_ids" binding:"required"`
+	}
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userIDs := splitUserIDs(strings.Join(req.UserIDs, ","))
+	if len(userIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_ids required"})
+		return
+	}
+	if len(userIDs) > maxBatchUsers {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d user_ids allowed", maxBatchUsers)})
+		return
+	}
+	token := h.Service.Token(userIDs)
+	q := url.Values{"user_ids": {strings.Join(userIDs, ",")}, "token": {token}}
+	c.JSON(http.StatusOK, gin.H{"user_ids": userIDs, "token": token, "path": "/api/calendar/feed.ics?" + q.Encode()})
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/app"
+	"scheduler-service/internal/service"
+)
+
+type UserSettingsHandler struct {
+	Service *service.UserSettingsService
+}
+
+// GET /users/:id/settings
+func (h *UserSettingsHandler) GetSettings(c *gin.Context) {
+	settings, err := h.Service.GetSettings(c.Request.Context(), app.ResolvedUserFrom(c).ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// PUT /users/:id/settings
+// Request body: { "default_calendar_id": "team@group.calendar.google.com", "rolling_days": 14, "holiday_region": "US" }
+// Omitted fields are left unchanged; an empty string or 0 resets to the default.
+func (h *UserSettingsHandler) UpdateSettings(c *gin.Context) {
+	var req service.UserSettingsUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	settings, err := h.Service.UpdateSettings(c.Request.Context(), app.ResolvedUserFrom(c).ID, req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "rolling_days must be") || strings.HasPrefix(err.Error(), "holiday_region must be") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+type vacationReq struct {
+	// UntilUTC ends vacation mode; null or omitted clears it
+	UntilUTC *time.Time `json:"until_utc"`
+}
+
+// POST /users/:id/vacation
+// Request body: { "until_utc": "2025-08-01T00:00:00Z" } sets vacation mode,
+// { "until_utc": null } clears it.
+func (h *UserSettingsHandler) SetVacation(c *gin.Context) {
+	var req vacationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	settings, err := h.Service.SetVacation(c.Request.Context(), app.ResolvedUserFrom(c).ID, req.UntilUTC)
+	if err != nil {
+		if err.Error() == "until must be in the future" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
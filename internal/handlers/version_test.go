@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"scheduler-service/internal/version"
+)
+
+func TestVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	orig := [3]string{version.Version, version.Commit, version.BuildTime}
+	defer func() { version.Version, version.Commit, version.BuildTime = orig[0], orig[1], orig[2] }()
+	cases := []struct {
+		name, ver, commit, buildTime string
+	}{
+		{"placeholders", "dev", "unknown", "unknown"},
+		{"injected", "1.2.0", "abc1234", "2026-03-02T09:00:00Z"},
+	}
+	for _, tc := range cases {
+		version.Version, version.Commit, version.BuildTime = tc.ver, tc.commit, tc.buildTime
+		r := gin.New()
+		r.GET("/version", Version)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status %d, want 200", tc.name, w.Code)
+		}
+		var body map[string]string
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]string{"version": tc.ver, "commit": tc.commit, "build_time": tc.buildTime}
+		for field, v := range want {
+			if got, ok := body[field]; !ok || got != v {
+				t.Errorf("%s: %s = %q (present %v), want %q", tc.name, field, got, ok, v)
+			}
+		}
+	}
+}
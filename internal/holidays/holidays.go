@@ -0,0 +1,194 @@
+// Package holidays computes public holidays for a small set of regions from
+// built-in rules, so no external calendar is needed. Dates are civil dates;
+// where a region moves a holiday falling on a weekend to a weekday, the
+// observed date is returned.
+package holidays
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Holiday is one public holiday on a civil date.
+type Holiday struct {
+	Date time.Time // midnight UTC of the civil date
+	Name string
+}
+
+// regions maps a region code to the generator of its holidays for a year.
+var regions = map[string]func(year int) []Holiday{
+	"US": usFederal,
+	"GB": englandAndWales,
+	"DE": germanyNational,
+	"FR": france,
+}
+
+// Supported reports whether region (case-insensitive) has a holiday calendar.
+func Supported(region string) bool {
+	_, ok := regions[strings.ToUpper(region)]
+	return ok
+}
+
+// Regions returns the supported region codes in sorted order.
+func Regions() []string {
+	out := make([]string, 0, len(regions))
+	for code := range regions {
+		out = append(out, code)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// InRange returns the region's holidays dated within [from, to], both
+// truncated to whole days, in date order. An unknown region has none.
+func InRange(region string, from, to time.Time) []Holiday {
+	gen, ok := regions[strings.ToUpper(region)]
+	if !ok {
+		return nil
+	}
+	from, to = day(from), day(to)
+	var out []Holiday
+	// A January 1st observed on December 31st belongs to the next year
+	for year := from.Year(); year <= to.Year()+1; year++ {
+		for _, h := range gen(year) {
+			if !h.Date.Before(from) && !h.Date.After(to) {
+				out = append(out, h)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out
+}
+
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return date(y, m, d)
+}
+
+func date(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// nthWeekday returns the nth (1-based) weekday of the month, or the last one
+// when n is -1.
+func nthWeekday(year int, month time.Month, wd time.Weekday, n int) time.Time {
+	if n < 0 {
+		last := date(year, month+1, 0)
+		return last.AddDate(0, 0, -((int(last.Weekday()) - int(wd) + 7) % 7))
+	}
+	first := date(year, month, 1)
+	return first.AddDate(0, 0, (int(wd)-int(first.Weekday())+7)%7+7*(n-1))
+}
+
+// easter returns Easter Sunday (Gregorian) using the anonymous algorithm.
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	dd := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), dd)
+}
+
+// usObserved moves a Saturday holiday to Friday and a Sunday one to Monday.
+func usObserved(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, -1)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+func usFederal(year int) []Holiday {
+	out := []Holiday{
+		{usObserved(date(year, time.January, 1)), "New Year's Day"},
+		{nthWeekday(year, time.January, time.Monday, 3), "Martin Luther King Jr. Day"},
+		{nthWeekday(year, time.February, time.Monday, 3), "Washington's Birthday"},
+		{nthWeekday(year, time.May, time.Monday, -1), "Memorial Day"},
+		{usObserved(date(year, time.July, 4)), "Independence Day"},
+		{nthWeekday(year, time.September, time.Monday, 1), "Labor Day"},
+		{nthWeekday(year, time.October, time.Monday, 2), "Columbus Day"},
+		{usObserved(date(year, time.November, 11)), "Veterans Day"},
+		{nthWeekday(year, time.November, time.Thursday, 4), "Thanksgiving Day"},
+		{usObserved(date(year, time.December, 25)), "Christmas Day"},
+	}
+	if year >= 2021 {
+		out = append(out, Holiday{usObserved(date(year, time.June, 19)), "Juneteenth"})
+	}
+	return out
+}
+
+// nextWeekday moves a weekend date to the following Monday.
+func nextWeekday(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, 2)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+func englandAndWales(year int) []Holiday {
+	e := easter(year)
+	christmas, boxing := date(year, time.December, 25), date(year, time.December, 26)
+	switch christmas.Weekday() {
+	case time.Saturday:
+		christmas, boxing = christmas.AddDate(0, 0, 2), boxing.AddDate(0, 0, 2)
+	case time.Sunday:
+		christmas = christmas.AddDate(0, 0, 2)
+	case time.Friday:
+		boxing = boxing.AddDate(0, 0, 2)
+	}
+	return []Holiday{
+		{nextWeekday(date(year, time.January, 1)), "New Year's Day"},
+		{e.AddDate(0, 0, -2), "Good Friday"},
+		{e.AddDate(0, 0, 1), "Easter Monday"},
+		{nthWeekday(year, time.May, time.Monday, 1), "Early May bank holiday"},
+		{nthWeekday(year, time.May, time.Monday, -1), "Spring bank holiday"},
+		{nthWeekday(year, time.August, time.Monday, -1), "Summer bank holiday"},
+		{christmas, "Christmas Day"},
+		{boxing, "Boxing Day"},
+	}
+}
+
+func germanyNational(year int) []Holiday {
+	e := easter(year)
+	return []Holiday{
+		{date(year, time.January, 1), "Neujahr"},
+		{e.AddDate(0, 0, -2), "Karfreitag"},
+		{e.AddDate(0, 0, 1), "Ostermontag"},
+		{date(year, time.May, 1), "Tag der Arbeit"},
+		{e.AddDate(0, 0, 39), "Christi Himmelfahrt"},
+		{e.AddDate(0, 0, 50), "Pfingstmontag"},
+		{date(year, time.October, 3), "Tag der Deutschen Einheit"},
+		{date(year, time.December, 25), "1. Weihnachtstag"},
+		{date(year, time.December, 26), "2. Weihnachtstag"},
+	}
+}
+
+func france(year int) []Holiday {
+	e := easter(year)
+	return []Holiday{
+		{date(year, time.January, 1), "Jour de l'an"},
+		{e.AddDate(0, 0, 1), "Lundi de Pâques"},
+		{date(year, time.May, 1), "Fête du Travail"},
+		{date(year, time.May, 8), "Victoire 1945"},
+		{e.AddDate(0, 0, 39), "Ascension"},
+		{e.AddDate(0, 0, 50), "Lundi de Pentecôte"},
+		{date(year, time.July, 14), "Fête nationale"},
+		{date(year, time.August, 15), "Assomption"},
+		{date(year, time.November, 1), "Toussaint"},
+		{date(year, time.November, 11), "Armistice 1918"},
+		{date(year, time.December, 25), "Noël"},
+	}
+}
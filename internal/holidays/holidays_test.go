@@ -0,0 +1,37 @@
+package holidays
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestInRangeKnownDates(t *testing.T) {
+	d := func(month time.Month, day int) time.Time { return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC) }
+	cases := []struct {
+		name     string
+		region   string
+		from, to time.Time
+		want     []string
+	}{
+		{"US Independence Day on a Saturday is observed Friday", "US", d(time.June, 29), d(time.July, 5), []string{"2026-07-03 Independence Day"}},
+		{"US Thanksgiving", "us", d(time.November, 23), d(time.November, 29), []string{"2026-11-26 Thanksgiving Day"}},
+		{"GB Easter weekend", "GB", d(time.April, 1), d(time.April, 7), []string{"2026-04-03 Good Friday", "2026-04-06 Easter Monday"}},
+		{"DE Whit Monday", "DE", d(time.May, 20), d(time.May, 31), []string{"2026-05-25 Pfingstmontag"}},
+		{"FR in mid-July", "FR", d(time.July, 10), d(time.July, 20), []string{"2026-07-14 Fête nationale"}},
+		{"range bounds are inclusive", "DE", d(time.October, 3), d(time.October, 3), []string{"2026-10-03 Tag der Deutschen Einheit"}},
+		{"New Year observed in the previous year", "US", time.Date(2027, time.December, 30, 0, 0, 0, 0, time.UTC), time.Date(2027, time.December, 31, 0, 0, 0, 0, time.UTC), []string{"2027-12-31 New Year's Day"}},
+		{"unknown region", "XX", d(time.January, 1), d(time.December, 31), nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, h := range InRange(tc.region, tc.from, tc.to) {
+				got = append(got, h.Date.Format("2006-01-02")+" "+h.Name)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("holidays = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
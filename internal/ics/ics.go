@@ -0,0 +1,418 @@
+// Package ics parses the subset of iCalendar (RFC 5545) needed to import busy
+// time from a calendar export: VEVENTs with their start, end and recurrence.
+// It also writes simple feeds of one-off events.
+package ics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is one VEVENT. All-day events start and end at UTC midnight. End is
+// exclusive.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	RRule       *RRule
+	ExDates     []time.Time
+
+	// Free is set for TRANSP:TRANSPARENT events, which do not block time.
+	Free bool
+	// Cancelled is set for STATUS:CANCELLED events.
+	Cancelled bool
+
+	recurrenceID time.Time
+}
+
+// RRule is a recurrence rule. Only FREQ, INTERVAL, COUNT, UNTIL and, for
+// weekly rules, BYDAY are supported.
+type RRule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    time.Time
+	ByDay    []time.Weekday
+}
+
+// Occurrence is one instance of an event.
+type Occurrence struct {
+	Start time.Time
+	End   time.Time
+}
+
+// maxExpansion bounds how many recurrence steps Occurrences walks, so a rule
+// with a tiny interval and no end cannot spin forever.
+const maxExpansion = 100000
+
+// Parse reads the VEVENTs of an iCalendar stream. Modified instances of a
+// recurring event (those with a RECURRENCE-ID) are returned as their own
+// events and excluded from the master's recurrence.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		events   []Event
+		cur      *Event
+		calendar bool
+		duration time.Duration
+		hasEnd   bool
+	)
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		name, params, value, err := splitProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		switch {
+		case name == "BEGIN" && value == "VCALENDAR":
+			calendar = true
+		case name == "BEGIN" && value == "VEVENT":
+			if cur != nil {
+				return nil, fmt.Errorf("line %d: nested VEVENT", i+1)
+			}
+			cur = &Event{}
+			duration, hasEnd = 0, false
+		case name == "END" && value == "VEVENT":
+			if cur == nil {
+				return nil, fmt.Errorf("line %d: END:VEVENT without BEGIN", i+1)
+			}
+			if cur.Start.IsZero() {
+				return nil, fmt.Errorf("line %d: VEVENT without DTSTART", i+1)
+			}
+			if !hasEnd {
+				switch {
+				case duration > 0:
+					cur.End = cur.Start.Add(duration)
+				case cur.AllDay:
+					cur.End = cur.Start.AddDate(0, 0, 1)
+				default:
+					cur.End = cur.Start
+				}
+			}
+			if cur.End.Before(cur.Start) {
+				return nil, fmt.Errorf("line %d: VEVENT ends before it starts", i+1)
+			}
+			events = append(events, *cur)
+			cur = nil
+		case cur == nil:
+			// Calendar-level and non-event component properties are ignored.
+		case name == "UID":
+			cur.UID = value
+		case name == "SUMMARY":
+			cur.Summary = unescape(value)
+		case name == "DESCRIPTION":
+			cur.Description = unescape(value)
+		case name == "DTSTART":
+			cur.Start, cur.AllDay, err = parseDateTime(params, value)
+		case name == "DTEND":
+			cur.End, _, err = parseDateTime(params, value)
+			hasEnd = true
+		case name == "DURATION":
+			duration, err = parseDuration(value)
+		case name == "RRULE":
+			cur.RRule, err = parseRRule(value)
+		case name == "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				var t time.Time
+				if t, _, err = parseDateTime(params, v); err != nil {
+					break
+				}
+				cur.ExDates = append(cur.ExDates, t)
+			}
+		case name == "RECURRENCE-ID":
+			cur.recurrenceID, _, err = parseDateTime(params, value)
+		case name == "TRANSP":
+			cur.Free = value == "TRANSPARENT"
+		case name == "STATUS":
+			cur.Cancelled = value == "CANCELLED"
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", i+1, name, err)
+		}
+	}
+	if !calendar {
+		return nil, errors.New("not an iCalendar file: missing BEGIN:VCALENDAR")
+	}
+	if cur != nil {
+		return nil, errors.New("unterminated VEVENT")
+	}
+
+	masters := map[string]int{}
+	for i, e := range events {
+		if e.RRule != nil && e.recurrenceID.IsZero() {
+			masters[e.UID] = i
+		}
+	}
+	for _, e := range events {
+		if e.recurrenceID.IsZero() {
+			continue
+		}
+		if i, ok := masters[e.UID]; ok {
+			events[i].ExDates = append(events[i].ExDates, e.recurrenceID)
+		}
+	}
+	return events, nil
+}
+
+// Occurrences returns the instances of e that overlap [from, to), expanding
+// its recurrence rule. Recurrences are stepped in the event's own location so
+// local times stay fixed across DST changes.
+func (e Event) Occurrences(from, to time.Time) []Occurrence {
+	length := e.End.Sub(e.Start)
+	var out []Occurrence
+	add := func(start time.Time) {
+		for _, ex := range e.ExDates {
+			if ex.Equal(start) {
+				return
+			}
+		}
+		end := start.Add(length)
+		if start.Before(to) && end.After(from) {
+			out = append(out, Occurrence{Start: start, End: end})
+		}
+	}
+	if e.RRule == nil {
+		add(e.Start)
+		return out
+	}
+
+	rule := e.RRule
+	byDay := append([]time.Weekday(nil), rule.ByDay...)
+	if len(byDay) == 0 {
+		byDay = []time.Weekday{e.Start.Weekday()}
+	}
+	// Monday-first, so a week's instances are emitted in order
+	sort.Slice(byDay, func(i, j int) bool { return (byDay[i]+6)%7 < (byDay[j]+6)%7 })
+	count := 0
+	emit := func(start time.Time) bool {
+		if !rule.Until.IsZero() && start.After(rule.Until) {
+			return false
+		}
+		if rule.Count > 0 && count >= rule.Count {
+			return false
+		}
+		count++
+		add(start)
+		return start.Before(to)
+	}
+	for step := 0; step < maxExpansion; step++ {
+		switch rule.Freq {
+		case "DAILY":
+			if !emit(e.Start.AddDate(0, 0, step*rule.Interval)) {
+				return out
+			}
+		case "WEEKLY":
+			weekStart := e.Start.AddDate(0, 0, 7*step*rule.Interval)
+			// Days of the week are taken Monday-first from the week of weekStart.
+			offset := (int(weekStart.Weekday()) + 6) % 7
+			monday := weekStart.AddDate(0, 0, -offset)
+			for _, wd := range byDay {
+				start := monday.AddDate(0, 0, (int(wd)+6)%7)
+				if start.Before(e.Start) {
+					continue
+				}
+				if !emit(start) {
+					return out
+				}
+			}
+		case "MONTHLY":
+			start := e.Start.AddDate(0, step*rule.Interval, 0)
+			if start.Day() != e.Start.Day() {
+				// Months without this day have no instance.
+				continue
+			}
+			if !emit(start) {
+				return out
+			}
+		case "YEARLY":
+			start := e.Start.AddDate(step*rule.Interval, 0, 0)
+			if start.Day() != e.Start.Day() {
+				continue
+			}
+			if !emit(start) {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// unfold reads content lines, joining continuation lines (those starting with
+// a space or tab) onto the previous line.
+func unfold(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var lines []string
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, sc.Err()
+}
+
+// splitProperty splits "NAME;PARAM=V;...:value", honouring quoted parameter
+// values that may contain ':' or ';'.
+func splitProperty(line string) (name string, params map[string]string, value string, err error) {
+	inQuote := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuote = !inQuote
+		} else if r == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", errors.New("missing ':'")
+	}
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	name = strings.ToUpper(parts[0])
+	params = map[string]string{}
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return name, params, value, nil
+}
+
+// parseDateTime parses a DATE or DATE-TIME value. UTC ("Z") values and TZID
+// values are converted to instants; floating times are taken as UTC.
+func parseDateTime(params map[string]string, value string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	loc := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		l, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown TZID %q", tzid)
+		}
+		loc = l
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseDuration parses a positive iCalendar duration such as PT1H30M or P1D.
+func parseDuration(value string) (time.Duration, error) {
+	v := strings.TrimPrefix(value, "+")
+	if !strings.HasPrefix(v, "P") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	v = v[1:]
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, r := range v {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		num = ""
+		switch {
+		case r == 'W' && !inTime:
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			d += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseRRule(value string) (*RRule, error) {
+	rule := &RRule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(v)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(v)
+			if err == nil && rule.Interval < 1 {
+				err = errors.New("INTERVAL must be positive")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(v)
+		case "UNTIL":
+			rule.Until, _, err = parseDateTime(map[string]string{}, v)
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				wd, ok := weekdays[strings.ToUpper(d)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported BYDAY %q", d)
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		case "WKST":
+		default:
+			return nil, fmt.Errorf("unsupported RRULE part %s", k)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("RRULE %s: %w", k, err)
+		}
+	}
+	switch rule.Freq {
+	case "DAILY", "MONTHLY", "YEARLY":
+		if len(rule.ByDay) > 0 {
+			return nil, fmt.Errorf("BYDAY is only supported with FREQ=WEEKLY")
+		}
+	case "WEEKLY":
+	default:
+		return nil, fmt.Errorf("unsupported FREQ %q", rule.Freq)
+	}
+	return rule, nil
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
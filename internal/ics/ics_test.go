@@ -0,0 +1,83 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Example//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup@example.com\r\n" +
+	"SUMMARY:Team standup\r\n" +
+	"DTSTART:20260302T090000Z\r\n" +
+	"DTEND:20260302T093000Z\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO,WE\r\n" +
+	"EXDATE:20260309T090000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:offsite@example.com\r\n" +
+	"SUMMARY:Offsite with a long\r\n" +
+	"  folded summary\r\n" +
+	"DTSTART:20260305T130000Z\r\n" +
+	"DURATION:PT2H\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseRecurringAndSingleEvent(t *testing.T) {
+	events, err := Parse(strings.NewReader(sampleICS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("parsed %d events, want 2", len(events))
+	}
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 14)
+	cases := []struct {
+		uid     string
+		summary string
+		want    []string // occurrence starts in [from, to)
+		wantLen time.Duration
+	}{
+		{"standup@example.com", "Team standup", []string{
+			"2026-03-02T09:00:00Z", "2026-03-04T09:00:00Z", "2026-03-11T09:00:00Z",
+		}, 30 * time.Minute},
+		{"offsite@example.com", "Offsite with a long folded summary", []string{"2026-03-05T13:00:00Z"}, 2 * time.Hour},
+	}
+	for i, tc := range cases {
+		e := events[i]
+		if e.UID != tc.uid || e.Summary != tc.summary {
+			t.Errorf("event %d = %q %q, want %q %q", i, e.UID, e.Summary, tc.uid, tc.summary)
+		}
+		occs := e.Occurrences(from, to)
+		var got []string
+		for _, o := range occs {
+			got = append(got, o.Start.UTC().Format(time.RFC3339))
+			if o.End.Sub(o.Start) != tc.wantLen {
+				t.Errorf("%s: occurrence at %s lasts %s, want %s", tc.uid, o.Start, o.End.Sub(o.Start), tc.wantLen)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s: occurrences = %v, want %v", tc.uid, got, tc.want)
+		}
+	}
+}
+
+func TestParseRejectsInvalidFiles(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"not a calendar", "hello\r\n"},
+		{"event without a start", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:x\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"},
+		{"malformed start", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:x\r\nDTSTART:tomorrow\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"},
+	}
+	for _, tc := range cases {
+		if _, err := Parse(strings.NewReader(tc.body)); err == nil {
+			t.Errorf("%s: parsed without error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package ics
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// prodID identifies this service in written calendars.
+const prodID = "-//scheduler-service//Interview Feed//EN"
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding.
+const maxLineOctets = 75
+
+// Write encodes events as a VCALENDAR named name, suitable for calendar
+// subscription. Times are written in UTC and stamp is used as every event's
+// DTSTAMP. Recurrence rules and exclusions are not written.
+func Write(w io.Writer, name string, events []Event, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) {
+		// Continuation lines start with a space, which counts towards the limit
+		limit := maxLineOctets
+		for len(s) > limit {
+			cut := limit
+			// Do not split a UTF-8 sequence across lines
+			for cut > 0 && s[cut]&0xC0 == 0x80 {
+				cut--
+			}
+			bw.WriteString(s[:cut] + "\r\n ")
+			s = s[cut:]
+			limit = maxLineOctets - 1
+		}
+		bw.WriteString(s + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:" + prodID)
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	if name != "" {
+		line("X-WR-CALNAME:" + escape(name))
+	}
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + formatUTC(stamp))
+		if e.AllDay {
+			line("DTSTART;VALUE=DATE:" + e.Start.UTC().Format("20060102"))
+			line("DTEND;VALUE=DATE:" + e.End.UTC().Format("20060102"))
+		} else {
+			line("DTSTART:" + formatUTC(e.Start))
+			line("DTEND:" + formatUTC(e.End))
+		}
+		if e.Summary != "" {
+			line("SUMMARY:" + escape(e.Summary))
+		}
+		if e.Description != "" {
+			line("DESCRIPTION:" + escape(e.Description))
+		}
+		if e.Free {
+			line("TRANSP:TRANSPARENT")
+		}
+		if e.Cancelled {
+			line("STATUS:CANCELLED")
+		} else {
+			line("STATUS:CONFIRMED")
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
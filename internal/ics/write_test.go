@@ -0,0 +1,47 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteRoundTrips(t *testing.T) {
+	stamp := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []Event{
+		{UID: "b1@example.com", Summary: "Interview; round 1, onsite", Description: "Candidate: c@example.com\nRoom: 4", Start: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), End: time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)},
+		{UID: "b2@example.com", Summary: strings.Repeat("Ünïcode title ", 10), Start: time.Date(2026, 3, 2, 15, 0, 0, 0, time.FixedZone("CET", 3600)), End: time.Date(2026, 3, 2, 16, 0, 0, 0, time.FixedZone("CET", 3600))},
+		{UID: "b3@example.com", Summary: "Cancelled", Start: time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), End: time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC), Cancelled: true},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, "Team interviews", events, stamp); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Errorf("feed is not wrapped in a VCALENDAR:\n%s", out)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line of %d octets is not folded: %q", len(line), line)
+		}
+	}
+	if n := strings.Count(out, "DTSTAMP:20260301T120000Z"); n != len(events) {
+		t.Errorf("%d events stamped, want %d", n, len(events))
+	}
+
+	got, err := Parse(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(events) {
+		t.Fatalf("parsed %d events, want %d", len(got), len(events))
+	}
+	for i, e := range events {
+		g := got[i]
+		if g.UID != e.UID || g.Summary != e.Summary || g.Description != e.Description || !g.Start.Equal(e.Start) || !g.End.Equal(e.End) || g.Cancelled != e.Cancelled {
+			t.Errorf("event %d = %+v, want %+v", i, g, e)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+-- Track progress of asynchronous post-booking steps (calendar event creation, email)
+ALTER TABLE bookings
+    ADD COLUMN confirmation_state TEXT NOT NULL DEFAULT 'confirmed'
+    CHECK (confirmation_state IN ('confirmed', 'pending_event', 'event_created', 'failed'));
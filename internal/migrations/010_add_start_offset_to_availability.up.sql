@@ -0,0 +1,3 @@
+-- Shift the first slot of a rule's window by a lead-in (e.g. start at :05)
+ALTER TABLE availability_rules
+    ADD COLUMN start_offset_minutes INT NOT NULL DEFAULT 0 CHECK (start_offset_minutes >= 0);
@@ -0,0 +1,2 @@
+-- Tag availability rules by interview type (screen, onsite, ...) for slot filtering
+ALTER TABLE availability_rules ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
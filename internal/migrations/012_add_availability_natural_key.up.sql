@@ -0,0 +1,25 @@
+-- Identify a weekly rule by its natural key so provisioning can upsert rules.
+-- Collapse existing duplicates first, keeping the most recently updated row;
+-- the rows removed are copied to availability_rules_natural_key_duplicates.
+CREATE TABLE IF NOT EXISTS availability_rules_natural_key_duplicates (LIKE availability_rules);
+
+INSERT INTO availability_rules_natural_key_duplicates
+    SELECT a.* FROM availability_rules a
+    WHERE EXISTS (
+        SELECT 1 FROM availability_rules b
+        WHERE a.user_id = b.user_id
+          AND a.day_of_week = b.day_of_week
+          AND a.start_time = b.start_time
+          AND a.end_time = b.end_time
+          AND (a.updated_at, a.id) < (b.updated_at, b.id));
+
+DELETE FROM availability_rules a
+    USING availability_rules b
+    WHERE a.user_id = b.user_id
+      AND a.day_of_week = b.day_of_week
+      AND a.start_time = b.start_time
+      AND a.end_time = b.end_time
+      AND (a.updated_at, a.id) < (b.updated_at, b.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_availability_rules_natural_key
+    ON availability_rules (user_id, day_of_week, start_time, end_time);
@@ -0,0 +1,13 @@
+-- Short-lived tentative reservations that block a slot while a candidate
+-- completes a multi-step booking flow
+CREATE TABLE IF NOT EXISTS slot_holds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    start_at_utc TIMESTAMPTZ NOT NULL,
+    end_at_utc TIMESTAMPTZ NOT NULL,
+    token TEXT NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_slot_holds_user_expires ON slot_holds(user_id, expires_at);
@@ -0,0 +1,6 @@
+-- Link bookings synced from Google Calendar to their source event
+ALTER TABLE bookings ADD COLUMN google_event_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_google_event_id
+    ON bookings (google_event_id)
+    WHERE google_event_id IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Additional attendees for panel interviews; candidate_email stays the primary
+ALTER TABLE bookings ADD COLUMN attendees JSONB NOT NULL DEFAULT '[]'::jsonb;
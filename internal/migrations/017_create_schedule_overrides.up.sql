@@ -0,0 +1,22 @@
+-- Date-specific overrides of the weekly rules: blackouts (full or partial
+-- day), replacement hours and additional hours. Dates and times are UTC, like
+-- availability_rules.
+CREATE TABLE IF NOT EXISTS schedule_overrides (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('blackout', 'custom_hours', 'extra_hours')),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    start_time TIME,
+    end_time TIME,
+    slot_length_minutes INT CHECK (slot_length_minutes > 0),
+    title TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT now(),
+    CHECK (end_date >= start_date),
+    CHECK ((start_time IS NULL) = (end_time IS NULL)),
+    CHECK (start_time IS NULL OR end_time > start_time),
+    CHECK (type = 'blackout' OR (start_time IS NOT NULL AND slot_length_minutes IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_schedule_overrides_user_dates
+    ON schedule_overrides (user_id, start_date, end_date);
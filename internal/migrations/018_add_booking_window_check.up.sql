@@ -0,0 +1,6 @@
+-- Bookings must have a positive length of at most one day. NOT VALID skips
+-- checking rows written before the constraint existed; new writes are checked.
+ALTER TABLE bookings
+    ADD CONSTRAINT bookings_window_valid
+    CHECK (end_at_utc > start_at_utc AND end_at_utc - start_at_utc <= interval '24 hours')
+    NOT VALID;
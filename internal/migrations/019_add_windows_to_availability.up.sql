@@ -0,0 +1,4 @@
+-- Optional split windows within one weekday rule, e.g. 09:00-12:00 and
+-- 13:00-17:00. When non-empty, start_time/end_time hold the envelope of the
+-- windows and slots are generated only inside the windows.
+ALTER TABLE availability_rules ADD COLUMN windows JSONB NOT NULL DEFAULT '[]'::jsonb;
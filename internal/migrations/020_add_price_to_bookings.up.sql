@@ -0,0 +1,5 @@
+-- Optional price for paid bookings, in minor units of an ISO 4217 currency
+ALTER TABLE bookings
+    ADD COLUMN amount_cents BIGINT CHECK (amount_cents >= 0),
+    ADD COLUMN currency CHAR(3),
+    ADD CONSTRAINT bookings_price_complete CHECK ((amount_cents IS NULL) = (currency IS NULL));
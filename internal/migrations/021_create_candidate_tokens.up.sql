@@ -0,0 +1,12 @@
+-- Tokens giving a candidate read-only access to their own bookings. The token
+-- handed out is signed over the row id and normalized email and is not stored;
+-- revoking sets revoked_at. A candidate has at most one live token.
+CREATE TABLE IF NOT EXISTS candidate_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_candidate_tokens_live_email
+    ON candidate_tokens (email) WHERE revoked_at IS NULL;
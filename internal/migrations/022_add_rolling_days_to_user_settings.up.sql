@@ -0,0 +1,4 @@
+-- Rolling availability window: when positive, slots are only offered within
+-- this many days from now, whatever range is queried.
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS rolling_days INT NOT NULL DEFAULT 0 CHECK (rolling_days >= 0);
@@ -0,0 +1,8 @@
+-- Occurrences of a recurring booking share a recurrence_group_id so a series
+-- can be cancelled as a whole or from one occurrence onwards.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS recurrence_group_id UUID;
+
+CREATE INDEX IF NOT EXISTS bookings_recurrence_group_idx
+    ON bookings (recurrence_group_id, start_at_utc)
+    WHERE recurrence_group_id IS NOT NULL;
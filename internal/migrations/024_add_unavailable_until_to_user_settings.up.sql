@@ -0,0 +1,4 @@
+-- Vacation mode: no slots are offered and no bookings accepted before this
+-- instant. NULL means the user is available as usual.
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS unavailable_until TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+-- Localized slot titles keyed by lowercase locale tag, e.g. {"de": "Vorstellungsgespräch"}.
+-- title remains the fallback when no translation matches.
+ALTER TABLE availability_rules ADD COLUMN IF NOT EXISTS title_translations JSONB NOT NULL DEFAULT '{}'::jsonb;
@@ -0,0 +1,4 @@
+-- Region code (e.g. US, GB) whose public holidays are treated as blackout
+-- days in slot generation. NULL disables holiday blackouts.
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS holiday_region TEXT;
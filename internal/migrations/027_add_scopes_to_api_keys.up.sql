@@ -0,0 +1,4 @@
+-- Permissions granted to an API key, e.g. bookings:read. '*' grants every
+-- scope; existing keys keep full access.
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT '{*}';
@@ -0,0 +1,4 @@
+-- Snapshot of the availability rule a booking's slot came from, so later rule
+-- edits don't rewrite what the candidate booked under.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS rule_snapshot JSONB;
@@ -0,0 +1,12 @@
+-- Append-only history of booking changes, written by the audit booking hook.
+CREATE TABLE IF NOT EXISTS booking_audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL,
+    action TEXT NOT NULL,
+    actor TEXT NOT NULL DEFAULT '',
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS booking_audit_log_booking_idx
+    ON booking_audit_log (booking_id, created_at);
@@ -0,0 +1,5 @@
+-- Who made the booking: the candidate themselves or a recruiter on their
+-- behalf, with the email of the API key used.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS booked_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS booked_by_email TEXT NOT NULL DEFAULT '';
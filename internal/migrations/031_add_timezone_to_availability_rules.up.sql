@@ -0,0 +1,4 @@
+-- IANA timezone a rule's times are written in; empty keeps the original UTC
+-- interpretation.
+ALTER TABLE availability_rules
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- Minutes of padding a rule keeps free before and after existing bookings.
+ALTER TABLE availability_rules
+    ADD COLUMN IF NOT EXISTS buffer_minutes INT NOT NULL DEFAULT 0;
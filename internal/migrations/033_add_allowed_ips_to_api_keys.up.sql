@@ -0,0 +1,4 @@
+-- Source addresses an API key may be used from, as CIDRs. An empty list
+-- allows any address.
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS allowed_ips TEXT[] NOT NULL DEFAULT '{}';
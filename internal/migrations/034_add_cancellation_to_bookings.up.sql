@@ -0,0 +1,5 @@
+-- Why a booking was cancelled and the email of the API key that cancelled
+-- it; both are empty for live bookings and cancellations without a caller.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS cancellation_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cancelled_by TEXT NOT NULL DEFAULT '';
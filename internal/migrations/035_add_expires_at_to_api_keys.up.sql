@@ -0,0 +1,3 @@
+-- When an API key stops being accepted; NULL keys never expire.
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
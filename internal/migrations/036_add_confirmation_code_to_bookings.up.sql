@@ -0,0 +1,7 @@
+-- Short human-friendly code candidates quote instead of the booking id.
+-- Bookings made before codes existed have none.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS confirmation_code TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS bookings_confirmation_code_idx
+    ON bookings (confirmation_code);
@@ -0,0 +1,22 @@
+-- Give bookings made before confirmation codes existed a code of their own,
+-- drawn from the same alphabet (Crockford base32) and length (8) new
+-- bookings use by default. A code that collides is simply drawn again.
+DO $$
+DECLARE
+    booking_id UUID;
+    code TEXT;
+BEGIN
+    FOR booking_id IN SELECT id FROM bookings WHERE confirmation_code IS NULL LOOP
+        LOOP
+            SELECT string_agg(substr('0123456789ABCDEFGHJKMNPQRSTVWXYZ', 1 + floor(random() * 32)::int, 1), '')
+              INTO code
+              FROM generate_series(1, 8);
+            BEGIN
+                UPDATE bookings SET confirmation_code = code WHERE id = booking_id;
+                EXIT;
+            EXCEPTION WHEN unique_violation THEN
+                -- taken; draw another
+            END;
+        END LOOP;
+    END LOOP;
+END $$;
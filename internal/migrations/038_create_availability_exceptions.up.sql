@@ -0,0 +1,22 @@
+-- One-date availability exceptions: a blocked row removes its window (the
+-- whole day when start_time/end_time are NULL) from the user's slots, any
+-- other row adds its window as extra slots of slot_length_minutes.
+-- Exceptions made before this table existed were stored as one-day
+-- schedule_overrides; they keep applying and are managed as overrides.
+CREATE TABLE IF NOT EXISTS availability_exceptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    date DATE NOT NULL,
+    start_time TIME,
+    end_time TIME,
+    blocked BOOLEAN NOT NULL,
+    slot_length_minutes INT,
+    title TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((start_time IS NULL) = (end_time IS NULL)),
+    CHECK (start_time IS NULL OR end_time > start_time),
+    CHECK (blocked OR (start_time IS NOT NULL AND slot_length_minutes > 0))
+);
+
+CREATE INDEX IF NOT EXISTS availability_exceptions_user_date_idx
+    ON availability_exceptions (user_id, date);
@@ -0,0 +1,11 @@
+-- Pending Google OAuth states. A row is written when the consent flow starts
+-- and deleted by the callback that uses it, so a state works at most once.
+CREATE TABLE IF NOT EXISTS oauth_states (
+    nonce TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS oauth_states_expires_at_idx
+    ON oauth_states (expires_at);
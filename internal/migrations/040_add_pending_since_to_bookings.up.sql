@@ -0,0 +1,13 @@
+-- Bookings awaiting approval are 'pending' from pending_since until they are
+-- approved ('confirmed') or left unapproved past the approval TTL ('expired').
+-- A pending booking holds its slot like a confirmed one.
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS pending_since TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS bookings_pending_since_idx
+    ON bookings (pending_since)
+    WHERE status = 'pending';
+
+DROP INDEX IF EXISTS ux_bookings_user_start_confirmed;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_bookings_user_start_live
+    ON bookings (user_id, start_at_utc)
+    WHERE status IN ('confirmed', 'pending');
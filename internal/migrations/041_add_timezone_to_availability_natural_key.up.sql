@@ -0,0 +1,6 @@
+-- Rules with the same local times in different timezones are different
+-- rules, so the timezone is part of the natural key. Widening the key cannot
+-- create duplicates, so no rows are removed.
+DROP INDEX IF EXISTS ux_availability_rules_natural_key;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_availability_rules_natural_key
+    ON availability_rules (user_id, day_of_week, start_time, end_time, timezone);
@@ -6,16 +6,39 @@ import (
 )
 
 type AvailabilityRule struct {
-	ID             string    `json:"id"`
-	UserID         string    `json:"user_id"`
-	DayOfWeek      int       `json:"day_of_week"`
-	StartTime      string    `json:"start_time"`
-	EndTime        string    `json:"end_time"`
-	SlotLengthMins int       `json:"slot_length_minutes"`
-	Title          string    `json:"title,omitempty"`
-	Available      bool      `json:"available"`
-	CreatedAt      time.Time `json:"created_at_utc,omitempty"`
-	UpdatedAt      time.Time `json:"updated_at_utc,omitempty"`
+	ID             string `json:"id"`
+	UserID         string `json:"user_id"`
+	DayOfWeek      int    `json:"day_of_week"`
+	StartTime      string `json:"start_time"`
+	EndTime        string `json:"end_time"`
+	SlotLengthMins int    `json:"slot_length_minutes"`
+	// StartOffsetMins delays the first slot within the window, e.g. 5 to
+	// start slots at :05 and leave the interviewer setup time.
+	StartOffsetMins int `json:"start_offset_minutes,omitempty"`
+	// BufferMins keeps this many minutes free on both sides of a booking:
+	// the rule's slots starting or ending that close to one are not offered.
+	BufferMins int      `json:"buffer_minutes,omitempty"`
+	Title      string   `json:"title,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	// TitleTranslations maps lowercase locale tags such as "de" or "pt-br"
+	// to a localized Title.
+	TitleTranslations map[string]string `json:"title_translations,omitempty"`
+	// Timezone is the IANA zone (e.g. "America/New_York") StartTime,
+	// EndTime and Windows are local to; empty means UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// Windows splits the day into several windows, e.g. 09:00-12:00 and
+	// 13:00-17:00. When set, StartTime and EndTime are derived as their
+	// envelope and slots are only generated inside the windows.
+	Windows   []TimeWindow `json:"windows,omitempty"`
+	Available bool         `json:"available"`
+	CreatedAt time.Time    `json:"created_at_utc,omitempty"`
+	UpdatedAt time.Time    `json:"updated_at_utc,omitempty"`
+}
+
+// TimeWindow is a UTC time-of-day range ("15:04") within an availability rule.
+type TimeWindow struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
 }
 
 // MarshalJSON ensures timestamps are serialized in UTC
@@ -32,27 +55,139 @@ func (a AvailabilityRule) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// AvailabilityDocumentVersion is the current AvailabilityDocument format.
+// Version 2 added exceptions; version 1 documents carry rules only.
+const AvailabilityDocumentVersion = 2
+
+// AvailabilityDocument is a portable snapshot of a user's schedule used to
+// export and import availability between users or environments. Rule and
+// exception ids, owners and timestamps are ignored on import.
+type AvailabilityDocument struct {
+	Version    int                     `json:"version"`
+	ExportedAt time.Time               `json:"exported_at_utc"`
+	UserID     string                  `json:"user_id,omitempty"`
+	Rules      []AvailabilityRule      `json:"rules"`
+	Exceptions []AvailabilityException `json:"exceptions"`
+}
+
 type Booking struct {
-	ID             string    `json:"id"`
-	UserID         string    `json:"user_id"`
-	CandidateEmail string    `json:"candidate_email"`
-	StartAtUTC     time.Time `json:"start_at_utc"`
-	EndAtUTC       time.Time `json:"end_at_utc"`
-	Status         string    `json:"status"`
-	Source         string    `json:"source,omitempty"`
-	Type           string    `json:"type,omitempty"`
-	Description    string    `json:"description,omitempty"`
-	Title          string    `json:"title,omitempty"`
-	CreatedAt      time.Time `json:"created_at_utc,omitempty"`
+	ID                string            `json:"id"`
+	UserID            string            `json:"user_id"`
+	CandidateEmail    string            `json:"candidate_email"`
+	StartAtUTC        time.Time         `json:"start_at_utc"`
+	EndAtUTC          time.Time         `json:"end_at_utc"`
+	Status            string            `json:"status"`
+	Source            string            `json:"source,omitempty"`
+	Type              string            `json:"type,omitempty"`
+	Description       string            `json:"description,omitempty"`
+	Title             string            `json:"title,omitempty"`
+	CreatedAt         time.Time         `json:"created_at_utc,omitempty"`
+	ConfirmationState string            `json:"confirmation_state,omitempty"`
+	GoogleEventID     string            `json:"google_event_id,omitempty"` // set for bookings synced from Google Calendar
+	Attendees         []BookingAttendee `json:"attendees,omitempty"`
+	// AmountCents and Currency price a paid booking; both are set or neither.
+	AmountCents *int64 `json:"amount_cents,omitempty"`
+	Currency    string `json:"currency,omitempty"`
+	// RecurrenceGroupID is shared by all occurrences of a recurring booking.
+	RecurrenceGroupID string `json:"recurrence_group_id,omitempty"`
+	// RuleSnapshot records the rule that produced the booked slot as it was
+	// at booking time; nil for slots added by a schedule override.
+	RuleSnapshot *BookingRuleSnapshot `json:"rule_snapshot,omitempty"`
+	// BookedBy is BookedByCandidate or BookedByRecruiter, and BookedByEmail
+	// the caller's API key email; both are empty for bookings made without
+	// a caller (e.g. synced from Google Calendar).
+	BookedBy      string `json:"booked_by,omitempty"`
+	BookedByEmail string `json:"booked_by_email,omitempty"`
+	// CancellationReason is the reason given when the booking was cancelled,
+	// and CancelledBy the email of the API key that cancelled it.
+	CancellationReason string `json:"cancellation_reason,omitempty"`
+	CancelledBy        string `json:"cancelled_by,omitempty"`
+	// ConfirmationCode is the short code candidates quote for the booking.
+	ConfirmationCode string `json:"confirmation_code,omitempty"`
+	// PendingSince is when a booking awaiting approval (status "pending")
+	// was made; it expires once pending longer than the approval TTL.
+	PendingSince *time.Time `json:"pending_since_utc,omitempty"`
 }
 
+// Booking initiators.
+const (
+	BookedByCandidate = "candidate"
+	BookedByRecruiter = "recruiter"
+)
+
+// BookingRuleSnapshot is the part of an availability rule copied onto a
+// booking when it is created.
+type BookingRuleSnapshot struct {
+	RuleID string   `json:"rule_id"`
+	Title  string   `json:"title,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+// Booking audit actions.
+const (
+	BookingAuditCreated      = "created"
+	BookingAuditRescheduled  = "rescheduled"
+	BookingAuditStateChanged = "state_changed"
+	BookingAuditCancelled    = "cancelled"
+	BookingAuditExpired      = "expired"
+)
+
+// BookingAuditEntry is one change in a booking's history. Actor is the email
+// of the API key that made the change, empty for background work.
+type BookingAuditEntry struct {
+	ID        string         `json:"id"`
+	BookingID string         `json:"booking_id"`
+	Action    string         `json:"action"`
+	Actor     string         `json:"actor,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+	CreatedAt time.Time      `json:"created_at_utc"`
+}
+
+// BookingAttendee is an additional participant in a booking, such as a second
+// interviewer on a panel.
+type BookingAttendee struct {
+	Email    string `json:"email"`
+	Role     string `json:"role,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// CandidateToken grants a candidate, identified by normalized email,
+// read-only access to their bookings until revoked.
+type CandidateToken struct {
+	ID        string     `json:"id"`
+	Email     string     `json:"email"`
+	CreatedAt time.Time  `json:"created_at_utc"`
+	RevokedAt *time.Time `json:"revoked_at_utc,omitempty"`
+}
+
+// BookingAggregates are a user's booking counts over a period. BusiestWeekday
+// is the UTC weekday (0 = Sunday) with the most confirmed bookings, nil when
+// there are none.
+type BookingAggregates struct {
+	Total               int
+	Confirmed           int
+	Cancelled           int
+	AvgDurationMins     float64
+	BusiestWeekday      *int
+	BusiestWeekdayCount int
+}
+
+// Booking confirmation states, advanced by the reconciliation worker as the
+// asynchronous post-booking steps complete.
+const (
+	ConfirmationStateConfirmed    = "confirmed"
+	ConfirmationStatePendingEvent = "pending_event"
+	ConfirmationStateEventCreated = "event_created"
+	ConfirmationStateFailed       = "failed"
+)
+
 // MarshalJSON ensures times are serialized in UTC
 func (b Booking) MarshalJSON() ([]byte, error) {
 	type Alias Booking
 	return json.Marshal(&struct {
-		StartAtUTC    time.Time `json:"start_at_utc"`
-		EndAtUTC      time.Time `json:"end_at_utc"`
-		CreatedAtUTC  time.Time `json:"created_at_utc,omitempty"`
+		StartAtUTC   time.Time `json:"start_at_utc"`
+		EndAtUTC     time.Time `json:"end_at_utc"`
+		CreatedAtUTC time.Time `json:"created_at_utc,omitempty"`
 		*Alias
 	}{
 		StartAtUTC:   b.StartAtUTC.UTC(),
@@ -62,29 +197,108 @@ func (b Booking) MarshalJSON() ([]byte, error) {
 	})
 }
 
-type APIKey struct {
+// SlotHold is a short-lived tentative reservation of a slot. The token is only
+// returned to the caller that created the hold and is needed to convert it
+// into a booking.
+type SlotHold struct {
 	ID         string    `json:"id"`
-	Email      string    `json:"email"`
-	KeyHash    string    `json:"-"` // Never expose hash in JSON
+	UserID     string    `json:"user_id"`
+	StartAtUTC time.Time `json:"start_at_utc"`
+	EndAtUTC   time.Time `json:"end_at_utc"`
+	Token      string    `json:"hold_token,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at_utc"`
 	CreatedAt  time.Time `json:"created_at_utc,omitempty"`
+}
+
+type APIKey struct {
+	ID         string     `json:"id"`
+	Email      string     `json:"email"`
+	KeyHash    string     `json:"-"` // Never expose hash in JSON
+	CreatedAt  time.Time  `json:"created_at_utc,omitempty"`
 	LastUsedAt *time.Time `json:"last_used_at_utc,omitempty"`
+	Scopes     []string   `json:"scopes"`                   // "*" grants every scope
+	AllowedIPs []string   `json:"allowed_ips"`              // CIDRs; empty allows any address
+	ExpiresAt  *time.Time `json:"expires_at_utc,omitempty"` // nil never expires
 }
 
 // MarshalJSON ensures timestamps are serialized in UTC
 func (a APIKey) MarshalJSON() ([]byte, error) {
 	type Alias APIKey
-	var lastUsedAtUTC *time.Time
+	var lastUsedAtUTC, expiresAtUTC *time.Time
 	if a.LastUsedAt != nil {
 		utc := a.LastUsedAt.UTC()
 		lastUsedAtUTC = &utc
 	}
+	if a.ExpiresAt != nil {
+		utc := a.ExpiresAt.UTC()
+		expiresAtUTC = &utc
+	}
 	return json.Marshal(&struct {
 		CreatedAtUTC  time.Time  `json:"created_at_utc,omitempty"`
 		LastUsedAtUTC *time.Time `json:"last_used_at_utc,omitempty"`
+		ExpiresAtUTC  *time.Time `json:"expires_at_utc,omitempty"`
 		*Alias
 	}{
 		CreatedAtUTC:  a.CreatedAt.UTC(),
 		LastUsedAtUTC: lastUsedAtUTC,
+		ExpiresAtUTC:  expiresAtUTC,
 		Alias:         (*Alias)(&a),
 	})
 }
+
+// UserSettings holds per-user preferences. Empty fields mean the default.
+type UserSettings struct {
+	UserID            string `json:"user_id"`
+	DefaultCalendarID string `json:"default_calendar_id,omitempty"`
+	// RollingDays, when positive, limits offered slots to the next this many
+	// days from now.
+	RollingDays int `json:"rolling_days"`
+	// UnavailableUntil, when set, hides all slots and rejects bookings
+	// starting before it (vacation mode).
+	UnavailableUntil *time.Time `json:"unavailable_until_utc,omitempty"`
+	// HolidayRegion names a built-in holiday calendar (e.g. "US") whose
+	// holidays block the whole day.
+	HolidayRegion string    `json:"holiday_region,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at_utc,omitempty"`
+}
+
+// Schedule override types, in order of precedence: a blackout removes time
+// even when other overrides add it; custom_hours replaces the weekly rules for
+// its dates; extra_hours adds time on top of whatever else applies.
+const (
+	OverrideBlackout    = "blackout"
+	OverrideCustomHours = "custom_hours"
+	OverrideExtraHours  = "extra_hours"
+)
+
+// AvailabilityException changes availability on one calendar date: Blocked
+// removes StartTime-EndTime (the whole day when both are empty), otherwise
+// the window is added as extra availability cut into SlotLengthMins slots.
+type AvailabilityException struct {
+	ID             string    `json:"id"`
+	UserID         string    `json:"user_id"`
+	Date           string    `json:"date"`
+	StartTime      string    `json:"start_time,omitempty"`
+	EndTime        string    `json:"end_time,omitempty"`
+	Blocked        bool      `json:"blocked"`
+	SlotLengthMins int       `json:"slot_length_minutes,omitempty"`
+	Title          string    `json:"title,omitempty"`
+	CreatedAt      time.Time `json:"created_at_utc,omitempty"`
+}
+
+// ScheduleOverride changes a user's availability on specific UTC dates.
+// EndDate defaults to StartDate for a single day. A blackout without times
+// covers the whole day; custom_hours and extra_hours need a time window and
+// slot length.
+type ScheduleOverride struct {
+	ID             string    `json:"id"`
+	UserID         string    `json:"user_id"`
+	Type           string    `json:"type"`
+	StartDate      string    `json:"start_date"`
+	EndDate        string    `json:"end_date,omitempty"`
+	StartTime      string    `json:"start_time,omitempty"`
+	EndTime        string    `json:"end_time,omitempty"`
+	SlotLengthMins int       `json:"slot_length_minutes,omitempty"`
+	Title          string    `json:"title,omitempty"`
+	CreatedAt      time.Time `json:"created_at_utc,omitempty"`
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrConflict is returned by repositories when a write violates a unique
+// constraint, so services can report a conflict without inspecting driver errors.
+var ErrConflict = errors.New("conflicts with an existing record")
+
+// ErrConfirmationCodeTaken is returned by InsertBooking when another booking
+// already has the new booking's confirmation code. Nothing is written, so
+// the caller can retry with another code in the same transaction.
+var ErrConfirmationCodeTaken = errors.New("confirmation code taken")
+
+// ErrInvalidBookingWindow is returned when a booking's end is not after its
+// start or the booking is longer than MaxBookingDuration.
+var ErrInvalidBookingWindow = errors.New("invalid booking window")
+
+// MaxBookingDuration is the longest booking the storage layer accepts,
+// mirrored by the bookings_window_valid check constraint.
+const MaxBookingDuration = 24 * time.Hour
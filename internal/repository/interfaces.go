@@ -18,26 +18,90 @@ type Querier interface {
 
 type AvailabilityRepository interface {
 	InsertAvailabilityRule(ctx context.Context, q Querier, r *models.AvailabilityRule) error
+	UpsertAvailabilityRule(ctx context.Context, q Querier, r *models.AvailabilityRule) error
 	ListAvailabilityRules(ctx context.Context, q Querier, userID string) ([]models.AvailabilityRule, error)
 	UpdateAvailabilityRule(ctx context.Context, q Querier, userID, ruleID string, r *models.AvailabilityRule) (string, error)
 	GetAvailabilityRule(ctx context.Context, q Querier, userID, ruleID string) (*models.AvailabilityRule, error)
+	DeleteAvailabilityRules(ctx context.Context, q Querier, userID string) (int64, error)
+	CountAvailabilityRules(ctx context.Context, q Querier, userID string) (int, error)
 }
 
 type BookingRepository interface {
 	ListBookingsInRange(ctx context.Context, q Querier, userID string, from, to AppTime) ([]models.Booking, error)
-	ListBookings(ctx context.Context, q Querier, userID string, from, to AppTime, filtered bool) ([]models.Booking, error)
-	CheckExistingBookingAtStart(ctx context.Context, q Querier, userID string, start AppTime) (string, error)
+	ListBookings(ctx context.Context, q Querier, userID string, from, to AppTime, filtered bool, limit, offset int) ([]models.Booking, error)
+	CountBookings(ctx context.Context, q Querier, userID string, from, to AppTime, filtered bool) (int, error)
+	ListBookingsAfter(ctx context.Context, q Querier, userID string, afterStart AppTime, afterID string, limit int) ([]models.Booking, error)
+	ListUpcomingBookings(ctx context.Context, q Querier, userID string, since, afterStart AppTime, afterID string, limit int) ([]models.Booking, error)
+	CheckOverlappingBooking(ctx context.Context, q Querier, userID string, start, end AppTime, excludeID string) (string, error)
+	FindCandidateOverlap(ctx context.Context, q Querier, candidateEmail string, start, end AppTime, excludeID string) (string, error)
 	InsertBooking(ctx context.Context, q Querier, b *models.Booking) (string, error)
+	GetBookingByConfirmationCode(ctx context.Context, q Querier, code string) (*models.Booking, error)
 	GetBookingStatus(ctx context.Context, q Querier, id string) (string, error)
-	CancelBooking(ctx context.Context, q Querier, id string) (int64, error)
+	GetBooking(ctx context.Context, q Querier, id string) (*models.Booking, error)
+	GetBookingByGoogleEventID(ctx context.Context, q Querier, eventID string) (*models.Booking, error)
+	UpdateConfirmationState(ctx context.Context, q Querier, id, from, to string) (int64, error)
+	ReassignBooking(ctx context.Context, q Querier, id, toUserID string) (int64, error)
+	UpdateBookingTimes(ctx context.Context, q Querier, id string, start, end AppTime) (int64, error)
+	CancelBooking(ctx context.Context, q Querier, id, reason, cancelledBy string) (int64, error)
+	CancelRecurrenceGroup(ctx context.Context, q Querier, groupID string, from AppTime, reason, cancelledBy string) ([]string, error)
+	AggregateBookings(ctx context.Context, q Querier, userID string, from, to AppTime) (*models.BookingAggregates, error)
+	ListAllBookings(ctx context.Context, q Querier, from, to AppTime, status string, afterStart AppTime, afterID string, limit int) ([]models.Booking, error)
+	PurgeCancelledBefore(ctx context.Context, q Querier, cutoff AppTime) ([]string, error)
+	ApproveBooking(ctx context.Context, q Querier, id string) (int64, error)
+	ExpirePendingBefore(ctx context.Context, q Querier, cutoff AppTime) ([]string, error)
+	ListBookingsByCandidate(ctx context.Context, q Querier, candidateEmail string) ([]models.Booking, error)
+}
+
+type BookingAuditRepository interface {
+	InsertAuditEntry(ctx context.Context, q Querier, e *models.BookingAuditEntry) error
+	ListAuditEntries(ctx context.Context, q Querier, bookingID string) ([]models.BookingAuditEntry, error)
+	DeleteAuditEntries(ctx context.Context, q Querier, bookingIDs []string) (int64, error)
+}
+
+type SlotHoldRepository interface {
+	InsertHold(ctx context.Context, q Querier, h *models.SlotHold) error
+	ListActiveHolds(ctx context.Context, q Querier, userID string, from, to AppTime) ([]models.SlotHold, error)
+	FindActiveHoldOverlap(ctx context.Context, q Querier, userID string, start, end AppTime) (string, error)
+	ConsumeHold(ctx context.Context, q Querier, userID, token string, start, end AppTime) (int64, error)
+	DeleteExpiredHolds(ctx context.Context, q Querier, userID string) (int64, error)
+}
+
+type ScheduleOverrideRepository interface {
+	InsertOverride(ctx context.Context, q Querier, o *models.ScheduleOverride) error
+	ListOverridesInRange(ctx context.Context, q Querier, userID string, fromDate, toDate string) ([]models.ScheduleOverride, error)
+	DeleteOverride(ctx context.Context, q Querier, userID, id string) (int64, error)
+}
+
+type AvailabilityExceptionRepository interface {
+	InsertException(ctx context.Context, q Querier, e *models.AvailabilityException) error
+	ListExceptionsInRange(ctx context.Context, q Querier, userID string, fromDate, toDate string) ([]models.AvailabilityException, error)
+	DeleteException(ctx context.Context, q Querier, userID, id string) (int64, error)
 }
 
 type APIKeyRepository interface {
-	CreateAPIKey(ctx context.Context, q Querier, email, keyHash string) (*models.APIKey, error)
+	CreateAPIKey(ctx context.Context, q Querier, email, keyHash string, scopes, allowedIPs []string, expiresAt AppTime) (*models.APIKey, error)
 	GetAPIKeyByHash(ctx context.Context, q Querier, keyHash string) (*models.APIKey, error)
 	GetAPIKeyByEmail(ctx context.Context, q Querier, email string) (*models.APIKey, error)
-	UpdateAPIKeyHash(ctx context.Context, q Querier, email, keyHash string) error
+	GetAPIKeyByID(ctx context.Context, q Querier, id string) (*models.APIKey, error)
+	UpdateAPIKeyHash(ctx context.Context, q Querier, email, keyHash string, scopes, allowedIPs []string, expiresAt AppTime) error
 	UpdateLastUsed(ctx context.Context, q Querier, keyHash string) error
+	ListAPIKeysExpiringBetween(ctx context.Context, q Querier, from, to AppTime) ([]models.APIKey, error)
+}
+
+type OAuthStateRepository interface {
+	InsertOAuthState(ctx context.Context, q Querier, nonce, userID string, expiresAt AppTime) error
+	ConsumeOAuthState(ctx context.Context, q Querier, nonce string, now AppTime) (string, error)
+}
+
+type CandidateTokenRepository interface {
+	EnsureCandidateToken(ctx context.Context, q Querier, email string) (*models.CandidateToken, bool, error)
+	GetCandidateToken(ctx context.Context, q Querier, id string) (*models.CandidateToken, error)
+	RevokeCandidateToken(ctx context.Context, q Querier, id string) (int64, error)
+}
+
+type UserSettingsRepository interface {
+	GetUserSettings(ctx context.Context, q Querier, userID string) (*models.UserSettings, error)
+	UpsertUserSettings(ctx context.Context, q Querier, s *models.UserSettings) error
 }
 
 // AppTime is a lightweight alias to avoid importing time here; implemented in impl files.
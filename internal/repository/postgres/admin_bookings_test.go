@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestListAllBookingsSpansUsers(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewBookingRepo()
+	u1, u2 := "11111111-1111-1111-1111-111111111111", "22222222-2222-2222-2222-222222222222"
+	monday := time.Date(2030, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	insert := func(user string, day int) string {
+		start := monday.AddDate(0, 0, day)
+		id, err := repo.InsertBooking(ctx, pool, &models.Booking{UserID: user, CandidateEmail: "c@example.com", StartAtUTC: start, EndAtUTC: start.Add(30 * time.Minute)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	first, second, cancelled, later := insert(u1, 0), insert(u2, 1), insert(u1, 2), insert(u2, 8)
+	if _, err := repo.CancelBooking(ctx, pool, cancelled, "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	from, to := monday.AddDate(0, 0, -1), monday.AddDate(0, 0, 7)
+	cases := []struct {
+		name     string
+		from, to any
+		status   string
+		want     []string
+	}{
+		{"all", nil, nil, "", []string{first, second, cancelled, later}},
+		{"range", from, to, "", []string{first, second, cancelled}},
+		{"range and status", from, to, "confirmed", []string{first, second}},
+		{"cancelled only", nil, nil, "cancelled", []string{cancelled}},
+	}
+	for _, tc := range cases {
+		got, err := repo.ListAllBookings(ctx, pool, tc.from, tc.to, tc.status, nil, "", 10)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if len(got) != len(tc.want) {
+			t.Fatalf("%s: got %d bookings, want %d", tc.name, len(got), len(tc.want))
+		}
+		for i, b := range got {
+			if b.ID != tc.want[i] {
+				t.Errorf("%s: booking %d = %s, want %s", tc.name, i, b.ID, tc.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"scheduler-service/internal/repository"
+)
+
+// failingQuerier fails every statement with err.
+type failingQuerier struct {
+	err error
+}
+
+func (q failingQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return nil, q.err
+}
+
+func (q failingQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return failingRow{q.err}
+}
+
+func (q failingQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, q.err
+}
+
+type failingRow struct {
+	err error
+}
+
+func (r failingRow) Scan(dest ...any) error { return r.err }
+
+func TestAPIKeyWritesTranslateConstraintErrors(t *testing.T) {
+	repo := NewAPIKeyRepo()
+	ctx := context.Background()
+	other := errors.New("connection reset")
+	cases := []struct {
+		name         string
+		err          error
+		wantConflict bool
+	}{
+		{"unique violation", &pgconn.PgError{Code: "23505", ConstraintName: "api_keys_email_key"}, true},
+		{"not-null violation", &pgconn.PgError{Code: "23502"}, false},
+		{"driver error", other, false},
+	}
+	writes := map[string]func(repository.Querier) error{
+		"CreateAPIKey": func(q repository.Querier) error {
+			_, err := repo.CreateAPIKey(ctx, q, "a@example.com", "hash", nil, nil, nil)
+			return err
+		},
+		"UpdateAPIKeyHash": func(q repository.Querier) error {
+			return repo.UpdateAPIKeyHash(ctx, q, "a@example.com", "hash", nil, nil, nil)
+		},
+	}
+	for _, tc := range cases {
+		for name, write := range writes {
+			err := write(failingQuerier{tc.err})
+			if got := errors.Is(err, repository.ErrConflict); got != tc.wantConflict {
+				t.Errorf("%s, %s: err = %v, conflict %v, want %v", name, tc.name, err, got, tc.wantConflict)
+			}
+			if !tc.wantConflict && !errors.Is(err, tc.err) {
+				t.Errorf("%s, %s: err = %v, want the original error", name, tc.name, err)
+			}
+		}
+	}
+}
@@ -15,30 +15,36 @@ func NewAPIKeyRepo() *APIKeyRepo {
 	return &APIKeyRepo{}
 }
 
-func (r *APIKeyRepo) CreateAPIKey(ctx context.Context, q repository.Querier, email, keyHash string) (*models.APIKey, error) {
-	query := `INSERT INTO api_keys (id, email, key_hash, created_at)
-		VALUES (gen_random_uuid(), $1, $2, now())
-		RETURNING id, email, key_hash, created_at, last_used_at`
-	
+// CreateAPIKey stores a key with the given scopes, allowed source CIDRs and
+// expiry; nil scopes grant full access, nil allowedIPs allow any address and
+// a nil expiresAt never expires.
+func (r *APIKeyRepo) CreateAPIKey(ctx context.Context, q repository.Querier, email, keyHash string, scopes, allowedIPs []string, expiresAt repository.AppTime) (*models.APIKey, error) {
+	query := `INSERT INTO api_keys (id, email, key_hash, scopes, allowed_ips, expires_at, created_at)
+		VALUES (gen_random_uuid(), $1, $2, COALESCE($3::text[], '{*}'), COALESCE($4::text[], '{}'), $5, now())
+		RETURNING id, email, key_hash, created_at, last_used_at, scopes, allowed_ips, expires_at`
+
 	var apiKey models.APIKey
-	err := q.QueryRow(ctx, query, email, keyHash).Scan(
+	err := q.QueryRow(ctx, query, email, keyHash, scopes, allowedIPs, expiresAt).Scan(
 		&apiKey.ID,
 		&apiKey.Email,
 		&apiKey.KeyHash,
 		&apiKey.CreatedAt,
 		&apiKey.LastUsedAt,
+		&apiKey.Scopes,
+		&apiKey.AllowedIPs,
+		&apiKey.ExpiresAt,
 	)
 	if err != nil {
-		return nil, err
+		return nil, translateConstraintError(err)
 	}
 	return &apiKey, nil
 }
 
 func (r *APIKeyRepo) GetAPIKeyByHash(ctx context.Context, q repository.Querier, keyHash string) (*models.APIKey, error) {
-	query := `SELECT id, email, key_hash, created_at, last_used_at
+	query := `SELECT id, email, key_hash, created_at, last_used_at, scopes, allowed_ips, expires_at
 		FROM api_keys
 		WHERE key_hash = $1`
-	
+
 	var apiKey models.APIKey
 	err := q.QueryRow(ctx, query, keyHash).Scan(
 		&apiKey.ID,
@@ -46,6 +52,9 @@ func (r *APIKeyRepo) GetAPIKeyByHash(ctx context.Context, q repository.Querier,
 		&apiKey.KeyHash,
 		&apiKey.CreatedAt,
 		&apiKey.LastUsedAt,
+		&apiKey.Scopes,
+		&apiKey.AllowedIPs,
+		&apiKey.ExpiresAt,
 	)
 	if err != nil {
 		return nil, err
@@ -54,10 +63,10 @@ func (r *APIKeyRepo) GetAPIKeyByHash(ctx context.Context, q repository.Querier,
 }
 
 func (r *APIKeyRepo) GetAPIKeyByEmail(ctx context.Context, q repository.Querier, email string) (*models.APIKey, error) {
-	query := `SELECT id, email, key_hash, created_at, last_used_at
+	query := `SELECT id, email, key_hash, created_at, last_used_at, scopes, allowed_ips, expires_at
 		FROM api_keys
 		WHERE email = $1`
-	
+
 	var apiKey models.APIKey
 	err := q.QueryRow(ctx, query, email).Scan(
 		&apiKey.ID,
@@ -65,6 +74,9 @@ func (r *APIKeyRepo) GetAPIKeyByEmail(ctx context.Context, q repository.Querier,
 		&apiKey.KeyHash,
 		&apiKey.CreatedAt,
 		&apiKey.LastUsedAt,
+		&apiKey.Scopes,
+		&apiKey.AllowedIPs,
+		&apiKey.ExpiresAt,
 	)
 	if err != nil && err != pgx.ErrNoRows {
 		return nil, err
@@ -75,21 +87,81 @@ func (r *APIKeyRepo) GetAPIKeyByEmail(ctx context.Context, q repository.Querier,
 	return &apiKey, nil
 }
 
-func (r *APIKeyRepo) UpdateAPIKeyHash(ctx context.Context, q repository.Querier, email, keyHash string) error {
+// GetAPIKeyByID returns nil, nil when no key has the given id.
+func (r *APIKeyRepo) GetAPIKeyByID(ctx context.Context, q repository.Querier, id string) (*models.APIKey, error) {
+	query := `SELECT id, email, key_hash, created_at, last_used_at, scopes, allowed_ips, expires_at
+		FROM api_keys
+		WHERE id = $1`
+
+	var apiKey models.APIKey
+	err := q.QueryRow(ctx, query, id).Scan(
+		&apiKey.ID,
+		&apiKey.Email,
+		&apiKey.KeyHash,
+		&apiKey.CreatedAt,
+		&apiKey.LastUsedAt,
+		&apiKey.Scopes,
+		&apiKey.AllowedIPs,
+		&apiKey.ExpiresAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// UpdateAPIKeyHash replaces the key, its scopes, its allowed source CIDRs
+// and its expiry, with the same defaults as CreateAPIKey.
+func (r *APIKeyRepo) UpdateAPIKeyHash(ctx context.Context, q repository.Querier, email, keyHash string, scopes, allowedIPs []string, expiresAt repository.AppTime) error {
 	query := `UPDATE api_keys
-		SET key_hash = $1
+		SET key_hash = $1, scopes = COALESCE($3::text[], '{*}'), allowed_ips = COALESCE($4::text[], '{}'), expires_at = $5
 		WHERE email = $2`
-	
-	_, err := q.Exec(ctx, query, keyHash, email)
-	return err
+
+	_, err := q.Exec(ctx, query, keyHash, email, scopes, allowedIPs, expiresAt)
+	return translateConstraintError(err)
 }
 
 func (r *APIKeyRepo) UpdateLastUsed(ctx context.Context, q repository.Querier, keyHash string) error {
 	query := `UPDATE api_keys
 		SET last_used_at = now()
 		WHERE key_hash = $1`
-	
+
 	_, err := q.Exec(ctx, query, keyHash)
 	return err
 }
 
+// ListAPIKeysExpiringBetween returns keys whose expiry falls in [from, to),
+// soonest first.
+func (r *APIKeyRepo) ListAPIKeysExpiringBetween(ctx context.Context, q repository.Querier, from, to repository.AppTime) ([]models.APIKey, error) {
+	query := `SELECT id, email, key_hash, created_at, last_used_at, scopes, allowed_ips, expires_at
+		FROM api_keys
+		WHERE expires_at >= $1 AND expires_at < $2
+		ORDER BY expires_at, email`
+
+	rows, err := q.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.APIKey
+	for rows.Next() {
+		var apiKey models.APIKey
+		if err := rows.Scan(
+			&apiKey.ID,
+			&apiKey.Email,
+			&apiKey.KeyHash,
+			&apiKey.CreatedAt,
+			&apiKey.LastUsedAt,
+			&apiKey.Scopes,
+			&apiKey.AllowedIPs,
+			&apiKey.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, apiKey)
+	}
+	return out, rows.Err()
+}
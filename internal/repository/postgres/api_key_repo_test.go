@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestListAPIKeysExpiringBetween(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewAPIKeyRepo()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	expiries := map[string]*time.Time{
+		"inside@example.com":  ptrTime(now.Add(24 * time.Hour)),
+		"edge@example.com":    ptrTime(now.Add(7 * 24 * time.Hour)),
+		"outside@example.com": ptrTime(now.Add(8 * 24 * time.Hour)),
+		"expired@example.com": ptrTime(now.Add(-time.Hour)),
+		"never@example.com":   nil,
+	}
+	for email, exp := range expiries {
+		if _, err := repo.CreateAPIKey(ctx, pool, email, "hash-"+email, nil, nil, exp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keys, err := repo.ListAPIKeysExpiringBetween(ctx, pool, now, now.Add(7*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0].Email != "inside@example.com" {
+		t.Fatalf("keys = %+v, want only inside@example.com", keys)
+	}
+	if keys[0].ExpiresAt == nil || !keys[0].ExpiresAt.Equal(*expiries["inside@example.com"]) {
+		t.Errorf("expires_at = %v", keys[0].ExpiresAt)
+	}
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+type AvailabilityExceptionRepo struct{}
+
+func NewAvailabilityExceptionRepo() *AvailabilityExceptionRepo { return &AvailabilityExceptionRepo{} }
+
+func (r *AvailabilityExceptionRepo) InsertException(ctx context.Context, q repository.Querier, e *models.AvailabilityException) error {
+	query := `INSERT INTO availability_exceptions
+		(id, user_id, date, start_time, end_time, blocked, slot_length_minutes, title, created_at)
+		VALUES (gen_random_uuid(), $1, $2::date, NULLIF($3, '')::time, NULLIF($4, '')::time, $5, NULLIF($6, 0), $7, now())
+		RETURNING id, created_at`
+	return q.QueryRow(ctx, query, e.UserID, e.Date, e.StartTime, e.EndTime, e.Blocked, e.SlotLengthMins, e.Title).
+		Scan(&e.ID, &e.CreatedAt)
+}
+
+// ListExceptionsInRange returns exceptions dated within [fromDate, toDate],
+// both inclusive YYYY-MM-DD dates.
+func (r *AvailabilityExceptionRepo) ListExceptionsInRange(ctx context.Context, q repository.Querier, userID string, fromDate, toDate string) ([]models.AvailabilityException, error) {
+	query := `SELECT id, user_id, to_char(date, 'YYYY-MM-DD'),
+		             COALESCE(to_char(start_time, 'HH24:MI'), ''), COALESCE(to_char(end_time, 'HH24:MI'), ''),
+		             blocked, COALESCE(slot_length_minutes, 0), title, created_at
+		      FROM availability_exceptions
+		      WHERE user_id=$1 AND date BETWEEN $2::date AND $3::date
+		      ORDER BY date, created_at`
+	rows, err := q.Query(ctx, query, userID, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.AvailabilityException
+	for rows.Next() {
+		var e models.AvailabilityException
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Date, &e.StartTime, &e.EndTime,
+			&e.Blocked, &e.SlotLengthMins, &e.Title, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *AvailabilityExceptionRepo) DeleteException(ctx context.Context, q repository.Querier, userID, id string) (int64, error) {
+	res, err := q.Exec(ctx, `DELETE FROM availability_exceptions WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
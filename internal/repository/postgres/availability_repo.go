@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+
 	"scheduler-service/internal/models"
 	"scheduler-service/internal/repository"
 )
@@ -12,36 +15,67 @@ type AvailabilityRepo struct{}
 
 func NewAvailabilityRepo() *AvailabilityRepo { return &AvailabilityRepo{} }
 
+// availabilityColumns is the column list read by scanAvailabilityRule.
+const availabilityColumns = `id,user_id,day_of_week,start_time,end_time,slot_length_minutes,start_offset_minutes,title,tags,windows,available,created_at,updated_at,title_translations,timezone,buffer_minutes`
+
+func scanAvailabilityRule(row pgx.Row, rule *models.AvailabilityRule) error {
+	var start, end string
+	if err := row.Scan(&rule.ID, &rule.UserID, &rule.DayOfWeek, &start, &end,
+		&rule.SlotLengthMins, &rule.StartOffsetMins, &rule.Title, &rule.Tags, &rule.Windows, &rule.Available, &rule.CreatedAt, &rule.UpdatedAt, &rule.TitleTranslations, &rule.Timezone, &rule.BufferMins); err != nil {
+		return err
+	}
+	rule.StartTime = start
+	rule.EndTime = end
+	return nil
+}
+
 func (r *AvailabilityRepo) InsertAvailabilityRule(ctx context.Context, q repository.Querier, ar *models.AvailabilityRule) error {
 	now := time.Now().UTC()
 	query := `INSERT INTO availability_rules
-		(id, user_id, day_of_week, start_time, end_time, slot_length_minutes, title, available, created_at, updated_at)
-		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
-	return q.QueryRow(ctx, query,
-		ar.UserID, ar.DayOfWeek, ar.StartTime, ar.EndTime, ar.SlotLengthMins,
-		ar.Title, ar.Available, now, now,
+		(id, user_id, day_of_week, start_time, end_time, slot_length_minutes, start_offset_minutes, title, tags, windows, available, created_at, updated_at, title_translations, timezone, buffer_minutes)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, COALESCE($12::jsonb, '[]'::jsonb), $9, $10, $11, COALESCE($13::jsonb, '{}'::jsonb), $14, $15) RETURNING id`
+	err := q.QueryRow(ctx, query,
+		ar.UserID, ar.DayOfWeek, ar.StartTime, ar.EndTime, ar.SlotLengthMins, ar.StartOffsetMins,
+		ar.Title, ar.Tags, ar.Available, now, now, ar.Windows, ar.TitleTranslations, ar.Timezone, ar.BufferMins,
 	).Scan(&ar.ID)
+	if isUniqueViolation(err) {
+		return errors.New("availability rule already exists")
+	}
+	return err
+}
+
+// UpsertAvailabilityRule inserts the rule or, when one with the same
+// (user_id, day_of_week, start_time, end_time, timezone) exists, updates its mutable
+// fields. The stored id and created_at are written back to ar.
+func (r *AvailabilityRepo) UpsertAvailabilityRule(ctx context.Context, q repository.Querier, ar *models.AvailabilityRule) error {
+	now := time.Now().UTC()
+	query := `INSERT INTO availability_rules
+		(id, user_id, day_of_week, start_time, end_time, slot_length_minutes, start_offset_minutes, title, tags, windows, available, created_at, updated_at, title_translations, timezone, buffer_minutes)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, COALESCE($12::jsonb, '[]'::jsonb), $9, $10, $11, COALESCE($13::jsonb, '{}'::jsonb), $14, $15)
+		ON CONFLICT (user_id, day_of_week, start_time, end_time, timezone) DO UPDATE
+		SET slot_length_minutes=EXCLUDED.slot_length_minutes,
+		    start_offset_minutes=EXCLUDED.start_offset_minutes,
+		    title=EXCLUDED.title, title_translations=EXCLUDED.title_translations, buffer_minutes=EXCLUDED.buffer_minutes, tags=EXCLUDED.tags, windows=EXCLUDED.windows, available=EXCLUDED.available,
+		    updated_at=EXCLUDED.updated_at
+		RETURNING id, created_at`
+	return q.QueryRow(ctx, query,
+		ar.UserID, ar.DayOfWeek, ar.StartTime, ar.EndTime, ar.SlotLengthMins, ar.StartOffsetMins,
+		ar.Title, ar.Tags, ar.Available, now, now, ar.Windows, ar.TitleTranslations, ar.Timezone, ar.BufferMins,
+	).Scan(&ar.ID, &ar.CreatedAt)
 }
 
 func (r *AvailabilityRepo) GetAvailabilityRule(ctx context.Context, q repository.Querier, userID, ruleID string) (*models.AvailabilityRule, error) {
-	query := `SELECT id,user_id,day_of_week,start_time,end_time,slot_length_minutes,title,available,created_at,updated_at
+	query := `SELECT ` + availabilityColumns + `
 		      FROM availability_rules WHERE id=$1 AND user_id=$2`
 	var rule models.AvailabilityRule
-	var start, end string
-	err := q.QueryRow(ctx, query, ruleID, userID).Scan(
-		&rule.ID, &rule.UserID, &rule.DayOfWeek, &start, &end,
-		&rule.SlotLengthMins, &rule.Title, &rule.Available, &rule.CreatedAt, &rule.UpdatedAt,
-	)
-	if err != nil {
+	if err := scanAvailabilityRule(q.QueryRow(ctx, query, ruleID, userID), &rule); err != nil {
 		return nil, err
 	}
-	rule.StartTime = start
-	rule.EndTime = end
 	return &rule, nil
 }
 
 func (r *AvailabilityRepo) ListAvailabilityRules(ctx context.Context, q repository.Querier, userID string) ([]models.AvailabilityRule, error) {
-	query := `SELECT id,user_id,day_of_week,start_time,end_time,slot_length_minutes,title,available,created_at,updated_at
+	query := `SELECT ` + availabilityColumns + `
 		      FROM availability_rules WHERE user_id=$1 ORDER BY id`
 	rows, err := q.Query(ctx, query, userID)
 	if err != nil {
@@ -51,13 +85,9 @@ func (r *AvailabilityRepo) ListAvailabilityRules(ctx context.Context, q reposito
 	var out []models.AvailabilityRule
 	for rows.Next() {
 		var rule models.AvailabilityRule
-		var start, end string
-		if err := rows.Scan(&rule.ID, &rule.UserID, &rule.DayOfWeek, &start, &end,
-			&rule.SlotLengthMins, &rule.Title, &rule.Available, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		if err := scanAvailabilityRule(rows, &rule); err != nil {
 			return nil, err
 		}
-		rule.StartTime = start
-		rule.EndTime = end
 		out = append(out, rule)
 	}
 	return out, nil
@@ -67,13 +97,31 @@ func (r *AvailabilityRepo) UpdateAvailabilityRule(ctx context.Context, q reposit
 	now := time.Now().UTC()
 	query := `UPDATE availability_rules
 		SET day_of_week=$1, start_time=$2, end_time=$3, slot_length_minutes=$4,
-		    title=$5, available=$6, updated_at=$7
-		WHERE id=$8 AND user_id=$9
+		    start_offset_minutes=$5, title=$6, tags=$7, available=$8, updated_at=$9,
+		    windows=COALESCE($12::jsonb, '[]'::jsonb), title_translations=COALESCE($13::jsonb, '{}'::jsonb), timezone=$14, buffer_minutes=$15
+		WHERE id=$10 AND user_id=$11
 		RETURNING id`
 	var updatedID string
 	err := q.QueryRow(ctx, query,
 		ar.DayOfWeek, ar.StartTime, ar.EndTime, ar.SlotLengthMins,
-		ar.Title, ar.Available, now, ruleID, userID,
+		ar.StartOffsetMins, ar.Title, ar.Tags, ar.Available, now, ruleID, userID, ar.Windows, ar.TitleTranslations, ar.Timezone, ar.BufferMins,
 	).Scan(&updatedID)
+	if isUniqueViolation(err) {
+		return "", errors.New("availability rule already exists")
+	}
 	return updatedID, err
 }
+
+func (r *AvailabilityRepo) DeleteAvailabilityRules(ctx context.Context, q repository.Querier, userID string) (int64, error) {
+	res, err := q.Exec(ctx, `DELETE FROM availability_rules WHERE user_id=$1`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+func (r *AvailabilityRepo) CountAvailabilityRules(ctx context.Context, q repository.Querier, userID string) (int, error) {
+	var n int
+	err := q.QueryRow(ctx, `SELECT count(*) FROM availability_rules WHERE user_id=$1`, userID).Scan(&n)
+	return n, err
+}
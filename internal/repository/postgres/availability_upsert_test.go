@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"scheduler-service/internal/models"
+)
+
+func TestUpsertAvailabilityRuleByNaturalKey(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewAvailabilityRepo()
+	userID := "11111111-1111-1111-1111-111111111111"
+	rule := func(title string, mins int) *models.AvailabilityRule {
+		return &models.AvailabilityRule{UserID: userID, DayOfWeek: 1, StartTime: "09:00", EndTime: "12:00", SlotLengthMins: mins, Title: title, Tags: []string{}, Available: true}
+	}
+
+	first := rule("Screen", 30)
+	if err := repo.UpsertAvailabilityRule(ctx, pool, first); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name  string
+		title string
+		mins  int
+	}{
+		{"identical repeat", "Screen", 30},
+		{"mutable fields change", "Onsite", 60},
+	}
+	for _, tc := range cases {
+		again := rule(tc.title, tc.mins)
+		if err := repo.UpsertAvailabilityRule(ctx, pool, again); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if again.ID != first.ID {
+			t.Errorf("%s: upsert returned id %s, want the existing %s", tc.name, again.ID, first.ID)
+		}
+		rules, err := repo.ListAvailabilityRules(ctx, pool, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rules) != 1 {
+			t.Fatalf("%s: %d rules, want 1", tc.name, len(rules))
+		}
+		if rules[0].Title != tc.title || rules[0].SlotLengthMins != tc.mins {
+			t.Errorf("%s: stored %q with %d-min slots, want %q with %d", tc.name, rules[0].Title, rules[0].SlotLengthMins, tc.title, tc.mins)
+		}
+		if !rules[0].CreatedAt.Equal(first.CreatedAt) {
+			t.Errorf("%s: created_at changed from %s to %s", tc.name, first.CreatedAt, rules[0].CreatedAt)
+		}
+	}
+
+	if err := repo.InsertAvailabilityRule(ctx, pool, rule("Screen", 30)); err == nil {
+		t.Error("plain insert of an existing key succeeded")
+	}
+
+	// the same local times in another timezone are another rule
+	zoned := rule("Screen", 30)
+	zoned.Timezone = "America/New_York"
+	if err := repo.UpsertAvailabilityRule(ctx, pool, zoned); err != nil {
+		t.Fatal(err)
+	}
+	if zoned.ID == first.ID {
+		t.Error("upsert in another timezone updated the existing rule")
+	}
+	rules, err := repo.ListAvailabilityRules(ctx, pool, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Errorf("%d rules after upserting another timezone, want 2", len(rules))
+	}
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestExpirePendingBefore(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewBookingRepo()
+	userID := "11111111-1111-1111-1111-111111111111"
+	now := time.Now().UTC().Truncate(time.Minute)
+	start := now.Add(24 * time.Hour)
+
+	insert := func(status string, pendingFor time.Duration, h int) string {
+		t.Helper()
+		b := &models.Booking{UserID: userID, CandidateEmail: "c@example.com", StartAtUTC: start.Add(time.Duration(h) * time.Hour), EndAtUTC: start.Add(time.Duration(h)*time.Hour + 30*time.Minute), Status: status}
+		if status == "pending" {
+			since := now.Add(-pendingFor)
+			b.PendingSince = &since
+		}
+		id, err := repo.InsertBooking(ctx, pool, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	stale := insert("pending", time.Hour, 0)
+	fresh := insert("pending", time.Minute, 1)
+	confirmed := insert("", 0, 2)
+
+	// a pending booking holds its slot
+	if id, err := repo.CheckOverlappingBooking(ctx, pool, userID, start, start.Add(time.Hour), ""); err != nil || id != stale {
+		t.Fatalf("overlap = %q (%v), want the pending booking", id, err)
+	}
+	expired, err := repo.ExpirePendingBefore(ctx, pool, now.Add(-30*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 1 || expired[0] != stale {
+		t.Fatalf("expired %v, want [%s]", expired, stale)
+	}
+	want := map[string]string{stale: "expired", fresh: "pending", confirmed: "confirmed"}
+	for id, status := range want {
+		if got, err := repo.GetBookingStatus(ctx, pool, id); err != nil || got != status {
+			t.Errorf("booking %s: status %q (%v), want %q", id, got, err, status)
+		}
+	}
+	if id, err := repo.CheckOverlappingBooking(ctx, pool, userID, start, start.Add(time.Hour), ""); err != nil || id != "" {
+		t.Errorf("expired booking still holds its slot: overlap %q (%v)", id, err)
+	}
+	if n, err := repo.ApproveBooking(ctx, pool, fresh); err != nil || n != 1 {
+		t.Fatalf("approved %d (%v), want 1", n, err)
+	}
+	if b, err := repo.GetBooking(ctx, pool, fresh); err != nil || b.Status != "confirmed" || b.PendingSince != nil {
+		t.Errorf("approved booking = %+v (%v), want confirmed and no longer pending", b, err)
+	}
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestBookingAttendeesRoundTrip(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewBookingRepo()
+	userID := "11111111-1111-1111-1111-111111111111"
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+
+	cases := []struct {
+		name      string
+		attendees []models.BookingAttendee
+	}{
+		{"panel", []models.BookingAttendee{
+			{Email: "lead@example.com", Role: "interviewer"},
+			{Email: "shadow@example.com", Role: "shadow", Optional: true},
+		}},
+		{"no attendees", nil},
+	}
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := start.Add(time.Duration(i) * time.Hour)
+			id, err := repo.InsertBooking(ctx, pool, &models.Booking{UserID: userID, CandidateEmail: "c@example.com", StartAtUTC: s, EndAtUTC: s.Add(time.Hour), Attendees: tc.attendees})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := repo.GetBooking(ctx, pool, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Attendees) != len(tc.attendees) || len(tc.attendees) > 0 && !reflect.DeepEqual(got.Attendees, tc.attendees) {
+				t.Errorf("attendees = %+v, want %+v", got.Attendees, tc.attendees)
+			}
+		})
+	}
+}
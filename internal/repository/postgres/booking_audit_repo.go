@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+type BookingAuditRepo struct{}
+
+func NewBookingAuditRepo() *BookingAuditRepo { return &BookingAuditRepo{} }
+
+func (r *BookingAuditRepo) InsertAuditEntry(ctx context.Context, q repository.Querier, e *models.BookingAuditEntry) error {
+	query := `INSERT INTO booking_audit_log (id, booking_id, action, actor, details, created_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, COALESCE($4::jsonb, '{}'::jsonb), now())
+		RETURNING id, created_at`
+	return q.QueryRow(ctx, query, e.BookingID, e.Action, e.Actor, e.Details).Scan(&e.ID, &e.CreatedAt)
+}
+
+// ListAuditEntries returns a booking's history, oldest first.
+func (r *BookingAuditRepo) ListAuditEntries(ctx context.Context, q repository.Querier, bookingID string) ([]models.BookingAuditEntry, error) {
+	query := `SELECT id, booking_id, action, actor, details, created_at
+		      FROM booking_audit_log
+		      WHERE booking_id=$1
+		      ORDER BY created_at, id`
+	rows, err := q.Query(ctx, query, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.BookingAuditEntry
+	for rows.Next() {
+		var e models.BookingAuditEntry
+		if err := rows.Scan(&e.ID, &e.BookingID, &e.Action, &e.Actor, &e.Details, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// DeleteAuditEntries removes the history of the given bookings.
+func (r *BookingAuditRepo) DeleteAuditEntries(ctx context.Context, q repository.Querier, bookingIDs []string) (int64, error) {
+	res, err := q.Exec(ctx, `DELETE FROM booking_audit_log WHERE booking_id = ANY($1::uuid[])`, bookingIDs)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/service"
+)
+
+// TestConcurrentBookingsUnderSerializable races bookings of one slot through
+// the booking service on a real database and checks only one is stored.
+func TestConcurrentBookingsUnderSerializable(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	userID := "11111111-1111-1111-1111-111111111111"
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 7).Add(9 * time.Hour)
+
+	availRepo, bookingRepo := NewAvailabilityRepo(), NewBookingRepo()
+	rule := models.AvailabilityRule{UserID: userID, DayOfWeek: int(start.Weekday()), StartTime: "09:00", EndTime: "12:00", SlotLengthMins: 30, Available: true, Windows: []models.TimeWindow{}}
+	if err := availRepo.InsertAvailabilityRule(ctx, pool, &rule); err != nil {
+		t.Fatal(err)
+	}
+	bookings := service.NewBookingService(pool, bookingRepo, service.NewAvailabilityService(pool, availRepo, bookingRepo))
+	bookings.TxIsolation = pgx.Serializable
+	req := service.CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute)}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make([]error, callers)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = bookings.CreateBooking(ctx, userID, req)
+		}(i)
+	}
+	wg.Wait()
+
+	booked := 0
+	for _, err := range errs {
+		if err == nil {
+			booked++
+		}
+	}
+	if booked != 1 {
+		t.Errorf("%d of %d concurrent bookings succeeded, want 1: %v", booked, callers, errs)
+	}
+	var stored int
+	if err := pool.QueryRow(ctx, `SELECT count(*) FROM bookings WHERE user_id=$1 AND status='confirmed'`, userID).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored != 1 {
+		t.Errorf("%d bookings stored, want 1", stored)
+	}
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestCheckOverlappingBookingContained(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewBookingRepo()
+	userID := "11111111-1111-1111-1111-111111111111"
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	id, err := repo.InsertBooking(ctx, pool, &models.Booking{UserID: userID, CandidateEmail: "c@example.com", StartAtUTC: start, EndAtUTC: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name       string
+		start, end time.Time
+		want       string
+	}{
+		{"inside", start.Add(15 * time.Minute), start.Add(45 * time.Minute), id},
+		{"around", start.Add(-15 * time.Minute), start.Add(75 * time.Minute), id},
+		{"back to back", start.Add(time.Hour), start.Add(2 * time.Hour), ""},
+	}
+	for _, tc := range cases {
+		got, err := repo.CheckOverlappingBooking(ctx, pool, userID, tc.start, tc.end, "")
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: overlap = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+// TestCheckOverlappingBookingWaitsForConcurrentInsert checks a contained
+// window booked in a second transaction is seen once the first commits,
+// rather than both inserting because neither had a row to lock.
+func TestCheckOverlappingBookingWaitsForConcurrentInsert(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewBookingRepo()
+	userID := "11111111-1111-1111-1111-111111111111"
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+
+	first, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Rollback(ctx)
+	if got, err := repo.CheckOverlappingBooking(ctx, first, userID, start, start.Add(time.Hour), ""); err != nil || got != "" {
+		t.Fatalf("first check = %q (%v), want none", got, err)
+	}
+	id, err := repo.InsertBooking(ctx, first, &models.Booking{UserID: userID, CandidateEmail: "c@example.com", StartAtUTC: start, EndAtUTC: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		id  string
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		second, err := pool.Begin(ctx)
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		defer second.Rollback(ctx)
+		got, err := repo.CheckOverlappingBooking(ctx, second, userID, start.Add(15*time.Minute), start.Add(45*time.Minute), "")
+		done <- result{got, err}
+	}()
+
+	select {
+	case r := <-done:
+		t.Fatalf("second check returned %q (%v) before the first transaction ended", r.id, r.err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if err := first.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	r := <-done
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	if r.id != id {
+		t.Errorf("second check = %q, want %q", r.id, id)
+	}
+}
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestBookingPriceRoundTrip(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewBookingRepo()
+	userID := "11111111-1111-1111-1111-111111111111"
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	amount := int64(15000)
+
+	cases := []struct {
+		name        string
+		amountCents *int64
+		currency    string
+	}{
+		{"paid", &amount, "EUR"},
+		{"unpriced", nil, ""},
+	}
+	for i, tc := range cases {
+		s := start.Add(time.Duration(i) * time.Hour)
+		id, err := repo.InsertBooking(ctx, pool, &models.Booking{UserID: userID, CandidateEmail: "c@example.com", StartAtUTC: s, EndAtUTC: s.Add(time.Hour), AmountCents: tc.amountCents, Currency: tc.currency})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		got, err := repo.GetBooking(ctx, pool, id)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got.Currency != tc.currency || (got.AmountCents == nil) != (tc.amountCents == nil) ||
+			got.AmountCents != nil && *got.AmountCents != *tc.amountCents {
+			t.Errorf("%s: price = %v %q, want %v %q", tc.name, got.AmountCents, got.Currency, tc.amountCents, tc.currency)
+		}
+	}
+}
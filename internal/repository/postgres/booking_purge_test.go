@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestPurgeCancelledBeforeWithAuditEntries(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	bookings, audit := NewBookingRepo(), NewBookingAuditRepo()
+	userID := "11111111-1111-1111-1111-111111111111"
+	old := time.Now().UTC().AddDate(0, 0, -100).Truncate(time.Hour)
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		start := old.Add(time.Duration(i) * time.Hour)
+		id, err := bookings.InsertBooking(ctx, pool, &models.Booking{UserID: userID, CandidateEmail: "c@example.com", StartAtUTC: start, EndAtUTC: start.Add(30 * time.Minute)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := audit.InsertAuditEntry(ctx, pool, &models.BookingAuditEntry{BookingID: id, Action: "created"}); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if _, err := bookings.CancelBooking(ctx, pool, ids[0], "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+	purged, err := bookings.PurgeCancelledBefore(ctx, tx, old.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(purged) != 1 || purged[0] != ids[0] {
+		t.Fatalf("purged %v, want [%s]", purged, ids[0])
+	}
+	if n, err := audit.DeleteAuditEntries(ctx, tx, purged); err != nil || n != 1 {
+		t.Fatalf("deleted %d audit entries (%v), want 1", n, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if entries, _ := audit.ListAuditEntries(ctx, pool, ids[0]); len(entries) != 0 {
+		t.Errorf("purged booking still has %d audit entries", len(entries))
+	}
+	if entries, _ := audit.ListAuditEntries(ctx, pool, ids[1]); len(entries) != 1 {
+		t.Errorf("kept booking has %d audit entries, want 1", len(entries))
+	}
+}
+
+func TestPurgeCancelledBeforeOnlyOldCancelled(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	bookings := NewBookingRepo()
+	userID := "22222222-2222-2222-2222-222222222222"
+	now := time.Now().UTC().Truncate(time.Hour)
+	cutoff := now.AddDate(0, 0, -30)
+	rows := []struct {
+		name       string
+		start      time.Time
+		cancel     bool
+		wantPurged bool
+	}{
+		{"recent cancelled", now.AddDate(0, 0, -1), true, false},
+		{"old cancelled", now.AddDate(0, 0, -100), true, true},
+		{"old confirmed", now.AddDate(0, 0, -100).Add(time.Hour), false, false},
+		{"cancelled ending at the cutoff", cutoff.Add(-30 * time.Minute), true, false},
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+	ids := make(map[string]string)
+	for _, row := range rows {
+		id, err := bookings.InsertBooking(ctx, tx, &models.Booking{UserID: userID, CandidateEmail: "c@example.com", StartAtUTC: row.start, EndAtUTC: row.start.Add(30 * time.Minute)})
+		if err != nil {
+			t.Fatalf("%s: %v", row.name, err)
+		}
+		if row.cancel {
+			if _, err := bookings.CancelBooking(ctx, tx, id, "", ""); err != nil {
+				t.Fatalf("%s: %v", row.name, err)
+			}
+		}
+		ids[id] = row.name
+	}
+
+	purged, err := bookings.PurgeCancelledBefore(ctx, tx, cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotPurged := make(map[string]bool)
+	for _, id := range purged {
+		gotPurged[ids[id]] = true
+	}
+	for _, row := range rows {
+		if gotPurged[row.name] != row.wantPurged {
+			t.Errorf("%s: purged = %v, want %v", row.name, gotPurged[row.name], row.wantPurged)
+		}
+	}
+}
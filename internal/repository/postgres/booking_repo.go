@@ -2,6 +2,8 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -14,10 +16,18 @@ type BookingRepo struct{}
 
 func NewBookingRepo() *BookingRepo { return &BookingRepo{} }
 
+// bookingColumns is the column list read by scanBooking, kept in one place so
+// every SELECT returns bookings in the same shape.
+const bookingColumns = `id,user_id,candidate_email,start_at_utc,end_at_utc,status,created_at,confirmation_state,COALESCE(google_event_id,''),attendees,COALESCE(title,''),amount_cents,COALESCE(currency,''),COALESCE(recurrence_group_id::text,''),rule_snapshot,booked_by,booked_by_email,cancellation_reason,cancelled_by,COALESCE(confirmation_code,''),pending_since`
+
+func scanBooking(row pgx.Row, b *models.Booking) error {
+	return row.Scan(&b.ID, &b.UserID, &b.CandidateEmail, &b.StartAtUTC, &b.EndAtUTC, &b.Status, &b.CreatedAt, &b.ConfirmationState, &b.GoogleEventID, &b.Attendees, &b.Title, &b.AmountCents, &b.Currency, &b.RecurrenceGroupID, &b.RuleSnapshot, &b.BookedBy, &b.BookedByEmail, &b.CancellationReason, &b.CancelledBy, &b.ConfirmationCode, &b.PendingSince)
+}
+
 func (r *BookingRepo) ListBookingsInRange(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime) ([]models.Booking, error) {
-	query := `SELECT id,user_id,candidate_email,start_at_utc,end_at_utc,status,created_at 
+	query := `SELECT ` + bookingColumns + `
 		      FROM bookings
-		      WHERE user_id=$1 AND start_at_utc >= $2 AND start_at_utc < $3 AND status='confirmed'`
+		      WHERE user_id=$1 AND start_at_utc >= $2 AND start_at_utc < $3 AND status IN ('confirmed', 'pending')`
 	rows, err := q.Query(ctx, query, userID, from, to)
 	if err != nil {
 		return nil, err
@@ -26,7 +36,7 @@ func (r *BookingRepo) ListBookingsInRange(ctx context.Context, q repository.Quer
 	var out []models.Booking
 	for rows.Next() {
 		var b models.Booking
-		if err := rows.Scan(&b.ID, &b.UserID, &b.CandidateEmail, &b.StartAtUTC, &b.EndAtUTC, &b.Status, &b.CreatedAt); err != nil {
+		if err := scanBooking(rows, &b); err != nil {
 			return nil, err
 		}
 		out = append(out, b)
@@ -34,24 +44,20 @@ func (r *BookingRepo) ListBookingsInRange(ctx context.Context, q repository.Quer
 	return out, nil
 }
 
-func (r *BookingRepo) ListBookings(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime, filtered bool) ([]models.Booking, error) {
-	var (
-		rows pgx.Rows
-		err  error
-	)
-	if filtered {
-		query := `SELECT id,user_id,candidate_email,start_at_utc,end_at_utc,status,created_at 
-		          FROM bookings 
-		          WHERE user_id=$1 AND start_at_utc >= $2 AND start_at_utc < $3 AND status != 'cancelled'
-		          ORDER BY start_at_utc`
-		rows, err = q.Query(ctx, query, userID, from, to)
-	} else {
-		query := `SELECT id,user_id,candidate_email,start_at_utc,end_at_utc,status,created_at 
-		          FROM bookings 
-		          WHERE user_id=$1 AND status != 'cancelled'
-		          ORDER BY start_at_utc`
-		rows, err = q.Query(ctx, query, userID)
+// ListBookings returns the user's non-cancelled bookings in start order,
+// within [from, to) when filtered. A positive limit returns at most limit of
+// them after skipping offset.
+func (r *BookingRepo) ListBookings(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime, filtered bool, limit, offset int) ([]models.Booking, error) {
+	where, args := listBookingsWhere(userID, from, to, filtered)
+	query := `SELECT ` + bookingColumns + `
+	          FROM bookings
+	          WHERE ` + where + `
+	          ORDER BY start_at_utc, id`
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+		args = append(args, limit, offset)
 	}
+	rows, err := q.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +65,7 @@ func (r *BookingRepo) ListBookings(ctx context.Context, q repository.Querier, us
 	var out []models.Booking
 	for rows.Next() {
 		var b models.Booking
-		if err := rows.Scan(&b.ID, &b.UserID, &b.CandidateEmail, &b.StartAtUTC, &b.EndAtUTC, &b.Status, &b.CreatedAt); err != nil {
+		if err := scanBooking(rows, &b); err != nil {
 			return nil, err
 		}
 		out = append(out, b)
@@ -67,26 +73,182 @@ func (r *BookingRepo) ListBookings(ctx context.Context, q repository.Querier, us
 	return out, nil
 }
 
-func (r *BookingRepo) CheckExistingBookingAtStart(ctx context.Context, q repository.Querier, userID string, start repository.AppTime) (string, error) {
-	query := `SELECT id FROM bookings 
-		       WHERE user_id=$1 AND status='confirmed' 
-		       AND start_at_utc = $2 FOR UPDATE`
-	var id string
-	err := q.QueryRow(ctx, query, userID, start).Scan(&id)
-	if err != nil && err != pgx.ErrNoRows {
+// CountBookings counts the bookings ListBookings would return without a limit.
+func (r *BookingRepo) CountBookings(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime, filtered bool) (int, error) {
+	where, args := listBookingsWhere(userID, from, to, filtered)
+	var n int
+	err := q.QueryRow(ctx, `SELECT count(*) FROM bookings WHERE `+where, args...).Scan(&n)
+	return n, err
+}
+
+func listBookingsWhere(userID string, from, to repository.AppTime, filtered bool) (string, []any) {
+	if filtered {
+		return `user_id=$1 AND start_at_utc >= $2 AND start_at_utc < $3 AND status NOT IN ('cancelled', 'expired')`, []any{userID, from, to}
+	}
+	return `user_id=$1 AND status NOT IN ('cancelled', 'expired')`, []any{userID}
+}
+
+// ListBookingsAfter returns up to limit bookings of any status ordered by
+// (start_at_utc, id), starting after the given position. Passing a nil
+// afterStart begins at the first booking.
+func (r *BookingRepo) ListBookingsAfter(ctx context.Context, q repository.Querier, userID string, afterStart repository.AppTime, afterID string, limit int) ([]models.Booking, error) {
+	query := `SELECT ` + bookingColumns + `
+		      FROM bookings
+		      WHERE user_id=$1 AND ($2::timestamptz IS NULL OR (start_at_utc, id) > ($2, $3::uuid))
+		      ORDER BY start_at_utc, id
+		      LIMIT $4`
+	var afterIDArg any
+	if afterID != "" {
+		afterIDArg = afterID
+	}
+	rows, err := q.Query(ctx, query, userID, afterStart, afterIDArg, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.Booking
+	for rows.Next() {
+		var b models.Booking
+		if err := scanBooking(rows, &b); err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// ListUpcomingBookings returns up to limit non-cancelled bookings starting at
+// or after since, in (start, id) order, resuming after (afterStart, afterID)
+// when afterStart is non-nil.
+func (r *BookingRepo) ListUpcomingBookings(ctx context.Context, q repository.Querier, userID string, since, afterStart repository.AppTime, afterID string, limit int) ([]models.Booking, error) {
+	query := `SELECT ` + bookingColumns + `
+		      FROM bookings
+		      WHERE user_id=$1 AND status NOT IN ('cancelled', 'expired') AND start_at_utc >= $2
+		        AND ($3::timestamptz IS NULL OR (start_at_utc, id) > ($3, $4::uuid))
+		      ORDER BY start_at_utc, id
+		      LIMIT $5`
+	var afterIDArg any
+	if afterID != "" {
+		afterIDArg = afterID
+	}
+	rows, err := q.Query(ctx, query, userID, since, afterStart, afterIDArg, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.Booking
+	for rows.Next() {
+		var b models.Booking
+		if err := scanBooking(rows, &b); err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// ListAllBookings returns up to limit bookings of any user in (start, id)
+// order. Nil from/to leave the start unbounded, an empty status matches every
+// status, and a non-nil afterStart resumes after (afterStart, afterID).
+func (r *BookingRepo) ListAllBookings(ctx context.Context, q repository.Querier, from, to repository.AppTime, status string, afterStart repository.AppTime, afterID string, limit int) ([]models.Booking, error) {
+	query := `SELECT ` + bookingColumns + `
+		      FROM bookings
+		      WHERE ($1::timestamptz IS NULL OR start_at_utc >= $1)
+		        AND ($2::timestamptz IS NULL OR start_at_utc < $2)
+		        AND ($3 = '' OR status = $3)
+		        AND ($4::timestamptz IS NULL OR (start_at_utc, id) > ($4, $5::uuid))
+		      ORDER BY start_at_utc, id
+		      LIMIT $6`
+	var afterIDArg any
+	if afterID != "" {
+		afterIDArg = afterID
+	}
+	rows, err := q.Query(ctx, query, from, to, status, afterStart, afterIDArg, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.Booking
+	for rows.Next() {
+		var b models.Booking
+		if err := scanBooking(rows, &b); err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// CheckOverlappingBooking returns the id of a confirmed or pending booking of userID,
+// other than excludeID, whose window overlaps [start, end), locking it for the
+// transaction. It returns "" when none exists.
+//
+// FOR UPDATE cannot lock a booking that does not exist yet, so the check first
+// takes a per-user advisory lock held until q's transaction ends; concurrent
+// checks for the same user then wait for the earlier insert to commit. Call it
+// inside the transaction that inserts or moves the booking.
+func (r *BookingRepo) CheckOverlappingBooking(ctx context.Context, q repository.Querier, userID string, start, end repository.AppTime, excludeID string) (string, error) {
+	if _, err := q.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('bookings:' || $1))`, userID); err != nil {
 		return "", err
 	}
+	query := `SELECT id FROM bookings
+		       WHERE user_id=$1 AND status IN ('confirmed', 'pending')
+		       AND start_at_utc < $3 AND end_at_utc > $2
+		       AND ($4 = '' OR id <> NULLIF($4, '')::uuid)
+		       LIMIT 1 FOR UPDATE`
+	var id string
+	err := q.QueryRow(ctx, query, userID, start, end, excludeID).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	return id, err
+}
+
+// FindCandidateOverlap returns the id of a confirmed or pending booking for the
+// candidate, with any user and other than excludeID, whose window overlaps
+// [start, end). It returns "" when none exists.
+func (r *BookingRepo) FindCandidateOverlap(ctx context.Context, q repository.Querier, candidateEmail string, start, end repository.AppTime, excludeID string) (string, error) {
+	query := `SELECT id FROM bookings
+		       WHERE lower(candidate_email)=lower($1) AND status IN ('confirmed', 'pending')
+		       AND start_at_utc < $3 AND end_at_utc > $2
+		       AND ($4 = '' OR id <> NULLIF($4, '')::uuid)
+		       LIMIT 1 FOR UPDATE`
+	var id string
+	err := q.QueryRow(ctx, query, candidateEmail, start, end, excludeID).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
 	return id, err
 }
 
 func (r *BookingRepo) InsertBooking(ctx context.Context, q repository.Querier, b *models.Booking) (string, error) {
+	// Enforced here as well as by the check constraint so a bad window never
+	// reaches the database, whatever the caller validated.
+	if !b.EndAtUTC.After(b.StartAtUTC) || b.EndAtUTC.Sub(b.StartAtUTC) > repository.MaxBookingDuration {
+		return "", repository.ErrInvalidBookingWindow
+	}
 	query := `INSERT INTO bookings 
-		(id, user_id, candidate_email, start_at_utc, end_at_utc, status, source, type, description, title, created_at)
-		VALUES (gen_random_uuid(), $1, $2, $3, $4, 'confirmed', $5, $6, $7, $8, now())
+		(id, user_id, candidate_email, start_at_utc, end_at_utc, status, source, type, description, title, google_event_id, attendees, amount_cents, currency, recurrence_group_id, rule_snapshot, booked_by, booked_by_email, confirmation_code, pending_since, created_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, COALESCE(NULLIF($19, ''), 'confirmed'), $5, $6, $7, $8, NULLIF($9, ''), COALESCE($10::jsonb, '[]'::jsonb), $11, NULLIF($12, ''), NULLIF($13, '')::uuid, $14::jsonb, $15, $16, NULLIF($17, ''), $18, now())
+		ON CONFLICT (confirmation_code) DO NOTHING
 		RETURNING id`
 	var newID string
-	err := q.QueryRow(ctx, query, b.UserID, b.CandidateEmail, b.StartAtUTC, b.EndAtUTC, b.Source, b.Type, b.Description, b.Title).Scan(&newID)
-	return newID, err
+	err := q.QueryRow(ctx, query, b.UserID, b.CandidateEmail, b.StartAtUTC, b.EndAtUTC, b.Source, b.Type, b.Description, b.Title, b.GoogleEventID, b.Attendees, b.AmountCents, b.Currency, b.RecurrenceGroupID, b.RuleSnapshot, b.BookedBy, b.BookedByEmail, b.ConfirmationCode, b.PendingSince, b.Status).Scan(&newID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Only a confirmation code collision is skipped rather than raised
+		return "", repository.ErrConfirmationCodeTaken
+	}
+	return newID, translateConstraintError(err)
+}
+
+// GetBookingByConfirmationCode returns pgx.ErrNoRows when no booking has code.
+func (r *BookingRepo) GetBookingByConfirmationCode(ctx context.Context, q repository.Querier, code string) (*models.Booking, error) {
+	query := `SELECT ` + bookingColumns + ` FROM bookings WHERE confirmation_code=$1`
+	var b models.Booking
+	if err := scanBooking(q.QueryRow(ctx, query, code), &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
 }
 
 func (r *BookingRepo) GetBookingStatus(ctx context.Context, q repository.Querier, id string) (string, error) {
@@ -96,8 +258,139 @@ func (r *BookingRepo) GetBookingStatus(ctx context.Context, q repository.Querier
 	return status, err
 }
 
-func (r *BookingRepo) CancelBooking(ctx context.Context, q repository.Querier, id string) (int64, error) {
-	query := `UPDATE bookings SET status='cancelled' WHERE id=$1 AND status != 'cancelled'`
+func (r *BookingRepo) GetBooking(ctx context.Context, q repository.Querier, id string) (*models.Booking, error) {
+	query := `SELECT ` + bookingColumns + ` FROM bookings WHERE id=$1`
+	var b models.Booking
+	if err := scanBooking(q.QueryRow(ctx, query, id), &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *BookingRepo) UpdateConfirmationState(ctx context.Context, q repository.Querier, id, from, to string) (int64, error) {
+	query := `UPDATE bookings SET confirmation_state=$1 WHERE id=$2 AND confirmation_state=$3`
+	res, err := q.Exec(ctx, query, to, id, from)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+// GetBookingByGoogleEventID returns the live booking synced from the given
+// Google Calendar event, or pgx.ErrNoRows when none is linked.
+func (r *BookingRepo) GetBookingByGoogleEventID(ctx context.Context, q repository.Querier, eventID string) (*models.Booking, error) {
+	query := `SELECT ` + bookingColumns + ` FROM bookings
+		WHERE google_event_id=$1 AND status NOT IN ('cancelled', 'expired')
+		ORDER BY created_at DESC
+		LIMIT 1`
+	var b models.Booking
+	if err := scanBooking(q.QueryRow(ctx, query, eventID), &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ReassignBooking moves a confirmed or pending booking to another user.
+func (r *BookingRepo) ReassignBooking(ctx context.Context, q repository.Querier, id, toUserID string) (int64, error) {
+	query := `UPDATE bookings SET user_id=$2 WHERE id=$1 AND status IN ('confirmed', 'pending')`
+	res, err := q.Exec(ctx, query, id, toUserID)
+	if err != nil {
+		return 0, translateConstraintError(err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// UpdateBookingTimes moves a confirmed or pending booking to [start, end).
+func (r *BookingRepo) UpdateBookingTimes(ctx context.Context, q repository.Querier, id string, start, end repository.AppTime) (int64, error) {
+	query := `UPDATE bookings SET start_at_utc=$2, end_at_utc=$3 WHERE id=$1 AND status IN ('confirmed', 'pending')`
+	res, err := q.Exec(ctx, query, id, start, end)
+	if err != nil {
+		return 0, translateConstraintError(err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// CancelBooking cancels a live booking, recording why and by whom.
+func (r *BookingRepo) CancelBooking(ctx context.Context, q repository.Querier, id, reason, cancelledBy string) (int64, error) {
+	query := `UPDATE bookings SET status='cancelled', cancellation_reason=$2, cancelled_by=$3
+		WHERE id=$1 AND status NOT IN ('cancelled', 'expired')`
+	res, err := q.Exec(ctx, query, id, reason, cancelledBy)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+// CancelRecurrenceGroup cancels the live bookings of a recurring series,
+// limited to those starting at or after from unless it is nil, and returns
+// the IDs it cancelled. reason and cancelledBy are recorded as by
+// CancelBooking.
+func (r *BookingRepo) CancelRecurrenceGroup(ctx context.Context, q repository.Querier, groupID string, from repository.AppTime, reason, cancelledBy string) ([]string, error) {
+	query := `UPDATE bookings SET status='cancelled', cancellation_reason=$3, cancelled_by=$4
+		      WHERE recurrence_group_id=$1 AND status NOT IN ('cancelled', 'expired')
+		        AND ($2::timestamptz IS NULL OR start_at_utc >= $2)
+		      RETURNING id`
+	rows, err := q.Query(ctx, query, groupID, from, reason, cancelledBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ListBookingsByCandidate returns every booking of candidateEmail, across all
+// users and statuses, in start order.
+func (r *BookingRepo) ListBookingsByCandidate(ctx context.Context, q repository.Querier, candidateEmail string) ([]models.Booking, error) {
+	query := `SELECT ` + bookingColumns + `
+		      FROM bookings
+		      WHERE lower(candidate_email)=lower($1)
+		      ORDER BY start_at_utc, id`
+	rows, err := q.Query(ctx, query, candidateEmail)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.Booking
+	for rows.Next() {
+		var b models.Booking
+		if err := scanBooking(rows, &b); err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// PurgeCancelledBefore deletes cancelled bookings whose end is before cutoff
+// and returns their ids.
+func (r *BookingRepo) PurgeCancelledBefore(ctx context.Context, q repository.Querier, cutoff repository.AppTime) ([]string, error) {
+	rows, err := q.Query(ctx, `DELETE FROM bookings WHERE status='cancelled' AND end_at_utc < $1 RETURNING id`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ApproveBooking confirms a pending booking.
+func (r *BookingRepo) ApproveBooking(ctx context.Context, q repository.Querier, id string) (int64, error) {
+	query := `UPDATE bookings SET status='confirmed', pending_since=NULL WHERE id=$1 AND status='pending'`
 	res, err := q.Exec(ctx, query, id)
 	if err != nil {
 		return 0, err
@@ -105,6 +398,50 @@ func (r *BookingRepo) CancelBooking(ctx context.Context, q repository.Querier, i
 	return res.RowsAffected(), nil
 }
 
+// ExpirePendingBefore expires the bookings pending since before cutoff,
+// freeing their slots, and returns their ids.
+func (r *BookingRepo) ExpirePendingBefore(ctx context.Context, q repository.Querier, cutoff repository.AppTime) ([]string, error) {
+	rows, err := q.Query(ctx, `UPDATE bookings SET status='expired' WHERE status='pending' AND pending_since < $1 RETURNING id`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// AggregateBookings counts userID's bookings starting in [from, to). The
+// average duration and busiest weekday consider confirmed bookings only.
+func (r *BookingRepo) AggregateBookings(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime) (*models.BookingAggregates, error) {
+	query := `WITH b AS (
+		          SELECT status, start_at_utc, end_at_utc FROM bookings
+		          WHERE user_id=$1 AND start_at_utc >= $2 AND start_at_utc < $3
+		      ), busiest AS (
+		          SELECT EXTRACT(DOW FROM start_at_utc AT TIME ZONE 'UTC')::int AS dow, count(*) AS n
+		          FROM b WHERE status='confirmed'
+		          GROUP BY dow ORDER BY n DESC, dow LIMIT 1
+		      )
+		      SELECT count(*),
+		             count(*) FILTER (WHERE status='confirmed'),
+		             count(*) FILTER (WHERE status='cancelled'),
+		             COALESCE(avg(EXTRACT(EPOCH FROM end_at_utc - start_at_utc) / 60) FILTER (WHERE status='confirmed'), 0)::float8,
+		             (SELECT dow FROM busiest),
+		             COALESCE((SELECT n FROM busiest), 0)
+		      FROM b`
+	var a models.BookingAggregates
+	if err := q.QueryRow(ctx, query, userID, from, to).Scan(&a.Total, &a.Confirmed, &a.Cancelled, &a.AvgDurationMins, &a.BusiestWeekday, &a.BusiestWeekdayCount); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
 // ensure interface satisfaction
 var (
 	_ = time.Now // silence unused import if needed
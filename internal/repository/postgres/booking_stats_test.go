@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestAggregateBookingsForKnownDataset(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewBookingRepo()
+	userID := "11111111-1111-1111-1111-111111111111"
+	monday := time.Date(2030, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	insert := func(days, hour, mins int) string {
+		start := monday.AddDate(0, 0, days).Add(time.Duration(hour) * time.Hour)
+		id, err := repo.InsertBooking(ctx, pool, &models.Booking{UserID: userID, CandidateEmail: "c@example.com", StartAtUTC: start, EndAtUTC: start.Add(time.Duration(mins) * time.Minute)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	insert(0, 9, 30)
+	insert(0, 10, 30)
+	cancelled := insert(0, 11, 30)
+	insert(2, 13, 60)
+	insert(7, 9, 30) // next week
+	if _, err := repo.CancelBooking(ctx, pool, cancelled, "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name          string
+		from, to      time.Time
+		want          models.BookingAggregates
+		wantBusiest   int
+		wantNoBusiest bool
+	}{
+		{"week", monday, monday.AddDate(0, 0, 7), models.BookingAggregates{Total: 4, Confirmed: 3, Cancelled: 1, AvgDurationMins: 40, BusiestWeekdayCount: 2}, int(time.Monday), false},
+		{"empty day", monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 2), models.BookingAggregates{}, 0, true},
+	}
+	for _, tc := range cases {
+		got, err := repo.AggregateBookings(ctx, pool, userID, tc.from, tc.to)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		busiest := got.BusiestWeekday
+		got.BusiestWeekday = nil
+		if *got != tc.want {
+			t.Errorf("%s: aggregates = %+v, want %+v", tc.name, *got, tc.want)
+		}
+		if tc.wantNoBusiest != (busiest == nil) || busiest != nil && *busiest != tc.wantBusiest {
+			t.Errorf("%s: busiest weekday = %v, want %d", tc.name, busiest, tc.wantBusiest)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+func TestInsertBookingRejectsInvalidWindow(t *testing.T) {
+	repo := NewBookingRepo()
+	ctx := context.Background()
+	reached := errors.New("reached the database")
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		end     time.Time
+		wantErr error
+	}{
+		{"inverted", start.Add(-30 * time.Minute), repository.ErrInvalidBookingWindow},
+		{"zero length", start, repository.ErrInvalidBookingWindow},
+		{"longer than a day", start.Add(repository.MaxBookingDuration + time.Minute), repository.ErrInvalidBookingWindow},
+		{"exactly a day", start.Add(repository.MaxBookingDuration), reached},
+		{"half an hour", start.Add(30 * time.Minute), reached},
+	}
+	for _, tc := range cases {
+		_, err := repo.InsertBooking(ctx, failingQuerier{reached}, &models.Booking{UserID: "u1", CandidateEmail: "c@example.com", StartAtUTC: start, EndAtUTC: tc.end})
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestInsertBookingTranslatesConstraintErrors(t *testing.T) {
+	repo := NewBookingRepo()
+	ctx := context.Background()
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	otherCheck := &pgconn.PgError{Code: "23514", ConstraintName: "bookings_status_check"}
+	cases := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{"window check violation", &pgconn.PgError{Code: "23514", ConstraintName: "bookings_window_valid"}, repository.ErrInvalidBookingWindow},
+		{"unique violation", &pgconn.PgError{Code: "23505", ConstraintName: "bookings_user_start_key"}, repository.ErrConflict},
+		{"other check violation", otherCheck, otherCheck},
+	}
+	for _, tc := range cases {
+		_, err := repo.InsertBooking(ctx, failingQuerier{tc.err}, &models.Booking{UserID: "u1", CandidateEmail: "c@example.com", StartAtUTC: start, EndAtUTC: start.Add(30 * time.Minute)})
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestBookingWindowCheckConstraint(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	// Bypass InsertBooking's own check to reach the constraint
+	_, err := pool.Exec(ctx, `INSERT INTO bookings (id, user_id, candidate_email, start_at_utc, end_at_utc, status, created_at)
+		VALUES (gen_random_uuid(), $1, 'c@example.com', $2, $3, 'confirmed', now())`,
+		"11111111-1111-1111-1111-111111111111", start, start.Add(-time.Hour))
+	if !errors.Is(translateConstraintError(err), repository.ErrInvalidBookingWindow) {
+		t.Errorf("inverted window insert: err = %v, want %v", err, repository.ErrInvalidBookingWindow)
+	}
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestListBookingsByCandidateAcrossUsers(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewBookingRepo()
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	insert := func(userID, email string, offset time.Duration) string {
+		s := start.Add(offset)
+		id, err := repo.InsertBooking(ctx, pool, &models.Booking{UserID: userID, CandidateEmail: email, StartAtUTC: s, EndAtUTC: s.Add(30 * time.Minute)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	second := insert("11111111-1111-1111-1111-111111111111", "cand@example.com", time.Hour)
+	first := insert("22222222-2222-2222-2222-222222222222", "Cand@Example.com", 0)
+	other := insert("11111111-1111-1111-1111-111111111111", "other@example.com", 2*time.Hour)
+
+	cases := []struct {
+		email string
+		want  []string
+	}{
+		{"cand@example.com", []string{first, second}},
+		{"OTHER@example.com", []string{other}},
+		{"nobody@example.com", nil},
+	}
+	for _, tc := range cases {
+		got, err := repo.ListBookingsByCandidate(ctx, pool, tc.email)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.email, err)
+		}
+		if len(got) != len(tc.want) {
+			t.Fatalf("%s: got %d bookings, want %d", tc.email, len(got), len(tc.want))
+		}
+		for i, b := range got {
+			if b.ID != tc.want[i] {
+				t.Errorf("%s: booking %d = %s, want %s", tc.email, i, b.ID, tc.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestFindCandidateOverlapAcrossUsers(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewBookingRepo()
+	u1 := "11111111-1111-1111-1111-111111111111"
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	id, err := repo.InsertBooking(ctx, pool, &models.Booking{UserID: u1, CandidateEmail: "C@example.com", StartAtUTC: start, EndAtUTC: start.Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name       string
+		email      string
+		start, end time.Time
+		want       string
+	}{
+		{"overlapping, other interviewer's window", "c@example.com", start.Add(30 * time.Minute), start.Add(90 * time.Minute), id},
+		{"inside", "c@example.com", start.Add(15 * time.Minute), start.Add(45 * time.Minute), id},
+		{"back to back", "c@example.com", start.Add(time.Hour), start.Add(2 * time.Hour), ""},
+		{"another candidate", "d@example.com", start, start.Add(time.Hour), ""},
+	}
+	for _, tc := range cases {
+		got, err := repo.FindCandidateOverlap(ctx, pool, tc.email, tc.start, tc.end, "")
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: overlap = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	// a booking awaiting approval holds the candidate's time too
+	since := start.Add(-time.Hour)
+	pending, err := repo.InsertBooking(ctx, pool, &models.Booking{UserID: "22222222-2222-2222-2222-222222222222", CandidateEmail: "p@example.com", StartAtUTC: start, EndAtUTC: start.Add(time.Hour), Status: "pending", PendingSince: &since})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repo.FindCandidateOverlap(ctx, pool, "p@example.com", start, start.Add(time.Hour), ""); err != nil || got != pending {
+		t.Errorf("pending booking: overlap = %q (%v), want %q", got, err, pending)
+	}
+
+	if _, err := repo.CancelBooking(ctx, pool, id, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repo.FindCandidateOverlap(ctx, pool, "c@example.com", start, start.Add(time.Hour), ""); err != nil || got != "" {
+		t.Errorf("cancelled booking: overlap = %q (%v), want none", got, err)
+	}
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+type CandidateTokenRepo struct{}
+
+func NewCandidateTokenRepo() *CandidateTokenRepo { return &CandidateTokenRepo{} }
+
+// EnsureCandidateToken returns the live token for email, creating one when
+// there is none. created reports whether this call created it.
+func (r *CandidateTokenRepo) EnsureCandidateToken(ctx context.Context, q repository.Querier, email string) (*models.CandidateToken, bool, error) {
+	var t models.CandidateToken
+	err := q.QueryRow(ctx, `INSERT INTO candidate_tokens (email, created_at) VALUES ($1, now())
+		ON CONFLICT (email) WHERE revoked_at IS NULL DO NOTHING
+		RETURNING id, email, created_at, revoked_at`, email).Scan(&t.ID, &t.Email, &t.CreatedAt, &t.RevokedAt)
+	if err == nil {
+		return &t, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, err
+	}
+	err = q.QueryRow(ctx, `SELECT id, email, created_at, revoked_at FROM candidate_tokens
+		WHERE email=$1 AND revoked_at IS NULL`, email).Scan(&t.ID, &t.Email, &t.CreatedAt, &t.RevokedAt)
+	if err != nil {
+		return nil, false, err
+	}
+	return &t, false, nil
+}
+
+func (r *CandidateTokenRepo) GetCandidateToken(ctx context.Context, q repository.Querier, id string) (*models.CandidateToken, error) {
+	var t models.CandidateToken
+	err := q.QueryRow(ctx, `SELECT id, email, created_at, revoked_at FROM candidate_tokens WHERE id=$1`, id).
+		Scan(&t.ID, &t.Email, &t.CreatedAt, &t.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *CandidateTokenRepo) RevokeCandidateToken(ctx context.Context, q repository.Querier, id string) (int64, error) {
+	res, err := q.Exec(ctx, `UPDATE candidate_tokens SET revoked_at=now() WHERE id=$1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"scheduler-service/internal/repository"
+)
+
+// isUniqueViolation reports whether err is a Postgres unique_violation (23505).
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// translateConstraintError maps constraint violations to repository domain
+// errors, naming the violated constraint. Other errors are returned unchanged.
+func translateConstraintError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch {
+	case pgErr.Code == "23505":
+		return fmt.Errorf("%w (%s)", repository.ErrConflict, pgErr.ConstraintName)
+	case pgErr.Code == "23514" && pgErr.ConstraintName == "bookings_window_valid":
+		return repository.ErrInvalidBookingWindow
+	}
+	return err
+}
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+
+	"scheduler-service/internal/repository"
+)
+
+type OAuthStateRepo struct{}
+
+func NewOAuthStateRepo() *OAuthStateRepo { return &OAuthStateRepo{} }
+
+// InsertOAuthState records a pending state, dropping states that expired
+// without being used.
+func (r *OAuthStateRepo) InsertOAuthState(ctx context.Context, q repository.Querier, nonce, userID string, expiresAt repository.AppTime) error {
+	if _, err := q.Exec(ctx, `DELETE FROM oauth_states WHERE expires_at < now()`); err != nil {
+		return err
+	}
+	_, err := q.Exec(ctx, `INSERT INTO oauth_states (nonce, user_id, expires_at, created_at) VALUES ($1, $2, $3, now())`,
+		nonce, userID, expiresAt)
+	return err
+}
+
+// ConsumeOAuthState deletes the pending state and returns its user id;
+// pgx.ErrNoRows when there is no such state, it expired or it was used.
+func (r *OAuthStateRepo) ConsumeOAuthState(ctx context.Context, q repository.Querier, nonce string, now repository.AppTime) (string, error) {
+	var userID string
+	err := q.QueryRow(ctx, `DELETE FROM oauth_states WHERE nonce=$1 AND expires_at >= $2 RETURNING user_id`, nonce, now).Scan(&userID)
+	return userID, err
+}
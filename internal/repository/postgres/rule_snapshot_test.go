@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestBookingRuleSnapshotRoundTrip(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewBookingRepo()
+	userID := "11111111-1111-1111-1111-111111111111"
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+
+	cases := []struct {
+		name     string
+		snapshot *models.BookingRuleSnapshot
+	}{
+		{"from a rule", &models.BookingRuleSnapshot{RuleID: "22222222-2222-2222-2222-222222222222", Title: "Phone screen", Tags: []string{"screen"}}},
+		{"from an override", nil},
+	}
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := start.Add(time.Duration(i) * time.Hour)
+			id, err := repo.InsertBooking(ctx, pool, &models.Booking{UserID: userID, CandidateEmail: "c@example.com", StartAtUTC: s, EndAtUTC: s.Add(time.Hour), RuleSnapshot: tc.snapshot})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := repo.GetBooking(ctx, pool, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.RuleSnapshot, tc.snapshot) {
+				t.Errorf("rule_snapshot = %+v, want %+v", got.RuleSnapshot, tc.snapshot)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+type ScheduleOverrideRepo struct{}
+
+func NewScheduleOverrideRepo() *ScheduleOverrideRepo { return &ScheduleOverrideRepo{} }
+
+func (r *ScheduleOverrideRepo) InsertOverride(ctx context.Context, q repository.Querier, o *models.ScheduleOverride) error {
+	query := `INSERT INTO schedule_overrides
+		(id, user_id, type, start_date, end_date, start_time, end_time, slot_length_minutes, title, created_at)
+		VALUES (gen_random_uuid(), $1, $2, $3::date, $4::date, NULLIF($5, '')::time, NULLIF($6, '')::time, NULLIF($7, 0), $8, now())
+		RETURNING id, created_at`
+	return q.QueryRow(ctx, query, o.UserID, o.Type, o.StartDate, o.EndDate, o.StartTime, o.EndTime, o.SlotLengthMins, o.Title).
+		Scan(&o.ID, &o.CreatedAt)
+}
+
+// ListOverridesInRange returns overrides whose date range intersects
+// [fromDate, toDate], both inclusive YYYY-MM-DD dates.
+func (r *ScheduleOverrideRepo) ListOverridesInRange(ctx context.Context, q repository.Querier, userID string, fromDate, toDate string) ([]models.ScheduleOverride, error) {
+	query := `SELECT id, user_id, type, to_char(start_date, 'YYYY-MM-DD'), to_char(end_date, 'YYYY-MM-DD'),
+		             COALESCE(to_char(start_time, 'HH24:MI'), ''), COALESCE(to_char(end_time, 'HH24:MI'), ''),
+		             COALESCE(slot_length_minutes, 0), title, created_at
+		      FROM schedule_overrides
+		      WHERE user_id=$1 AND start_date <= $3::date AND end_date >= $2::date
+		      ORDER BY start_date, created_at`
+	rows, err := q.Query(ctx, query, userID, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.ScheduleOverride
+	for rows.Next() {
+		var o models.ScheduleOverride
+		if err := rows.Scan(&o.ID, &o.UserID, &o.Type, &o.StartDate, &o.EndDate, &o.StartTime, &o.EndTime,
+			&o.SlotLengthMins, &o.Title, &o.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+func (r *ScheduleOverrideRepo) DeleteOverride(ctx context.Context, q repository.Querier, userID, id string) (int64, error) {
+	res, err := q.Exec(ctx, `DELETE FROM schedule_overrides WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+type SlotHoldRepo struct{}
+
+func NewSlotHoldRepo() *SlotHoldRepo { return &SlotHoldRepo{} }
+
+func (r *SlotHoldRepo) InsertHold(ctx context.Context, q repository.Querier, h *models.SlotHold) error {
+	query := `INSERT INTO slot_holds (id, user_id, start_at_utc, end_at_utc, token, expires_at, created_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, now())
+		RETURNING id, created_at`
+	return q.QueryRow(ctx, query, h.UserID, h.StartAtUTC, h.EndAtUTC, h.Token, h.ExpiresAt).Scan(&h.ID, &h.CreatedAt)
+}
+
+// ListActiveHolds returns unexpired holds for the user overlapping [from, to).
+func (r *SlotHoldRepo) ListActiveHolds(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime) ([]models.SlotHold, error) {
+	query := `SELECT id,user_id,start_at_utc,end_at_utc,expires_at,created_at
+		      FROM slot_holds
+		      WHERE user_id=$1 AND start_at_utc < $3 AND end_at_utc > $2 AND expires_at > now()`
+	rows, err := q.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []models.SlotHold
+	for rows.Next() {
+		var h models.SlotHold
+		if err := rows.Scan(&h.ID, &h.UserID, &h.StartAtUTC, &h.EndAtUTC, &h.ExpiresAt, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, nil
+}
+
+// FindActiveHoldOverlap returns the id of an unexpired hold overlapping
+// [start, end), or "" when the window is free of holds.
+func (r *SlotHoldRepo) FindActiveHoldOverlap(ctx context.Context, q repository.Querier, userID string, start, end repository.AppTime) (string, error) {
+	query := `SELECT id FROM slot_holds
+		       WHERE user_id=$1 AND start_at_utc < $3 AND end_at_utc > $2 AND expires_at > now()
+		       LIMIT 1 FOR UPDATE`
+	var id string
+	err := q.QueryRow(ctx, query, userID, start, end).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	return id, err
+}
+
+// ConsumeHold deletes the unexpired hold matching token, user and window,
+// returning the number of rows removed (0 or 1).
+func (r *SlotHoldRepo) ConsumeHold(ctx context.Context, q repository.Querier, userID, token string, start, end repository.AppTime) (int64, error) {
+	query := `DELETE FROM slot_holds
+		WHERE token=$1 AND user_id=$2 AND start_at_utc=$3 AND end_at_utc=$4 AND expires_at > now()`
+	res, err := q.Exec(ctx, query, token, userID, start, end)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+func (r *SlotHoldRepo) DeleteExpiredHolds(ctx context.Context, q repository.Querier, userID string) (int64, error) {
+	res, err := q.Exec(ctx, `DELETE FROM slot_holds WHERE user_id=$1 AND expires_at <= now()`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
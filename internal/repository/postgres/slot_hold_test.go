@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestSlotHoldExpiry(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewSlotHoldRepo()
+	userID := "11111111-1111-1111-1111-111111111111"
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	cases := []struct {
+		name       string
+		expiresIn  time.Duration
+		wantActive bool
+	}{
+		{"active hold", 5 * time.Minute, true},
+		{"expired hold", -time.Minute, false},
+	}
+	for i, tc := range cases {
+		slotStart := start.Add(time.Duration(i) * time.Hour)
+		slotEnd := slotStart.Add(30 * time.Minute)
+		h := &models.SlotHold{UserID: userID, StartAtUTC: slotStart, EndAtUTC: slotEnd, Token: "hold_" + tc.name, ExpiresAt: time.Now().UTC().Add(tc.expiresIn)}
+		if err := repo.InsertHold(ctx, pool, h); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		listed, err := repo.ListActiveHolds(ctx, pool, userID, slotStart, slotEnd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(listed) == 1; got != tc.wantActive {
+			t.Errorf("%s: listed = %v, want %v", tc.name, got, tc.wantActive)
+		}
+		id, err := repo.FindActiveHoldOverlap(ctx, pool, userID, slotStart, slotEnd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := id == h.ID; got != tc.wantActive {
+			t.Errorf("%s: overlap = %q, want active %v", tc.name, id, tc.wantActive)
+		}
+		n, err := repo.ConsumeHold(ctx, pool, userID, h.Token, slotStart, slotEnd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := n == 1; got != tc.wantActive {
+			t.Errorf("%s: consumed %d, want active %v", tc.name, n, tc.wantActive)
+		}
+	}
+	if n, err := repo.DeleteExpiredHolds(ctx, pool, userID); err != nil || n != 1 {
+		t.Errorf("deleted %d expired holds (%v), want 1", n, err)
+	}
+}
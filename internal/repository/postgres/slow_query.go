@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"scheduler-service/internal/repository"
+)
+
+// beginner is a Querier that can also start transactions, as *pgxpool.Pool can.
+type beginner interface {
+	repository.Querier
+	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error)
+}
+
+// SlowQueryLogger wraps a pool and logs every Query, QueryRow or Exec that
+// takes longer than Threshold. Transactions begun through it are wrapped too,
+// so statements inside them are timed the same way.
+type SlowQueryLogger struct {
+	DB        beginner
+	Threshold time.Duration
+
+	// Logf defaults to log.Printf.
+	Logf func(format string, args ...any)
+}
+
+func NewSlowQueryLogger(db beginner, threshold time.Duration) *SlowQueryLogger {
+	return &SlowQueryLogger{DB: db, Threshold: threshold}
+}
+
+func (l *SlowQueryLogger) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	defer l.observe("query", sql, time.Now())
+	return l.DB.Query(ctx, sql, args...)
+}
+
+// QueryRow times only issuing the query; rows are read when the caller scans.
+func (l *SlowQueryLogger) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	defer l.observe("query_row", sql, time.Now())
+	return l.DB.QueryRow(ctx, sql, args...)
+}
+
+func (l *SlowQueryLogger) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	defer l.observe("exec", sql, time.Now())
+	return l.DB.Exec(ctx, sql, args...)
+}
+
+func (l *SlowQueryLogger) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := l.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &slowQueryTx{Tx: tx, l: l}, nil
+}
+
+func (l *SlowQueryLogger) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	tx, err := l.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &slowQueryTx{Tx: tx, l: l}, nil
+}
+
+func (l *SlowQueryLogger) observe(op, sql string, start time.Time) {
+	d := time.Since(start)
+	if d < l.Threshold {
+		return
+	}
+	logf := l.Logf
+	if logf == nil {
+		logf = log.Printf
+	}
+	logf("slow query op=%s duration_ms=%d sql=%q", op, d.Milliseconds(), strings.Join(strings.Fields(sql), " "))
+}
+
+// slowQueryTx times statements run inside a transaction.
+type slowQueryTx struct {
+	pgx.Tx
+	l *SlowQueryLogger
+}
+
+func (t *slowQueryTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	defer t.l.observe("query", sql, time.Now())
+	return t.Tx.Query(ctx, sql, args...)
+}
+
+func (t *slowQueryTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	defer t.l.observe("query_row", sql, time.Now())
+	return t.Tx.QueryRow(ctx, sql, args...)
+}
+
+func (t *slowQueryTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	defer t.l.observe("exec", sql, time.Now())
+	return t.Tx.Exec(ctx, sql, args...)
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// sleepyDB takes delay to run any statement whose SQL contains "slow".
+type sleepyDB struct {
+	delay time.Duration
+}
+
+func (d sleepyDB) wait(sql string) {
+	if strings.Contains(sql, "slow") {
+		time.Sleep(d.delay)
+	}
+}
+
+func (d sleepyDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	d.wait(sql)
+	return nil, nil
+}
+
+func (d sleepyDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	d.wait(sql)
+	return failingRow{}
+}
+
+func (d sleepyDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	d.wait(sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (d sleepyDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return sleepyTx{db: d}, nil
+}
+
+func (d sleepyDB) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	return sleepyTx{db: d}, nil
+}
+
+type sleepyTx struct {
+	pgx.Tx
+	db sleepyDB
+}
+
+func (t sleepyTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return t.db.Exec(ctx, sql, args...)
+}
+
+func TestSlowQueryLogger(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name    string
+		run     func(l *SlowQueryLogger, sql string)
+		sql     string
+		wantLog string
+	}{
+		{"slow query", func(l *SlowQueryLogger, sql string) { l.Query(ctx, sql) }, "SELECT slow\n\t FROM t", `op=query duration_ms=`},
+		{"slow query row", func(l *SlowQueryLogger, sql string) { l.QueryRow(ctx, sql) }, "SELECT slow", `op=query_row`},
+		{"slow exec", func(l *SlowQueryLogger, sql string) { l.Exec(ctx, sql) }, "UPDATE slow", `op=exec`},
+		{"slow exec in a transaction", func(l *SlowQueryLogger, sql string) {
+			tx, _ := l.Begin(ctx)
+			tx.Exec(ctx, sql)
+		}, "UPDATE slow", `op=exec`},
+		{"fast query", func(l *SlowQueryLogger, sql string) { l.Query(ctx, sql) }, "SELECT fast", ""},
+		{"fast exec in a transaction", func(l *SlowQueryLogger, sql string) {
+			tx, _ := l.BeginTx(ctx, pgx.TxOptions{})
+			tx.Exec(ctx, sql)
+		}, "UPDATE fast", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var logged []string
+			l := NewSlowQueryLogger(sleepyDB{delay: 30 * time.Millisecond}, 20*time.Millisecond)
+			l.Logf = func(format string, args ...any) {
+				logged = append(logged, fmt.Sprintf(format, args...))
+			}
+			tc.run(l, tc.sql)
+
+			if tc.wantLog == "" {
+				if len(logged) != 0 {
+					t.Errorf("logged %q for a fast call", logged)
+				}
+				return
+			}
+			if len(logged) != 1 || !strings.Contains(logged[0], tc.wantLog) {
+				t.Fatalf("logged %q, want one line containing %q", logged, tc.wantLog)
+			}
+			if sql := strings.Join(strings.Fields(tc.sql), " "); !strings.Contains(logged[0], `sql="`+sql+`"`) {
+				t.Errorf("logged %q, want the SQL %q on one line", logged[0], sql)
+			}
+		})
+	}
+}
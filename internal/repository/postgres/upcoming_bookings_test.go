@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestListUpcomingBookingsExcludesPast(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+	repo := NewBookingRepo()
+	userID := "11111111-1111-1111-1111-111111111111"
+	now := time.Now().UTC().Truncate(time.Minute)
+
+	insert := func(offset time.Duration) string {
+		start := now.Add(offset)
+		id, err := repo.InsertBooking(ctx, pool, &models.Booking{UserID: userID, CandidateEmail: "c@example.com", StartAtUTC: start, EndAtUTC: start.Add(30 * time.Minute)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	insert(-48 * time.Hour)
+	insert(-15 * time.Minute)
+	later := insert(48 * time.Hour)
+	soon := insert(time.Hour)
+	cancelled := insert(2 * time.Hour)
+	if _, err := repo.CancelBooking(ctx, pool, cancelled, "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name       string
+		afterStart any
+		afterID    string
+		want       []string
+	}{
+		{"first page", nil, "", []string{soon, later}},
+		{"after the first", now.Add(time.Hour), soon, []string{later}},
+	}
+	for _, tc := range cases {
+		got, err := repo.ListUpcomingBookings(ctx, pool, userID, now, tc.afterStart, tc.afterID, 10)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if len(got) != len(tc.want) {
+			t.Fatalf("%s: got %d bookings, want %d", tc.name, len(got), len(tc.want))
+		}
+		for i, b := range got {
+			if b.ID != tc.want[i] {
+				t.Errorf("%s: booking %d = %s, want %s", tc.name, i, b.ID, tc.want[i])
+			}
+		}
+	}
+}
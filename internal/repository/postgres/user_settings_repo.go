@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+type UserSettingsRepo struct{}
+
+func NewUserSettingsRepo() *UserSettingsRepo { return &UserSettingsRepo{} }
+
+// GetUserSettings returns pgx.ErrNoRows when the user has never saved settings.
+func (r *UserSettingsRepo) GetUserSettings(ctx context.Context, q repository.Querier, userID string) (*models.UserSettings, error) {
+	query := `SELECT user_id, COALESCE(default_calendar_id,''), rolling_days, unavailable_until, COALESCE(holiday_region,''), updated_at
+		      FROM user_settings WHERE user_id=$1`
+	var s models.UserSettings
+	if err := q.QueryRow(ctx, query, userID).Scan(&s.UserID, &s.DefaultCalendarID, &s.RollingDays, &s.UnavailableUntil, &s.HolidayRegion, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *UserSettingsRepo) UpsertUserSettings(ctx context.Context, q repository.Querier, s *models.UserSettings) error {
+	query := `INSERT INTO user_settings (user_id, default_calendar_id, rolling_days, unavailable_until, holiday_region, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, NULLIF($5, ''), now(), now())
+		ON CONFLICT (user_id) DO UPDATE
+		SET default_calendar_id = EXCLUDED.default_calendar_id, rolling_days = EXCLUDED.rolling_days, unavailable_until = EXCLUDED.unavailable_until, holiday_region = EXCLUDED.holiday_region, updated_at = now()
+		RETURNING updated_at`
+	return q.QueryRow(ctx, query, s.UserID, s.DefaultCalendarID, s.RollingDays, s.UnavailableUntil, s.HolidayRegion).Scan(&s.UpdatedAt)
+}
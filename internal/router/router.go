@@ -1,60 +1,217 @@
 package router
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 
 	"scheduler-service/internal/app"
 	"scheduler-service/internal/config"
 	"scheduler-service/internal/handlers"
+	"scheduler-service/internal/repository"
 	"scheduler-service/internal/repository/postgres"
 	"scheduler-service/internal/service"
 )
 
 func Build(appInstance *app.App, cfg *config.Config) *gin.Engine {
-	r := gin.Default()
+	r := gin.New()
+	// nil trusts no proxy, so ClientIP is the connection's address
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+	r.Use(gin.Logger(), app.RequestIDMiddleware(), app.RecoveryMiddleware())
+	r.Use(app.FeatureFlagsMiddleware(service.Flags{StrictUTC: cfg.StrictUTCTimestamps}))
+
+	// Build metadata for operators (unauthenticated)
+	r.GET("/version", handlers.Version)
 
 	// OAuth2 callback (must be before auth middleware)
 	r.GET("/oauth2callback", appInstance.GoogleOAuth2CallbackHandler)
 
+	// Services query through db, which optionally times and logs slow calls
+	var db repository.Querier = appInstance.DB
+	if cfg.SlowQueryMS > 0 {
+		db = postgres.NewSlowQueryLogger(appInstance.DB, time.Duration(cfg.SlowQueryMS)*time.Millisecond)
+	}
+
 	api := r.Group("/api")
 	{
 		// Public endpoint for generating API keys (no auth required)
 		apiKeyRepo := postgres.NewAPIKeyRepo()
-		apiKeyService := service.NewAPIKeyService(appInstance.DB, apiKeyRepo)
+		apiKeyService := service.NewAPIKeyService(db, apiKeyRepo)
+		if cfg.SeedDefaultAvailability {
+			apiKeyService.Avail = postgres.NewAvailabilityRepo()
+			apiKeyService.DefaultAvailability = service.DefaultWeeklyAvailability()
+		}
 		apiKeyHandler := &handlers.APIKeyHandler{Service: apiKeyService}
 		api.POST("/auth/key", apiKeyHandler.GenerateAPIKey)
 
 		// Google Calendar integration routes - no API key required
+		google := app.GoogleConcurrencyMiddleware(cfg.GoogleMaxConcurrency, time.Duration(cfg.GoogleConcurrencyWaitMS)*time.Millisecond)
 		calendar := api.Group("/calendar")
 		{
 			calendar.GET("/auth", appInstance.GoogleAuthHandler)
-			calendar.GET("/events", appInstance.GetGoogleCalendarEvents)
-			calendar.GET("/calendars", appInstance.GetGoogleCalendarList)
-			calendar.POST("/refresh-token", appInstance.RefreshGoogleToken)
-			calendar.POST("/interview", appInstance.CreateInterviewEvent)
+			calendar.GET("/events", google, appInstance.GetGoogleCalendarEvents)
+			calendar.GET("/calendars", google, appInstance.GetGoogleCalendarList)
+			calendar.POST("/refresh-token", google, appInstance.RefreshGoogleToken)
+			calendar.POST("/validate-token", google, appInstance.ValidateGoogleToken)
+			calendar.POST("/interview", google, appInstance.CreateInterviewEvent)
+			calendar.POST("/interview/preview", appInstance.PreviewInterviewEvent)
 		}
 
+		// Admin routes use the admin token instead of API keys; the group is
+		// created before api.Use so it does not inherit API key auth.
+		admin := api.Group("/admin", app.AdminTokenMiddleware(cfg.AdminToken))
+		admin.POST("/keys/bulk", apiKeyHandler.GenerateAPIKeysBulk)
+		admin.GET("/keys/expiring", apiKeyHandler.ListExpiringAPIKeys)
+
 		// All other endpoints require API key authentication
 		api.Use(app.AuthMiddlewareWithDB(appInstance.DB))
 
 		availRepo := postgres.NewAvailabilityRepo()
 		bookingRepo := postgres.NewBookingRepo()
-		availService := service.NewAvailabilityService(appInstance.DB, availRepo, bookingRepo)
-		bookingService := service.NewBookingService(appInstance.DB, bookingRepo, availService)
+		holdRepo := postgres.NewSlotHoldRepo()
+		availService := service.NewAvailabilityService(db, availRepo, bookingRepo)
+		availService.Holds = holdRepo
+		availService.Overrides = postgres.NewScheduleOverrideRepo()
+		availService.Exceptions = postgres.NewAvailabilityExceptionRepo()
+		availService.Settings = postgres.NewUserSettingsRepo()
+		if cfg.BusinessHours != "" {
+			bh, err := service.ParseBusinessHours(cfg.BusinessHours, cfg.BusinessHoursTZ)
+			if err != nil {
+				log.Fatalf("invalid BUSINESS_HOURS: %v", err)
+			}
+			availService.BusinessHours = bh
+		}
+		availService.MaxRulesPerUser = cfg.MaxRulesPerUser
+		availService.SkipPastSlots = cfg.SkipPastSlots
+		availService.DSTRepeatedHour = cfg.DSTRepeatedHour
+		availService.BatchConcurrency = cfg.SlotBatchConcurrency
+		if maxConns := int(appInstance.DB.Config().MaxConns); availService.BatchConcurrency > maxConns {
+			// Leave the pool's connections as the upper bound on parallel queries
+			availService.BatchConcurrency = maxConns
+		}
+		bookingService := service.NewBookingService(db, bookingRepo, availService)
+		bookingService.BlockCandidateOverlap = cfg.BlockCandidateDoubleBooking
+		bookingService.MinCandidateGap = time.Duration(cfg.MinCandidateGapMins) * time.Minute
+		bookingService.AllowedEmailDomains = cfg.AllowedEmailDomains
+		bookingService.Holds = holdRepo
+		bookingService.HoldTTL = time.Duration(cfg.SlotHoldTTLSeconds) * time.Second
+		bookingService.TxIsolation = pgx.TxIsoLevel(cfg.BookingTxIsolation)
+		bookingService.ConfirmationCodeLength = cfg.ConfirmationCodeLength
+		bookingService.RequireApproval = cfg.RequireBookingApproval
+		bookingService.ApprovalTTL = time.Duration(cfg.ApprovalTTLMins) * time.Minute
+		// In-process booking hooks; register implementations (embedding
+		// service.NopBookingHook) with bookingService.Hooks.Register.
+		bookingService.Hooks = &service.BookingHooks{}
+		auditLog := service.NewBookingAuditLog(db, postgres.NewBookingAuditRepo())
+		bookingService.Hooks.Register(auditLog)
+		if cfg.RequireBookingApproval && cfg.ApprovalTTLMins > 0 {
+			interval := time.Duration(cfg.ApprovalExpiryIntervalMinutes) * time.Minute
+			if interval <= 0 {
+				interval = 5 * time.Minute
+			}
+			// Runs on bookingService so every registered hook hears OnExpired
+			go bookingService.RunApprovalExpiry(context.Background(), interval)
+		}
 
-		availHandlers := &handlers.AvailabilityHandlers{DB: appInstance.DB, AvailSv: availService, BookSv: bookingService}
+		settingsService := service.NewUserSettingsService(db, postgres.NewUserSettingsRepo())
+		settingsHandler := &handlers.UserSettingsHandler{Service: settingsService}
+
+		availHandlers := &handlers.AvailabilityHandlers{DB: appInstance.DB, AvailSv: availService, BookSv: bookingService, StreamBatchSize: cfg.BookingStreamBatchSize, CacheMaxAge: time.Duration(cfg.ReadCacheMaxAgeSeconds) * time.Second, EnforceOwnership: cfg.EnforceUserOwnership}
+
+		if cfg.CandidateTokenSecret != "" {
+			candidateService := service.NewCandidateTokenService(db, postgres.NewCandidateTokenRepo(), bookingRepo, []byte(cfg.CandidateTokenSecret))
+			candidateService.Booker = bookingService
+			candidateService.EnforceEmail = cfg.EnforceCandidateTokenEmail
+			// Tokens are issued directly only by admins; to send one to a
+			// candidate on their first booking, register a
+			// service.CandidateTokenHook with a Send for the channel at hand.
+			candidateHandler := &handlers.CandidateHandler{Service: candidateService}
+			// Candidate self-service is authenticated by the token in the path
+			r.GET("/public/candidate/:token/bookings", candidateHandler.ListBookings)
+			r.POST("/public/candidate/:token/bookings", app.RateLimitMiddleware(cfg.BookingRateLimitPerMinute, candidateHandler.RateLimitKey), candidateHandler.CreateBooking)
+			admin.POST("/candidate-tokens", candidateHandler.IssueToken)
+			admin.DELETE("/candidate-tokens/:token_id", candidateHandler.RevokeToken)
+		}
+
+		if cfg.FeedTokenSecret != "" {
+			feedHandler := &handlers.TeamFeedHandler{Service: service.NewTeamFeedService(db, bookingRepo, []byte(cfg.FeedTokenSecret))}
+			// The calendar group predates api.Use, so the feed is authenticated
+			// by its token alone and calendar apps can subscribe to it
+			calendar.GET("/feed.ics", feedHandler.GetFeed)
+			admin.POST("/feed-tokens", feedHandler.IssueToken)
+		}
+
+		// Per-route API key scopes; a write scope also grants the read
+		availRead, availWrite := app.RequireScope(service.ScopeAvailabilityRead), app.RequireScope(service.ScopeAvailabilityWrite)
+		bookRead, bookWrite := app.RequireScope(service.ScopeBookingsRead), app.RequireScope(service.ScopeBookingsWrite)
+		settingsRead, settingsWrite := app.RequireScope(service.ScopeSettingsRead), app.RequireScope(service.ScopeSettingsWrite)
 
 		users := api.Group("/users")
+		users.Use(app.ResolveUserMiddleware(appInstance.DB, cfg.EnforceUserOwnership))
 		{
-			users.POST("/:id/availability", availHandlers.SetAvailability)
-			users.PUT("/:id/availability/:rule_id", availHandlers.UpdateAvailability)
-			users.GET("/:id/availability", availHandlers.ListAvailability)
-			users.GET("/:id/slots", availHandlers.GetSlots)
-			users.POST("/:id/bookings", availHandlers.CreateBooking)
-			users.GET("/:id/bookings", availHandlers.ListBookings)
+			users.POST("/:id/availability", availWrite, availHandlers.SetAvailability)
+			users.PUT("/:id/availability/:rule_id", availWrite, availHandlers.UpdateAvailability)
+			users.GET("/:id/availability", availRead, availHandlers.ListAvailability)
+			users.GET("/:id/availability/effective", availRead, availHandlers.GetEffectiveAvailability)
+			users.GET("/:id/availability/resolve", availRead, availHandlers.ResolveAvailability)
+			users.GET("/:id/availability/export", availRead, availHandlers.ExportAvailability)
+			users.POST("/:id/availability/import", availWrite, availHandlers.ImportAvailability)
+			users.POST("/:id/availability/import-ics", availWrite, availHandlers.ImportICS)
+			users.POST("/:id/availability/one-off", availWrite, availHandlers.CreateOneOffAvailability)
+			users.POST("/:id/availability/clone-to/:to_user_id", availWrite, availHandlers.CloneAvailability)
+			users.POST("/:id/availability/retimezone", availWrite, availHandlers.RetimezoneAvailability)
+			users.POST("/:id/availability/exceptions", availWrite, availHandlers.CreateException)
+			users.GET("/:id/availability/exceptions", availRead, availHandlers.ListExceptions)
+			users.DELETE("/:id/availability/exceptions/:exception_id", availWrite, availHandlers.DeleteException)
+			users.POST("/:id/overrides", availWrite, availHandlers.CreateOverride)
+			users.GET("/:id/overrides", availRead, availHandlers.ListOverrides)
+			users.DELETE("/:id/overrides/:override_id", availWrite, availHandlers.DeleteOverride)
+			users.GET("/:id/slots", availRead, availHandlers.GetSlots)
+			users.GET("/:id/slots/count", availRead, availHandlers.CountSlots)
+			users.GET("/:id/slots/report", availRead, availHandlers.SlotsReport)
+			users.POST("/:id/slots/hold", bookWrite, availHandlers.HoldSlot)
+			users.POST("/:id/slots/check-batch", availRead, availHandlers.CheckSlotsBatch)
+			users.POST("/:id/bookings", bookWrite, app.UserRateLimitMiddleware(cfg.BookingRateLimitPerMinute), availHandlers.CreateBooking)
+			users.GET("/:id/bookings", bookRead, availHandlers.ListBookings)
+			users.GET("/:id/bookings/upcoming", bookRead, availHandlers.ListUpcomingBookings)
+			users.GET("/:id/bookings/stream", bookRead, availHandlers.StreamBookings)
+			users.POST("/:id/bookings/transfer", bookWrite, availHandlers.TransferBookings)
+			users.POST("/:id/bookings/shift", bookWrite, availHandlers.ShiftBookings)
+			users.PUT("/:id/bookings/:booking_id", bookWrite, availHandlers.RescheduleBooking)
+			users.GET("/:id/digest", bookRead, availHandlers.GetDigest)
+			users.GET("/:id/stats", bookRead, availHandlers.GetStats)
+			users.GET("/:id/forecast", bookRead, availHandlers.GetForecast)
+			users.GET("/:id/weeks", availRead, availHandlers.GetWeeks)
+			users.GET("/:id/schedule/conflicts", availRead, availHandlers.GetScheduleConflicts)
+			users.POST("/:id/calendar/reconcile", bookWrite, google, appInstance.ReconcileCalendarHandler(bookingService))
+			users.POST("/:id/calendar/sync", bookWrite, google, appInstance.SyncCalendarHandler(bookingService))
+			users.GET("/:id/settings", settingsRead, settingsHandler.GetSettings)
+			users.PUT("/:id/settings", settingsWrite, settingsHandler.UpdateSettings)
+			users.POST("/:id/vacation", settingsWrite, settingsHandler.SetVacation)
 		}
 
-		api.DELETE("/bookings/:id", availHandlers.CancelBooking)
+		adminHandler := &handlers.AdminHandler{BookSv: bookingService}
+		admin.GET("/bookings", adminHandler.ListBookings)
+
+		api.GET("/slots/batch", availRead, availHandlers.GetBatchSlots)
+		api.GET("/slots/panel", availRead, availHandlers.GetPanelSlot)
+		api.GET("/slots/matrix", availRead, availHandlers.GetSlotMatrix)
+
+		api.DELETE("/bookings/:id", bookWrite, availHandlers.CancelBooking)
+		api.POST("/bookings/:id/approve", bookWrite, availHandlers.ApproveBooking)
+		api.GET("/bookings/:id/state", bookRead, availHandlers.GetBookingState)
+		api.GET("/bookings/by-code/:code", bookRead, availHandlers.GetBookingByCode)
+		historyHandler := &handlers.BookingHistoryHandler{Bookings: bookingService, Audit: auditLog, EnforceOwnership: cfg.EnforceUserOwnership}
+		api.GET("/bookings/:id/history", bookRead, historyHandler.GetHistory)
+		api.GET("/bookings/:id/conference", bookRead, google, appInstance.GetBookingConference)
+
+		// Called when a synced Google event is deleted; kept behind API key auth
+		api.DELETE("/calendar/bookings/by-event/:event_id", bookWrite, availHandlers.CancelBookingByGoogleEvent)
 	}
 
 	return r
@@ -0,0 +1,60 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"scheduler-service/internal/app"
+	"scheduler-service/internal/config"
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+	"scheduler-service/internal/service"
+)
+
+// issuedTokenRepo hands out one candidate token row so a test can sign a
+// token without a database.
+type issuedTokenRepo struct {
+	repository.CandidateTokenRepository
+}
+
+func (issuedTokenRepo) EnsureCandidateToken(ctx context.Context, q repository.Querier, email string) (*models.CandidateToken, bool, error) {
+	return &models.CandidateToken{ID: "66666666-6666-6666-6666-666666666666", Email: email}, true, nil
+}
+
+func TestCandidateBookingRouteIsRateLimited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// The pool connects lazily; the requests below fail validation first
+	pool, err := pgxpool.New(context.Background(), "postgres://scheduler@127.0.0.1:1/scheduler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	cfg := &config.Config{CandidateTokenSecret: "candidate-secret", BookingRateLimitPerMinute: 2}
+	r := Build(&app.App{DB: pool}, cfg)
+
+	tokens := service.NewCandidateTokenService(nil, issuedTokenRepo{}, nil, []byte(cfg.CandidateTokenSecret))
+	token, _, _, err := tokens.Issue(context.Background(), "c@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	book := func(token string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/public/candidate/"+token+"/bookings", strings.NewReader(`{}`)))
+		return w.Code
+	}
+
+	for i, want := range []int{http.StatusBadRequest, http.StatusBadRequest, http.StatusTooManyRequests} {
+		if got := book(token); got != want {
+			t.Errorf("call %d: status %d, want %d", i, got, want)
+		}
+	}
+	if got := book("not-a-token"); got == http.StatusTooManyRequests {
+		t.Error("a token that does not verify shared the limited bucket")
+	}
+}
@@ -0,0 +1,17 @@
+package service
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx naming who is making the change, normally
+// the email of the calling API key.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor stored in ctx, or "" for background work.
+func ActorFrom(ctx context.Context) string {
+	a, _ := ctx.Value(actorKey{}).(string)
+	return a
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestListAllBookingsAcrossUsers(t *testing.T) {
+	booking := func(id, user string, day int, status string) models.Booking {
+		start := monday.AddDate(0, 0, day).Add(9 * time.Hour)
+		return models.Booking{ID: id, UserID: user, CandidateEmail: id + "@example.com", StartAtUTC: start, EndAtUTC: start.Add(30 * time.Minute), Status: status}
+	}
+	repo := newFakeBookingRepo(
+		booking("a-mon", "u1", 0, ""),
+		booking("b-mon", "u2", 0, ""),
+		booking("c-tue", "u3", 1, "cancelled"),
+		booking("d-wed", "u1", 2, "pending"),
+		booking("e-thu", "u2", 3, ""),
+		booking("f-next", "u3", 7, ""),
+	)
+	s := NewBookingService(fakeDB{}, repo, nil)
+	at := func(day int) *time.Time { d := monday.AddDate(0, 0, day); return &d }
+
+	cases := []struct {
+		name      string
+		filter    AdminBookingFilter
+		limit     int
+		want      []string // ids per page, comma-separated
+		wantUsers int
+		wantErr   string
+	}{
+		{"every user", AdminBookingFilter{}, 10, []string{"a-mon,b-mon,c-tue,d-wed,e-thu,f-next"}, 3, ""},
+		{"range", AdminBookingFilter{From: at(1), To: at(4)}, 10, []string{"c-tue,d-wed,e-thu"}, 3, ""},
+		{"status", AdminBookingFilter{Status: "confirmed"}, 10, []string{"a-mon,b-mon,e-thu,f-next"}, 3, ""},
+		{"range and status", AdminBookingFilter{From: at(0), To: at(7), Status: "confirmed"}, 10, []string{"a-mon,b-mon,e-thu"}, 2, ""},
+		{"paged", AdminBookingFilter{}, 4, []string{"a-mon,b-mon,c-tue,d-wed", "e-thu,f-next"}, 3, ""},
+		{"same start on a page boundary", AdminBookingFilter{}, 1, []string{"a-mon", "b-mon", "c-tue", "d-wed", "e-thu", "f-next"}, 3, ""},
+		{"unknown status", AdminBookingFilter{Status: "done"}, 10, nil, 0, "status must be confirmed, pending, cancelled or expired"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var cursor *BookingCursor
+			users := map[string]bool{}
+			for page, want := range tc.want {
+				got, next, err := s.ListAllBookings(context.Background(), tc.filter, cursor, tc.limit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var ids []string
+				for _, b := range got {
+					ids = append(ids, b.ID)
+					users[b.UserID] = true
+				}
+				if strings.Join(ids, ",") != want {
+					t.Fatalf("page %d = %v, want %s", page, ids, want)
+				}
+				if last := page == len(tc.want)-1; last != (next == nil) {
+					t.Fatalf("page %d: next cursor %v, want one only before the last page", page, next)
+				}
+				cursor = next
+			}
+			if tc.wantErr != "" {
+				_, _, err := s.ListAllBookings(context.Background(), tc.filter, nil, tc.limit)
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("err = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if len(users) != tc.wantUsers {
+				t.Errorf("listed bookings of %d users, want %d", len(users), tc.wantUsers)
+			}
+		})
+	}
+}
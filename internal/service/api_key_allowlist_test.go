@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestIPAllowed(t *testing.T) {
+	cases := []struct {
+		name    string
+		allowed []string
+		ip      string
+		want    bool
+	}{
+		{"empty list allows any", nil, "198.51.100.7", true},
+		{"inside the CIDR", []string{"203.0.113.0/24"}, "203.0.113.42", true},
+		{"outside the CIDR", []string{"203.0.113.0/24"}, "203.0.114.1", false},
+		{"single address", []string{"198.51.100.7/32"}, "198.51.100.7", true},
+		{"next to a single address", []string{"198.51.100.7/32"}, "198.51.100.8", false},
+		{"second entry matches", []string{"203.0.113.0/24", "10.0.0.0/8"}, "10.1.2.3", true},
+		{"IPv4-mapped IPv6", []string{"203.0.113.0/24"}, "::ffff:203.0.113.9", true},
+		{"IPv6 inside", []string{"2001:db8::/32"}, "2001:db8::1", true},
+		{"IPv6 outside", []string{"2001:db8::/32"}, "2001:db9::1", false},
+		{"unparsable address", []string{"203.0.113.0/24"}, "not-an-ip", false},
+	}
+	for _, tc := range cases {
+		if got := IPAllowed(tc.allowed, tc.ip); got != tc.want {
+			t.Errorf("%s: IPAllowed(%v, %q) = %v, want %v", tc.name, tc.allowed, tc.ip, got, tc.want)
+		}
+	}
+}
+
+func TestRegenerateAPIKeyCannotWidenAllowedIPs(t *testing.T) {
+	cases := []struct {
+		name      string
+		existing  []string
+		requested []string
+		wantIPs   []string
+		wantErr   error
+	}{
+		{"omitted keeps the list", []string{"203.0.113.0/24"}, nil, []string{"203.0.113.0/24"}, nil},
+		{"omitted keeps any address", nil, nil, nil, nil},
+		{"any address narrows", nil, []string{"203.0.113.0/24"}, []string{"203.0.113.0/24"}, nil},
+		{"narrows to a subnet", []string{"203.0.113.0/24"}, []string{"203.0.113.128/25"}, []string{"203.0.113.128/25"}, nil},
+		{"narrows to an address", []string{"203.0.113.0/24"}, []string{"203.0.113.9"}, []string{"203.0.113.9/32"}, nil},
+		{"wider prefix", []string{"203.0.113.0/24"}, []string{"203.0.0.0/16"}, nil, ErrAPIKeyWiden},
+		{"other network", []string{"203.0.113.0/24"}, []string{"198.51.100.0/24"}, nil, ErrAPIKeyWiden},
+		{"one entry outside", []string{"203.0.113.0/24"}, []string{"203.0.113.9", "198.51.100.7"}, nil, ErrAPIKeyWiden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeAPIKeyRepo{}
+			s := &APIKeyService{DB: fakeDB{}, Repo: repo}
+			ctx := context.Background()
+			if _, _, err := s.GenerateAPIKey(ctx, "a@example.com", "pw", nil, tc.existing, 0); err != nil {
+				t.Fatal(err)
+			}
+
+			_, rec, err := s.GenerateAPIKey(ctx, "a@example.com", "pw", nil, tc.requested, 0)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				if !reflect.DeepEqual(repo.keys[0].AllowedIPs, tc.existing) {
+					t.Errorf("allowed IPs changed to %v despite the rejected request", repo.keys[0].AllowedIPs)
+				}
+				return
+			}
+			if !reflect.DeepEqual(rec.AllowedIPs, tc.wantIPs) {
+				t.Errorf("allowed IPs = %v, want %v", rec.AllowedIPs, tc.wantIPs)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"scheduler-service/internal/models"
+)
+
+func TestGenerateAPIKeysBulk(t *testing.T) {
+	cases := []struct {
+		name         string
+		existing     []models.APIKey
+		emails       []string
+		skipExisting bool
+		want         []string // status per returned email
+		wantErr      error
+	}{
+		{"several new emails", nil, []string{"a@example.com", "b@example.com", "c@example.com"}, false, []string{"created", "created", "created"}, nil},
+		{"duplicates and blanks collapsed", nil, []string{"a@example.com", " a@example.com ", "", "b@example.com"}, false, []string{"created", "created"}, nil},
+		{"existing key skipped", []models.APIKey{{ID: "old", Email: "b@example.com"}}, []string{"a@example.com", "b@example.com"}, true, []string{"created", "skipped"}, nil},
+		{"existing key fails the batch", []models.APIKey{{ID: "old", Email: "b@example.com"}}, []string{"a@example.com", "b@example.com"}, false, nil, ErrAPIKeyConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &APIKeyService{DB: fakeDB{}, Repo: &fakeAPIKeyRepo{keys: tc.existing}}
+			got, err := s.GenerateAPIKeysBulk(context.Background(), tc.emails, tc.skipExisting)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) || got != nil {
+					t.Fatalf("got %v, err = %v, want %v", got, err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d results, want %d", len(got), len(tc.want))
+			}
+			keys, ids := map[string]bool{}, map[string]bool{}
+			for i, k := range got {
+				if k.Status != tc.want[i] {
+					t.Errorf("%s: status = %q, want %q", k.Email, k.Status, tc.want[i])
+				}
+				if ids[k.ID] {
+					t.Errorf("%s: id %s returned twice", k.Email, k.ID)
+				}
+				ids[k.ID] = true
+				if k.Status == "skipped" {
+					if k.APIKey != "" {
+						t.Errorf("%s: skipped email returned a key", k.Email)
+					}
+					continue
+				}
+				if k.APIKey == "" || keys[k.APIKey] {
+					t.Errorf("%s: api_key %q is empty or not unique", k.Email, k.APIKey)
+				}
+				keys[k.APIKey] = true
+				if _, err := s.ValidateAPIKey(context.Background(), k.APIKey); err != nil {
+					t.Errorf("%s: generated key does not validate: %v", k.Email, err)
+				}
+			}
+		})
+	}
+}
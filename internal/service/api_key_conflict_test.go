@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+// conflictingAPIKeyRepo fails writes with a wrapped ErrConflict, as the
+// Postgres repository does on a unique violation.
+type conflictingAPIKeyRepo struct {
+	*fakeAPIKeyRepo
+}
+
+func (r conflictingAPIKeyRepo) CreateAPIKey(ctx context.Context, q repository.Querier, email, keyHash string, scopes, allowedIPs []string, expiresAt repository.AppTime) (*models.APIKey, error) {
+	return nil, fmt.Errorf("%w (api_keys_email_key)", repository.ErrConflict)
+}
+
+func (r conflictingAPIKeyRepo) UpdateAPIKeyHash(ctx context.Context, q repository.Querier, email, keyHash string, scopes, allowedIPs []string, expiresAt repository.AppTime) error {
+	return fmt.Errorf("%w (api_keys_key_hash_key)", repository.ErrConflict)
+}
+
+func TestGenerateAPIKeyReportsConflicts(t *testing.T) {
+	cases := []struct {
+		name     string
+		existing []models.APIKey
+	}{
+		{"new key", nil},
+		{"regenerated key", []models.APIKey{{ID: "k1", Email: "a@example.com", Scopes: []string{ScopeAll}}}},
+	}
+	for _, tc := range cases {
+		repo := conflictingAPIKeyRepo{&fakeAPIKeyRepo{keys: tc.existing}}
+		s := &APIKeyService{DB: fakeDB{}, Repo: repo, Clock: FixedClock(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))}
+		_, _, err := s.GenerateAPIKey(context.Background(), "a@example.com", "pw", nil, nil, 0)
+		if !errors.Is(err, ErrAPIKeyConflict) {
+			t.Errorf("%s: err = %v, want ErrAPIKeyConflict", tc.name, err)
+		}
+	}
+}
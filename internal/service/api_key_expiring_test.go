@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+// fakeAPIKeyRepo lists keys by expiry the way the expires_at query does.
+type fakeAPIKeyRepo struct {
+	repository.APIKeyRepository
+	keys []models.APIKey
+}
+
+func (r *fakeAPIKeyRepo) ListAPIKeysExpiringBetween(ctx context.Context, q repository.Querier, from, to repository.AppTime) ([]models.APIKey, error) {
+	var out []models.APIKey
+	for _, k := range r.keys {
+		if k.ExpiresAt != nil && !k.ExpiresAt.Before(from.(time.Time)) && k.ExpiresAt.Before(to.(time.Time)) {
+			out = append(out, k)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ExpiresAt.Before(*out[j].ExpiresAt) })
+	return out, nil
+}
+
+func (r *fakeAPIKeyRepo) CreateAPIKey(ctx context.Context, q repository.Querier, email, keyHash string, scopes, allowedIPs []string, expiresAt repository.AppTime) (*models.APIKey, error) {
+	exp, _ := expiresAt.(*time.Time)
+	if scopes == nil {
+		scopes = []string{ScopeAll}
+	}
+	k := models.APIKey{ID: fmt.Sprintf("key-%d", len(r.keys)+1), Email: email, KeyHash: keyHash, Scopes: scopes, AllowedIPs: allowedIPs, ExpiresAt: exp}
+	r.keys = append(r.keys, k)
+	return &k, nil
+}
+
+func (r *fakeAPIKeyRepo) find(match func(models.APIKey) bool) (*models.APIKey, error) {
+	for _, k := range r.keys {
+		if match(k) {
+			return &k, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeAPIKeyRepo) GetAPIKeyByHash(ctx context.Context, q repository.Querier, keyHash string) (*models.APIKey, error) {
+	k, _ := r.find(func(k models.APIKey) bool { return k.KeyHash == keyHash })
+	if k == nil {
+		return nil, pgx.ErrNoRows
+	}
+	return k, nil
+}
+
+func (r *fakeAPIKeyRepo) GetAPIKeyByEmail(ctx context.Context, q repository.Querier, email string) (*models.APIKey, error) {
+	return r.find(func(k models.APIKey) bool { return k.Email == email })
+}
+
+func (r *fakeAPIKeyRepo) UpdateAPIKeyHash(ctx context.Context, q repository.Querier, email, keyHash string, scopes, allowedIPs []string, expiresAt repository.AppTime) error {
+	exp, _ := expiresAt.(*time.Time)
+	if scopes == nil {
+		scopes = []string{ScopeAll}
+	}
+	for i := range r.keys {
+		if r.keys[i].Email == email {
+			r.keys[i].KeyHash, r.keys[i].Scopes, r.keys[i].AllowedIPs, r.keys[i].ExpiresAt = keyHash, scopes, allowedIPs, exp
+		}
+	}
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) UpdateLastUsed(ctx context.Context, q repository.Querier, keyHash string) error {
+	return nil
+}
+
+func TestExpiringAPIKeys(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+	repo := &fakeAPIKeyRepo{keys: []models.APIKey{
+		{ID: "later", Email: "later@example.com", ExpiresAt: at(8 * 24 * time.Hour)},
+		{ID: "edge", Email: "edge@example.com", ExpiresAt: at(7 * 24 * time.Hour)},
+		{ID: "soon", Email: "soon@example.com", ExpiresAt: at(6 * 24 * time.Hour)},
+		{ID: "now", Email: "now@example.com", ExpiresAt: at(0)},
+		{ID: "hour", Email: "hour@example.com", ExpiresAt: at(time.Hour)},
+		{ID: "expired", Email: "expired@example.com", ExpiresAt: at(-time.Hour)},
+		{ID: "never", Email: "never@example.com"},
+	}}
+	s := &APIKeyService{Repo: repo, Clock: FixedClock(now)}
+
+	keys, err := s.ExpiringAPIKeys(context.Background(), 7*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, k := range keys {
+		got = append(got, k.ID)
+	}
+	want := []string{"now", "hour", "soon"}
+	if len(got) != len(want) {
+		t.Fatalf("keys = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("keys = %v, want %v", got, want)
+		}
+	}
+
+	if keys, err := s.ExpiringAPIKeys(context.Background(), time.Minute); err != nil || len(keys) != 1 || keys[0].ID != "now" {
+		t.Errorf("one minute window: %v, %v", keys, err)
+	}
+	if _, err := s.ExpiringAPIKeys(context.Background(), 0); err == nil {
+		t.Error("empty window accepted")
+	}
+}
+
+func TestAPIKeyExpiryFollowsClock(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	s := &APIKeyService{DB: fakeDB{}, Repo: &fakeAPIKeyRepo{}, Clock: FixedClock(now)}
+	key, rec, err := s.GenerateAPIKey(context.Background(), "a@example.com", "pw", nil, nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.ExpiresAt == nil || !rec.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expires_at = %v, want an hour after the clock's %s", rec.ExpiresAt, now)
+	}
+	cases := []struct {
+		at      time.Duration
+		wantErr error
+	}{
+		{0, nil},
+		{59 * time.Minute, nil},
+		{time.Hour, ErrAPIKeyExpired},
+		{2 * time.Hour, ErrAPIKeyExpired},
+	}
+	for _, tc := range cases {
+		s.Clock = FixedClock(now.Add(tc.at))
+		if _, err := s.ValidateAPIKey(context.Background(), key); !errors.Is(err, tc.wantErr) {
+			t.Errorf("at +%s: err = %v, want %v", tc.at, err, tc.wantErr)
+		}
+	}
+}
+
+func TestRegenerateAPIKeyCannotExtendExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		existing time.Duration // 0: the key never expires
+		ttl      time.Duration
+		want     time.Duration // 0: no expiry
+	}{
+		{"omitted keeps the expiry", time.Hour, 0, time.Hour},
+		{"longer ttl is capped", time.Hour, 24 * time.Hour, time.Hour},
+		{"shorter ttl narrows", time.Hour, 30 * time.Minute, 30 * time.Minute},
+		{"non-expiring key may gain one", 0, time.Hour, time.Hour},
+		{"non-expiring key stays so", 0, 0, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &APIKeyService{DB: fakeDB{}, Repo: &fakeAPIKeyRepo{}, Clock: FixedClock(now)}
+			ctx := context.Background()
+			if _, _, err := s.GenerateAPIKey(ctx, "a@example.com", "pw", nil, nil, tc.existing); err != nil {
+				t.Fatal(err)
+			}
+
+			_, rec, err := s.GenerateAPIKey(ctx, "a@example.com", "pw", nil, nil, tc.ttl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == 0 {
+				if rec.ExpiresAt != nil {
+					t.Errorf("expires_at = %v, want none", rec.ExpiresAt)
+				}
+				return
+			}
+			if rec.ExpiresAt == nil || !rec.ExpiresAt.Equal(now.Add(tc.want)) {
+				t.Errorf("expires_at = %v, want %s", rec.ExpiresAt, now.Add(tc.want))
+			}
+		})
+	}
+}
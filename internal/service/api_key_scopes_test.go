@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRegenerateAPIKeyCannotWidenScopes(t *testing.T) {
+	cases := []struct {
+		name       string
+		existing   []string
+		requested  []string
+		wantScopes []string
+		wantErr    error
+	}{
+		{"omitted keeps read-only", []string{ScopeBookingsRead}, nil, []string{ScopeBookingsRead}, nil},
+		{"omitted keeps full access", nil, nil, []string{ScopeAll}, nil},
+		{"full access narrows", nil, []string{ScopeBookingsRead}, []string{ScopeBookingsRead}, nil},
+		{"write narrows to read", []string{ScopeBookingsWrite}, []string{ScopeBookingsRead}, []string{ScopeBookingsRead}, nil},
+		{"read-only asks for write", []string{ScopeBookingsRead}, []string{ScopeBookingsWrite}, nil, ErrAPIKeyWiden},
+		{"read-only asks for full access", []string{ScopeBookingsRead}, []string{ScopeAll}, nil, ErrAPIKeyWiden},
+		{"adds another resource", []string{ScopeBookingsRead}, []string{ScopeBookingsRead, ScopeSettingsRead}, nil, ErrAPIKeyWiden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeAPIKeyRepo{}
+			s := &APIKeyService{DB: fakeDB{}, Repo: repo}
+			ctx := context.Background()
+			if _, _, err := s.GenerateAPIKey(ctx, "a@example.com", "pw", tc.existing, nil, 0); err != nil {
+				t.Fatal(err)
+			}
+			oldHash := repo.keys[0].KeyHash
+
+			_, rec, err := s.GenerateAPIKey(ctx, "a@example.com", "pw", tc.requested, nil, 0)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				if repo.keys[0].KeyHash != oldHash {
+					t.Error("key replaced despite the rejected request")
+				}
+				return
+			}
+			if !reflect.DeepEqual(rec.Scopes, tc.wantScopes) {
+				t.Errorf("scopes = %v, want %v", rec.Scopes, tc.wantScopes)
+			}
+		})
+	}
+}
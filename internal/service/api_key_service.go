@@ -6,6 +6,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/netip"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -13,9 +16,196 @@ import (
 	"scheduler-service/internal/repository"
 )
 
+// API key scopes. A key holding ScopeAll may do anything; keys created
+// without requested scopes get it.
+const (
+	ScopeAll               = "*"
+	ScopeAvailabilityRead  = "availability:read"
+	ScopeAvailabilityWrite = "availability:write"
+	ScopeBookingsRead      = "bookings:read"
+	ScopeBookingsWrite     = "bookings:write"
+	ScopeSettingsRead      = "settings:read"
+	ScopeSettingsWrite     = "settings:write"
+)
+
+var knownScopes = map[string]bool{
+	ScopeAll:               true,
+	ScopeAvailabilityRead:  true,
+	ScopeAvailabilityWrite: true,
+	ScopeBookingsRead:      true,
+	ScopeBookingsWrite:     true,
+	ScopeSettingsRead:      true,
+	ScopeSettingsWrite:     true,
+}
+
+// NormalizeScopes trims, de-duplicates and validates requested scopes. An
+// empty request yields nil, which stores full access.
+func NormalizeScopes(scopes []string) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for _, sc := range scopes {
+		sc = strings.ToLower(strings.TrimSpace(sc))
+		if sc == "" || seen[sc] {
+			continue
+		}
+		if !knownScopes[sc] {
+			return nil, fmt.Errorf("unknown scope %q", sc)
+		}
+		seen[sc] = true
+		out = append(out, sc)
+	}
+	return out, nil
+}
+
+// HasScope reports whether granted allows scope. A write scope also allows
+// the matching read.
+func HasScope(granted []string, scope string) bool {
+	for _, g := range granted {
+		if g == ScopeAll || g == scope {
+			return true
+		}
+		if res, ok := strings.CutSuffix(g, ":write"); ok && scope == res+":read" {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeAllowedIPs validates an API key's source allowlist. Entries are
+// CIDRs or single addresses, stored as their masked prefix (a single address
+// becomes a /32 or /128). An empty list yields nil, which allows any address.
+func NormalizeAllowedIPs(entries []string) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		var prefix netip.Prefix
+		if strings.Contains(e, "/") {
+			p, err := netip.ParsePrefix(e)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowed IP %q", e)
+			}
+			prefix = p.Masked()
+		} else {
+			addr, err := netip.ParseAddr(e)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowed IP %q", e)
+			}
+			prefix = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())
+		}
+		if sp := prefix.String(); !seen[sp] {
+			seen[sp] = true
+			out = append(out, sp)
+		}
+	}
+	return out, nil
+}
+
+// IPAllowed reports whether a key restricted to the allowed CIDRs may be used
+// from clientIP. An empty allowlist allows any address; an unparsable address
+// is only allowed then.
+func IPAllowed(allowed []string, clientIP string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(clientIP)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, a := range allowed {
+		if p, err := netip.ParsePrefix(a); err == nil && p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrAPIKeyExpired is returned by ValidateAPIKey for a key past its expiry.
+var ErrAPIKeyExpired = errors.New("api key expired")
+
+// ErrAPIKeyConflict is returned when a key cannot be stored because it collides
+// with an existing key or email.
+var ErrAPIKeyConflict = errors.New("API key already exists")
+
+// ErrAPIKeyWiden is returned when regenerating a key would grant it more than
+// the key it replaces.
+var ErrAPIKeyWiden = errors.New("cannot widen an existing API key")
+
+// narrowScopes returns the scopes of a regenerated key. No requested scopes
+// keep the existing ones; otherwise each must already be granted.
+func narrowScopes(existing, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return existing, nil
+	}
+	for _, sc := range requested {
+		if !HasScope(existing, sc) {
+			return nil, fmt.Errorf("%w: scope %q not granted", ErrAPIKeyWiden, sc)
+		}
+	}
+	return requested, nil
+}
+
+// narrowExpiry returns the expiry of a regenerated key. No requested expiry
+// keeps the existing one, and a later one is capped at it.
+func narrowExpiry(existing, requested *time.Time) *time.Time {
+	if requested == nil || (existing != nil && existing.Before(*requested)) {
+		return existing
+	}
+	return requested
+}
+
+// narrowAllowedIPs returns the allowlist of a regenerated key. No requested
+// entries keep the existing list; otherwise each must fall inside it.
+func narrowAllowedIPs(existing, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return existing, nil
+	}
+	if len(existing) == 0 {
+		return requested, nil
+	}
+	for _, r := range requested {
+		p, err := netip.ParsePrefix(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed IP %q", r)
+		}
+		inside := false
+		for _, e := range existing {
+			if ep, err := netip.ParsePrefix(e); err == nil && ep.Bits() <= p.Bits() && ep.Contains(p.Addr()) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			return nil, fmt.Errorf("%w: %s is outside the allowed IPs", ErrAPIKeyWiden, r)
+		}
+	}
+	return requested, nil
+}
+
 type APIKeyService struct {
-	DB  repository.Querier
+	DB   repository.Querier
 	Repo repository.APIKeyRepository
+	// Avail and DefaultAvailability, when both set, seed a user's rules on
+	// their first key creation if they have none yet.
+	Avail               repository.AvailabilityRepository
+	DefaultAvailability []models.AvailabilityRule
+
+	// Clock supplies the current time; nil uses the wall clock.
+	Clock Clock
+}
+
+// DefaultWeeklyAvailability is the template seeded for new users: 30 minute
+// slots from 09:00 to 17:00, Monday to Friday.
+func DefaultWeeklyAvailability() []models.AvailabilityRule {
+	rules := make([]models.AvailabilityRule, 0, 5)
+	for day := 1; day <= 5; day++ {
+		rules = append(rules, models.AvailabilityRule{DayOfWeek: day, StartTime: "09:00", EndTime: "17:00", SlotLengthMins: 30, Available: true})
+	}
+	return rules
 }
 
 func NewAPIKeyService(db repository.Querier, repo repository.APIKeyRepository) *APIKeyService {
@@ -25,11 +215,31 @@ func NewAPIKeyService(db repository.Querier, repo repository.APIKeyRepository) *
 // GenerateAPIKey creates a new API key for the given email and password
 // For now, it verifies email+password combination and generates a key
 // Later this can be made user-specific
-func (s *APIKeyService) GenerateAPIKey(ctx context.Context, email, password string) (string, *models.APIKey, error) {
+// The key is limited to scopes; none requested grants full access. A
+// non-empty allowedIPs restricts the addresses the key may be used from, and
+// a positive ttl makes the key expire that long from now. The endpoint is
+// unauthenticated, so regenerating an existing key may only narrow it.
+func (s *APIKeyService) GenerateAPIKey(ctx context.Context, email, password string, scopes, allowedIPs []string, ttl time.Duration) (string, *models.APIKey, error) {
 	// Validate email and password
 	if email == "" || password == "" {
 		return "", nil, errors.New("email and password are required")
 	}
+	scopes, err := NormalizeScopes(scopes)
+	if err != nil {
+		return "", nil, err
+	}
+	allowedIPs, err = NormalizeAllowedIPs(allowedIPs)
+	if err != nil {
+		return "", nil, err
+	}
+	if ttl < 0 {
+		return "", nil, errors.New("ttl_seconds must be positive")
+	}
+	var expiresAt *time.Time
+	if ttl > 0 {
+		t := nowUTC(s.Clock).Add(ttl)
+		expiresAt = &t
+	}
 
 	// Check if key already exists for this email
 	existing, err := s.Repo.GetAPIKeyByEmail(ctx, s.DB, email)
@@ -53,8 +263,18 @@ func (s *APIKeyService) GenerateAPIKey(ctx context.Context, email, password stri
 	var apiKeyRecord *models.APIKey
 
 	if existing != nil {
+		if scopes, err = narrowScopes(existing.Scopes, scopes); err != nil {
+			return "", nil, err
+		}
+		if allowedIPs, err = narrowAllowedIPs(existing.AllowedIPs, allowedIPs); err != nil {
+			return "", nil, err
+		}
+		expiresAt = narrowExpiry(existing.ExpiresAt, expiresAt)
 		// Update existing key with new hash (invalidates old key)
-		err = s.Repo.UpdateAPIKeyHash(ctx, s.DB, email, keyHash)
+		err = s.Repo.UpdateAPIKeyHash(ctx, s.DB, email, keyHash, scopes, allowedIPs, expiresAt)
+		if errors.Is(err, repository.ErrConflict) {
+			return "", nil, ErrAPIKeyConflict
+		}
 		if err != nil {
 			return "", nil, fmt.Errorf("failed to update API key: %w", err)
 		}
@@ -65,16 +285,125 @@ func (s *APIKeyService) GenerateAPIKey(ctx context.Context, email, password stri
 		}
 	} else {
 		// Create new API key
-		apiKeyRecord, err = s.Repo.CreateAPIKey(ctx, s.DB, email, keyHash)
+		apiKeyRecord, err = s.createKeyAndSeed(ctx, email, keyHash, scopes, allowedIPs, expiresAt)
+		if errors.Is(err, repository.ErrConflict) {
+			return "", nil, ErrAPIKeyConflict
+		}
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to create API key: %w", err)
+			return "", nil, err
 		}
 	}
 
 	return apiKey, apiKeyRecord, nil
 }
 
-// ValidateAPIKey checks if the provided API key is valid
+// createKeyAndSeed stores a brand-new key and, when seeding is enabled, the
+// default availability of its user, all in one transaction. Rules are only
+// added if the user has none.
+func (s *APIKeyService) createKeyAndSeed(ctx context.Context, email, keyHash string, scopes, allowedIPs []string, expiresAt *time.Time) (*models.APIKey, error) {
+	if s.Avail == nil || len(s.DefaultAvailability) == 0 {
+		rec, err := s.Repo.CreateAPIKey(ctx, s.DB, email, keyHash, scopes, allowedIPs, expiresAt)
+		if err != nil && !errors.Is(err, repository.ErrConflict) {
+			return nil, fmt.Errorf("failed to create API key: %w", err)
+		}
+		return rec, err
+	}
+
+	trx, err := beginTx(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+	defer trx.Rollback(ctx)
+
+	rec, err := s.Repo.CreateAPIKey(ctx, trx, email, keyHash, scopes, allowedIPs, expiresAt)
+	if errors.Is(err, repository.ErrConflict) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+	n, err := s.Avail.CountAvailabilityRules(ctx, trx, rec.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check availability: %w", err)
+	}
+	if n == 0 {
+		for _, tmpl := range s.DefaultAvailability {
+			rule := tmpl
+			rule.UserID = rec.ID
+			rule.Tags = append([]string(nil), tmpl.Tags...)
+			rule.Windows = append([]models.TimeWindow(nil), tmpl.Windows...)
+			if err := s.Avail.InsertAvailabilityRule(ctx, trx, &rule); err != nil {
+				return nil, fmt.Errorf("failed to seed availability: %w", err)
+			}
+		}
+	}
+	if err := trx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// BulkAPIKey is one email's outcome in GenerateAPIKeysBulk. APIKey holds the
+// plaintext key and is only set for created keys.
+type BulkAPIKey struct {
+	Email     string     `json:"email"`
+	Status    string     `json:"status"` // "created" or "skipped"
+	ID        string     `json:"uuid,omitempty"`
+	APIKey    string     `json:"api_key,omitempty"`
+	CreatedAt *time.Time `json:"created_at_utc,omitempty"`
+}
+
+// GenerateAPIKeysBulk creates a key for each email in one transaction.
+// Emails that already have a key are skipped when skipExisting is set;
+// otherwise the first one fails the whole batch. Duplicate emails in the
+// input are collapsed.
+func (s *APIKeyService) GenerateAPIKeysBulk(ctx context.Context, emails []string, skipExisting bool) ([]BulkAPIKey, error) {
+	trx, err := beginTx(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+	defer trx.Rollback(ctx)
+
+	seen := map[string]bool{}
+	out := make([]BulkAPIKey, 0, len(emails))
+	for _, email := range emails {
+		email = strings.TrimSpace(email)
+		if email == "" || seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		existing, err := s.Repo.GetAPIKeyByEmail(ctx, trx, email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing key: %w", err)
+		}
+		if existing != nil {
+			if !skipExisting {
+				return nil, fmt.Errorf("%w: %s", ErrAPIKeyConflict, email)
+			}
+			out = append(out, BulkAPIKey{Email: email, Status: "skipped", ID: existing.ID})
+			continue
+		}
+
+		apiKey := fmt.Sprintf("sk_%s", uuid.New().String())
+		rec, err := s.Repo.CreateAPIKey(ctx, trx, email, hashAPIKey(apiKey), nil, nil, nil)
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, fmt.Errorf("%w: %s", ErrAPIKeyConflict, email)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create API key: %w", err)
+		}
+		createdAt := rec.CreatedAt.UTC()
+		out = append(out, BulkAPIKey{Email: rec.Email, Status: "created", ID: rec.ID, APIKey: apiKey, CreatedAt: &createdAt})
+	}
+	if err := trx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ValidateAPIKey checks if the provided API key is valid. A key past its
+// expiry fails with ErrAPIKeyExpired.
 func (s *APIKeyService) ValidateAPIKey(ctx context.Context, apiKey string) (*models.APIKey, error) {
 	if apiKey == "" {
 		return nil, errors.New("API key is required")
@@ -88,6 +417,9 @@ func (s *APIKeyService) ValidateAPIKey(ctx context.Context, apiKey string) (*mod
 	if err != nil {
 		return nil, errors.New("invalid API key")
 	}
+	if apiKeyRecord.ExpiresAt != nil && !nowUTC(s.Clock).Before(*apiKeyRecord.ExpiresAt) {
+		return nil, ErrAPIKeyExpired
+	}
 
 	// Update last used timestamp
 	_ = s.Repo.UpdateLastUsed(ctx, s.DB, keyHash)
@@ -95,6 +427,29 @@ func (s *APIKeyService) ValidateAPIKey(ctx context.Context, apiKey string) (*mod
 	return apiKeyRecord, nil
 }
 
+// GetAPIKeyByID looks up a key by its id, which doubles as the owning user's
+// id. It returns nil, nil when no such key exists.
+func (s *APIKeyService) GetAPIKeyByID(ctx context.Context, id string) (*models.APIKey, error) {
+	return s.Repo.GetAPIKeyByID(ctx, s.DB, id)
+}
+
+// ExpiringAPIKeys returns the keys that expire within the given duration from
+// now, soonest first. Keys already expired are not included.
+func (s *APIKeyService) ExpiringAPIKeys(ctx context.Context, within time.Duration) ([]models.APIKey, error) {
+	if within <= 0 {
+		return nil, errors.New("window must be positive")
+	}
+	now := nowUTC(s.Clock)
+	keys, err := s.Repo.ListAPIKeysExpiringBetween(ctx, s.DB, now, now.Add(within))
+	if err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		keys = []models.APIKey{}
+	}
+	return keys, nil
+}
+
 // hashEmailPassword creates a hash from email and password combination
 // This is used to verify credentials (for now)
 func hashEmailPassword(email, password string) string {
@@ -108,4 +463,3 @@ func hashAPIKey(apiKey string) string {
 	hash := sha256.Sum256([]byte(apiKey))
 	return hex.EncodeToString(hash[:])
 }
-
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"scheduler-service/internal/models"
+)
+
+// CloneResult is what CloneAvailability wrote to the target user.
+type CloneResult struct {
+	FromUserID string                         `json:"from_user_id"`
+	ToUserID   string                         `json:"to_user_id"`
+	Replaced   bool                           `json:"replaced"`
+	Rules      []models.AvailabilityRule      `json:"rules"`
+	Exceptions []models.AvailabilityException `json:"exceptions,omitempty"`
+}
+
+// CloneAvailability copies the weekly rules of fromUserID to toUserID in one
+// transaction. With replace the target's rules are deleted first; otherwise
+// the copies are added, updating any target rule with the same day and hours.
+// With exceptions the source's upcoming availability exceptions are copied
+// too, replacing the target's upcoming ones when replace is set.
+func (s *AvailabilityService) CloneAvailability(ctx context.Context, fromUserID, toUserID string, replace, exceptions bool) (*CloneResult, error) {
+	if fromUserID == toUserID {
+		return nil, errors.New("cannot clone availability to the same user")
+	}
+	if exceptions && s.Exceptions == nil {
+		return nil, errors.New("availability exceptions not enabled")
+	}
+
+	trx, err := beginTx(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+	defer trx.Rollback(ctx)
+
+	source, err := s.Avail.ListAvailabilityRules(ctx, trx, fromUserID)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]models.AvailabilityRule, len(source))
+	for i, r := range source {
+		r.ID = ""
+		r.Tags = append([]string(nil), r.Tags...)
+		r.Windows = append([]models.TimeWindow(nil), r.Windows...)
+		rules[i] = r
+	}
+	if replace {
+		if _, err := s.Avail.DeleteAvailabilityRules(ctx, trx, toUserID); err != nil {
+			return nil, err
+		}
+	}
+	incoming := len(rules)
+	if !replace {
+		if incoming, err = s.newRuleCount(ctx, trx, toUserID, rules); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkRuleLimit(ctx, trx, toUserID, incoming); err != nil {
+		return nil, err
+	}
+	saved, err := s.insertRules(ctx, trx, toUserID, rules, !replace)
+	if err != nil {
+		return nil, err
+	}
+	if saved == nil {
+		saved = []models.AvailabilityRule{}
+	}
+	res := &CloneResult{FromUserID: fromUserID, ToUserID: toUserID, Replaced: replace, Rules: saved}
+
+	if exceptions {
+		today := nowUTC(s.Clock).Format("2006-01-02")
+		if replace {
+			existing, err := s.Exceptions.ListExceptionsInRange(ctx, trx, toUserID, today, "9999-12-31")
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range existing {
+				if _, err := s.Exceptions.DeleteException(ctx, trx, toUserID, e.ID); err != nil {
+					return nil, err
+				}
+			}
+		}
+		source, err := s.Exceptions.ListExceptionsInRange(ctx, trx, fromUserID, today, "9999-12-31")
+		if err != nil {
+			return nil, err
+		}
+		res.Exceptions = []models.AvailabilityException{}
+		for _, e := range source {
+			e.ID, e.UserID = "", toUserID
+			if err := s.Exceptions.InsertException(ctx, trx, &e); err != nil {
+				return nil, err
+			}
+			res.Exceptions = append(res.Exceptions, e)
+		}
+	}
+
+	if err := trx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
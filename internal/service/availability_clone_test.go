@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+// ruleShapes strips rules down to what a clone must reproduce, ignoring ids,
+// owner and timestamps, in a stable order. Empty collections are compared as
+// nil since inserting a rule normalizes them.
+func ruleShapes(rules []models.AvailabilityRule) []models.AvailabilityRule {
+	out := make([]models.AvailabilityRule, len(rules))
+	for i, r := range rules {
+		r.ID, r.UserID = "", ""
+		r.CreatedAt, r.UpdatedAt = time.Time{}, time.Time{}
+		if len(r.Tags) == 0 {
+			r.Tags = nil
+		}
+		if len(r.TitleTranslations) == 0 {
+			r.TitleTranslations = nil
+		}
+		if len(r.Windows) == 0 {
+			r.Windows = nil
+		}
+		out[i] = r
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].DayOfWeek != out[j].DayOfWeek {
+			return out[i].DayOfWeek < out[j].DayOfWeek
+		}
+		return out[i].StartTime < out[j].StartTime
+	})
+	return out
+}
+
+func TestCloneAvailability(t *testing.T) {
+	ctx := context.Background()
+	source := []models.AvailabilityRule{
+		{DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "12:00", SlotLengthMins: 30, Available: true, Title: "Screen", Tags: []string{"screen"}},
+		{DayOfWeek: int(time.Wednesday), StartTime: "13:00", EndTime: "15:00", SlotLengthMins: 60, BufferMins: 10, Available: true, Timezone: "Europe/Berlin"},
+	}
+	cases := []struct {
+		name      string
+		target    []models.AvailabilityRule
+		replace   bool
+		wantRules func(src, dst []models.AvailabilityRule) []models.AvailabilityRule // from the rules stored before cloning
+	}{
+		{"replace leaves exactly the source's rules", []models.AvailabilityRule{
+			{DayOfWeek: int(time.Tuesday), StartTime: "10:00", EndTime: "11:00", SlotLengthMins: 30, Available: true},
+			{DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "12:00", SlotLengthMins: 15, Available: true},
+		}, true, func(src, dst []models.AvailabilityRule) []models.AvailabilityRule { return src }},
+		{"replace onto a user without rules", nil, true, func(src, dst []models.AvailabilityRule) []models.AvailabilityRule { return src }},
+		{"append keeps the target's other rules", []models.AvailabilityRule{
+			{DayOfWeek: int(time.Tuesday), StartTime: "10:00", EndTime: "11:00", SlotLengthMins: 30, Available: true},
+			{DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "12:00", SlotLengthMins: 15, Available: true},
+		}, false, func(src, dst []models.AvailabilityRule) []models.AvailabilityRule {
+			// The Monday copy overwrites the target's rule with the same hours
+			return append(src, dst[0])
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newFakeServices(time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC))
+			for _, set := range []struct {
+				user  string
+				rules []models.AvailabilityRule
+			}{{"src", source}, {"dst", tc.target}} {
+				for _, r := range set.rules {
+					r.UserID = set.user
+					if err := s.Avail.InsertAvailabilityRule(ctx, s.DB, &r); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			src, _ := s.Avail.ListAvailabilityRules(ctx, s.DB, "src")
+			dst, _ := s.Avail.ListAvailabilityRules(ctx, s.DB, "dst")
+			want := ruleShapes(tc.wantRules(src, dst))
+
+			res, err := s.CloneAvailability(ctx, "src", "dst", tc.replace, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Replaced != tc.replace || len(res.Rules) != len(source) {
+				t.Errorf("result replaced = %v with %d rules, want %v with %d", res.Replaced, len(res.Rules), tc.replace, len(source))
+			}
+			got, _ := s.Avail.ListAvailabilityRules(ctx, s.DB, "dst")
+			if !reflect.DeepEqual(ruleShapes(got), want) {
+				t.Errorf("target rules = %+v, want %+v", ruleShapes(got), want)
+			}
+			if after, _ := s.Avail.ListAvailabilityRules(ctx, s.DB, "src"); !reflect.DeepEqual(after, src) {
+				t.Errorf("source rules changed to %+v", after)
+			}
+		})
+	}
+}
+
+func TestCloneAvailabilityCopiesExceptions(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC))
+	exceptions := &fakeExceptionRepo{}
+	s.Exceptions = exceptions
+	addRule(t, s, "src", time.Monday, "09:00", "11:00", 30)
+	for _, e := range []models.AvailabilityException{
+		{UserID: "src", Date: "2026-02-23", Blocked: true}, // past, not copied
+		{UserID: "src", Date: "2026-03-02", Blocked: true},
+		{UserID: "dst", Date: "2026-03-03", StartTime: "14:00", EndTime: "15:00", SlotLengthMins: 30},
+	} {
+		if err := exceptions.InsertException(ctx, nil, &e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := s.CloneAvailability(ctx, "src", "dst", true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := exceptions.ListExceptionsInRange(ctx, nil, "dst", "2026-01-01", "9999-12-31")
+	dates := []string{}
+	for _, e := range got {
+		dates = append(dates, e.Date)
+	}
+	if want := []string{"2026-03-02"}; !reflect.DeepEqual(dates, want) || len(res.Exceptions) != 1 {
+		t.Errorf("target exceptions = %v (result %d), want %v", dates, len(res.Exceptions), want)
+	}
+}
+
+func TestCloneAvailabilityRejects(t *testing.T) {
+	s, _ := newFakeServices(time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC))
+	cases := []struct {
+		from, to   string
+		exceptions bool
+		wantErr    string
+	}{
+		{"u1", "u1", false, "cannot clone availability to the same user"},
+		{"u1", "u2", true, "availability exceptions not enabled"},
+	}
+	for _, tc := range cases {
+		if _, err := s.CloneAvailability(context.Background(), tc.from, tc.to, true, tc.exceptions); err == nil || err.Error() != tc.wantErr {
+			t.Errorf("%s -> %s: err = %v, want %q", tc.from, tc.to, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestAvailabilityExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(monday)
+	s.Exceptions = &fakeExceptionRepo{}
+	if _, err := s.SetAvailability(ctx, "u1", []models.AvailabilityRule{
+		{DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "11:00", SlotLengthMins: 30, Tags: []string{"screen"}, Available: true},
+		{DayOfWeek: int(time.Wednesday), StartTime: "13:00", EndTime: "15:00", SlotLengthMins: 60, StartOffsetMins: 5, Title: "Onsite", Available: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateException(ctx, "u1", &models.AvailabilityException{Date: "2026-03-04", Blocked: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateException(ctx, "u1", &models.AvailabilityException{Date: "2026-03-07", StartTime: "10:00", EndTime: "11:00", SlotLengthMins: 30}); err != nil {
+		t.Fatal(err)
+	}
+	// u2's own schedule is replaced, not merged
+	addRule(t, s, "u2", time.Friday, "08:00", "09:00", 30)
+	if _, err := s.CreateException(ctx, "u2", &models.AvailabilityException{Date: "2026-03-02", Blocked: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := s.ExportAvailability(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var imported models.AvailabilityDocument
+	if err := json.Unmarshal(raw, &imported); err != nil {
+		t.Fatal(err)
+	}
+	if imported.Version != 2 || len(imported.Exceptions) != 2 {
+		t.Fatalf("exported version %d with %d exceptions, want version 2 with 2", imported.Version, len(imported.Exceptions))
+	}
+	if _, exceptions, err := s.ImportAvailability(ctx, "u2", &imported); err != nil {
+		t.Fatal(err)
+	} else if len(exceptions) != 2 {
+		t.Fatalf("imported %d exceptions, want 2", len(exceptions))
+	}
+
+	week := monday.Add(7 * 24 * time.Hour)
+	for _, user := range []string{"u1", "u2"} {
+		rules, _ := s.ListAvailability(ctx, user)
+		if len(rules) != 2 {
+			t.Fatalf("%s has %d rules, want 2", user, len(rules))
+		}
+	}
+	want, err := s.GenerateAvailableSlots(ctx, "u1", monday, week)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.GenerateAvailableSlots(ctx, "u2", monday, week)
+	if err != nil {
+		t.Fatal(err)
+	}
+	strip := func(slots []Slot) []Slot {
+		out := make([]Slot, len(slots))
+		for i, sl := range slots {
+			sl.RuleID = ""
+			out[i] = sl
+		}
+		return out
+	}
+	if len(want) == 0 || !reflect.DeepEqual(strip(got), strip(want)) {
+		t.Errorf("u2 slots after import = %v, want u1's %v", slotStarts(got), slotStarts(want))
+	}
+}
+
+func TestImportAvailabilityRejectsBadDocuments(t *testing.T) {
+	rule := models.AvailabilityRule{DayOfWeek: 1, StartTime: "09:00", EndTime: "10:00", SlotLengthMins: 30, Available: true}
+	cases := []struct {
+		name    string
+		doc     models.AvailabilityDocument
+		wantErr string
+	}{
+		{"future version", models.AvailabilityDocument{Version: 3, Rules: []models.AvailabilityRule{rule}}, "invalid availability document: unsupported document version 3"},
+		{"no rules field", models.AvailabilityDocument{Version: models.AvailabilityDocumentVersion}, "invalid availability document: document has no rules field"},
+		{"invalid rule", models.AvailabilityDocument{Version: models.AvailabilityDocumentVersion, Rules: []models.AvailabilityRule{rule, {DayOfWeek: 1, StartTime: "10:00", EndTime: "09:00", SlotLengthMins: 30}}}, "invalid availability document: rule 1: end_time must be after start_time"},
+		{"invalid exception", models.AvailabilityDocument{Version: models.AvailabilityDocumentVersion, Rules: []models.AvailabilityRule{rule}, Exceptions: []models.AvailabilityException{{Blocked: true}}}, "invalid availability document: exception 0: date required"},
+		{"exceptions in a version 1 document", models.AvailabilityDocument{Version: 1, Rules: []models.AvailabilityRule{rule}, Exceptions: []models.AvailabilityException{{Date: "2026-03-04", Blocked: true}}}, "invalid availability document: version 1 documents have no exceptions"},
+	}
+	for _, tc := range cases {
+		s, _ := newFakeServices(monday)
+		s.Exceptions = &fakeExceptionRepo{}
+		addRule(t, s, "u2", time.Friday, "08:00", "09:00", 30)
+		_, _, err := s.ImportAvailability(context.Background(), "u2", &tc.doc)
+		if !errors.Is(err, ErrInvalidAvailabilityDocument) || err.Error() != tc.wantErr {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.wantErr)
+		}
+		if rules, _ := s.ListAvailability(context.Background(), "u2"); len(rules) != 1 {
+			t.Errorf("%s: u2 has %d rules after a rejected import, want 1", tc.name, len(rules))
+		}
+	}
+}
+
+func TestImportVersion1DocumentKeepsExceptions(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(monday)
+	s.Exceptions = &fakeExceptionRepo{}
+	if _, err := s.CreateException(ctx, "u2", &models.AvailabilityException{Date: "2026-03-04", Blocked: true}); err != nil {
+		t.Fatal(err)
+	}
+	doc := models.AvailabilityDocument{Version: 1, Rules: []models.AvailabilityRule{{DayOfWeek: 1, StartTime: "09:00", EndTime: "10:00", SlotLengthMins: 30, Available: true}}}
+	if _, _, err := s.ImportAvailability(ctx, "u2", &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.ListExceptions(ctx, "u2", "2026-03-01", "2026-03-31"); len(got) != 1 {
+		t.Errorf("u2 has %d exceptions after a version 1 import, want 1", len(got))
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"scheduler-service/internal/models"
+)
+
+// Availability exceptions are stored on their own but planned like a
+// single-day override: a blocked exception is a one-day blackout and any
+// other a one-day extra_hours override.
+
+func exceptionOverride(e *models.AvailabilityException) *models.ScheduleOverride {
+	o := &models.ScheduleOverride{
+		ID:             e.ID,
+		UserID:         e.UserID,
+		Type:           models.OverrideExtraHours,
+		StartDate:      e.Date,
+		EndDate:        e.Date,
+		StartTime:      e.StartTime,
+		EndTime:        e.EndTime,
+		SlotLengthMins: e.SlotLengthMins,
+		Title:          e.Title,
+	}
+	if e.Blocked {
+		o.Type = models.OverrideBlackout
+	}
+	return o
+}
+
+// validateException checks e with the rules of the override it plans as.
+func validateException(e *models.AvailabilityException) error {
+	if e.Date == "" {
+		return errors.New("date required")
+	}
+	o := exceptionOverride(e)
+	if err := validateOverride(o); err != nil {
+		return err
+	}
+	e.SlotLengthMins = o.SlotLengthMins
+	return nil
+}
+
+// CreateException validates and stores an availability exception.
+func (s *AvailabilityService) CreateException(ctx context.Context, userID string, e *models.AvailabilityException) (*models.AvailabilityException, error) {
+	if s.Exceptions == nil {
+		return nil, errors.New("availability exceptions not enabled")
+	}
+	e.UserID = userID
+	if err := validateException(e); err != nil {
+		return nil, err
+	}
+	if err := s.Exceptions.InsertException(ctx, s.DB, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ListExceptions returns the user's exceptions on dates in [fromDate, toDate].
+func (s *AvailabilityService) ListExceptions(ctx context.Context, userID, fromDate, toDate string) ([]models.AvailabilityException, error) {
+	if s.Exceptions == nil {
+		return nil, errors.New("availability exceptions not enabled")
+	}
+	out, err := s.Exceptions.ListExceptionsInRange(ctx, s.DB, userID, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		out = []models.AvailabilityException{}
+	}
+	return out, nil
+}
+
+// DeleteException removes an exception by id.
+func (s *AvailabilityService) DeleteException(ctx context.Context, userID, id string) error {
+	if s.Exceptions == nil {
+		return errors.New("availability exceptions not enabled")
+	}
+	n, err := s.Exceptions.DeleteException(ctx, s.DB, userID, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("exception not found")
+	}
+	return nil
+}
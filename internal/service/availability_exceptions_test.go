@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func newExceptionTestService(t *testing.T) *AvailabilityService {
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC) // a Sunday
+	s, _ := newFakeServices(now)
+	s.Exceptions = &fakeExceptionRepo{}
+	s.Overrides = &fakeOverrideRepo{}
+	addRule(t, s, "u1", time.Monday, "09:00", "11:00", 30)
+	return s
+}
+
+func slotsOn(t *testing.T, s *AvailabilityService, date string) []string {
+	t.Helper()
+	day, _ := time.Parse("2006-01-02", date)
+	slots, err := s.GenerateAvailableSlots(context.Background(), "u1", day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return slotStarts(slots)
+}
+
+func TestExceptionsApplyToSlots(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name      string
+		exception models.AvailabilityException
+		date      string
+		want      []string
+	}{
+		{"whole day blocked", models.AvailabilityException{Date: "2026-03-02", Blocked: true}, "2026-03-02", []string{}},
+		{"window blocked", models.AvailabilityException{Date: "2026-03-02", StartTime: "09:30", EndTime: "10:30", Blocked: true}, "2026-03-02", []string{"09:00", "10:30"}},
+		{"other date blocked", models.AvailabilityException{Date: "2026-03-09", Blocked: true}, "2026-03-02", []string{"09:00", "09:30", "10:00", "10:30"}},
+		{"extra hours on a ruleless day", models.AvailabilityException{Date: "2026-03-03", StartTime: "14:00", EndTime: "15:00", SlotLengthMins: 30}, "2026-03-03", []string{"14:00", "14:30"}},
+		{"extra hours next to the rule", models.AvailabilityException{Date: "2026-03-02", StartTime: "11:00", EndTime: "12:00", SlotLengthMins: 60}, "2026-03-02", []string{"09:00", "09:30", "10:00", "10:30", "11:00"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newExceptionTestService(t)
+			e := tc.exception
+			if _, err := s.CreateException(ctx, "u1", &e); err != nil {
+				t.Fatal(err)
+			}
+			if got := slotsOn(t, s, tc.date); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("slots = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCreateExceptionValidates(t *testing.T) {
+	s := newExceptionTestService(t)
+	bad := []models.AvailabilityException{
+		{Blocked: true},
+		{Date: "2026-13-01", Blocked: true},
+		{Date: "2026-03-02", StartTime: "10:00", Blocked: true},
+		{Date: "2026-03-02", StartTime: "10:00", EndTime: "09:00", Blocked: true},
+		{Date: "2026-03-02"},
+		{Date: "2026-03-02", StartTime: "10:00", EndTime: "11:00"},
+	}
+	for _, e := range bad {
+		e := e
+		if _, err := s.CreateException(context.Background(), "u1", &e); err == nil {
+			t.Errorf("%+v: no error", e)
+		}
+	}
+}
+
+func TestListAndDeleteExceptionsIgnoreOverrides(t *testing.T) {
+	ctx := context.Background()
+	s := newExceptionTestService(t)
+	override, err := s.CreateOverride(ctx, "u1", &models.ScheduleOverride{Type: models.OverrideExtraHours, StartDate: "2026-03-02", EndDate: "2026-03-02", StartTime: "13:00", EndTime: "14:00", SlotLengthMins: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := s.CreateException(ctx, "u1", &models.AvailabilityException{Date: "2026-03-02", Blocked: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := s.ListExceptions(ctx, "u1", "2026-03-01", "2026-03-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ID != e.ID {
+		t.Fatalf("ListExceptions = %+v, want only %s", list, e.ID)
+	}
+	if err := s.DeleteException(ctx, "u1", override.ID); err == nil || err.Error() != "exception not found" {
+		t.Errorf("deleting an override: err = %v, want exception not found", err)
+	}
+	if err := s.DeleteException(ctx, "u2", e.ID); err == nil || err.Error() != "exception not found" {
+		t.Errorf("deleting another user's exception: err = %v, want exception not found", err)
+	}
+	if err := s.DeleteException(ctx, "u1", e.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := slotsOn(t, s, "2026-03-02"); len(got) != 6 {
+		t.Errorf("after delete slots = %v, want the rule's 4 plus the override's 2", got)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"scheduler-service/internal/models"
+)
+
+// Modes of RetimezoneAvailability.
+const (
+	// RetimezoneRelabel keeps the rules' wall-clock times, so a 09:00 rule
+	// is still at 09:00 in the new zone.
+	RetimezoneRelabel = "relabel"
+	// RetimezoneShift keeps the instants the rules describe, rewriting their
+	// times (and, past midnight, their days) into the new zone.
+	RetimezoneShift = "shift"
+)
+
+// RetimezoneAvailability moves all of userID's weekly rules to the zone tz.
+// In shift mode times are converted with the offsets of both zones at the
+// current time, so a rule keeps its UTC times until either zone next changes
+// its offset. The rules are reissued in one transaction, as by an import, and
+// get new ids.
+func (s *AvailabilityService) RetimezoneAvailability(ctx context.Context, userID, tz, mode string) ([]models.AvailabilityRule, error) {
+	if mode != RetimezoneRelabel && mode != RetimezoneShift {
+		return nil, errors.New("mode must be relabel or shift")
+	}
+	tz = strings.TrimSpace(tz)
+	if tz == "" {
+		return nil, errors.New("timezone required")
+	}
+	newLoc, err := ruleLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	trx, err := beginTx(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+	defer trx.Rollback(ctx)
+
+	rules, err := s.Avail.ListAvailabilityRules(ctx, trx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := nowUTC(s.Clock)
+	_, newOffset := now.In(newLoc).Zone()
+	for i := range rules {
+		r := &rules[i]
+		delta := 0
+		if mode == RetimezoneShift {
+			oldLoc, err := ruleLocation(r.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %w", r.ID, err)
+			}
+			_, oldOffset := now.In(oldLoc).Zone()
+			delta = (newOffset - oldOffset) / 60
+		}
+		if err := shiftRule(r, delta); err != nil {
+			return nil, err
+		}
+		r.ID = ""
+		r.Timezone = tz
+	}
+
+	if _, err := s.Avail.DeleteAvailabilityRules(ctx, trx, userID); err != nil {
+		return nil, err
+	}
+	saved, err := s.insertRules(ctx, trx, userID, rules, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := trx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	if saved == nil {
+		saved = []models.AvailabilityRule{}
+	}
+	return saved, nil
+}
+
+// shiftRule moves r's times by delta minutes, carrying whole days into
+// DayOfWeek. Times are rewritten as HH:MM either way. A rule whose hours
+// would then span midnight cannot be expressed and is rejected.
+func shiftRule(r *models.AvailabilityRule, delta int) error {
+	windows := ruleWindows(*r)
+	first, err := minuteOfDay(windows[0].StartTime)
+	if err != nil {
+		return err
+	}
+	// floor division, so a start shifted before midnight moves a day back
+	days := (first + delta) / (24 * 60)
+	if first+delta < 0 {
+		days = (first+delta+1)/(24*60) - 1
+	}
+	shift := func(t string) (string, error) {
+		m, err := minuteOfDay(t)
+		if err != nil {
+			return "", err
+		}
+		m += delta - days*24*60
+		if m < 0 || m >= 24*60 {
+			return "", fmt.Errorf("rule %s would span midnight in the new timezone", r.ID)
+		}
+		return fmt.Sprintf("%02d:%02d", m/60, m%60), nil
+	}
+	shifted := make([]models.TimeWindow, len(windows))
+	for i, w := range windows {
+		if shifted[i].StartTime, err = shift(w.StartTime); err != nil {
+			return err
+		}
+		if shifted[i].EndTime, err = shift(w.EndTime); err != nil {
+			return err
+		}
+	}
+	if len(r.Windows) > 0 {
+		// StartTime and EndTime are reset to the envelope on validation
+		r.Windows = shifted
+	} else {
+		r.StartTime, r.EndTime = shifted[0].StartTime, shifted[0].EndTime
+	}
+	r.DayOfWeek = ((r.DayOfWeek+days)%7 + 7) % 7
+	return nil
+}
+
+// minuteOfDay parses an HH:MM (or HH:MM:SS) time into minutes after midnight.
+func minuteOfDay(t string) (int, error) {
+	tod, err := parseHHMM(t)
+	if err != nil {
+		return 0, err
+	}
+	return tod.Hour()*60 + tod.Minute(), nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestRetimezoneRelabelKeepsWallClock(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(monday)
+	old := addRule(t, s, "u1", time.Monday, "09:00", "10:00", 30)
+
+	rules, err := s.RetimezoneAvailability(ctx, "u1", "America/New_York", RetimezoneRelabel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 {
+		t.Fatalf("rules = %+v", rules)
+	}
+	r := rules[0]
+	if r.DayOfWeek != int(time.Monday) || r.StartTime != "09:00" || r.EndTime != "10:00" || r.Timezone != "America/New_York" {
+		t.Errorf("rule = %d %s-%s %q, want Monday 09:00-10:00 America/New_York", r.DayOfWeek, r.StartTime, r.EndTime, r.Timezone)
+	}
+	if r.ID == old.ID {
+		t.Error("rule not reissued")
+	}
+	slots, err := s.GenerateAvailableSlots(ctx, "u1", monday, monday.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 09:00 EST
+	if got := slotStarts(slots); !reflect.DeepEqual(got, []string{"14:00", "14:30"}) {
+		t.Errorf("slots = %v, want 14:00 and 14:30 UTC", got)
+	}
+}
+
+func TestRetimezoneShiftKeepsUTCTimes(t *testing.T) {
+	ctx := context.Background()
+	week := monday.AddDate(0, 0, 7)
+	s, _ := newFakeServices(monday)
+	addRule(t, s, "u1", time.Monday, "09:00", "10:00", 30)
+	addRule(t, s, "u1", time.Monday, "02:00", "03:00", 30) // Sunday evening in New York
+	split := models.AvailabilityRule{UserID: "u1", DayOfWeek: int(time.Saturday), SlotLengthMins: 60, Available: true,
+		Windows: []models.TimeWindow{{StartTime: "13:00", EndTime: "14:00"}, {StartTime: "16:00", EndTime: "17:00"}}}
+	if err := validateAvailabilityRule(&split); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Avail.InsertAvailabilityRule(ctx, s.DB, &split); err != nil {
+		t.Fatal(err)
+	}
+	before, err := s.GenerateAvailableSlots(ctx, "u1", monday, week)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := s.RetimezoneAvailability(ctx, "u1", "America/New_York", RetimezoneShift)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type shape struct {
+		day        time.Weekday
+		start, end string
+	}
+	var got []shape
+	for _, r := range rules {
+		if r.Timezone != "America/New_York" {
+			t.Errorf("rule %s timezone = %q", r.ID, r.Timezone)
+		}
+		got = append(got, shape{time.Weekday(r.DayOfWeek), r.StartTime, r.EndTime})
+	}
+	want := []shape{{time.Monday, "04:00", "05:00"}, {time.Sunday, "21:00", "22:00"}, {time.Saturday, "08:00", "12:00"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rules = %v, want %v", got, want)
+	}
+	if w := rules[2].Windows; len(w) != 2 || w[0].StartTime != "08:00" || w[1].EndTime != "12:00" {
+		t.Errorf("windows = %+v", w)
+	}
+
+	after, err := s.GenerateAvailableSlots(ctx, "u1", monday, week)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(before) {
+		t.Fatalf("%d slots after shifting, %d before", len(after), len(before))
+	}
+	for i := range before {
+		if !after[i].StartUTC.Equal(before[i].StartUTC) || !after[i].EndUTC.Equal(before[i].EndUTC) {
+			t.Errorf("slot %d moved from %s to %s", i, before[i].StartUTC, after[i].StartUTC)
+		}
+	}
+}
+
+func TestRetimezoneShiftRejectsRulesSpanningMidnight(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(monday)
+	addRule(t, s, "u1", time.Monday, "12:00", "16:00", 60) // 21:00-01:00 in Tokyo
+
+	if _, err := s.RetimezoneAvailability(ctx, "u1", "Asia/Tokyo", RetimezoneShift); err == nil {
+		t.Fatal("rule spanning midnight accepted")
+	}
+	rules, _ := s.ListAvailability(ctx, "u1")
+	if len(rules) != 1 || rules[0].StartTime != "12:00" || rules[0].Timezone != "" {
+		t.Errorf("rules changed: %+v", rules)
+	}
+	if _, err := s.RetimezoneAvailability(ctx, "u1", "Asia/Tokyo", "move"); err == nil {
+		t.Error("unknown mode accepted")
+	}
+}
@@ -4,8 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+
 	"scheduler-service/internal/models"
 	"scheduler-service/internal/repository"
 )
@@ -14,11 +19,81 @@ type AvailabilityService struct {
 	DB    repository.Querier
 	Avail repository.AvailabilityRepository
 	Book  repository.BookingRepository
+
+	// BatchConcurrency bounds how many users' slots are generated in parallel
+	// by GenerateAvailableSlotsBatch. Values below 1 mean serial generation.
+	BatchConcurrency int
+
+	// Holds, when set, hides slots covered by an unexpired slot hold.
+	Holds repository.SlotHoldRepository
+
+	// MaxRulesPerUser caps how many rules a user may hold; 0 means no cap.
+	MaxRulesPerUser int
+
+	// Overrides, when set, applies date-specific schedule overrides on top of
+	// the weekly rules.
+	Overrides repository.ScheduleOverrideRepository
+
+	// Exceptions, when set, applies one-date availability exceptions the
+	// same way as single-day overrides.
+	Exceptions repository.AvailabilityExceptionRepository
+
+	// Clock supplies the current time; nil uses the wall clock.
+	Clock Clock
+
+	// Settings, when set, applies each user's rolling window (RollingDays)
+	// and vacation (UnavailableUntil).
+	Settings repository.UserSettingsRepository
+
+	// BusinessHours, when set, drops every slot outside the organisation's
+	// business hours, whatever the rules allow.
+	BusinessHours *BusinessHours
+
+	// SkipPastSlots drops slots that have already ended from generated
+	// availability: the result is the full listing without them, but days
+	// entirely in the past are never planned. A request can opt out with the
+	// include_past flag.
+	SkipPastSlots bool
+
+	// DSTRepeatedHour is DSTRepeatedHourBoth or DSTRepeatedHourFirst: whether
+	// rules offer slots in both passes of the hour a fall-back change repeats,
+	// or only the first. Empty means both.
+	DSTRepeatedHour string
+}
+
+// slotOptions tweaks slot generation for internal callers.
+type slotOptions struct {
+	// ignoreHolds keeps held slots in the result, for callers that check
+	// holds themselves (e.g. booking with a hold token).
+	ignoreHolds bool
+	// ignoreBookings keeps booked slots in the result, for callers that
+	// report why a slot is unavailable.
+	ignoreBookings bool
+	// skipPast starts generation at now, skipping days already over.
+	skipPast bool
+	// excludeBooking leaves the booking with this ID out of the booked set,
+	// so its slot shows as free (e.g. when offering reschedule targets).
+	excludeBooking string
 }
 
 type Slot struct {
 	StartUTC time.Time `json:"start_utc"`
 	EndUTC   time.Time `json:"end_utc"`
+	Tags     []string  `json:"tags,omitempty"`
+	Title    string    `json:"title,omitempty"`
+	// RuleID is the availability rule that produced the slot; empty for
+	// slots added by a schedule override.
+	RuleID string `json:"rule_id,omitempty"`
+	// TimezoneFallback marks a slot whose rule's timezone could not be
+	// loaded, so its times were taken as UTC.
+	TimezoneFallback bool `json:"timezone_fallback,omitempty"`
+}
+
+// UserSlots holds one user's result from GenerateAvailableSlotsBatch.
+type UserSlots struct {
+	UserID string `json:"user_id"`
+	Slots  []Slot `json:"slots"`
+	Error  string `json:"error,omitempty"`
 }
 
 func NewAvailabilityService(db repository.Querier, ar repository.AvailabilityRepository, br repository.BookingRepository) *AvailabilityService {
@@ -26,16 +101,103 @@ func NewAvailabilityService(db repository.Querier, ar repository.AvailabilityRep
 }
 
 func (s *AvailabilityService) SetAvailability(ctx context.Context, userID string, rules []models.AvailabilityRule) ([]models.AvailabilityRule, error) {
+	if err := s.checkRuleLimit(ctx, s.DB, userID, len(rules)); err != nil {
+		return nil, err
+	}
+	return s.insertRules(ctx, s.DB, userID, rules, false)
+}
+
+// checkRuleLimit rejects adding incoming rules when the user's total would
+// exceed MaxRulesPerUser.
+func (s *AvailabilityService) checkRuleLimit(ctx context.Context, q repository.Querier, userID string, incoming int) error {
+	if s.MaxRulesPerUser <= 0 {
+		return nil
+	}
+	existing, err := s.Avail.CountAvailabilityRules(ctx, q, userID)
+	if err != nil {
+		return err
+	}
+	if existing+incoming > s.MaxRulesPerUser {
+		return errors.New("availability rule limit exceeded")
+	}
+	return nil
+}
+
+// UpsertAvailability saves rules keyed by (day_of_week, start_time, end_time, timezone),
+// updating an existing rule with the same key instead of adding a duplicate.
+// Only the rules that would be added count against MaxRulesPerUser.
+func (s *AvailabilityService) UpsertAvailability(ctx context.Context, userID string, rules []models.AvailabilityRule) ([]models.AvailabilityRule, error) {
+	// Validate first: split rules only get their key once windows are normalized
+	for i := range rules {
+		if err := validateAvailabilityRule(&rules[i]); err != nil {
+			return nil, err
+		}
+	}
+	added, err := s.newRuleCount(ctx, s.DB, userID, rules)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkRuleLimit(ctx, s.DB, userID, added); err != nil {
+		return nil, err
+	}
+	return s.insertRules(ctx, s.DB, userID, rules, true)
+}
+
+// ruleKey is a rule's natural key; times are cut to HH:MM because the
+// database returns them with seconds.
+type ruleKey struct {
+	day        int
+	start, end string
+	timezone   string
+}
+
+func keyOf(r models.AvailabilityRule) ruleKey {
+	clip := func(t string) string {
+		if len(t) > 5 {
+			return t[:5]
+		}
+		return t
+	}
+	return ruleKey{r.DayOfWeek, clip(r.StartTime), clip(r.EndTime), r.Timezone}
+}
+
+// newRuleCount reports how many distinct keys among rules the user has no
+// rule for yet, i.e. how many rules an upsert of rules would add.
+func (s *AvailabilityService) newRuleCount(ctx context.Context, q repository.Querier, userID string, rules []models.AvailabilityRule) (int, error) {
+	existing, err := s.Avail.ListAvailabilityRules(ctx, q, userID)
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[ruleKey]bool, len(existing))
+	for _, r := range existing {
+		seen[keyOf(r)] = true
+	}
+	added := 0
+	for _, r := range rules {
+		k := keyOf(r)
+		if !seen[k] {
+			seen[k] = true
+			added++
+		}
+	}
+	return added, nil
+}
+
+func (s *AvailabilityService) insertRules(ctx context.Context, q repository.Querier, userID string, rules []models.AvailabilityRule, upsert bool) ([]models.AvailabilityRule, error) {
 	var saved []models.AvailabilityRule
 	for i := range rules {
 		rules[i].UserID = userID
-		now := time.Now().UTC()
+		now := nowUTC(s.Clock)
 		rules[i].CreatedAt = now
 		rules[i].UpdatedAt = now
 		if err := validateAvailabilityRule(&rules[i]); err != nil {
 			return nil, err
 		}
-		if err := s.Avail.InsertAvailabilityRule(ctx, s.DB, &rules[i]); err != nil {
+		save := s.Avail.InsertAvailabilityRule
+		if upsert {
+			save = s.Avail.UpsertAvailabilityRule
+		}
+		if err := save(ctx, q, &rules[i]); err != nil {
 			return nil, err
 		}
 		saved = append(saved, rules[i])
@@ -43,6 +205,116 @@ func (s *AvailabilityService) SetAvailability(ctx context.Context, userID string
 	return saved, nil
 }
 
+// ErrInvalidAvailabilityDocument wraps ImportAvailability failures caused by
+// the document itself rather than by storage.
+var ErrInvalidAvailabilityDocument = errors.New("invalid availability document")
+
+// ExportAvailability returns the user's rules and upcoming exceptions as a
+// portable document.
+func (s *AvailabilityService) ExportAvailability(ctx context.Context, userID string) (*models.AvailabilityDocument, error) {
+	rules, err := s.Avail.ListAvailabilityRules(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []models.AvailabilityRule{}
+	}
+	exceptions := []models.AvailabilityException{}
+	if s.Exceptions != nil {
+		today := nowUTC(s.Clock).Format("2006-01-02")
+		list, err := s.Exceptions.ListExceptionsInRange(ctx, s.DB, userID, today, "9999-12-31")
+		if err != nil {
+			return nil, err
+		}
+		exceptions = append(exceptions, list...)
+	}
+	return &models.AvailabilityDocument{
+		Version:    models.AvailabilityDocumentVersion,
+		ExportedAt: nowUTC(s.Clock),
+		UserID:     userID,
+		Rules:      rules,
+		Exceptions: exceptions,
+	}, nil
+}
+
+// ImportAvailability replaces the user's rules, and their upcoming exceptions,
+// with those in doc. Version 1 documents have no exceptions and leave the
+// user's alone. The document is validated up front, failing with
+// ErrInvalidAvailabilityDocument, and the deletes and inserts run in one
+// transaction so a failure leaves the schedule intact.
+func (s *AvailabilityService) ImportAvailability(ctx context.Context, userID string, doc *models.AvailabilityDocument) ([]models.AvailabilityRule, []models.AvailabilityException, error) {
+	if doc.Version != 1 && doc.Version != models.AvailabilityDocumentVersion {
+		return nil, nil, fmt.Errorf("%w: unsupported document version %d", ErrInvalidAvailabilityDocument, doc.Version)
+	}
+	if doc.Rules == nil {
+		return nil, nil, fmt.Errorf("%w: document has no rules field", ErrInvalidAvailabilityDocument)
+	}
+	rules := make([]models.AvailabilityRule, len(doc.Rules))
+	for i, r := range doc.Rules {
+		r.ID = ""
+		if err := validateAvailabilityRule(&r); err != nil {
+			return nil, nil, fmt.Errorf("%w: rule %d: %v", ErrInvalidAvailabilityDocument, i, err)
+		}
+		rules[i] = r
+	}
+	if doc.Version < 2 && len(doc.Exceptions) > 0 {
+		return nil, nil, fmt.Errorf("%w: version %d documents have no exceptions", ErrInvalidAvailabilityDocument, doc.Version)
+	}
+	replaceExceptions := doc.Version >= 2
+	exceptions := make([]models.AvailabilityException, len(doc.Exceptions))
+	for i, e := range doc.Exceptions {
+		e.ID, e.UserID = "", userID
+		if err := validateException(&e); err != nil {
+			return nil, nil, fmt.Errorf("%w: exception %d: %v", ErrInvalidAvailabilityDocument, i, err)
+		}
+		exceptions[i] = e
+	}
+	if s.Exceptions == nil {
+		if len(exceptions) > 0 {
+			return nil, nil, errors.New("availability exceptions not enabled")
+		}
+		replaceExceptions = false
+	}
+
+	trx, err := beginTx(ctx, s.DB)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer trx.Rollback(ctx)
+
+	if _, err := s.Avail.DeleteAvailabilityRules(ctx, trx, userID); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkRuleLimit(ctx, trx, userID, len(rules)); err != nil {
+		return nil, nil, err
+	}
+	saved, err := s.insertRules(ctx, trx, userID, rules, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if replaceExceptions {
+		today := nowUTC(s.Clock).Format("2006-01-02")
+		existing, err := s.Exceptions.ListExceptionsInRange(ctx, trx, userID, today, "9999-12-31")
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, e := range existing {
+			if _, err := s.Exceptions.DeleteException(ctx, trx, userID, e.ID); err != nil {
+				return nil, nil, err
+			}
+		}
+		for i := range exceptions {
+			if err := s.Exceptions.InsertException(ctx, trx, &exceptions[i]); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if err := trx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+	return saved, exceptions, nil
+}
+
 func (s *AvailabilityService) UpdateAvailability(ctx context.Context, userID, ruleID string, rule *models.AvailabilityRule) (*models.AvailabilityRule, error) {
 	// Fetch existing rule first
 	existing, err := s.Avail.GetAvailabilityRule(ctx, s.DB, userID, ruleID)
@@ -73,86 +345,448 @@ func (s *AvailabilityService) ListAvailability(ctx context.Context, userID strin
 }
 
 func (s *AvailabilityService) ListBookings(ctx context.Context, userID string, from, to time.Time, filtered bool) ([]models.Booking, error) {
-	return s.Book.ListBookings(ctx, s.DB, userID, from, to, filtered)
+	return s.Book.ListBookings(ctx, s.DB, userID, from, to, filtered, 0, 0)
 }
 
 func (s *AvailabilityService) GenerateAvailableSlots(ctx context.Context, userID string, fromUTC, toUTC time.Time) ([]Slot, error) {
+	flags := FlagsFrom(ctx)
+	return s.generateSlots(ctx, userID, fromUTC, toUTC, slotOptions{ignoreHolds: flags.IncludeHeld, skipPast: s.SkipPastSlots && !flags.IncludePast})
+}
+
+// GenerateAvailableSlotsExcluding is GenerateAvailableSlots as if the booking
+// bookingID did not exist, so the slot it occupies is offered again.
+func (s *AvailabilityService) GenerateAvailableSlotsExcluding(ctx context.Context, userID string, fromUTC, toUTC time.Time, bookingID string) ([]Slot, error) {
+	flags := FlagsFrom(ctx)
+	return s.generateSlots(ctx, userID, fromUTC, toUTC, slotOptions{ignoreHolds: flags.IncludeHeld, skipPast: s.SkipPastSlots && !flags.IncludePast, excludeBooking: bookingID})
+}
+
+func (s *AvailabilityService) generateSlots(ctx context.Context, userID string, fromUTC, toUTC time.Time, opts slotOptions) ([]Slot, error) {
+	var available []Slot
+	err := s.eachSlotDay(ctx, userID, fromUTC, toUTC, opts, func(day []Slot) error {
+		available = append(available, day...)
+		return nil
+	})
+	return available, err
+}
+
+// StreamAvailableSlots generates the same slots as GenerateAvailableSlots but
+// passes them to yield one UTC day at a time, in start order, so only a
+// day's slots are held in memory. It stops at the first error from yield.
+func (s *AvailabilityService) StreamAvailableSlots(ctx context.Context, userID string, fromUTC, toUTC time.Time, yield func(day []Slot) error) error {
+	flags := FlagsFrom(ctx)
+	return s.eachSlotDay(ctx, userID, fromUTC, toUTC, slotOptions{ignoreHolds: flags.IncludeHeld, skipPast: s.SkipPastSlots && !flags.IncludePast}, yield)
+}
+
+// eachSlotDay is slot generation: it plans the windows for [fromUTC, toUTC)
+// and loads bookings and holds up front, then cuts the windows into slots a
+// UTC day at a time, yielding each non-empty day sorted by start.
+func (s *AvailabilityService) eachSlotDay(ctx context.Context, userID string, fromUTC, toUTC time.Time, opts slotOptions, yield func(day []Slot) error) error {
+	fromUTC, toUTC, err := s.clampToUserSettings(ctx, userID, fromUTC, toUTC)
+	if err != nil {
+		return err
+	}
+	// Skipping the past drops the slots that ended by now; starting from now
+	// instead of filtering afterwards also leaves the days already over
+	// unplanned. Slots still in progress are kept either way.
+	dataFrom := fromUTC
+	if opts.skipPast {
+		if now := nowUTC(s.Clock); fromUTC.Before(now) {
+			fromUTC = now
+		}
+	}
+	if !fromUTC.Before(toUTC) {
+		return nil
+	}
 	rules, err := s.Avail.ListAvailabilityRules(ctx, s.DB, userID)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if len(rules) == 0 {
-		return nil, nil
+	startDate, endDate := planningDates(fromUTC, toUTC)
+	overrides, err := s.listOverrides(ctx, userID, startDate, endDate)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 && len(overrides) == 0 {
+		return nil
 	}
 
-	var candidate []Slot
-	startDate := fromUTC.Truncate(24 * time.Hour)
-	endDate := toUTC.Truncate(24 * time.Hour)
-	for day := startDate; !day.After(endDate); day = day.Add(24 * time.Hour) {
-		for _, r := range rules {
-			if int(day.Weekday()) != r.DayOfWeek {
+	windows, blocks, err := planRange(startDate, endDate, rules, overrides, s.DSTRepeatedHour)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(windows, func(i, j int) bool { return windows[i].start.Before(windows[j].start) })
+	var maxBuffer, maxSlotLen time.Duration
+	for _, w := range windows {
+		if w.buffer > maxBuffer {
+			maxBuffer = w.buffer
+		}
+		if w.slotLen > maxSlotLen {
+			maxSlotLen = w.slotLen
+		}
+	}
+	if lo := fromUTC.Add(-maxSlotLen); lo.After(dataFrom) {
+		// A slot in progress started up to a slot length before from; load
+		// the bookings and holds it could collide with, as without skipping
+		dataFrom = lo
+	}
+	var booked []models.Booking
+	if !opts.ignoreBookings {
+		// Wide enough for the longest booking starting before from to reach in
+		bookings, err := s.Book.ListBookingsInRange(ctx, s.DB, userID, dataFrom.Add(-repository.MaxBookingDuration-maxBuffer), toUTC.Add(maxBuffer))
+		if err != nil {
+			return err
+		}
+		for _, b := range bookings {
+			if opts.excludeBooking != "" && b.ID == opts.excludeBooking {
 				continue
 			}
-			startTOD, err := parseHHMM(r.StartTime)
-			if err != nil {
-				return nil, err
-			}
-			endTOD, err := parseHHMM(r.EndTime)
-			if err != nil {
-				return nil, err
+			booked = append(booked, b)
+		}
+	}
+	var holds []models.SlotHold
+	if s.Holds != nil && !opts.ignoreHolds {
+		holds, err = s.Holds.ListActiveHolds(ctx, s.DB, userID, dataFrom, toUTC)
+		if err != nil {
+			return err
+		}
+	}
+
+	locale := FlagsFrom(ctx).Locale
+	// next[i] is where windows[i] resumes on the following day
+	next := make([]time.Time, len(windows))
+	for i, w := range windows {
+		next[i] = w.start
+	}
+	for day := fromUTC.Truncate(24 * time.Hour); day.Before(toUTC); day = day.Add(24 * time.Hour) {
+		dayEnd := day.Add(24 * time.Hour)
+		var slots []Slot
+		for i, w := range windows {
+			if !w.start.Before(dayEnd) {
+				break
 			}
-			if !endTOD.After(startTOD) {
-				return nil, fmt.Errorf("end_time must be after start_time for rule %s", r.ID)
+			if w.slotLen <= 0 {
+				continue
 			}
-			y, m, d := day.Date()
-			utcStart := time.Date(y, m, d, startTOD.Hour(), startTOD.Minute(), 0, 0, time.UTC)
-			utcEnd := time.Date(y, m, d, endTOD.Hour(), endTOD.Minute(), 0, 0, time.UTC)
-			slotLen := time.Duration(r.SlotLengthMins) * time.Minute
-			for s0 := utcStart; s0.Add(slotLen).Equal(utcEnd) || s0.Add(slotLen).Before(utcEnd); s0 = s0.Add(slotLen) {
-				startUTC := s0
-				endUTC := s0.Add(slotLen)
-				if !endUTC.After(fromUTC) || !startUTC.Before(toUTC) {
+			s0 := next[i]
+			for ; s0.Before(dayEnd) && !s0.Add(w.slotLen).After(w.end); s0 = s0.Add(w.slotLen) {
+				sl := Slot{StartUTC: s0, EndUTC: s0.Add(w.slotLen)}
+				if !sl.EndUTC.After(fromUTC) || !sl.StartUTC.Before(toUTC) {
+					continue
+				}
+				if overlapsBlock(sl.StartUTC, sl.EndUTC, blocks) || !s.BusinessHours.Contains(sl.StartUTC, sl.EndUTC) {
 					continue
 				}
-				if !r.Available {
+				if slotBooked(sl, w.buffer, booked) || slotHeld(sl, holds) {
 					continue
 				}
-				candidate = append(candidate, Slot{StartUTC: startUTC, EndUTC: endUTC})
+				sl.Tags, sl.Title, sl.RuleID = w.tags, localizedTitle(w.title, w.titles, locale), w.ruleID
+				sl.TimezoneFallback = w.tzFallback
+				slots = append(slots, sl)
 			}
+			next[i] = s0
+		}
+		if len(slots) == 0 {
+			continue
+		}
+		SortSlots(slots)
+		if err := yield(slots); err != nil {
+			return err
 		}
 	}
-	bookings, err := s.Book.ListBookingsInRange(ctx, s.DB, userID, fromUTC.Add(-1*time.Hour), toUTC.Add(1*time.Hour))
-	if err != nil {
+	return nil
+}
+
+// slotBooked reports whether sl, widened by buffer on both sides, overlaps
+// any of bookings.
+func slotBooked(sl Slot, buffer time.Duration, bookings []models.Booking) bool {
+	start, end := sl.StartUTC.Add(-buffer), sl.EndUTC.Add(buffer)
+	for _, b := range bookings {
+		if start.Before(b.EndAtUTC) && end.After(b.StartAtUTC) {
+			return true
+		}
+	}
+	return false
+}
+
+func slotHeld(sl Slot, holds []models.SlotHold) bool {
+	for _, h := range holds {
+		if sl.StartUTC.Before(h.EndAtUTC) && sl.EndUTC.After(h.StartAtUTC) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateAvailableSlotsBatch generates slots for several users, running up to
+// BatchConcurrency generations at once. Results are returned in the order of
+// userIDs; a failure for one user is reported in its entry rather than failing
+// the whole batch.
+func (s *AvailabilityService) GenerateAvailableSlotsBatch(ctx context.Context, userIDs []string, fromUTC, toUTC time.Time) []UserSlots {
+	workers := s.BatchConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	out := make([]UserSlots, len(userIDs))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, userID := range userIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, userID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			out[i].UserID = userID
+			slots, err := s.GenerateAvailableSlots(ctx, userID, fromUTC, toUTC)
+			if err != nil {
+				out[i].Error = err.Error()
+				return
+			}
+			out[i].Slots = slots
+		}(i, userID)
+	}
+	wg.Wait()
+	return out
+}
+
+// GroupSlotsByDay buckets slots by the calendar date of their start in loc,
+// keyed as YYYY-MM-DD.
+func GroupSlotsByDay(slots []Slot, loc *time.Location) map[string][]Slot {
+	out := map[string][]Slot{}
+	for _, sl := range slots {
+		day := sl.StartUTC.In(loc).Format("2006-01-02")
+		out[day] = append(out[day], sl)
+	}
+	return out
+}
+
+// clampToUserSettings narrows [fromUTC, toUTC) to the user's rolling window
+// [now, now+RollingDays) when they have one, and starts it no earlier than
+// their UnavailableUntil. The result may be empty.
+func (s *AvailabilityService) clampToUserSettings(ctx context.Context, userID string, fromUTC, toUTC time.Time) (time.Time, time.Time, error) {
+	st, err := s.userSettings(ctx, userID)
+	if err != nil || st == nil {
+		return fromUTC, toUTC, err
+	}
+	if st.UnavailableUntil != nil && fromUTC.Before(*st.UnavailableUntil) {
+		fromUTC = st.UnavailableUntil.UTC()
+	}
+	if st.RollingDays <= 0 {
+		return fromUTC, toUTC, nil
+	}
+	now := nowUTC(s.Clock)
+	if fromUTC.Before(now) {
+		fromUTC = now
+	}
+	if limit := now.AddDate(0, 0, st.RollingDays); toUTC.After(limit) {
+		toUTC = limit
+	}
+	return fromUTC, toUTC, nil
+}
+
+// UnavailableUntil returns the end of the user's vacation, or nil when they
+// have none.
+func (s *AvailabilityService) UnavailableUntil(ctx context.Context, userID string) (*time.Time, error) {
+	st, err := s.userSettings(ctx, userID)
+	if err != nil || st == nil {
 		return nil, err
 	}
-	booked := map[int64]struct{}{}
-	for _, b := range bookings {
-		booked[b.StartAtUTC.Unix()] = struct{}{}
+	return st.UnavailableUntil, nil
+}
+
+// userSettings returns the user's saved settings, or nil when Settings is
+// unset or they have none.
+func (s *AvailabilityService) userSettings(ctx context.Context, userID string) (*models.UserSettings, error) {
+	if s.Settings == nil {
+		return nil, nil
 	}
-	var available []Slot
-	for _, sl := range candidate {
-		if _, ok := booked[sl.StartUTC.Unix()]; !ok {
-			available = append(available, sl)
+	st, err := s.Settings.GetUserSettings(ctx, s.DB, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return st, err
+}
+
+// SortSlots orders slots by start time, keeping rule order for equal starts.
+func SortSlots(slots []Slot) {
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].StartUTC.Before(slots[j].StartUTC) })
+}
+
+// TruncateSlots orders slots by start and keeps at most limit of them. When
+// slots were dropped it returns the start of the first dropped slot, which a
+// client can pass as the next from to page forward.
+func TruncateSlots(slots []Slot, limit int) ([]Slot, *time.Time) {
+	SortSlots(slots)
+	if limit <= 0 || len(slots) <= limit {
+		return slots, nil
+	}
+	next := slots[limit].StartUTC
+	return slots[:limit], &next
+}
+
+// FilterSlotsByTag keeps only slots generated from rules carrying tag.
+func FilterSlotsByTag(slots []Slot, tag string) []Slot {
+	tag = normalizeTag(tag)
+	var out []Slot
+	for _, sl := range slots {
+		for _, t := range sl.Tags {
+			if t == tag {
+				out = append(out, sl)
+				break
+			}
 		}
 	}
-	return available, nil
+	return out
+}
+
+// MergeFreeWindows joins slots that touch or overlap into contiguous free
+// windows and keeps those lasting at least minDuration, in start order. A
+// booked or blocked slot is missing from slots, so it splits the windows
+// around it. Windows carry only their start and end.
+func MergeFreeWindows(slots []Slot, minDuration time.Duration) []Slot {
+	sorted := append([]Slot(nil), slots...)
+	SortSlots(sorted)
+	out := []Slot{}
+	for i := 0; i < len(sorted); {
+		w := Slot{StartUTC: sorted[i].StartUTC, EndUTC: sorted[i].EndUTC}
+		for i++; i < len(sorted) && !sorted[i].StartUTC.After(w.EndUTC); i++ {
+			if sorted[i].EndUTC.After(w.EndUTC) {
+				w.EndUTC = sorted[i].EndUTC
+			}
+		}
+		if w.EndUTC.Sub(w.StartUTC) >= minDuration {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// normalizeTags lowercases and de-duplicates tags, always returning a non-nil
+// slice so the NOT NULL tags column is satisfied.
+func normalizeTags(tags []string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, t := range tags {
+		t = normalizeTag(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
 }
 
 func validateAvailabilityRule(rule *models.AvailabilityRule) error {
-	startTime, err := time.Parse("15:04", rule.StartTime)
-	if err != nil {
+	rule.Tags = normalizeTags(rule.Tags)
+	rule.TitleTranslations = normalizeTranslations(rule.TitleTranslations)
+	rule.Timezone = strings.TrimSpace(rule.Timezone)
+	if _, err := ruleLocation(rule.Timezone); err != nil {
 		return err
 	}
-	endTime, err := time.Parse("15:04", rule.EndTime)
-	if err != nil {
+	if err := normalizeRuleWindows(rule); err != nil {
 		return err
 	}
-	if !endTime.After(startTime) {
-		return errors.New("end_time must be after start_time")
+	if rule.StartOffsetMins < 0 {
+		return errors.New("start_offset_minutes must not be negative")
+	}
+	if rule.BufferMins < 0 {
+		return errors.New("buffer_minutes must not be negative")
+	}
+	for _, w := range ruleWindows(*rule) {
+		startTime, err := time.Parse("15:04", w.StartTime)
+		if err != nil {
+			return err
+		}
+		endTime, err := time.Parse("15:04", w.EndTime)
+		if err != nil {
+			return err
+		}
+		if !endTime.After(startTime) {
+			return errors.New("end_time must be after start_time")
+		}
+		if rule.StartOffsetMins > 0 {
+			offset := time.Duration(rule.StartOffsetMins) * time.Minute
+			if offset+time.Duration(rule.SlotLengthMins)*time.Minute > endTime.Sub(startTime) {
+				return errors.New("start_offset_minutes leaves no room for a slot before end_time")
+			}
+		}
 	}
 	return nil
 }
 
+// normalizeRuleWindows sorts a split rule's windows, rejects overlaps and sets
+// StartTime/EndTime to the windows' envelope. Rules without windows are left
+// as they are.
+func normalizeRuleWindows(rule *models.AvailabilityRule) error {
+	if len(rule.Windows) == 0 {
+		rule.Windows = []models.TimeWindow{}
+		return nil
+	}
+	for _, w := range rule.Windows {
+		if _, err := time.Parse("15:04", w.StartTime); err != nil {
+			return err
+		}
+		if _, err := time.Parse("15:04", w.EndTime); err != nil {
+			return err
+		}
+	}
+	// "15:04" strings order the same as the times they encode
+	sort.Slice(rule.Windows, func(i, j int) bool { return rule.Windows[i].StartTime < rule.Windows[j].StartTime })
+	end := rule.Windows[0].EndTime
+	for _, w := range rule.Windows[1:] {
+		if w.StartTime < end {
+			return errors.New("windows must not overlap")
+		}
+		if w.EndTime > end {
+			end = w.EndTime
+		}
+	}
+	rule.StartTime = rule.Windows[0].StartTime
+	rule.EndTime = end
+	return nil
+}
+
+// ruleWindows returns the time windows a rule generates slots in: its split
+// windows, or the single StartTime-EndTime window.
+func ruleWindows(r models.AvailabilityRule) []models.TimeWindow {
+	if len(r.Windows) > 0 {
+		return r.Windows
+	}
+	return []models.TimeWindow{{StartTime: r.StartTime, EndTime: r.EndTime}}
+}
+
+// AvailabilityRuleWarnings flags legal but suspicious rule shapes, such as a
+// slot length that does not divide the window and leaves an unused gap. It
+// assumes the rule already passed validation.
+func AvailabilityRuleWarnings(rule models.AvailabilityRule) []string {
+	if rule.SlotLengthMins <= 0 {
+		return nil
+	}
+	var warnings []string
+	for _, w := range ruleWindows(rule) {
+		startTOD, err := parseHHMM(w.StartTime)
+		if err != nil {
+			continue
+		}
+		endTOD, err := parseHHMM(w.EndTime)
+		if err != nil {
+			continue
+		}
+		window := int(endTOD.Sub(startTOD).Minutes()) - rule.StartOffsetMins
+		label := fmt.Sprintf("rule %s\u2013%s", startTOD.Format("15:04"), endTOD.Format("15:04"))
+		if window < rule.SlotLengthMins {
+			warnings = append(warnings, fmt.Sprintf("%s with %d-min slots produces no slots", label, rule.SlotLengthMins))
+		} else if gap := window % rule.SlotLengthMins; gap > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s with %d-min slots leaves a %d-min gap", label, rule.SlotLengthMins, gap))
+		}
+	}
+	return warnings
+}
+
 func parseHHMM(s string) (time.Time, error) {
 	if len(s) < 5 {
 		return time.Time{}, fmt.Errorf("invalid time string: %s", s)
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"scheduler-service/internal/models"
+)
+
+func TestUpsertAvailabilityIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(monday)
+	// The limit only counts rules an upsert adds, so repeats stay under it
+	s.MaxRulesPerUser = 1
+	cases := []struct {
+		name  string
+		title string
+		mins  int
+	}{
+		{"first POST", "Screen", 30},
+		{"identical repeat", "Screen", 30},
+		{"mutable fields change", "Onsite", 60},
+	}
+	var firstID string
+	for _, tc := range cases {
+		saved, err := s.UpsertAvailability(ctx, "u1", []models.AvailabilityRule{{DayOfWeek: 1, StartTime: "09:00", EndTime: "12:00", SlotLengthMins: tc.mins, Title: tc.title, Available: true}})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if firstID == "" {
+			firstID = saved[0].ID
+		}
+		rules, _ := s.ListAvailability(ctx, "u1")
+		if len(rules) != 1 || rules[0].ID != firstID {
+			t.Fatalf("%s: rules = %+v, want only %s", tc.name, rules, firstID)
+		}
+		if rules[0].Title != tc.title || rules[0].SlotLengthMins != tc.mins {
+			t.Errorf("%s: stored %q with %d-min slots, want %q with %d", tc.name, rules[0].Title, rules[0].SlotLengthMins, tc.title, tc.mins)
+		}
+	}
+
+	if _, err := s.UpsertAvailability(ctx, "u1", []models.AvailabilityRule{{DayOfWeek: 2, StartTime: "09:00", EndTime: "12:00", SlotLengthMins: 30, Available: true}}); err == nil || err.Error() != "availability rule limit exceeded" {
+		t.Errorf("new key over the limit: err = %v", err)
+	}
+	// the same local times in another timezone are another rule
+	if _, err := s.UpsertAvailability(ctx, "u1", []models.AvailabilityRule{{DayOfWeek: 1, StartTime: "09:00", EndTime: "12:00", SlotLengthMins: 30, Timezone: "America/New_York", Available: true}}); err == nil || err.Error() != "availability rule limit exceeded" {
+		t.Errorf("same times in another timezone over the limit: err = %v", err)
+	}
+	s.MaxRulesPerUser = 2
+	if _, err := s.UpsertAvailability(ctx, "u1", []models.AvailabilityRule{{DayOfWeek: 1, StartTime: "09:00", EndTime: "12:00", SlotLengthMins: 30, Timezone: "America/New_York", Available: true}}); err != nil {
+		t.Fatal(err)
+	}
+	if rules, _ := s.ListAvailability(ctx, "u1"); len(rules) != 2 {
+		t.Errorf("%d rules after upserting another timezone, want 2", len(rules))
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestBookedByReflectsInitiator(t *testing.T) {
+	start := monday.Add(9 * time.Hour)
+	cases := []struct {
+		name        string
+		actor       string
+		wantBy      string
+		wantByEmail string
+		wantSummary string
+	}{
+		{"recruiter for the candidate", "recruiter@example.com", models.BookedByRecruiter, "recruiter@example.com",
+			"recruiter@example.com scheduled candidate c@example.com for 2026-03-02 09:00 UTC"},
+		{"candidate for themselves", "C@Example.com", models.BookedByCandidate, "C@Example.com", "c@example.com booked 2026-03-02 09:00 UTC"},
+		{"no caller", "", "", "", "c@example.com booked 2026-03-02 09:00 UTC"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u1", time.Monday, "09:00", "10:00", 30)
+			audit := NewBookingAuditLog(fakeDB{}, &fakeAuditRepo{})
+			s.Hooks = &BookingHooks{}
+			s.Hooks.Register(audit)
+
+			ctx := context.Background()
+			if tc.actor != "" {
+				ctx = WithActor(ctx, tc.actor)
+			}
+			b, err := s.CreateBooking(ctx, "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			stored := s.Repo.(*fakeBookingRepo).get(b.ID)
+			if stored.BookedBy != tc.wantBy || stored.BookedByEmail != tc.wantByEmail {
+				t.Errorf("booked_by = %q/%q, want %q/%q", stored.BookedBy, stored.BookedByEmail, tc.wantBy, tc.wantByEmail)
+			}
+
+			history, err := audit.History(context.Background(), b.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(history) != 1 {
+				t.Fatalf("history = %v, want the creation", history)
+			}
+			if d := history[0].Details; d["booked_by"] != tc.wantBy || d["summary"] != tc.wantSummary {
+				t.Errorf("payload booked_by = %v, summary = %q; want %q, %q", d["booked_by"], d["summary"], tc.wantBy, tc.wantSummary)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ApproveBooking confirms a booking awaiting approval. Hooks get
+// OnStateChanged from "pending".
+func (s *BookingService) ApproveBooking(ctx context.Context, id string) error {
+	n, err := s.Repo.ApproveBooking(ctx, s.DB, id)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		if len(s.Hooks.snapshot()) > 0 {
+			if b, err := s.Repo.GetBooking(ctx, s.DB, id); err == nil {
+				s.Hooks.stateChanged(ctx, *b, "pending")
+			}
+		}
+		return nil
+	}
+	if _, err := s.Repo.GetBookingStatus(ctx, s.DB, id); err == pgx.ErrNoRows {
+		return errors.New("booking not found")
+	} else if err != nil {
+		return err
+	}
+	return errors.New("booking not pending")
+}
+
+// ExpirePendingBookings expires the bookings pending for longer than
+// ApprovalTTL, which frees their slots, and returns how many expired. Hooks
+// get OnExpired for each so the candidate can be told. A zero TTL expires
+// nothing.
+func (s *BookingService) ExpirePendingBookings(ctx context.Context) (int, error) {
+	if s.ApprovalTTL <= 0 {
+		return 0, nil
+	}
+	ids, err := s.Repo.ExpirePendingBefore(ctx, s.DB, nowUTC(s.Clock).Add(-s.ApprovalTTL))
+	if err != nil {
+		return 0, err
+	}
+	if len(s.Hooks.snapshot()) > 0 {
+		for _, id := range ids {
+			if b, err := s.Repo.GetBooking(ctx, s.DB, id); err == nil {
+				s.Hooks.expired(ctx, *b)
+			}
+		}
+	}
+	return len(ids), nil
+}
+
+// RunApprovalExpiry expires unapproved bookings once at start and then every
+// interval, until ctx is done.
+func (s *BookingService) RunApprovalExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n, err := s.ExpirePendingBookings(ctx)
+		if err != nil {
+			log.Printf("pending booking expiry failed: %v", err)
+		} else if n > 0 {
+			log.Printf("expired %d bookings pending approval longer than %s", n, s.ApprovalTTL)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
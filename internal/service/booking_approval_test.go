@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+// expiryRecorder records the bookings OnExpired was called for.
+type expiryRecorder struct {
+	NopBookingHook
+	expired []models.Booking
+}
+
+func (r *expiryRecorder) OnExpired(ctx context.Context, b models.Booking) error {
+	r.expired = append(r.expired, b)
+	return nil
+}
+
+func TestPendingBookingExpiresAfterTTL(t *testing.T) {
+	start := monday.Add(9 * time.Hour)
+	cases := []struct {
+		name        string
+		approve     bool
+		elapsed     time.Duration
+		wantStatus  string
+		wantExpired int
+	}{
+		{"within the TTL", false, 29 * time.Minute, "pending", 0},
+		{"past the TTL", false, 31 * time.Minute, "expired", 1},
+		{"approved before the TTL", true, 31 * time.Minute, "confirmed", 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u1", time.Monday, "09:00", "10:00", 30)
+			s.RequireApproval, s.ApprovalTTL = true, 30*time.Minute
+			hook := &expiryRecorder{}
+			s.Hooks = &BookingHooks{}
+			s.Hooks.Register(hook)
+
+			b, err := s.CreateBooking(context.Background(), "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.Status != "pending" || b.PendingSince == nil || !b.PendingSince.Equal(monday) {
+				t.Fatalf("created %s pending since %v, want pending since %s", b.Status, b.PendingSince, monday)
+			}
+			if slots, _ := avail.GenerateAvailableSlots(context.Background(), "u1", monday, monday.Add(24*time.Hour)); len(slots) != 1 {
+				t.Fatalf("slots while pending = %v, want only 09:30", slotStarts(slots))
+			}
+			if tc.approve {
+				if err := s.ApproveBooking(context.Background(), b.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			s.Clock = FixedClock(monday.Add(tc.elapsed))
+			n, err := s.ExpirePendingBookings(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != tc.wantExpired || len(hook.expired) != tc.wantExpired {
+				t.Errorf("expired %d, hook told of %d, want %d", n, len(hook.expired), tc.wantExpired)
+			}
+			if got := s.Repo.(*fakeBookingRepo).get(b.ID).Status; got != tc.wantStatus {
+				t.Errorf("status = %s, want %s", got, tc.wantStatus)
+			}
+			wantSlots := 1
+			if tc.wantStatus == "expired" {
+				wantSlots = 2
+				if hook.expired[0].CandidateEmail != "c@example.com" {
+					t.Errorf("hook told of %+v, want the candidate's booking", hook.expired[0])
+				}
+			}
+			if slots, _ := avail.GenerateAvailableSlots(context.Background(), "u1", monday, monday.Add(24*time.Hour)); len(slots) != wantSlots {
+				t.Errorf("slots afterwards = %v, want %d", slotStarts(slots), wantSlots)
+			}
+		})
+	}
+}
+
+func TestApproveBookingErrors(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	repo := newFakeBookingRepo(models.Booking{ID: "confirmed", UserID: "u1", StartAtUTC: start, EndAtUTC: start.Add(time.Hour)})
+	s := NewBookingService(fakeDB{}, repo, nil)
+	cases := []struct{ id, want string }{
+		{"confirmed", "booking not pending"},
+		{"missing", "booking not found"},
+	}
+	for _, tc := range cases {
+		if err := s.ApproveBooking(context.Background(), tc.id); err == nil || err.Error() != tc.want {
+			t.Errorf("%s: err = %v, want %q", tc.id, err, tc.want)
+		}
+	}
+}
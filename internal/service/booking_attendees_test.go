@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestCreateBookingKeepsAttendees(t *testing.T) {
+	start := monday.Add(9 * time.Hour)
+	panel := []models.BookingAttendee{
+		{Email: "lead@example.com", Role: "interviewer"},
+		{Email: "shadow@example.com", Role: "shadow", Optional: true},
+	}
+	cases := []struct {
+		name      string
+		attendees []models.BookingAttendee
+	}{
+		{"panel", panel},
+		{"candidate only", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u1", time.Monday, "09:00", "10:00", 30)
+
+			b, err := s.CreateBooking(context.Background(), "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute), Attendees: tc.attendees})
+			if err != nil {
+				t.Fatal(err)
+			}
+			stored := s.Repo.(*fakeBookingRepo).get(b.ID)
+			if !reflect.DeepEqual(b.Attendees, tc.attendees) || !reflect.DeepEqual(stored.Attendees, tc.attendees) {
+				t.Errorf("attendees = %+v, stored %+v; want %+v", b.Attendees, stored.Attendees, tc.attendees)
+			}
+			if stored.CandidateEmail != "c@example.com" {
+				t.Errorf("candidate_email = %q, want the primary contact kept", stored.CandidateEmail)
+			}
+		})
+	}
+}
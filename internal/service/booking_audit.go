@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+// BookingAuditLog is a BookingHook that records each booking change, with the
+// actor from ctx, so a booking's history can be listed later.
+type BookingAuditLog struct {
+	DB   repository.Querier
+	Repo repository.BookingAuditRepository
+}
+
+func NewBookingAuditLog(db repository.Querier, repo repository.BookingAuditRepository) *BookingAuditLog {
+	return &BookingAuditLog{DB: db, Repo: repo}
+}
+
+func (l *BookingAuditLog) OnCreated(ctx context.Context, b models.Booking) error {
+	return l.record(ctx, b.ID, models.BookingAuditCreated, map[string]any{
+		"user_id":         b.UserID,
+		"candidate_email": b.CandidateEmail,
+		"start_at_utc":    b.StartAtUTC.UTC().Format(time.RFC3339),
+		"end_at_utc":      b.EndAtUTC.UTC().Format(time.RFC3339),
+		"booked_by":       b.BookedBy,
+		"summary":         CreatedSummary(b),
+	})
+}
+
+func (l *BookingAuditLog) OnCancelled(ctx context.Context, b models.Booking) error {
+	var details map[string]any
+	if b.CancellationReason != "" {
+		details = map[string]any{"reason": b.CancellationReason}
+	}
+	return l.record(ctx, b.ID, models.BookingAuditCancelled, details)
+}
+
+func (l *BookingAuditLog) OnRescheduled(ctx context.Context, before, after models.Booking) error {
+	details := map[string]any{}
+	if !before.StartAtUTC.Equal(after.StartAtUTC) || !before.EndAtUTC.Equal(after.EndAtUTC) {
+		details["from_start_at_utc"] = before.StartAtUTC.UTC().Format(time.RFC3339)
+		details["from_end_at_utc"] = before.EndAtUTC.UTC().Format(time.RFC3339)
+		details["start_at_utc"] = after.StartAtUTC.UTC().Format(time.RFC3339)
+		details["end_at_utc"] = after.EndAtUTC.UTC().Format(time.RFC3339)
+	}
+	if before.UserID != after.UserID {
+		details["from_user_id"] = before.UserID
+		details["user_id"] = after.UserID
+	}
+	return l.record(ctx, after.ID, models.BookingAuditRescheduled, details)
+}
+
+func (l *BookingAuditLog) OnStateChanged(ctx context.Context, b models.Booking, from string) error {
+	to := b.ConfirmationState
+	if from == "pending" {
+		// approval changes the status, not the confirmation state
+		to = b.Status
+	}
+	return l.record(ctx, b.ID, models.BookingAuditStateChanged, map[string]any{"from": from, "to": to})
+}
+
+func (l *BookingAuditLog) OnExpired(ctx context.Context, b models.Booking) error {
+	var details map[string]any
+	if b.PendingSince != nil {
+		details = map[string]any{"pending_since_utc": b.PendingSince.UTC().Format(time.RFC3339)}
+	}
+	return l.record(ctx, b.ID, models.BookingAuditExpired, details)
+}
+
+func (l *BookingAuditLog) record(ctx context.Context, bookingID, action string, details map[string]any) error {
+	return l.Repo.InsertAuditEntry(ctx, l.DB, &models.BookingAuditEntry{BookingID: bookingID, Action: action, Actor: ActorFrom(ctx), Details: details})
+}
+
+// History returns the recorded changes to a booking, oldest first.
+func (l *BookingAuditLog) History(ctx context.Context, bookingID string) ([]models.BookingAuditEntry, error) {
+	entries, err := l.Repo.ListAuditEntries(ctx, l.DB, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []models.BookingAuditEntry{}
+	}
+	return entries, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestBookingHistoryRecordsLifecycle(t *testing.T) {
+	avail, s := newFakeServices(monday)
+	addRule(t, avail, "u1", time.Monday, "09:00", "12:00", 30)
+	audit := NewBookingAuditLog(fakeDB{}, &fakeAuditRepo{})
+	s.Hooks = &BookingHooks{}
+	s.Hooks.Register(audit)
+
+	start := monday.Add(9 * time.Hour)
+	b, err := s.CreateBooking(WithActor(context.Background(), "recruiter@example.com"), "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	moved := start.Add(2 * time.Hour)
+	if _, err := s.RescheduleBooking(WithActor(context.Background(), "candidate"), "u1", b.ID, moved, moved.Add(30*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CancelBooking(WithActor(context.Background(), "interviewer"), b.ID, "conflict"); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := audit.History(context.Background(), b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][2]string
+	for _, e := range history {
+		got = append(got, [2]string{e.Action, e.Actor})
+	}
+	want := [][2]string{
+		{models.BookingAuditCreated, "recruiter@example.com"},
+		{models.BookingAuditRescheduled, "candidate"},
+		{models.BookingAuditCancelled, "interviewer"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("history = %v, want %v", got, want)
+	}
+	if d := history[1].Details; d["from_start_at_utc"] != "2026-03-02T09:00:00Z" || d["start_at_utc"] != "2026-03-02T11:00:00Z" {
+		t.Errorf("reschedule details = %v", d)
+	}
+	if d := history[2].Details; d["reason"] != "conflict" {
+		t.Errorf("cancel details = %v", d)
+	}
+	if other, _ := audit.History(context.Background(), "booking-other"); other == nil || len(other) != 0 {
+		t.Errorf("history of an unknown booking = %v, want empty", other)
+	}
+}
+
+func TestBookingHistoryRecordsApproval(t *testing.T) {
+	avail, s := newFakeServices(monday)
+	addRule(t, avail, "u1", time.Monday, "09:00", "10:00", 30)
+	s.RequireApproval = true
+	audit := NewBookingAuditLog(fakeDB{}, &fakeAuditRepo{})
+	s.Hooks = &BookingHooks{}
+	s.Hooks.Register(audit)
+
+	start := monday.Add(9 * time.Hour)
+	b, err := s.CreateBooking(context.Background(), "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ApproveBooking(WithActor(context.Background(), "interviewer"), b.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := audit.History(context.Background(), b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[1].Action != models.BookingAuditStateChanged || history[1].Actor != "interviewer" {
+		t.Fatalf("history = %+v, want created then an approval by interviewer", history)
+	}
+	if d := history[1].Details; d["from"] != "pending" || d["to"] != "confirmed" {
+		t.Errorf("approval details = %v, want pending to confirmed", d)
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestSlotsExcludeBookingsWithBuffer(t *testing.T) {
+	cases := []struct {
+		name       string
+		bufferMins int
+		start, end time.Time
+		from       time.Time
+		want       []string
+	}{
+		{"straddles two slots", 0, at(9, 45), at(10, 15), monday, []string{"09:00", "10:30", "11:00", "11:30"}},
+		{"inside one slot", 0, at(10, 5), at(10, 25), monday, []string{"09:00", "09:30", "10:30", "11:00", "11:30"}},
+		{"buffer on both sides", 15, at(10, 0), at(10, 30), monday, []string{"09:00", "11:00", "11:30"}},
+		{"straddling, neighbours just clear", 15, at(9, 45), at(10, 15), monday, []string{"09:00", "10:30", "11:00", "11:30"}},
+		{"straddling, buffer reaches neighbours", 20, at(9, 45), at(10, 15), monday, []string{"11:00", "11:30"}},
+		{"slots exactly a buffer away", 30, at(10, 0), at(10, 30), monday, []string{"09:00", "11:00", "11:30"}},
+		{"booking before the range", 10, at(8, 30), at(8, 55), at(9, 0), []string{"09:30", "10:00", "10:30", "11:00", "11:30"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newFakeServices(monday)
+			rule := models.AvailabilityRule{UserID: "u1", DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "12:00", SlotLengthMins: 30, BufferMins: tc.bufferMins, Available: true}
+			if err := s.Avail.InsertAvailabilityRule(context.Background(), s.DB, &rule); err != nil {
+				t.Fatal(err)
+			}
+			s.Book.(*fakeBookingRepo).bookings["b1"] = &models.Booking{ID: "b1", UserID: "u1", Status: "confirmed", StartAtUTC: tc.start, EndAtUTC: tc.end}
+
+			slots, err := s.GenerateAvailableSlots(context.Background(), "u1", tc.from, monday.Add(24*time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := slotStarts(slots); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("slots = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
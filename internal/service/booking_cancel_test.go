@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestCancelBookingScope(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	cases := []struct {
+		scope     string
+		anchor    int
+		want      int
+		cancelled []bool
+	}{
+		{CancelScopeSingle, 2, 1, []bool{false, false, true, false, false}},
+		{"", 2, 1, []bool{false, false, true, false, false}},
+		{CancelScopeFollowing, 2, 3, []bool{false, false, true, true, true}},
+		{CancelScopeFollowing, 0, 5, []bool{true, true, true, true, true}},
+		{CancelScopeAll, 3, 5, []bool{true, true, true, true, true}},
+	}
+	for _, tc := range cases {
+		var series []models.Booking
+		for i := 0; i < 5; i++ {
+			start := monday.AddDate(0, 0, 7*i)
+			series = append(series, models.Booking{
+				ID: fmt.Sprintf("occ-%d", i), UserID: "u", RecurrenceGroupID: "series",
+				StartAtUTC: start, EndAtUTC: start.Add(30 * time.Minute),
+			})
+		}
+		repo := newFakeBookingRepo(series...)
+		s := NewBookingService(fakeDB{}, repo, nil)
+
+		n, err := s.CancelBookingScope(context.Background(), series[tc.anchor].ID, tc.scope, "")
+		if err != nil {
+			t.Fatalf("scope %q from occ-%d: %v", tc.scope, tc.anchor, err)
+		}
+		if n != tc.want {
+			t.Errorf("scope %q from occ-%d: cancelled %d, want %d", tc.scope, tc.anchor, n, tc.want)
+		}
+		for i, want := range tc.cancelled {
+			if got := repo.get(series[i].ID).Status == "cancelled"; got != want {
+				t.Errorf("scope %q from occ-%d: occ-%d cancelled = %v, want %v", tc.scope, tc.anchor, i, got, want)
+			}
+		}
+	}
+}
+
+func TestCancelBookingScopeErrors(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	repo := newFakeBookingRepo(
+		models.Booking{ID: "live", UserID: "u", RecurrenceGroupID: "series", StartAtUTC: start, EndAtUTC: start.Add(time.Hour)},
+		models.Booking{ID: "gone", UserID: "u", RecurrenceGroupID: "done", StartAtUTC: start, EndAtUTC: start.Add(time.Hour), Status: "cancelled"},
+	)
+	s := NewBookingService(fakeDB{}, repo, nil)
+	cases := []struct {
+		id, scope, want string
+	}{
+		{"live", "weekly", "invalid scope"},
+		{"missing", CancelScopeAll, "booking not found"},
+		{"gone", CancelScopeAll, "already cancelled"},
+	}
+	for _, tc := range cases {
+		_, err := s.CancelBookingScope(context.Background(), tc.id, tc.scope, "")
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("%s scope %q: err = %v, want %q", tc.id, tc.scope, err, tc.want)
+		}
+	}
+}
+
+func TestCancelBookingByGoogleEventID(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	repo := newFakeBookingRepo(
+		models.Booking{ID: "linked", UserID: "u", GoogleEventID: "evt-1", StartAtUTC: start, EndAtUTC: start.Add(time.Hour)},
+		models.Booking{ID: "plain", UserID: "u", StartAtUTC: start.Add(2 * time.Hour), EndAtUTC: start.Add(3 * time.Hour)},
+	)
+	s := NewBookingService(fakeDB{}, repo, nil)
+	cases := []struct {
+		eventID string
+		wantID  string
+		wantErr string
+	}{
+		{"evt-1", "linked", ""},
+		{"evt-1", "linked", "already cancelled"},
+		{"evt-unlinked", "", "booking not found"},
+	}
+	for _, tc := range cases {
+		b, err := s.CancelBookingByGoogleEventID(context.Background(), tc.eventID)
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+			t.Fatalf("%s: err = %v, want %q", tc.eventID, err, tc.wantErr)
+		}
+		if b.ID != tc.wantID {
+			t.Errorf("%s: booking %q, want %q", tc.eventID, b.ID, tc.wantID)
+		}
+	}
+	if got := repo.get("linked"); got.Status != "cancelled" || got.CancellationReason != CancelReasonEventDeleted {
+		t.Errorf("linked booking = %s/%q, want cancelled for the deleted event", got.Status, got.CancellationReason)
+	}
+	if repo.get("plain").Status == "cancelled" {
+		t.Error("booking without an event was cancelled")
+	}
+}
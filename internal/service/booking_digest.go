@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// DigestBooking is a booking as shown in a schedule digest, with times in the
+// digest's timezone.
+type DigestBooking struct {
+	ID             string    `json:"id"`
+	CandidateEmail string    `json:"candidate_email"`
+	Title          string    `json:"title,omitempty"`
+	StartLocal     string    `json:"start_local"`
+	EndLocal       string    `json:"end_local"`
+	StartUTC       time.Time `json:"start_utc"`
+	EndUTC         time.Time `json:"end_utc"`
+}
+
+// DigestDay lists one local day's bookings.
+type DigestDay struct {
+	Date     string          `json:"date"`
+	Weekday  string          `json:"weekday"`
+	Count    int             `json:"count"`
+	Bookings []DigestBooking `json:"bookings"`
+}
+
+// ScheduleDigest summarises a user's confirmed bookings over whole local days.
+type ScheduleDigest struct {
+	UserID   string      `json:"user_id"`
+	Timezone string      `json:"timezone"`
+	From     string      `json:"from"`
+	To       string      `json:"to"`
+	Total    int         `json:"total"`
+	Days     []DigestDay `json:"days"`
+}
+
+// Digest summarises userID's confirmed bookings for numDays local days
+// starting on the date of start in loc. Every day in the period is listed,
+// including days without bookings.
+func (s *BookingService) Digest(ctx context.Context, userID string, start time.Time, numDays int, loc *time.Location) (ScheduleDigest, error) {
+	y, m, d := start.In(loc).Date()
+	first := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	// AddDate keeps local midnights across DST changes
+	end := first.AddDate(0, 0, numDays)
+
+	out := ScheduleDigest{
+		UserID:   userID,
+		Timezone: loc.String(),
+		From:     first.Format("2006-01-02"),
+		To:       end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Days:     make([]DigestDay, 0, numDays),
+	}
+	index := map[string]int{}
+	for i := 0; i < numDays; i++ {
+		day := first.AddDate(0, 0, i)
+		key := day.Format("2006-01-02")
+		index[key] = i
+		out.Days = append(out.Days, DigestDay{Date: key, Weekday: day.Weekday().String(), Bookings: []DigestBooking{}})
+	}
+
+	bookings, err := s.Repo.ListBookings(ctx, s.DB, userID, first.UTC(), end.UTC(), true, 0, 0)
+	if err != nil {
+		return out, err
+	}
+	for _, b := range bookings {
+		if b.Status != "confirmed" {
+			continue
+		}
+		startLocal, endLocal := b.StartAtUTC.In(loc), b.EndAtUTC.In(loc)
+		i, ok := index[startLocal.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		out.Days[i].Bookings = append(out.Days[i].Bookings, DigestBooking{
+			ID:             b.ID,
+			CandidateEmail: b.CandidateEmail,
+			Title:          b.Title,
+			StartLocal:     startLocal.Format(time.RFC3339),
+			EndLocal:       endLocal.Format(time.RFC3339),
+			StartUTC:       b.StartAtUTC.UTC(),
+			EndUTC:         b.EndAtUTC.UTC(),
+		})
+		out.Days[i].Count++
+		out.Total++
+	}
+	return out, nil
+}
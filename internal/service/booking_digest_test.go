@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestDigestGroupsByLocalDay(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	utc := func(s string) time.Time {
+		ts, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+	booking := func(id, start, status string) models.Booking {
+		return models.Booking{ID: id, UserID: "u1", CandidateEmail: id + "@example.com", StartAtUTC: utc(start), EndAtUTC: utc(start).Add(30 * time.Minute), Status: status}
+	}
+	repo := newFakeBookingRepo(
+		booking("sunday-night-la", "2026-03-02T07:00:00Z", ""),
+		booking("monday-night-la", "2026-03-03T07:30:00Z", ""),
+		booking("tuesday", "2026-03-03T17:00:00Z", ""),
+		booking("pending", "2026-03-04T17:00:00Z", "pending"),
+		booking("after-dst", "2026-03-08T17:00:00Z", ""),
+		booking("last-evening-la", "2026-03-09T06:30:00Z", ""),
+		booking("next-week", "2026-03-09T07:30:00Z", ""),
+	)
+	s := NewBookingService(fakeDB{}, repo, nil)
+
+	cases := []struct {
+		name       string
+		loc        *time.Location
+		wantCounts []int
+		wantTotal  int
+		wantIDs    map[string][]string  // bookings on a date
+		wantLocal  map[string][2]string // booking id: local start, end
+	}{
+		{"los angeles", la, []int{1, 1, 0, 0, 0, 0, 2}, 4,
+			map[string][]string{"2026-03-02": {"monday-night-la"}, "2026-03-08": {"after-dst", "last-evening-la"}},
+			map[string][2]string{
+				"monday-night-la": {"2026-03-02T23:30:00-08:00", "2026-03-03T00:00:00-08:00"},
+				"after-dst":       {"2026-03-08T10:00:00-07:00", "2026-03-08T10:30:00-07:00"},
+			}},
+		{"utc", time.UTC, []int{1, 2, 0, 0, 0, 0, 1}, 4,
+			map[string][]string{"2026-03-02": {"sunday-night-la"}, "2026-03-03": {"monday-night-la", "tuesday"}},
+			map[string][2]string{"tuesday": {"2026-03-03T17:00:00Z", "2026-03-03T17:30:00Z"}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Date(2026, 3, 2, 0, 0, 0, 0, tc.loc)
+			d, err := s.Digest(context.Background(), "u1", start, 7, tc.loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.From != "2026-03-02" || d.To != "2026-03-08" || d.Timezone != tc.loc.String() {
+				t.Errorf("period = %s to %s in %s, want 2026-03-02 to 2026-03-08 in %s", d.From, d.To, d.Timezone, tc.loc)
+			}
+			counts := make([]int, len(d.Days))
+			local := map[string][2]string{}
+			for i, day := range d.Days {
+				counts[i] = day.Count
+				var ids []string
+				for _, b := range day.Bookings {
+					ids = append(ids, b.ID)
+					local[b.ID] = [2]string{b.StartLocal, b.EndLocal}
+				}
+				if want, ok := tc.wantIDs[day.Date]; ok && !reflect.DeepEqual(ids, want) {
+					t.Errorf("%s bookings = %v, want %v", day.Date, ids, want)
+				}
+			}
+			if !reflect.DeepEqual(counts, tc.wantCounts) || d.Total != tc.wantTotal {
+				t.Errorf("counts = %v (total %d), want %v (total %d)", counts, d.Total, tc.wantCounts, tc.wantTotal)
+			}
+			if d.Days[0].Weekday != "Monday" || d.Days[6].Weekday != "Sunday" {
+				t.Errorf("weekdays run %s to %s, want Monday to Sunday", d.Days[0].Weekday, d.Days[6].Weekday)
+			}
+			for id, want := range tc.wantLocal {
+				if local[id] != want {
+					t.Errorf("%s local times = %v, want %v", id, local[id], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"scheduler-service/internal/models"
+)
+
+// BookingHook receives booking lifecycle events in-process, after the change
+// has been committed. Errors are logged and never undo the change.
+type BookingHook interface {
+	OnCreated(ctx context.Context, b models.Booking) error
+	OnCancelled(ctx context.Context, b models.Booking) error
+	// OnRescheduled fires when a booking moves to another time or user.
+	OnRescheduled(ctx context.Context, before, after models.Booking) error
+	// OnStateChanged fires when a booking's confirmation state advances, or
+	// when approval moves it from "pending" to b.Status; b carries the
+	// new state.
+	OnStateChanged(ctx context.Context, b models.Booking, from string) error
+	// OnExpired fires when a booking awaiting approval expires unapproved.
+	// The service has no channel of its own to the candidate, so telling
+	// them (see ExpiredNotice) is left to a hook.
+	OnExpired(ctx context.Context, b models.Booking) error
+}
+
+// CreatedSummary is the interviewer-facing line for a new booking, naming the
+// recruiter when they booked on the candidate's behalf.
+func CreatedSummary(b models.Booking) string {
+	when := b.StartAtUTC.UTC().Format("2006-01-02 15:04 UTC")
+	if b.BookedBy == models.BookedByRecruiter {
+		return fmt.Sprintf("%s scheduled candidate %s for %s", b.BookedByEmail, b.CandidateEmail, when)
+	}
+	return fmt.Sprintf("%s booked %s", b.CandidateEmail, when)
+}
+
+// ExpiredNotice is the candidate-facing line for a booking request that
+// expired before it was approved.
+func ExpiredNotice(b models.Booking) string {
+	return fmt.Sprintf("Your booking request for %s was not approved in time and has been released; please pick another slot.",
+		b.StartAtUTC.UTC().Format("2006-01-02 15:04 UTC"))
+}
+
+// NopBookingHook implements BookingHook with no-ops; embed it to implement
+// only the events of interest.
+type NopBookingHook struct{}
+
+func (NopBookingHook) OnCreated(context.Context, models.Booking) error { return nil }
+
+func (NopBookingHook) OnCancelled(context.Context, models.Booking) error { return nil }
+
+func (NopBookingHook) OnRescheduled(context.Context, models.Booking, models.Booking) error {
+	return nil
+}
+
+func (NopBookingHook) OnStateChanged(context.Context, models.Booking, string) error { return nil }
+
+func (NopBookingHook) OnExpired(context.Context, models.Booking) error { return nil }
+
+// BookingHooks is a registry of hooks invoked in registration order.
+type BookingHooks struct {
+	mu    sync.RWMutex
+	hooks []BookingHook
+}
+
+// Register adds a hook. It is safe to call concurrently with dispatch.
+func (r *BookingHooks) Register(h BookingHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, h)
+}
+
+func (r *BookingHooks) snapshot() []BookingHook {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]BookingHook(nil), r.hooks...)
+}
+
+func (r *BookingHooks) created(ctx context.Context, b models.Booking) {
+	for _, h := range r.snapshot() {
+		if err := h.OnCreated(ctx, b); err != nil {
+			log.Printf("booking hook %T OnCreated for booking %s: %v", h, b.ID, err)
+		}
+	}
+}
+
+func (r *BookingHooks) cancelled(ctx context.Context, b models.Booking) {
+	for _, h := range r.snapshot() {
+		if err := h.OnCancelled(ctx, b); err != nil {
+			log.Printf("booking hook %T OnCancelled for booking %s: %v", h, b.ID, err)
+		}
+	}
+}
+
+func (r *BookingHooks) rescheduled(ctx context.Context, before, after models.Booking) {
+	for _, h := range r.snapshot() {
+		if err := h.OnRescheduled(ctx, before, after); err != nil {
+			log.Printf("booking hook %T OnRescheduled for booking %s: %v", h, after.ID, err)
+		}
+	}
+}
+
+func (r *BookingHooks) stateChanged(ctx context.Context, b models.Booking, from string) {
+	for _, h := range r.snapshot() {
+		if err := h.OnStateChanged(ctx, b, from); err != nil {
+			log.Printf("booking hook %T OnStateChanged for booking %s: %v", h, b.ID, err)
+		}
+	}
+}
+
+func (r *BookingHooks) expired(ctx context.Context, b models.Booking) {
+	for _, h := range r.snapshot() {
+		if err := h.OnExpired(ctx, b); err != nil {
+			log.Printf("booking hook %T OnExpired for booking %s: %v", h, b.ID, err)
+		}
+	}
+}
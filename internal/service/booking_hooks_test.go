@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+// recordingHook records each event it receives and fails every call when err
+// is set.
+type recordingHook struct {
+	NopBookingHook
+	events []string
+	err    error
+}
+
+func (h *recordingHook) OnCreated(ctx context.Context, b models.Booking) error {
+	h.events = append(h.events, "created "+b.StartAtUTC.Format("15:04"))
+	return h.err
+}
+
+func (h *recordingHook) OnCancelled(ctx context.Context, b models.Booking) error {
+	h.events = append(h.events, "cancelled "+b.StartAtUTC.Format("15:04"))
+	return h.err
+}
+
+func (h *recordingHook) OnRescheduled(ctx context.Context, before, after models.Booking) error {
+	h.events = append(h.events, "rescheduled "+before.StartAtUTC.Format("15:04")+" to "+after.StartAtUTC.Format("15:04"))
+	return h.err
+}
+
+func TestBookingHooksSeeLifecycle(t *testing.T) {
+	start := monday.Add(9 * time.Hour)
+	want := []string{"created 09:00", "rescheduled 09:00 to 09:30", "cancelled 09:30"}
+	cases := []struct {
+		name    string
+		hookErr error
+	}{
+		{"hooks succeed", nil},
+		{"failing hook is not fatal", errors.New("slack is down")},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u1", time.Monday, "09:00", "10:00", 30)
+			first, second := &recordingHook{err: tc.hookErr}, &recordingHook{}
+			s.Hooks = &BookingHooks{}
+			s.Hooks.Register(first)
+			s.Hooks.Register(second)
+
+			b, err := s.CreateBooking(ctx, "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.RescheduleBooking(ctx, "u1", b.ID, start.Add(30*time.Minute), start.Add(time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.CancelBooking(ctx, b.ID, ""); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, h := range []*recordingHook{first, second} {
+				if !reflect.DeepEqual(h.events, want) {
+					t.Errorf("events = %q, want %q", h.events, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBookingHooksNotCalledOnFailure(t *testing.T) {
+	_, s := newFakeServices(monday)
+	hook := &recordingHook{}
+	s.Hooks = &BookingHooks{}
+	s.Hooks.Register(hook)
+
+	start := monday.Add(9 * time.Hour)
+	if _, err := s.CreateBooking(context.Background(), "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute)}); err == nil {
+		t.Fatal("booking outside availability succeeded")
+	}
+	if len(hook.events) != 0 {
+		t.Errorf("events = %q, want none for a rejected booking", hook.events)
+	}
+}
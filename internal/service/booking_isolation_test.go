@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+func TestCreateBookingConcurrentlyBooksSlotOnce(t *testing.T) {
+	avail, s := newFakeServices(monday)
+	s.TxIsolation = pgx.Serializable
+	addRule(t, avail, "u1", time.Monday, "09:00", "12:00", 30)
+	start := monday.Add(9 * time.Hour)
+	req := CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute)}
+
+	const callers = 20
+	var wg sync.WaitGroup
+	errs := make([]error, callers)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = s.CreateBooking(context.Background(), "u1", req)
+		}(i)
+	}
+	wg.Wait()
+
+	booked := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			booked++
+		case errors.Is(err, repository.ErrConflict), err.Error() == "slot already booked", err.Error() == "slot not available":
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if booked != 1 {
+		t.Errorf("%d of %d concurrent bookings succeeded, want 1", booked, callers)
+	}
+	if n := len(s.Repo.(*fakeBookingRepo).bookings); n != 1 {
+		t.Errorf("%d bookings stored, want 1", n)
+	}
+}
+
+// serializationFailingRepo fails its first inserts, failures of them, the way
+// Postgres aborts a serializable transaction that lost a conflict.
+type serializationFailingRepo struct {
+	*fakeBookingRepo
+	failures int
+	inserts  int
+}
+
+func (r *serializationFailingRepo) InsertBooking(ctx context.Context, q repository.Querier, b *models.Booking) (string, error) {
+	r.inserts++
+	if r.failures > 0 {
+		r.failures--
+		return "", &pgconn.PgError{Code: "40001"}
+	}
+	return r.fakeBookingRepo.InsertBooking(ctx, q, b)
+}
+
+func TestCreateBookingRetriesSerializationFailures(t *testing.T) {
+	start := monday.Add(9 * time.Hour)
+	req := CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute)}
+
+	for _, tc := range []struct {
+		failures int
+		ok       bool
+	}{
+		{maxSerializationRetries - 1, true},
+		{maxSerializationRetries, false},
+	} {
+		avail, s := newFakeServices(monday)
+		s.TxIsolation = pgx.Serializable
+		addRule(t, avail, "u1", time.Monday, "09:00", "12:00", 30)
+		repo := &serializationFailingRepo{fakeBookingRepo: s.Repo.(*fakeBookingRepo), failures: tc.failures}
+		s.Repo, avail.Book = repo, repo
+
+		_, err := s.CreateBooking(context.Background(), "u1", req)
+		if (err == nil) != tc.ok {
+			t.Errorf("%d failures: err = %v, want success %v", tc.failures, err, tc.ok)
+		}
+		if want := min(tc.failures+1, maxSerializationRetries); repo.inserts != want {
+			t.Errorf("%d failures: %d inserts, want %d", tc.failures, repo.inserts, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCreateBookingPrice(t *testing.T) {
+	start := monday.Add(9 * time.Hour)
+	amount := func(n int64) *int64 { return &n }
+	cases := []struct {
+		name         string
+		amountCents  *int64
+		currency     string
+		wantErr      string
+		wantCurrency string
+	}{
+		{"paid booking", amount(15000), "EUR", "", "EUR"},
+		{"lower-case currency", amount(2500), " usd ", "", "USD"},
+		{"free of charge", amount(0), "GBP", "", "GBP"},
+		{"no price", nil, "", "", ""},
+		{"unknown currency", amount(100), "XYZ", "currency must be an ISO 4217 code", ""},
+		{"not a currency code", amount(100), "dollars", "currency must be an ISO 4217 code", ""},
+		{"negative amount", amount(-1), "EUR", "amount_cents must not be negative", ""},
+		{"amount without currency", amount(100), "", "amount_cents and currency must be given together", ""},
+		{"currency without amount", nil, "EUR", "amount_cents and currency must be given together", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u1", time.Monday, "09:00", "10:00", 30)
+
+			b, err := s.CreateBooking(context.Background(), "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute), AmountCents: tc.amountCents, Currency: tc.currency})
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+			if tc.wantErr != "" {
+				if n := len(s.Repo.(*fakeBookingRepo).bookings); n != 0 {
+					t.Errorf("%d bookings stored after an invalid price", n)
+				}
+				return
+			}
+			stored := s.Repo.(*fakeBookingRepo).get(b.ID)
+			if stored.Currency != tc.wantCurrency || (stored.AmountCents == nil) != (tc.amountCents == nil) ||
+				stored.AmountCents != nil && *stored.AmountCents != *tc.amountCents {
+				t.Errorf("stored price = %v %q, want %v %q", stored.AmountCents, stored.Currency, tc.amountCents, tc.wantCurrency)
+			}
+		})
+	}
+}
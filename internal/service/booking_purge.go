@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// PurgeCancelledBefore deletes cancelled bookings that ended before cutoff,
+// along with their audit entries, in one transaction and returns how many
+// bookings were removed.
+func (s *BookingService) PurgeCancelledBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	trx, err := beginTx(ctx, s.DB)
+	if err != nil {
+		return 0, err
+	}
+	defer trx.Rollback(ctx)
+
+	ids, err := s.Repo.PurgeCancelledBefore(ctx, trx, cutoff.UTC())
+	if err != nil {
+		return 0, err
+	}
+	if s.Audit != nil && len(ids) > 0 {
+		if _, err := s.Audit.DeleteAuditEntries(ctx, trx, ids); err != nil {
+			return 0, err
+		}
+	}
+	if err := trx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
+// RunCancelledPurge purges cancelled bookings that ended more than retention
+// ago, once at start and then every interval, until ctx is done.
+func (s *BookingService) RunCancelledPurge(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		n, err := s.PurgeCancelledBefore(ctx, nowUTC(s.Clock).Add(-retention))
+		if err != nil {
+			log.Printf("cancelled booking purge failed: %v", err)
+		} else if n > 0 {
+			log.Printf("purged %d cancelled bookings older than %s", n, retention)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestPurgeCancelledDeletesAuditEntries(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	old, recent := now.AddDate(0, 0, -100), now.AddDate(0, 0, -1)
+	repo := newFakeBookingRepo(
+		models.Booking{ID: "old-cancelled", Status: "cancelled", StartAtUTC: old, EndAtUTC: old.Add(time.Hour)},
+		models.Booking{ID: "old-confirmed", StartAtUTC: old, EndAtUTC: old.Add(time.Hour)},
+		models.Booking{ID: "recent-cancelled", Status: "cancelled", StartAtUTC: recent, EndAtUTC: recent.Add(time.Hour)},
+	)
+	audit := &fakeAuditRepo{}
+	for _, id := range []string{"old-cancelled", "old-confirmed", "recent-cancelled"} {
+		audit.entries = append(audit.entries,
+			models.BookingAuditEntry{BookingID: id, Action: "created"},
+			models.BookingAuditEntry{BookingID: id, Action: "cancelled"})
+	}
+	s := NewBookingService(fakeDB{}, repo, nil)
+	s.Audit = audit
+
+	n, err := s.PurgeCancelledBefore(context.Background(), now.AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("purged %d bookings, want 1", n)
+	}
+	if _, ok := repo.bookings["old-cancelled"]; ok {
+		t.Error("old cancelled booking kept")
+	}
+	if len(audit.entries) != 4 {
+		t.Fatalf("%d audit entries left, want 4", len(audit.entries))
+	}
+	for _, e := range audit.entries {
+		if e.BookingID == "old-cancelled" {
+			t.Errorf("audit entry %q of the purged booking kept", e.Action)
+		}
+	}
+}
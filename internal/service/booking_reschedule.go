@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+// RescheduleBooking moves userID's confirmed or pending booking id to [start, end),
+// keeping its id and created_at. The new window must be one of the user's
+// open slots, counting the booking's current slot as free, must not overlap
+// another booking or a hold, and must pass the candidate checks bookings are
+// created under.
+func (s *BookingService) RescheduleBooking(ctx context.Context, userID, id string, start, end time.Time) (models.Booking, error) {
+	var out models.Booking
+	start, end = start.UTC(), end.UTC()
+	if start.After(nowUTC(s.Clock).Add(maxBookingLead)) {
+		return out, errors.New("start too far in the future")
+	}
+	if !s.Avail.BusinessHours.Contains(start, end) {
+		return out, errors.New("outside business hours")
+	}
+	if until, err := s.Avail.UnavailableUntil(ctx, userID); err != nil {
+		return out, err
+	} else if until != nil && start.Before(*until) {
+		return out, errors.New("user is unavailable")
+	}
+
+	trx, err := beginTxWithIsolation(ctx, s.DB, s.TxIsolation)
+	if err != nil {
+		return out, err
+	}
+	defer trx.Rollback(ctx)
+
+	before, err := s.Repo.GetBooking(ctx, trx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return out, errors.New("booking not found")
+	}
+	if err != nil {
+		return out, err
+	}
+	if before.UserID != userID || (before.Status != "confirmed" && before.Status != "pending") {
+		return out, errors.New("booking not found")
+	}
+	if before.StartAtUTC.Equal(start) && before.EndAtUTC.Equal(end) {
+		return *before, nil
+	}
+
+	if other, err := s.Repo.CheckOverlappingBooking(ctx, trx, userID, start, end, id); err != nil {
+		return out, err
+	} else if other != "" {
+		return out, errors.New("slot already booked")
+	}
+	if s.Holds != nil {
+		if hold, err := s.Holds.FindActiveHoldOverlap(ctx, trx, userID, start, end); err != nil {
+			return out, err
+		} else if hold != "" {
+			return out, errors.New("slot is held")
+		}
+	}
+	if conflict, err := s.candidateConflict(ctx, trx, before.CandidateEmail, start, end, id); err != nil {
+		return out, err
+	} else if conflict != "" {
+		return out, errors.New(conflict)
+	}
+
+	// Holds were checked above; the booking's own slot counts as free
+	slots, err := s.Avail.generateSlots(ctx, userID, start.Add(-1*time.Second), end.Add(1*time.Second), slotOptions{ignoreHolds: true, excludeBooking: id})
+	if err != nil {
+		return out, err
+	}
+	offered := false
+	for _, sl := range slots {
+		if sl.StartUTC.Equal(start) && sl.EndUTC.Equal(end) {
+			offered = true
+			break
+		}
+	}
+	if !offered {
+		return out, errors.New("slot not available")
+	}
+
+	n, err := s.Repo.UpdateBookingTimes(ctx, trx, id, start, end)
+	if errors.Is(err, repository.ErrConflict) {
+		return out, errors.New("slot already booked")
+	}
+	if err != nil {
+		return out, err
+	}
+	if n == 0 {
+		return out, errors.New("booking not found")
+	}
+	if err := trx.Commit(ctx); err != nil {
+		return out, err
+	}
+
+	out = *before
+	out.StartAtUTC, out.EndAtUTC = start, end
+	s.Hooks.rescheduled(ctx, *before, out)
+	return out, nil
+}
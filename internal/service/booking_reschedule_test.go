@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestRescheduleLiveBookings(t *testing.T) {
+	cases := []struct {
+		status  string
+		wantErr string
+	}{
+		{"confirmed", ""},
+		{"pending", ""},
+		{"cancelled", "booking not found"},
+		{"expired", "booking not found"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.status, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u1", time.Monday, "09:00", "12:00", 30)
+			repo := newFakeBookingRepo(models.Booking{ID: "b1", UserID: "u1", CandidateEmail: "c@example.com", StartAtUTC: at(9, 0), EndAtUTC: at(9, 30), Status: tc.status})
+			s.Repo, avail.Book = repo, repo
+
+			_, err := s.RescheduleBooking(context.Background(), "u1", "b1", at(10, 0), at(10, 30))
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+			want := at(10, 0)
+			if tc.wantErr != "" {
+				want = at(9, 0)
+			}
+			got := repo.get("b1")
+			if !got.StartAtUTC.Equal(want) || got.Status != tc.status {
+				t.Errorf("booking = %s at %v, want %s at %v", got.Status, got.StartAtUTC, tc.status, want)
+			}
+		})
+	}
+}
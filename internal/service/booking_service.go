@@ -3,9 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"golang.org/x/text/currency"
 
 	"scheduler-service/internal/models"
 	"scheduler-service/internal/repository"
@@ -15,58 +19,192 @@ type BookingService struct {
 	DB    repository.Querier
 	Avail *AvailabilityService
 	Repo  repository.BookingRepository
+
+	// BlockCandidateOverlap rejects a booking when the candidate already holds
+	// an overlapping confirmed booking with any other user.
+	BlockCandidateOverlap bool
+
+	// MinCandidateGap rejects a booking when the candidate has another
+	// confirmed booking, with any user, ending less than this before the
+	// start or starting less than this after the end. Zero disables it.
+	MinCandidateGap time.Duration
+
+	// AllowedEmailDomains, when non-empty, limits candidate emails to these
+	// domains (compared case-insensitively).
+	AllowedEmailDomains []string
+
+	// Holds enables tentative slot holds; HoldTTL is how long a hold lasts
+	// (defaultHoldTTL when zero).
+	Holds   repository.SlotHoldRepository
+	HoldTTL time.Duration
+
+	// TxIsolation is the isolation level for the booking transaction; empty
+	// uses the server default. Under serializable, conflicting concurrent
+	// bookings abort with a serialization failure and are retried.
+	TxIsolation pgx.TxIsoLevel
+
+	// Hooks, when set, are notified of booking changes after they commit.
+	Hooks *BookingHooks
+
+	// Audit, when set, holds the bookings' audit history; purging a booking
+	// deletes its entries too.
+	Audit repository.BookingAuditRepository
+
+	// Clock supplies the current time; nil uses the wall clock.
+	Clock Clock
+
+	// ConfirmationCodeLength is the length of new bookings' confirmation
+	// codes; zero uses DefaultConfirmationCodeLength.
+	ConfirmationCodeLength int
+
+	// RequireApproval creates bookings as "pending" until ApproveBooking
+	// confirms them. A pending booking holds its slot; ApprovalTTL, when
+	// positive, is how long it may stay pending before
+	// ExpirePendingBookings releases it.
+	RequireApproval bool
+	ApprovalTTL     time.Duration
+
+	syncs calendarSyncs
 }
 
+const defaultHoldTTL = 5 * time.Minute
+
 // NewBookingService wires booking repo and availability service.
 func NewBookingService(db repository.Querier, repo repository.BookingRepository, avail *AvailabilityService) *BookingService {
 	return &BookingService{DB: db, Repo: repo, Avail: avail}
 }
 
-func (s *BookingService) ListBookings(ctx context.Context, userID string, from, to time.Time, filtered bool) ([]models.Booking, error) {
-	return s.Repo.ListBookings(ctx, s.DB, userID, from, to, filtered)
+// ListBookings returns up to limit of the user's non-cancelled bookings after
+// skipping offset, along with how many there are in total.
+func (s *BookingService) ListBookings(ctx context.Context, userID string, from, to time.Time, filtered bool, limit, offset int) ([]models.Booking, int, error) {
+	total, err := s.Repo.CountBookings(ctx, s.DB, userID, from, to, filtered)
+	if err != nil {
+		return nil, 0, err
+	}
+	bookings, err := s.Repo.ListBookings(ctx, s.DB, userID, from, to, filtered, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bookings, total, nil
 }
 
+// maxBookingLead is a sanity bound on how far ahead a booking may start. Starts
+// beyond it are almost always client clock bugs, so they are rejected
+// regardless of any per-user horizon.
+const maxBookingLead = 5 * 365 * 24 * time.Hour
+
 func (s *BookingService) CreateBooking(ctx context.Context, userID string, req CreateBookingParams) (models.Booking, error) {
+	for attempt := 1; ; attempt++ {
+		b, err := s.createBooking(ctx, userID, req)
+		if err != nil && isSerializationFailure(err) && attempt < maxSerializationRetries {
+			continue
+		}
+		if err == nil {
+			s.Hooks.created(ctx, b)
+		}
+		return b, err
+	}
+}
+
+// createBooking runs one attempt of CreateBooking in its own transaction.
+func (s *BookingService) createBooking(ctx context.Context, userID string, req CreateBookingParams) (models.Booking, error) {
 	var out models.Booking
 	start := req.Start.UTC()
 	end := req.End.UTC()
 
-	// Begin transaction from underlying pool if available
-	tx, ok := s.DB.(interface {
-		Begin(context.Context) (pgx.Tx, error)
-	})
-	if !ok {
-		return out, errors.New("db does not support transactions")
+	if start.After(nowUTC(s.Clock).Add(maxBookingLead)) {
+		return out, errors.New("start too far in the future")
+	}
+	if !s.emailDomainAllowed(req.CandidateEmail) {
+		return out, errors.New("candidate email domain not allowed")
 	}
-	trx, err := tx.Begin(ctx)
+	if !s.Avail.BusinessHours.Contains(start, end) {
+		return out, errors.New("outside business hours")
+	}
+	if until, err := s.Avail.UnavailableUntil(ctx, userID); err != nil {
+		return out, err
+	} else if until != nil && start.Before(*until) {
+		return out, errors.New("user is unavailable")
+	}
+	currencyCode, err := validateBookingPrice(req.AmountCents, req.Currency)
+	if err != nil {
+		return out, err
+	}
+
+	// Begin transaction from underlying pool if available
+	trx, err := beginTxWithIsolation(ctx, s.DB, s.TxIsolation)
 	if err != nil {
 		return out, err
 	}
 	defer trx.Rollback(ctx)
 
-	if id, err := s.Repo.CheckExistingBookingAtStart(ctx, trx, userID, start); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+	if id, err := s.Repo.CheckOverlappingBooking(ctx, trx, userID, start, end, ""); err != nil {
 		return out, err
 	} else if id != "" {
 		return out, errors.New("slot already booked")
 	}
 
-	slots, err := s.Avail.GenerateAvailableSlots(ctx, userID, start.Add(-1*time.Second), end.Add(1*time.Second))
+	if conflict, err := s.candidateConflict(ctx, trx, req.CandidateEmail, start, end, ""); err != nil {
+		return out, err
+	} else if conflict != "" {
+		return out, errors.New(conflict)
+	}
+
+	if s.Holds != nil {
+		if req.HoldToken != "" {
+			n, err := s.Holds.ConsumeHold(ctx, trx, userID, req.HoldToken, start, end)
+			if err != nil {
+				return out, err
+			}
+			if n == 0 {
+				return out, errors.New("invalid or expired hold")
+			}
+		} else {
+			id, err := s.Holds.FindActiveHoldOverlap(ctx, trx, userID, start, end)
+			if err != nil {
+				return out, err
+			}
+			if id != "" {
+				return out, errors.New("slot is held")
+			}
+		}
+	}
+
+	// Holds were checked above, so generate without hiding held slots
+	slots, err := s.Avail.generateSlots(ctx, userID, start.Add(-1*time.Second), end.Add(1*time.Second), slotOptions{ignoreHolds: true})
 	if err != nil {
 		return out, err
 	}
-	ok = false
-	for _, s := range slots {
-		if s.StartUTC.Equal(start) && s.EndUTC.Equal(end) {
-			ok = true
+	var matched *Slot
+	for i := range slots {
+		if slots[i].StartUTC.Equal(start) && slots[i].EndUTC.Equal(end) {
+			matched = &slots[i]
 			break
 		}
 	}
-	if !ok {
+	if matched == nil {
 		return out, errors.New("slot not available")
 	}
 
-	b := &models.Booking{UserID: userID, CandidateEmail: req.CandidateEmail, StartAtUTC: start, EndAtUTC: end, Source: req.Source, Type: req.Type, Description: req.Description, Title: req.Title, Status: "confirmed", CreatedAt: time.Now().UTC()}
-	newID, err := s.Repo.InsertBooking(ctx, trx, b)
+	b := &models.Booking{UserID: userID, CandidateEmail: req.CandidateEmail, StartAtUTC: start, EndAtUTC: end, Source: req.Source, Type: req.Type, Description: req.Description, Title: req.Title, GoogleEventID: req.GoogleEventID, Attendees: req.Attendees, AmountCents: req.AmountCents, Currency: currencyCode, RecurrenceGroupID: req.RecurrenceGroupID, Status: "confirmed", CreatedAt: nowUTC(s.Clock)}
+	b.BookedBy, b.BookedByEmail = bookedBy(ActorFrom(ctx), req.CandidateEmail)
+	if s.RequireApproval {
+		b.Status, b.PendingSince = "pending", &b.CreatedAt
+	}
+	if matched.RuleID != "" {
+		b.RuleSnapshot = &models.BookingRuleSnapshot{RuleID: matched.RuleID, Title: matched.Title, Tags: matched.Tags}
+	}
+	var newID string
+	for attempt := 1; ; attempt++ {
+		if b.ConfirmationCode, err = newConfirmationCode(s.confirmationCodeLength()); err != nil {
+			return out, err
+		}
+		newID, err = s.Repo.InsertBooking(ctx, trx, b)
+		if errors.Is(err, repository.ErrConfirmationCodeTaken) && attempt < maxConfirmationCodeAttempts {
+			continue
+		}
+		break
+	}
 	if err != nil {
 		return out, err
 	}
@@ -80,7 +218,16 @@ func (s *BookingService) CreateBooking(ctx context.Context, userID string, req C
 	return out, nil
 }
 
-func (s *BookingService) CancelBooking(ctx context.Context, id string) error {
+// maxCancellationReasonLen bounds the reason stored with a cancellation.
+const maxCancellationReasonLen = 500
+
+// CancelBooking cancels a live booking, recording reason and the actor from
+// ctx as the canceller.
+func (s *BookingService) CancelBooking(ctx context.Context, id, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if len(reason) > maxCancellationReasonLen {
+		return fmt.Errorf("reason must be at most %d characters", maxCancellationReasonLen)
+	}
 	status, err := s.Repo.GetBookingStatus(ctx, s.DB, id)
 	if err == pgx.ErrNoRows {
 		return errors.New("booking not found")
@@ -91,16 +238,436 @@ func (s *BookingService) CancelBooking(ctx context.Context, id string) error {
 	if status == "cancelled" {
 		return errors.New("already cancelled")
 	}
-	rows, err := s.Repo.CancelBooking(ctx, s.DB, id)
+	rows, err := s.Repo.CancelBooking(ctx, s.DB, id, reason, ActorFrom(ctx))
 	if err != nil {
 		return err
 	}
 	if rows == 0 {
 		return errors.New("booking not found")
 	}
+	if len(s.Hooks.snapshot()) > 0 {
+		if b, err := s.Repo.GetBooking(ctx, s.DB, id); err == nil {
+			s.Hooks.cancelled(ctx, *b)
+		}
+	}
+	return nil
+}
+
+// Cancellation scopes for a booking that belongs to a recurring series.
+const (
+	CancelScopeSingle    = "single"
+	CancelScopeFollowing = "following"
+	CancelScopeAll       = "all"
+)
+
+// CancelBookingScope cancels booking id and, for the following and all
+// scopes, the other live occurrences of its series: following cancels those
+// starting at or after it, all cancels the whole series. A booking outside any
+// series only cancels itself. Every cancelled booking records reason. It
+// returns how many bookings were cancelled.
+func (s *BookingService) CancelBookingScope(ctx context.Context, id, scope, reason string) (int, error) {
+	switch scope {
+	case "", CancelScopeSingle:
+		if err := s.CancelBooking(ctx, id, reason); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	case CancelScopeFollowing, CancelScopeAll:
+	default:
+		return 0, errors.New("invalid scope")
+	}
+	reason = strings.TrimSpace(reason)
+	if len(reason) > maxCancellationReasonLen {
+		return 0, fmt.Errorf("reason must be at most %d characters", maxCancellationReasonLen)
+	}
+
+	trx, err := beginTx(ctx, s.DB)
+	if err != nil {
+		return 0, err
+	}
+	defer trx.Rollback(ctx)
+
+	anchor, err := s.Repo.GetBooking(ctx, trx, id)
+	if err == pgx.ErrNoRows {
+		return 0, errors.New("booking not found")
+	}
+	if err != nil {
+		return 0, err
+	}
+	var ids []string
+	if anchor.RecurrenceGroupID == "" {
+		rows, err := s.Repo.CancelBooking(ctx, trx, id, reason, ActorFrom(ctx))
+		if err != nil {
+			return 0, err
+		}
+		if rows > 0 {
+			ids = []string{id}
+		}
+	} else {
+		var from repository.AppTime
+		if scope == CancelScopeFollowing {
+			from = anchor.StartAtUTC
+		}
+		if ids, err = s.Repo.CancelRecurrenceGroup(ctx, trx, anchor.RecurrenceGroupID, from, reason, ActorFrom(ctx)); err != nil {
+			return 0, err
+		}
+	}
+	if len(ids) == 0 {
+		return 0, errors.New("already cancelled")
+	}
+	if err := trx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	if len(s.Hooks.snapshot()) > 0 {
+		for _, bid := range ids {
+			if b, err := s.Repo.GetBooking(ctx, s.DB, bid); err == nil {
+				s.Hooks.cancelled(ctx, *b)
+			}
+		}
+	}
+	return len(ids), nil
+}
+
+// BookingCursor marks the last booking of a page for keyset pagination.
+type BookingCursor struct {
+	StartAtUTC time.Time
+	ID         string
+}
+
+// ListUpcomingBookings returns up to limit live bookings starting at or after
+// now, in start order, continuing after cursor when it is non-nil. The
+// returned cursor is nil on the last page.
+func (s *BookingService) ListUpcomingBookings(ctx context.Context, userID string, now time.Time, cursor *BookingCursor, limit int) ([]models.Booking, *BookingCursor, error) {
+	var afterStart any
+	afterID := ""
+	if cursor != nil {
+		afterStart, afterID = cursor.StartAtUTC, cursor.ID
+	}
+	// Fetch one extra row to learn whether another page exists
+	bookings, err := s.Repo.ListUpcomingBookings(ctx, s.DB, userID, now.UTC(), afterStart, afterID, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(bookings) <= limit {
+		return bookings, nil, nil
+	}
+	bookings = bookings[:limit]
+	last := bookings[limit-1]
+	return bookings, &BookingCursor{StartAtUTC: last.StartAtUTC, ID: last.ID}, nil
+}
+
+// AdminBookingFilter narrows ListAllBookings. From and To bound the start
+// time when set; Status is "confirmed", "pending", "cancelled", "expired" or
+// empty for all.
+type AdminBookingFilter struct {
+	From, To *time.Time
+	Status   string
+}
+
+// ListAllBookings returns up to limit bookings of every user matching filter,
+// in start order, continuing after cursor when it is non-nil. The returned
+// cursor is nil on the last page.
+func (s *BookingService) ListAllBookings(ctx context.Context, filter AdminBookingFilter, cursor *BookingCursor, limit int) ([]models.Booking, *BookingCursor, error) {
+	switch filter.Status {
+	case "", "confirmed", "pending", "cancelled", "expired":
+	default:
+		return nil, nil, errors.New("status must be confirmed, pending, cancelled or expired")
+	}
+	var from, to, afterStart any
+	if filter.From != nil {
+		from = filter.From.UTC()
+	}
+	if filter.To != nil {
+		to = filter.To.UTC()
+	}
+	afterID := ""
+	if cursor != nil {
+		afterStart, afterID = cursor.StartAtUTC, cursor.ID
+	}
+	bookings, err := s.Repo.ListAllBookings(ctx, s.DB, from, to, filter.Status, afterStart, afterID, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(bookings) <= limit {
+		return bookings, nil, nil
+	}
+	bookings = bookings[:limit]
+	last := bookings[limit-1]
+	return bookings, &BookingCursor{StartAtUTC: last.StartAtUTC, ID: last.ID}, nil
+}
+
+// GetBookingByGoogleEventID returns the booking linked to a Google Calendar
+// event.
+func (s *BookingService) GetBookingByGoogleEventID(ctx context.Context, eventID string) (*models.Booking, error) {
+	b, err := s.Repo.GetBookingByGoogleEventID(ctx, s.DB, eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("booking not found")
+	}
+	return b, err
+}
+
+// CancelBookingByGoogleEventID cancels the booking linked to a Google Calendar
+// event, for when the event is deleted on the Google side.
+func (s *BookingService) CancelBookingByGoogleEventID(ctx context.Context, eventID string) (models.Booking, error) {
+	b, err := s.Repo.GetBookingByGoogleEventID(ctx, s.DB, eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Booking{}, errors.New("booking not found")
+	}
+	if err != nil {
+		return models.Booking{}, err
+	}
+	if err := s.CancelBooking(ctx, b.ID, CancelReasonEventDeleted); err != nil {
+		if err.Error() == "already cancelled" {
+			// The booking is returned so callers can report which one it was
+			return *b, err
+		}
+		return models.Booking{}, err
+	}
+	b.Status = "cancelled"
+	b.CancellationReason, b.CancelledBy = CancelReasonEventDeleted, ActorFrom(ctx)
+	return *b, nil
+}
+
+// StreamBookings walks the user's entire booking history in (start, id) order,
+// handing each batch of up to batchSize bookings to fn. Only one batch is held
+// in memory at a time; a non-nil error from fn stops the walk.
+func (s *BookingService) StreamBookings(ctx context.Context, userID string, batchSize int, fn func([]models.Booking) error) error {
+	var (
+		afterStart any
+		afterID    string
+	)
+	for {
+		batch, err := s.Repo.ListBookingsAfter(ctx, s.DB, userID, afterStart, afterID, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		last := batch[len(batch)-1]
+		afterStart, afterID = last.StartAtUTC, last.ID
+	}
+}
+
+// HoldSlot places a tentative hold on an available slot so other callers
+// cannot book it until the hold is converted or expires.
+func (s *BookingService) HoldSlot(ctx context.Context, userID string, start, end time.Time) (*models.SlotHold, error) {
+	if s.Holds == nil {
+		return nil, errors.New("slot holds not enabled")
+	}
+	start, end = start.UTC(), end.UTC()
+	ttl := s.HoldTTL
+	if ttl <= 0 {
+		ttl = defaultHoldTTL
+	}
+
+	trx, err := beginTx(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+	defer trx.Rollback(ctx)
+
+	if _, err := s.Holds.DeleteExpiredHolds(ctx, trx, userID); err != nil {
+		return nil, err
+	}
+	if id, err := s.Holds.FindActiveHoldOverlap(ctx, trx, userID, start, end); err != nil {
+		return nil, err
+	} else if id != "" {
+		return nil, errors.New("slot is held")
+	}
+	if id, err := s.Repo.CheckOverlappingBooking(ctx, trx, userID, start, end, ""); err != nil {
+		return nil, err
+	} else if id != "" {
+		return nil, errors.New("slot already booked")
+	}
+
+	slots, err := s.Avail.generateSlots(ctx, userID, start.Add(-1*time.Second), end.Add(1*time.Second), slotOptions{ignoreHolds: true})
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, sl := range slots {
+		if sl.StartUTC.Equal(start) && sl.EndUTC.Equal(end) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.New("slot not available")
+	}
+
+	hold := &models.SlotHold{
+		UserID:     userID,
+		StartAtUTC: start,
+		EndAtUTC:   end,
+		Token:      "hold_" + uuid.New().String(),
+		ExpiresAt:  nowUTC(s.Clock).Add(ttl),
+	}
+	if err := s.Holds.InsertHold(ctx, trx, hold); err != nil {
+		return nil, err
+	}
+	if err := trx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// emailDomainAllowed reports whether the email's domain is on the allowlist.
+// An empty allowlist permits every domain.
+func (s *BookingService) emailDomainAllowed(email string) bool {
+	if len(s.AllowedEmailDomains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(strings.TrimSpace(email[at+1:]))
+	for _, allowed := range s.AllowedEmailDomains {
+		if domain == strings.ToLower(strings.TrimSpace(allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// candidateConflict checks the candidate's other bookings, with any user and
+// other than excludeID, against [start, end) under BlockCandidateOverlap and
+// MinCandidateGap. It returns the error message of the rule broken, or "".
+func (s *BookingService) candidateConflict(ctx context.Context, q repository.Querier, email string, start, end time.Time, excludeID string) (string, error) {
+	if s.BlockCandidateOverlap {
+		id, err := s.Repo.FindCandidateOverlap(ctx, q, email, start, end, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if id != "" {
+			return "candidate already booked", nil
+		}
+	}
+	if s.MinCandidateGap > 0 {
+		// Bookings exactly the gap apart touch the widened window without overlapping it
+		id, err := s.Repo.FindCandidateOverlap(ctx, q, email, start.Add(-s.MinCandidateGap), end.Add(s.MinCandidateGap), excludeID)
+		if err != nil {
+			return "", err
+		}
+		if id != "" {
+			return "candidate has another booking too close", nil
+		}
+	}
+	return "", nil
+}
+
+// GetBooking returns a single booking by id.
+func (s *BookingService) GetBooking(ctx context.Context, id string) (*models.Booking, error) {
+	b, err := s.Repo.GetBooking(ctx, s.DB, id)
+	if err == pgx.ErrNoRows {
+		return nil, errors.New("booking not found")
+	}
+	return b, err
+}
+
+// confirmationTransitions lists the states each confirmation state may move to.
+// A failed booking may be retried by moving it back to pending_event.
+var confirmationTransitions = map[string][]string{
+	models.ConfirmationStateConfirmed:    {models.ConfirmationStatePendingEvent},
+	models.ConfirmationStatePendingEvent: {models.ConfirmationStateEventCreated, models.ConfirmationStateFailed},
+	models.ConfirmationStateFailed:       {models.ConfirmationStatePendingEvent},
+}
+
+// AdvanceConfirmationState moves a booking to the next confirmation state. It is
+// driven by the reconciliation worker as asynchronous steps complete.
+func (s *BookingService) AdvanceConfirmationState(ctx context.Context, id, to string) error {
+	b, err := s.GetBooking(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !canAdvanceConfirmation(b.ConfirmationState, to) {
+		return fmt.Errorf("invalid confirmation state transition from %s to %s", b.ConfirmationState, to)
+	}
+	rows, err := s.Repo.UpdateConfirmationState(ctx, s.DB, id, b.ConfirmationState, to)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("confirmation state changed concurrently")
+	}
+	from := b.ConfirmationState
+	b.ConfirmationState = to
+	s.Hooks.stateChanged(ctx, *b, from)
 	return nil
 }
 
+func canAdvanceConfirmation(from, to string) bool {
+	for _, next := range confirmationTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// settleConfirmationState advances b step by step until it reaches target,
+// going through pending_event where there is no direct transition. It
+// reports whether the state changed.
+func (s *BookingService) settleConfirmationState(ctx context.Context, b models.Booking, target string) (bool, error) {
+	state, changed := b.ConfirmationState, false
+	for state != target {
+		next := target
+		if !canAdvanceConfirmation(state, next) {
+			next = models.ConfirmationStatePendingEvent
+			if !canAdvanceConfirmation(state, next) {
+				return changed, fmt.Errorf("invalid confirmation state transition from %s to %s", state, target)
+			}
+		}
+		if err := s.AdvanceConfirmationState(ctx, b.ID, next); err != nil {
+			return changed, err
+		}
+		state, changed = next, true
+	}
+	return changed, nil
+}
+
+// bookedBy classifies who made a booking from the calling key's email: the
+// candidate when it is their own address, otherwise a recruiter acting for
+// them. Bookings without a caller are left unclassified.
+func bookedBy(actor, candidateEmail string) (string, string) {
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return "", ""
+	}
+	if strings.EqualFold(actor, strings.TrimSpace(candidateEmail)) {
+		return models.BookedByCandidate, actor
+	}
+	return models.BookedByRecruiter, actor
+}
+
+// validateBookingPrice checks an optional booking price and returns the
+// canonical upper-case currency code.
+func validateBookingPrice(amountCents *int64, currencyCode string) (string, error) {
+	currencyCode = strings.TrimSpace(currencyCode)
+	if amountCents == nil && currencyCode == "" {
+		return "", nil
+	}
+	if amountCents == nil || currencyCode == "" {
+		return "", errors.New("amount_cents and currency must be given together")
+	}
+	if *amountCents < 0 {
+		return "", errors.New("amount_cents must not be negative")
+	}
+	unit, err := currency.ParseISO(currencyCode)
+	if err != nil {
+		return "", errors.New("currency must be an ISO 4217 code")
+	}
+	return unit.String(), nil
+}
+
 type createBookingRequest struct {
 	CandidateEmail string
 	Start          time.Time
@@ -119,4 +686,11 @@ type CreateBookingParams struct {
 	Type           string
 	Description    string
 	Title          string
+	HoldToken      string
+	GoogleEventID  string
+	Attendees      []models.BookingAttendee
+	AmountCents    *int64
+	Currency       string
+	// RecurrenceGroupID links the booking to the other occurrences of a series.
+	RecurrenceGroupID string
 }
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCreateBookingRejectsFarFutureStart(t *testing.T) {
+	cases := []struct {
+		name    string
+		start   time.Time
+		wantErr string
+	}{
+		{"year 3000", time.Date(3000, 1, 6, 9, 0, 0, 0, time.UTC), "start too far in the future"},
+		{"just past the lead", monday.Add(maxBookingLead + time.Minute), "start too far in the future"},
+		{"next week", monday.Add(7*24*time.Hour + 9*time.Hour), ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u1", time.Monday, "09:00", "10:00", 30)
+
+			_, err := s.CreateBooking(context.Background(), "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: tc.start, End: tc.start.Add(30 * time.Minute)})
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+			if n := len(s.Repo.(*fakeBookingRepo).bookings); tc.wantErr != "" && n != 0 {
+				t.Errorf("%d bookings stored after a rejected start", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+// Reasons a booking was left in place by ShiftBookings.
+const (
+	ShiftSkipNotAvailable = "outside availability"
+	ShiftSkipConflict     = "conflicts with another booking"
+	ShiftSkipPast         = "would start in the past"
+	ShiftSkipCandidate    = "candidate has another booking"
+)
+
+// maxShiftOffset bounds ShiftBookings offsets to a day either way.
+const maxShiftOffset = 24 * time.Hour
+
+// SkippedShift is a booking ShiftBookings could not move.
+type SkippedShift struct {
+	BookingID  string    `json:"booking_id"`
+	StartAtUTC time.Time `json:"start_at_utc"`
+	Reason     string    `json:"reason"`
+}
+
+// ShiftResult reports the outcome of ShiftBookings.
+type ShiftResult struct {
+	Shifted []models.Booking `json:"shifted"`
+	Skipped []SkippedShift   `json:"skipped"`
+}
+
+// ShiftBookings moves userID's confirmed bookings starting at or after since
+// by offset. A booking moves only when its new window lies within the user's
+// availability, starts in the future, overlaps no other booking where that
+// booking ends up and passes the candidate checks bookings are created under;
+// the rest are reported as skipped. All moves commit
+// together.
+func (s *BookingService) ShiftBookings(ctx context.Context, userID string, since time.Time, offset time.Duration) (ShiftResult, error) {
+	out := ShiftResult{Shifted: []models.Booking{}, Skipped: []SkippedShift{}}
+	if offset == 0 {
+		return out, errors.New("offset_mins must not be zero")
+	}
+	if offset > maxShiftOffset || offset < -maxShiftOffset {
+		return out, errors.New("offset_mins must be within a day")
+	}
+	since = since.UTC()
+	now := nowUTC(s.Clock)
+
+	trx, err := beginTx(ctx, s.DB)
+	if err != nil {
+		return out, err
+	}
+	defer trx.Rollback(ctx)
+
+	bookings, err := s.Repo.ListBookings(ctx, trx, userID, since, since.Add(maxBookingLead), true, 0, 0)
+	if err != nil {
+		return out, err
+	}
+	var pending []models.Booking
+	var spanEnd time.Time
+	for _, b := range bookings {
+		if b.Status != "confirmed" {
+			continue
+		}
+		pending = append(pending, b)
+		if b.EndAtUTC.After(spanEnd) {
+			spanEnd = b.EndAtUTC
+		}
+	}
+	if len(pending) == 0 {
+		return out, nil
+	}
+
+	// Availability and other bookings across every old and new window
+	lo, hi := since.Add(-maxShiftOffset), spanEnd.Add(maxShiftOffset)
+	slots, err := s.Avail.generateSlots(ctx, userID, lo, hi, slotOptions{ignoreHolds: true, ignoreBookings: true})
+	if err != nil {
+		return out, err
+	}
+	spans := mergeSlotSpans(slots)
+	others, err := s.Repo.ListBookingsInRange(ctx, trx, userID, lo, hi)
+	if err != nil {
+		return out, err
+	}
+	// Where each booking sits as moves are applied
+	placed := map[string][2]time.Time{}
+	for _, b := range others {
+		placed[b.ID] = [2]time.Time{b.StartAtUTC, b.EndAtUTC}
+	}
+
+	// Move the booking nearest the direction of travel first so it clears
+	// the way for the ones behind it
+	sort.SliceStable(pending, func(i, j int) bool {
+		if offset > 0 {
+			return pending[i].StartAtUTC.After(pending[j].StartAtUTC)
+		}
+		return pending[i].StartAtUTC.Before(pending[j].StartAtUTC)
+	})
+
+	var moves [][2]models.Booking
+	for _, b := range pending {
+		start, end := b.StartAtUTC.Add(offset), b.EndAtUTC.Add(offset)
+		skip := ""
+		switch {
+		case start.Before(now):
+			skip = ShiftSkipPast
+		case !spansCover(spans, start, end):
+			skip = ShiftSkipNotAvailable
+		default:
+			for id, w := range placed {
+				if id != b.ID && start.Before(w[1]) && end.After(w[0]) {
+					skip = ShiftSkipConflict
+					break
+				}
+			}
+		}
+		if skip == "" {
+			conflict, err := s.candidateConflict(ctx, trx, b.CandidateEmail, start, end, b.ID)
+			if err != nil {
+				return ShiftResult{}, err
+			}
+			if conflict != "" {
+				skip = ShiftSkipCandidate
+			}
+		}
+		if skip != "" {
+			out.Skipped = append(out.Skipped, SkippedShift{BookingID: b.ID, StartAtUTC: b.StartAtUTC, Reason: skip})
+			continue
+		}
+		n, err := s.Repo.UpdateBookingTimes(ctx, trx, b.ID, start, end)
+		if errors.Is(err, repository.ErrConflict) {
+			// Overlaps were checked above, so another writer got in first
+			return ShiftResult{}, errors.New("bookings changed concurrently")
+		}
+		if err != nil {
+			return ShiftResult{}, err
+		}
+		if n == 0 {
+			continue
+		}
+		placed[b.ID] = [2]time.Time{start, end}
+		moved := b
+		moved.StartAtUTC, moved.EndAtUTC = start, end
+		out.Shifted = append(out.Shifted, moved)
+		moves = append(moves, [2]models.Booking{b, moved})
+	}
+
+	if err := trx.Commit(ctx); err != nil {
+		return ShiftResult{}, err
+	}
+	for _, m := range moves {
+		s.Hooks.rescheduled(ctx, m[0], m[1])
+	}
+	return out, nil
+}
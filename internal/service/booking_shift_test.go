@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestShiftBookings(t *testing.T) {
+	booking := func(id string, h, m int) models.Booking {
+		return models.Booking{ID: id, UserID: "u1", CandidateEmail: id + "@example.com", StartAtUTC: at(h, m), EndAtUTC: at(h, m+30)}
+	}
+	cases := []struct {
+		name        string
+		bookings    []models.Booking
+		since       time.Time
+		offset      time.Duration
+		wantShifted []string
+		wantSkipped map[string]string
+		wantStarts  map[string]string // every booking's start afterwards
+	}{
+		{"clean shift of the afternoon",
+			[]models.Booking{booking("morning", 10, 0), booking("a", 13, 0), booking("b", 13, 30), booking("c", 15, 0)},
+			at(12, 0), 30 * time.Minute,
+			[]string{"c", "b", "a"}, map[string]string{},
+			map[string]string{"morning": "10:00", "a": "13:30", "b": "14:00", "c": "15:30"}},
+		{"blocked by a booking that stays",
+			[]models.Booking{booking("stays", 12, 30), booking("blocked", 13, 0), booking("moves", 15, 0)},
+			at(13, 0), -30 * time.Minute,
+			[]string{"moves"}, map[string]string{"blocked": ShiftSkipConflict},
+			map[string]string{"stays": "12:30", "blocked": "13:00", "moves": "14:30"}},
+		{"pushed past the end of availability",
+			[]models.Booking{booking("late", 17, 30), booking("early", 16, 0)},
+			at(12, 0), 30 * time.Minute,
+			[]string{"early"}, map[string]string{"late": ShiftSkipNotAvailable},
+			map[string]string{"late": "17:30", "early": "16:30"}},
+		{"pulled into the past",
+			[]models.Booking{booking("first", 9, 0)},
+			at(9, 0), -90 * time.Minute,
+			[]string{}, map[string]string{"first": ShiftSkipPast},
+			map[string]string{"first": "09:00"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(at(8, 0))
+			addRule(t, avail, "u1", time.Monday, "09:00", "18:00", 30)
+			repo := newFakeBookingRepo(tc.bookings...)
+			s.Repo, avail.Book = repo, repo
+
+			res, err := s.ShiftBookings(context.Background(), "u1", tc.since, tc.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			shifted := []string{}
+			for _, b := range res.Shifted {
+				shifted = append(shifted, b.ID)
+			}
+			if !reflect.DeepEqual(shifted, tc.wantShifted) {
+				t.Errorf("shifted = %v, want %v", shifted, tc.wantShifted)
+			}
+			skipped := map[string]string{}
+			for _, sk := range res.Skipped {
+				skipped[sk.BookingID] = sk.Reason
+			}
+			if !reflect.DeepEqual(skipped, tc.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", skipped, tc.wantSkipped)
+			}
+			for id, want := range tc.wantStarts {
+				if got := repo.get(id).StartAtUTC.Format("15:04"); got != want {
+					t.Errorf("%s starts at %s, want %s", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestShiftBookingsValidatesOffset(t *testing.T) {
+	_, s := newFakeServices(time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC))
+	cases := []struct {
+		offset  time.Duration
+		wantErr string
+	}{
+		{0, "offset_mins must not be zero"},
+		{25 * time.Hour, "offset_mins must be within a day"},
+		{-25 * time.Hour, "offset_mins must be within a day"},
+	}
+	for _, tc := range cases {
+		_, err := s.ShiftBookings(context.Background(), "u1", time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC), tc.offset)
+		if err == nil || err.Error() != tc.wantErr {
+			t.Errorf("offset %s: err = %v, want %q", tc.offset, err, tc.wantErr)
+		}
+	}
+}
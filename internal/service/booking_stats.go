@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// BookingStats summarises a user's bookings over a period. Utilization is
+// confirmed bookings over the slots the user's availability offers in the
+// period, booked or not; it is zero when no slots are offered.
+type BookingStats struct {
+	UserID              string    `json:"user_id"`
+	From                time.Time `json:"from"`
+	To                  time.Time `json:"to"`
+	Total               int       `json:"total"`
+	Confirmed           int       `json:"confirmed"`
+	Cancelled           int       `json:"cancelled"`
+	AvgDurationMins     float64   `json:"avg_duration_mins"`
+	BusiestWeekday      string    `json:"busiest_weekday,omitempty"`
+	BusiestWeekdayCount int       `json:"busiest_weekday_count"`
+	TotalSlots          int       `json:"total_slots"`
+	Utilization         float64   `json:"utilization"`
+}
+
+// Stats computes userID's booking statistics for bookings starting in
+// [from, to). Counts come from the database; the slot total comes from slot
+// generation with bookings and holds left in.
+func (s *BookingService) Stats(ctx context.Context, userID string, from, to time.Time) (BookingStats, error) {
+	out := BookingStats{UserID: userID, From: from.UTC(), To: to.UTC()}
+	agg, err := s.Repo.AggregateBookings(ctx, s.DB, userID, from.UTC(), to.UTC())
+	if err != nil {
+		return out, err
+	}
+	out.Total = agg.Total
+	out.Confirmed = agg.Confirmed
+	out.Cancelled = agg.Cancelled
+	out.AvgDurationMins = math.Round(agg.AvgDurationMins*100) / 100
+	if agg.BusiestWeekday != nil {
+		out.BusiestWeekday = time.Weekday(*agg.BusiestWeekday).String()
+		out.BusiestWeekdayCount = agg.BusiestWeekdayCount
+	}
+
+	slots, err := s.Avail.generateSlots(ctx, userID, from.UTC(), to.UTC(), slotOptions{ignoreHolds: true, ignoreBookings: true})
+	if err != nil {
+		return out, err
+	}
+	out.TotalSlots = len(slots)
+	if out.TotalSlots > 0 {
+		out.Utilization = math.Round(float64(out.Confirmed)/float64(out.TotalSlots)*10000) / 10000
+	}
+	return out, nil
+}
+
+// UtilizationForecast is the booking capacity of a user over a period:
+// offered slots, how many are already booked and how many remain.
+type UtilizationForecast struct {
+	UserID         string    `json:"user_id"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	TotalSlots     int       `json:"total_slots"`
+	BookedSlots    int       `json:"booked_slots"`
+	RemainingSlots int       `json:"remaining_slots"`
+	UtilizationPct float64   `json:"utilization_pct"`
+}
+
+// Forecast computes userID's capacity for [from, to) from slot generation:
+// the slots their availability offers, and those still free once bookings
+// are subtracted. Holds are not counted as booked.
+func (s *BookingService) Forecast(ctx context.Context, userID string, from, to time.Time) (UtilizationForecast, error) {
+	out := UtilizationForecast{UserID: userID, From: from.UTC(), To: to.UTC()}
+	all, err := s.Avail.generateSlots(ctx, userID, from.UTC(), to.UTC(), slotOptions{ignoreHolds: true, ignoreBookings: true})
+	if err != nil {
+		return out, err
+	}
+	free, err := s.Avail.generateSlots(ctx, userID, from.UTC(), to.UTC(), slotOptions{ignoreHolds: true})
+	if err != nil {
+		return out, err
+	}
+	out.TotalSlots = len(all)
+	out.RemainingSlots = len(free)
+	out.BookedSlots = out.TotalSlots - out.RemainingSlots
+	if out.TotalSlots > 0 {
+		out.UtilizationPct = math.Round(float64(out.BookedSlots)/float64(out.TotalSlots)*10000) / 100
+	}
+	return out, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestBookingStatsForKnownDataset(t *testing.T) {
+	at := func(days, h, m int) time.Time {
+		return monday.AddDate(0, 0, days).Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	booking := func(id string, start time.Time, mins int, status string) models.Booking {
+		return models.Booking{ID: id, UserID: "u1", StartAtUTC: start, EndAtUTC: start.Add(time.Duration(mins) * time.Minute), Status: status}
+	}
+
+	cases := []struct {
+		name     string
+		bookings []models.Booking
+		from, to time.Time
+		want     BookingStats
+	}{
+		{"week with bookings", []models.Booking{
+			booking("mon-0900", at(0, 9, 0), 30, ""),
+			booking("mon-1000", at(0, 10, 0), 30, ""),
+			booking("mon-1100", at(0, 11, 0), 30, "cancelled"),
+			booking("wed-1300", at(2, 13, 0), 60, ""),
+			booking("next-mon", at(7, 9, 0), 30, ""),
+		}, monday, monday.AddDate(0, 0, 7), BookingStats{
+			Total: 4, Confirmed: 3, Cancelled: 1, AvgDurationMins: 40,
+			BusiestWeekday: "Monday", BusiestWeekdayCount: 2, TotalSlots: 8, Utilization: 0.375,
+		}},
+		{"uneven average", []models.Booking{
+			booking("mon-0900", at(0, 9, 0), 30, ""),
+			booking("mon-0930", at(0, 9, 30), 30, ""),
+			booking("wed-1300", at(2, 13, 0), 40, ""),
+		}, monday, monday.AddDate(0, 0, 7), BookingStats{
+			Total: 3, Confirmed: 3, AvgDurationMins: 33.33,
+			BusiestWeekday: "Monday", BusiestWeekdayCount: 2, TotalSlots: 8, Utilization: 0.375,
+		}},
+		{"no bookings", nil, monday, monday.AddDate(0, 0, 7), BookingStats{TotalSlots: 8}},
+		{"no availability", nil, at(1, 0, 0), at(2, 0, 0), BookingStats{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u1", time.Monday, "09:00", "12:00", 30)
+			addRule(t, avail, "u1", time.Wednesday, "13:00", "15:00", 60)
+			repo := newFakeBookingRepo(tc.bookings...)
+			s.Repo, avail.Book = repo, repo
+
+			got, err := s.Stats(context.Background(), "u1", tc.from, tc.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tc.want
+			want.UserID, want.From, want.To = "u1", tc.from, tc.to
+			if got != want {
+				t.Errorf("stats = %+v\nwant    %+v", got, want)
+			}
+		})
+	}
+}
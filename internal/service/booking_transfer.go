@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+// Reasons a booking was left with its original owner by TransferBookings.
+const (
+	TransferSkipNotAvailable = "outside target availability"
+	TransferSkipConflict     = "conflicts with target booking"
+)
+
+// SkippedTransfer is a booking TransferBookings could not move.
+type SkippedTransfer struct {
+	BookingID  string    `json:"booking_id"`
+	StartAtUTC time.Time `json:"start_at_utc"`
+	Reason     string    `json:"reason"`
+}
+
+// TransferResult reports the outcome of TransferBookings.
+type TransferResult struct {
+	Transferred []models.Booking  `json:"transferred"`
+	Skipped     []SkippedTransfer `json:"skipped"`
+}
+
+// TransferBookings moves fromUserID's confirmed and pending bookings starting at or after
+// since to toUserID. A booking moves only when it matches one of the target's
+// open slots and overlaps none of the target's bookings; the rest are
+// reported as skipped. All moves commit together.
+func (s *BookingService) TransferBookings(ctx context.Context, fromUserID, toUserID string, since time.Time) (TransferResult, error) {
+	out := TransferResult{Transferred: []models.Booking{}, Skipped: []SkippedTransfer{}}
+	if toUserID == "" {
+		return out, errors.New("to_user_id required")
+	}
+	if toUserID == fromUserID {
+		return out, errors.New("cannot transfer bookings to the same user")
+	}
+	since = since.UTC()
+
+	trx, err := beginTx(ctx, s.DB)
+	if err != nil {
+		return out, err
+	}
+	defer trx.Rollback(ctx)
+
+	bookings, err := s.Repo.ListBookings(ctx, trx, fromUserID, since, since.Add(maxBookingLead), true, 0, 0)
+	if err != nil {
+		return out, err
+	}
+	var pending []models.Booking
+	var spanEnd time.Time
+	for _, b := range bookings {
+		if b.Status != "confirmed" && b.Status != "pending" {
+			continue
+		}
+		pending = append(pending, b)
+		if b.EndAtUTC.After(spanEnd) {
+			spanEnd = b.EndAtUTC
+		}
+	}
+	if len(pending) == 0 {
+		return out, nil
+	}
+
+	// One generation and one booking lookup for the target covers every move
+	slots, err := s.Avail.generateSlots(ctx, toUserID, since, spanEnd, slotOptions{ignoreBookings: true})
+	if err != nil {
+		return out, err
+	}
+	offered := map[[2]int64]struct{}{}
+	for _, sl := range slots {
+		offered[[2]int64{sl.StartUTC.Unix(), sl.EndUTC.Unix()}] = struct{}{}
+	}
+	taken, err := s.Repo.ListBookingsInRange(ctx, trx, toUserID, since.Add(-24*time.Hour), spanEnd)
+	if err != nil {
+		return out, err
+	}
+
+	var moves [][2]models.Booking
+	for _, b := range pending {
+		skip := ""
+		if _, ok := offered[[2]int64{b.StartAtUTC.Unix(), b.EndAtUTC.Unix()}]; !ok {
+			skip = TransferSkipNotAvailable
+		} else {
+			for _, t := range taken {
+				if b.StartAtUTC.Before(t.EndAtUTC) && b.EndAtUTC.After(t.StartAtUTC) {
+					skip = TransferSkipConflict
+					break
+				}
+			}
+		}
+		if skip != "" {
+			out.Skipped = append(out.Skipped, SkippedTransfer{BookingID: b.ID, StartAtUTC: b.StartAtUTC, Reason: skip})
+			continue
+		}
+		n, err := s.Repo.ReassignBooking(ctx, trx, b.ID, toUserID)
+		if err != nil {
+			return TransferResult{}, err
+		}
+		if n == 0 {
+			continue
+		}
+		moved := b
+		moved.UserID = toUserID
+		taken = append(taken, moved)
+		out.Transferred = append(out.Transferred, moved)
+		moves = append(moves, [2]models.Booking{b, moved})
+	}
+
+	if err := trx.Commit(ctx); err != nil {
+		return TransferResult{}, err
+	}
+	for _, m := range moves {
+		s.Hooks.rescheduled(ctx, m[0], m[1])
+	}
+	return out, nil
+}
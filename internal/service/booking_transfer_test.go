@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestTransferBookings(t *testing.T) {
+	at := func(h int) time.Time { return monday.Add(time.Duration(h) * time.Hour) }
+	booking := func(id, userID string, h int) models.Booking {
+		return models.Booking{ID: id, UserID: userID, StartAtUTC: at(h), EndAtUTC: at(h).Add(30 * time.Minute)}
+	}
+	avail, s := newFakeServices(monday)
+	repo := s.Repo.(*fakeBookingRepo)
+	for _, b := range []models.Booking{
+		booking("free", "from", 9),
+		booking("conflicting", "from", 10),
+		booking("unavailable", "from", 11),
+		booking("target-own", "to", 10),
+	} {
+		b := b
+		b.Status = "confirmed"
+		repo.bookings[b.ID] = &b
+	}
+	awaiting := booking("awaiting", "from", 9)
+	awaiting.StartAtUTC, awaiting.EndAtUTC = at(9).Add(30*time.Minute), at(10)
+	awaiting.Status = "pending"
+	repo.bookings[awaiting.ID] = &awaiting
+	addRule(t, avail, "to", time.Monday, "09:00", "11:00", 30)
+
+	res, err := s.TransferBookings(context.Background(), "from", "to", monday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Transferred) != 2 || res.Transferred[0].ID != "free" || res.Transferred[1].ID != "awaiting" {
+		t.Errorf("transferred = %+v, want the free and awaiting bookings", res.Transferred)
+	}
+	for _, id := range []string{"free", "awaiting"} {
+		if repo.bookings[id].UserID != "to" {
+			t.Errorf("%s booking not reassigned", id)
+		}
+	}
+	if repo.bookings["awaiting"].Status != "pending" {
+		t.Errorf("awaiting booking is %s after the transfer, want pending", repo.bookings["awaiting"].Status)
+	}
+	skipped := map[string]string{}
+	for _, sk := range res.Skipped {
+		skipped[sk.BookingID] = sk.Reason
+	}
+	want := map[string]string{"conflicting": TransferSkipConflict, "unavailable": TransferSkipNotAvailable}
+	for id, reason := range want {
+		if skipped[id] != reason {
+			t.Errorf("%s skipped with %q, want %q", id, skipped[id], reason)
+		}
+		if repo.bookings[id].UserID != "from" {
+			t.Errorf("%s moved despite being skipped", id)
+		}
+	}
+	if len(res.Skipped) != len(want) {
+		t.Errorf("skipped = %+v", res.Skipped)
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BusinessHours is an organisation-wide outer bound on bookable time: slots
+// and bookings must fall entirely within Start..End local time on one of
+// Days, in Location. Rules reaching outside it are clipped.
+type BusinessHours struct {
+	Days     [7]bool // indexed by time.Weekday
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+}
+
+var weekdayAbbrevs = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseBusinessHours parses a spec such as "Mon-Fri 08:00-18:00" or
+// "Mon,Wed,Fri 09:00-17:00" with times in the IANA zone tz (UTC when empty).
+func ParseBusinessHours(spec, tz string) (*BusinessHours, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("business hours %q must look like \"Mon-Fri 08:00-18:00\"", spec)
+	}
+	bh := &BusinessHours{Location: time.UTC}
+	if tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid business hours timezone %q", tz)
+		}
+		bh.Location = loc
+	}
+	for _, part := range strings.Split(fields[0], ",") {
+		first, last, isRange := strings.Cut(strings.ToLower(part), "-")
+		from, ok1 := weekdayAbbrevs[first]
+		to, ok2 := from, true
+		if isRange {
+			to, ok2 = weekdayAbbrevs[last]
+		}
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("invalid business days %q", part)
+		}
+		for d := from; ; d = (d + 1) % 7 {
+			bh.Days[d] = true
+			if d == to {
+				break
+			}
+		}
+	}
+	startStr, endStr, ok := strings.Cut(fields[1], "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid business hours %q", fields[1])
+	}
+	start, err := time.Parse("15:04", startStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid business hours start %q", startStr)
+	}
+	bh.Start = time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+	if endStr == "24:00" {
+		bh.End = 24 * time.Hour
+	} else {
+		end, err := time.Parse("15:04", endStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid business hours end %q", endStr)
+		}
+		bh.End = time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute
+	}
+	if bh.End <= bh.Start {
+		return nil, fmt.Errorf("business hours must end after they start")
+	}
+	return bh, nil
+}
+
+// Contains reports whether [start, end) lies within business hours of a
+// single local day. A nil policy contains everything.
+func (bh *BusinessHours) Contains(start, end time.Time) bool {
+	if bh == nil {
+		return true
+	}
+	ls := start.In(bh.Location)
+	y, m, d := ls.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, bh.Location)
+	if !bh.Days[ls.Weekday()] {
+		return false
+	}
+	open := wallClock(midnight, bh.Start, bh.Location)
+	closing := wallClock(midnight, bh.End, bh.Location)
+	return !start.Before(open) && !end.After(closing)
+}
+
+// wallClock returns the instant at local time-of-day tod on the date of
+// midnight, so DST changes shift the bound like a wall clock would.
+func wallClock(midnight time.Time, tod time.Duration, loc *time.Location) time.Time {
+	y, m, d := midnight.Date()
+	if tod >= 24*time.Hour {
+		return time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	}
+	return time.Date(y, m, d, int(tod/time.Hour), int(tod%time.Hour/time.Minute), 0, 0, loc)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBusinessHoursClipRules(t *testing.T) {
+	cases := []struct {
+		name     string
+		spec, tz string
+		day      time.Weekday
+		from, to string // the rule, in UTC
+		want     []string
+	}{
+		{"rule inside business hours", "Mon-Fri 08:00-18:00", "", time.Monday, "09:00", "11:00", []string{"09:00", "10:00"}},
+		{"rule extending past both ends", "Mon-Fri 08:00-18:00", "", time.Monday, "06:00", "20:00", []string{"08:00", "09:00", "10:00", "11:00", "12:00", "13:00", "14:00", "15:00", "16:00", "17:00"}},
+		{"slot straddling closing time", "Mon-Fri 08:00-17:30", "", time.Monday, "15:00", "19:00", []string{"15:00", "16:00"}},
+		{"rule on a closed day", "Mon-Fri 08:00-18:00", "", time.Saturday, "09:00", "11:00", []string{}},
+		{"hours in another timezone", "Mon-Fri 09:00-17:00", "America/New_York", time.Monday, "12:00", "23:00", []string{"14:00", "15:00", "16:00", "17:00", "18:00", "19:00", "20:00", "21:00"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			bh, err := ParseBusinessHours(tc.spec, tc.tz)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, _ := newFakeServices(monday)
+			s.BusinessHours = bh
+			addRule(t, s, "u1", tc.day, tc.from, tc.to, 60)
+
+			slots, err := s.GenerateAvailableSlots(context.Background(), "u1", monday, monday.AddDate(0, 0, 7))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := slotStarts(slots); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("slots = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBusinessHoursRejectBookings(t *testing.T) {
+	cases := []struct {
+		name    string
+		start   time.Time
+		wantErr string
+	}{
+		{"inside business hours", monday.Add(9 * time.Hour), ""},
+		{"before opening", monday.Add(7 * time.Hour), "outside business hours"},
+		{"running past closing", monday.Add(17*time.Hour + 30*time.Minute), "outside business hours"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			avail.BusinessHours, _ = ParseBusinessHours("Mon-Fri 08:00-18:00", "")
+			addRule(t, avail, "u1", time.Monday, "06:00", "20:00", 60)
+
+			_, err := s.CreateBooking(context.Background(), "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: tc.start, End: tc.start.Add(time.Hour)})
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseBusinessHours(t *testing.T) {
+	cases := []struct {
+		spec, tz string
+		days     []time.Weekday
+		wantErr  string
+	}{
+		{"Mon-Fri 08:00-18:00", "", []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, ""},
+		{"mon,wed,fri 09:00-17:00", "Europe/Berlin", []time.Weekday{time.Monday, time.Wednesday, time.Friday}, ""},
+		{"Fri-Mon 10:00-24:00", "", []time.Weekday{time.Sunday, time.Monday, time.Friday, time.Saturday}, ""},
+		{"Mon-Fri", "", nil, `business hours "Mon-Fri" must look like "Mon-Fri 08:00-18:00"`},
+		{"Mon-Funday 08:00-18:00", "", nil, `invalid business days "Mon-Funday"`},
+		{"Mon-Fri 18:00-08:00", "", nil, "business hours must end after they start"},
+		{"Mon-Fri 08:00", "", nil, `invalid business hours "08:00"`},
+		{"Mon-Fri 08:00-18:00", "Mars/Base", nil, `invalid business hours timezone "Mars/Base"`},
+	}
+	for _, tc := range cases {
+		bh, err := ParseBusinessHours(tc.spec, tc.tz)
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+			t.Errorf("%q: err = %v, want %q", tc.spec, err, tc.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		var days []time.Weekday
+		for d, open := range bh.Days {
+			if open {
+				days = append(days, time.Weekday(d))
+			}
+		}
+		if !reflect.DeepEqual(days, tc.days) {
+			t.Errorf("%q: days = %v, want %v", tc.spec, days, tc.days)
+		}
+	}
+}
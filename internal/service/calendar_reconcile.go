@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+// ErrEventNotFound is returned by a CalendarEventSource for an event that was
+// deleted from the calendar.
+var ErrEventNotFound = errors.New("calendar event not found")
+
+// CalendarEvent is the part of a calendar event that reconciliation compares
+// against its booking.
+type CalendarEvent struct {
+	StartUTC  time.Time
+	EndUTC    time.Time
+	Cancelled bool
+}
+
+// CalendarEventSource looks up calendar events by id, such as a user's Google
+// Calendar.
+type CalendarEventSource interface {
+	GetEvent(ctx context.Context, eventID string) (*CalendarEvent, error)
+}
+
+// Reconciliation issues, in ReconcileItem.Issue.
+const (
+	ReconcileEventDeleted = "event_deleted"
+	ReconcileTimeChanged  = "time_changed"
+	ReconcileLookupFailed = "lookup_failed"
+	ReconcileStateFailed  = "state_update_failed"
+)
+
+// CancelReasonEventDeleted is the cancellation reason of bookings cancelled
+// because their calendar event was deleted.
+const CancelReasonEventDeleted = "calendar event deleted"
+
+// reconcileMaxBookings caps how many bookings one reconciliation checks.
+const reconcileMaxBookings = 500
+
+// ReconcileItem is one booking that disagrees with its calendar event.
+type ReconcileItem struct {
+	BookingID     string     `json:"booking_id"`
+	GoogleEventID string     `json:"google_event_id"`
+	Issue         string     `json:"issue"`
+	StartAtUTC    time.Time  `json:"start_at_utc"`
+	EndAtUTC      time.Time  `json:"end_at_utc"`
+	EventStartUTC *time.Time `json:"event_start_utc,omitempty"`
+	EventEndUTC   *time.Time `json:"event_end_utc,omitempty"`
+	Applied       bool       `json:"applied"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// ReconcileReport is the outcome of ReconcileWithCalendar.
+type ReconcileReport struct {
+	UserID  string          `json:"user_id"`
+	Checked int             `json:"checked"`
+	Apply   bool            `json:"apply"`
+	Items   []ReconcileItem `json:"items"`
+	// StatesAdvanced counts bookings whose confirmation state was moved on.
+	StatesAdvanced int `json:"states_advanced"`
+}
+
+// ReconcileWithCalendar compares the user's upcoming live bookings that are
+// linked to a calendar event against the events in src. Bookings whose event
+// was deleted or cancelled are reported as event_deleted, and those whose
+// event moved as time_changed. With apply set, the former are cancelled and
+// the latter moved to the event's times; a fix that fails is reported on its
+// item and does not stop the others.
+//
+// With apply set it also drives the confirmation state: a booking whose event
+// exists becomes event_created, and one still pending_event whose event is
+// gone becomes failed before it is cancelled.
+func (s *BookingService) ReconcileWithCalendar(ctx context.Context, userID string, src CalendarEventSource, apply bool) (ReconcileReport, error) {
+	report := ReconcileReport{UserID: userID, Apply: apply, Items: []ReconcileItem{}}
+	bookings, err := s.Repo.ListUpcomingBookings(ctx, s.DB, userID, nowUTC(s.Clock), nil, "", reconcileMaxBookings)
+	if err != nil {
+		return report, err
+	}
+	for _, b := range bookings {
+		if b.GoogleEventID == "" {
+			continue
+		}
+		report.Checked++
+		item := ReconcileItem{BookingID: b.ID, GoogleEventID: b.GoogleEventID, StartAtUTC: b.StartAtUTC, EndAtUTC: b.EndAtUTC}
+
+		ev, err := src.GetEvent(ctx, b.GoogleEventID)
+		switch {
+		case errors.Is(err, ErrEventNotFound) || (err == nil && ev.Cancelled):
+			item.Issue = ReconcileEventDeleted
+			if apply {
+				if b.ConfirmationState == models.ConfirmationStatePendingEvent {
+					if changed, _ := s.settleConfirmationState(ctx, b, models.ConfirmationStateFailed); changed {
+						report.StatesAdvanced++
+					}
+				}
+				item.Applied, item.Error = applyFix(s.CancelBooking(ctx, b.ID, CancelReasonEventDeleted))
+			}
+		case err != nil:
+			item.Issue = ReconcileLookupFailed
+			item.Error = err.Error()
+		case !ev.StartUTC.Equal(b.StartAtUTC) || !ev.EndUTC.Equal(b.EndAtUTC):
+			item.Issue = ReconcileTimeChanged
+			start, end := ev.StartUTC.UTC(), ev.EndUTC.UTC()
+			item.EventStartUTC, item.EventEndUTC = &start, &end
+			if apply {
+				item.Applied, item.Error = applyFix(s.moveBooking(ctx, b.ID, start, end))
+			}
+		}
+		if apply && item.Issue != ReconcileEventDeleted && item.Issue != ReconcileLookupFailed {
+			changed, err := s.settleConfirmationState(ctx, b, models.ConfirmationStateEventCreated)
+			if changed {
+				report.StatesAdvanced++
+			}
+			if err != nil && item.Issue == "" {
+				item.Issue, item.Error = ReconcileStateFailed, err.Error()
+			}
+		}
+		if item.Issue == "" {
+			continue
+		}
+		report.Items = append(report.Items, item)
+	}
+	return report, nil
+}
+
+func applyFix(err error) (bool, string) {
+	if err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+// moveBooking sets a live booking's times, notifying hooks of the change.
+// Availability is not checked: the calendar is taken as the source of truth.
+func (s *BookingService) moveBooking(ctx context.Context, id string, start, end time.Time) error {
+	before, err := s.Repo.GetBooking(ctx, s.DB, id)
+	if err != nil {
+		return err
+	}
+	n, err := s.Repo.UpdateBookingTimes(ctx, s.DB, id, start, end)
+	if errors.Is(err, repository.ErrConflict) {
+		return errors.New("event time overlaps another booking")
+	}
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("booking is no longer live")
+	}
+	after := *before
+	after.StartAtUTC, after.EndAtUTC = start, end
+	s.Hooks.rescheduled(ctx, *before, after)
+	return nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestReconcileWithCalendarTimeChanged(t *testing.T) {
+	now := monday.Add(8 * time.Hour)
+	start, moved := at(10, 0), at(14, 0)
+	booking := models.Booking{ID: "b1", UserID: "u1", StartAtUTC: start, EndAtUTC: start.Add(time.Hour), GoogleEventID: "ev1", ConfirmationState: models.ConfirmationStateEventCreated}
+	src := fakeEventSource{"ev1": {StartUTC: moved, EndUTC: moved.Add(30 * time.Minute)}}
+
+	for _, apply := range []bool{false, true} {
+		repo := newFakeBookingRepo(booking)
+		hook := &recordingHook{}
+		s := &BookingService{Repo: repo, Clock: FixedClock(now), Hooks: &BookingHooks{}}
+		s.Hooks.Register(hook)
+
+		report, err := s.ReconcileWithCalendar(context.Background(), "u1", src, apply)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.Items) != 1 {
+			t.Fatalf("apply %v: items = %+v, want one", apply, report.Items)
+		}
+		item := report.Items[0]
+		if item.Issue != ReconcileTimeChanged || item.BookingID != "b1" || item.Applied != apply || item.Error != "" {
+			t.Errorf("apply %v: item = %+v", apply, item)
+		}
+		if !item.StartAtUTC.Equal(start) || item.EventStartUTC == nil || !item.EventStartUTC.Equal(moved) ||
+			item.EventEndUTC == nil || !item.EventEndUTC.Equal(moved.Add(30*time.Minute)) {
+			t.Errorf("apply %v: item times = %v, event %v-%v", apply, item.StartAtUTC, item.EventStartUTC, item.EventEndUTC)
+		}
+
+		got := repo.get("b1")
+		wantStart, wantEnd := start, start.Add(time.Hour)
+		var wantEvents []string
+		if apply {
+			wantStart, wantEnd = moved, moved.Add(30*time.Minute)
+			wantEvents = []string{"rescheduled 10:00 to 14:00"}
+		}
+		if !got.StartAtUTC.Equal(wantStart) || !got.EndAtUTC.Equal(wantEnd) {
+			t.Errorf("apply %v: booking at %v-%v, want %v-%v", apply, got.StartAtUTC, got.EndAtUTC, wantStart, wantEnd)
+		}
+		if len(hook.events) != len(wantEvents) || (len(wantEvents) > 0 && hook.events[0] != wantEvents[0]) {
+			t.Errorf("apply %v: hook events = %v, want %v", apply, hook.events, wantEvents)
+		}
+	}
+}
+
+func TestReconcileWithCalendarTimeChangedIntoAnotherBooking(t *testing.T) {
+	now := monday.Add(8 * time.Hour)
+	start, taken := at(10, 0), at(14, 0)
+	repo := newFakeBookingRepo(
+		models.Booking{ID: "b1", UserID: "u1", StartAtUTC: start, EndAtUTC: start.Add(time.Hour), GoogleEventID: "ev1", ConfirmationState: models.ConfirmationStateEventCreated},
+		models.Booking{ID: "b2", UserID: "u1", StartAtUTC: taken, EndAtUTC: taken.Add(time.Hour)},
+	)
+	src := fakeEventSource{"ev1": {StartUTC: taken, EndUTC: taken.Add(time.Hour)}}
+	s := &BookingService{Repo: repo, Clock: FixedClock(now)}
+
+	report, err := s.ReconcileWithCalendar(context.Background(), "u1", src, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Items) != 1 {
+		t.Fatalf("items = %+v, want one", report.Items)
+	}
+	item := report.Items[0]
+	if item.Issue != ReconcileTimeChanged || item.Applied || item.Error != "event time overlaps another booking" {
+		t.Errorf("item = %+v, want an unapplied time_changed with the overlap error", item)
+	}
+	if got := repo.get("b1"); !got.StartAtUTC.Equal(start) {
+		t.Errorf("booking moved to %v despite the collision", got.StartAtUTC)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// CalendarSyncReport is the outcome of SyncCalendar. Shared is set when the
+// caller joined a sync that another request had already started.
+type CalendarSyncReport struct {
+	ReconcileReport
+	Shared bool `json:"shared"`
+}
+
+// calendarSyncs tracks the calendar sync running for each user.
+type calendarSyncs struct {
+	mu      sync.Mutex
+	running map[string]*calendarSync
+}
+
+type calendarSync struct {
+	done   chan struct{}
+	report ReconcileReport
+	err    error
+}
+
+// SyncCalendar brings the user's upcoming bookings in line with their
+// calendar now, cancelling those whose event was deleted and moving those
+// whose event moved. Requests arriving while a sync of the same user is
+// running wait for it and share its result instead of starting another. The
+// sync runs to completion even if the request that started it goes away.
+func (s *BookingService) SyncCalendar(ctx context.Context, userID string, src CalendarEventSource) (CalendarSyncReport, error) {
+	s.syncs.mu.Lock()
+	if run, ok := s.syncs.running[userID]; ok {
+		s.syncs.mu.Unlock()
+		select {
+		case <-run.done:
+			return CalendarSyncReport{ReconcileReport: run.report, Shared: true}, run.err
+		case <-ctx.Done():
+			return CalendarSyncReport{}, ctx.Err()
+		}
+	}
+	if s.syncs.running == nil {
+		s.syncs.running = map[string]*calendarSync{}
+	}
+	// err stays set if the sync panics, so waiters do not see a clean result
+	run := &calendarSync{done: make(chan struct{}), err: errors.New("calendar sync aborted")}
+	s.syncs.running[userID] = run
+	s.syncs.mu.Unlock()
+	defer func() {
+		s.syncs.mu.Lock()
+		delete(s.syncs.running, userID)
+		s.syncs.mu.Unlock()
+		close(run.done)
+	}()
+
+	run.report, run.err = s.ReconcileWithCalendar(context.WithoutCancel(ctx), userID, src, true)
+	return CalendarSyncReport{ReconcileReport: run.report}, run.err
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+// blockingEventSource counts lookups and holds each one until release is
+// closed, announcing on started when the first begins.
+type blockingEventSource struct {
+	fakeEventSource
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingEventSource) GetEvent(ctx context.Context, eventID string) (*CalendarEvent, error) {
+	if b.calls.Add(1) == 1 {
+		close(b.started)
+	}
+	<-b.release
+	return b.fakeEventSource.GetEvent(ctx, eventID)
+}
+
+// waitingCtx reports on waiting the first time a caller selects on Done, which
+// SyncCalendar does only once it has joined a running sync.
+type waitingCtx struct {
+	context.Context
+	once    sync.Once
+	waiting chan struct{}
+}
+
+func (c *waitingCtx) Done() <-chan struct{} {
+	c.once.Do(func() { close(c.waiting) })
+	return c.Context.Done()
+}
+
+func TestSyncCalendarRunsOnceUnderConcurrentTriggers(t *testing.T) {
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	start := now.Add(2 * time.Hour)
+	cases := []struct {
+		name     string
+		triggers int
+	}{
+		{"two triggers", 2},
+		{"a burst of clicks", 10},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newFakeBookingRepo(models.Booking{ID: "b1", UserID: "u1", StartAtUTC: start, EndAtUTC: start.Add(30 * time.Minute), GoogleEventID: "ev1"})
+			s := &BookingService{Repo: repo, Clock: FixedClock(now)}
+			src := &blockingEventSource{
+				fakeEventSource: fakeEventSource{"ev1": {StartUTC: start, EndUTC: start.Add(30 * time.Minute)}},
+				started:         make(chan struct{}),
+				release:         make(chan struct{}),
+			}
+
+			reports := make([]CalendarSyncReport, tc.triggers)
+			errs := make([]error, tc.triggers)
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				reports[0], errs[0] = s.SyncCalendar(context.Background(), "u1", src)
+			}()
+			<-src.started
+			for i := 1; i < tc.triggers; i++ {
+				ctx := &waitingCtx{Context: context.Background(), waiting: make(chan struct{})}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					reports[i], errs[i] = s.SyncCalendar(ctx, "u1", src)
+				}()
+				<-ctx.waiting
+			}
+			close(src.release)
+			wg.Wait()
+
+			if got := src.calls.Load(); got != 1 {
+				t.Errorf("calendar looked up %d times, want 1", got)
+			}
+			for i, r := range reports {
+				if errs[i] != nil {
+					t.Fatalf("trigger %d: %v", i, errs[i])
+				}
+				if r.Checked != 1 {
+					t.Errorf("trigger %d checked %d bookings, want 1", i, r.Checked)
+				}
+				if want := i > 0; r.Shared != want {
+					t.Errorf("trigger %d shared = %v, want %v", i, r.Shared, want)
+				}
+			}
+
+			// Once the sync is over the next trigger starts a fresh one
+			report, err := s.SyncCalendar(context.Background(), "u1", src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Shared || src.calls.Load() != 2 {
+				t.Errorf("follow-up sync shared = %v after %d lookups, want a new sync", report.Shared, src.calls.Load())
+			}
+		})
+	}
+}
+
+func TestSyncCalendarWaiterGivesUpWithItsContext(t *testing.T) {
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	start := now.Add(2 * time.Hour)
+	repo := newFakeBookingRepo(models.Booking{ID: "b1", UserID: "u1", StartAtUTC: start, EndAtUTC: start.Add(30 * time.Minute), GoogleEventID: "ev1"})
+	s := &BookingService{Repo: repo, Clock: FixedClock(now)}
+	src := &blockingEventSource{
+		fakeEventSource: fakeEventSource{"ev1": {StartUTC: start, EndUTC: start.Add(30 * time.Minute)}},
+		started:         make(chan struct{}),
+		release:         make(chan struct{}),
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.SyncCalendar(context.Background(), "u1", src)
+	}()
+	<-src.started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.SyncCalendar(ctx, "u1", src); err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	close(src.release)
+	<-done
+	if got := src.calls.Load(); got != 1 {
+		t.Errorf("calendar looked up %d times, want 1", got)
+	}
+}
+
+// panickingEventSource panics on its first lookup only.
+type panickingEventSource struct {
+	fakeEventSource
+	calls atomic.Int32
+}
+
+func (p *panickingEventSource) GetEvent(ctx context.Context, eventID string) (*CalendarEvent, error) {
+	if p.calls.Add(1) == 1 {
+		panic("calendar client bug")
+	}
+	return p.fakeEventSource.GetEvent(ctx, eventID)
+}
+
+func TestSyncCalendarRecoversFromPanic(t *testing.T) {
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	start := now.Add(2 * time.Hour)
+	repo := newFakeBookingRepo(models.Booking{ID: "b1", UserID: "u1", StartAtUTC: start, EndAtUTC: start.Add(30 * time.Minute), GoogleEventID: "ev1"})
+	s := &BookingService{Repo: repo, Clock: FixedClock(now)}
+	src := &panickingEventSource{fakeEventSource: fakeEventSource{"ev1": {StartUTC: start, EndUTC: start.Add(30 * time.Minute)}}}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("first sync did not panic")
+			}
+		}()
+		s.SyncCalendar(context.Background(), "u1", src)
+	}()
+
+	// the panicked sync no longer counts as running, so this one starts anew
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	report, err := s.SyncCalendar(ctx, "u1", src)
+	if err != nil {
+		t.Fatalf("sync after a panic: %v", err)
+	}
+	if report.Shared || src.calls.Load() != 2 {
+		t.Errorf("shared = %v after %d lookups, want a fresh sync", report.Shared, src.calls.Load())
+	}
+}
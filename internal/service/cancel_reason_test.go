@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestCancelBookingRecordsReasonAndCanceller(t *testing.T) {
+	cases := []struct {
+		name       string
+		scope      string
+		reason     string
+		wantReason string
+		wantErr    string
+	}{
+		{"reason trimmed", CancelScopeSingle, "  candidate no-show  ", "candidate no-show", ""},
+		{"no reason", CancelScopeSingle, "", "", ""},
+		{"reason at the limit", CancelScopeSingle, strings.Repeat("r", maxCancellationReasonLen), strings.Repeat("r", maxCancellationReasonLen), ""},
+		{"reason too long", CancelScopeSingle, strings.Repeat("r", maxCancellationReasonLen+1), "", "reason must be at most 500 characters"},
+		{"series", CancelScopeAll, "role filled", "role filled", ""},
+		{"series reason too long", CancelScopeAll, strings.Repeat("r", maxCancellationReasonLen+1), "", "reason must be at most 500 characters"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newFakeBookingRepo(
+				models.Booking{ID: "b1", UserID: "u1", StartAtUTC: at(9, 0), EndAtUTC: at(9, 30), RecurrenceGroupID: "g1"},
+				models.Booking{ID: "b2", UserID: "u1", StartAtUTC: at(9, 0).AddDate(0, 0, 7), EndAtUTC: at(9, 30).AddDate(0, 0, 7), RecurrenceGroupID: "g1"},
+			)
+			s := &BookingService{DB: fakeDB{}, Repo: repo, Clock: FixedClock(monday)}
+			ctx := WithActor(context.Background(), "ops@example.com")
+
+			_, err := s.CancelBookingScope(ctx, "b1", tc.scope, tc.reason)
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+			if tc.wantErr != "" {
+				if got := repo.get("b1"); got.Status != "confirmed" || got.CancellationReason != "" {
+					t.Errorf("rejected cancel left the booking %s with reason %q", got.Status, got.CancellationReason)
+				}
+				return
+			}
+
+			ids := []string{"b1"}
+			if tc.scope == CancelScopeAll {
+				ids = append(ids, "b2")
+			}
+			for _, id := range ids {
+				// read back through the service, as booking reads do
+				b, err := s.GetBooking(ctx, id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if b.Status != "cancelled" || b.CancellationReason != tc.wantReason || b.CancelledBy != "ops@example.com" {
+					t.Errorf("%s: %s with reason %q by %q, want cancelled with %q by ops@example.com", id, b.Status, b.CancellationReason, b.CancelledBy, tc.wantReason)
+				}
+			}
+		})
+	}
+}
+
+func TestCancelBookingWithoutActorLeavesCancellerEmpty(t *testing.T) {
+	repo := newFakeBookingRepo(models.Booking{ID: "b1", UserID: "u1", StartAtUTC: at(9, 0), EndAtUTC: at(9, 0).Add(30 * time.Minute)})
+	s := &BookingService{Repo: repo}
+	if err := s.CancelBooking(context.Background(), "b1", CancelReasonEventDeleted); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.get("b1"); got.CancellationReason != CancelReasonEventDeleted || got.CancelledBy != "" {
+		t.Errorf("reason %q by %q, want %q by nobody", got.CancellationReason, got.CancelledBy, CancelReasonEventDeleted)
+	}
+}
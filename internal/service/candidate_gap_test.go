@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestMinCandidateGapAcrossUsers(t *testing.T) {
+	// every case books 10:00–10:30 with u2, 15 minutes of gap required
+	cases := []struct {
+		name     string
+		gap      time.Duration
+		existing models.Booking
+		wantErr  string
+	}{
+		{"ends exactly the gap before", 15 * time.Minute, models.Booking{UserID: "u1", StartAtUTC: at(9, 15), EndAtUTC: at(9, 45)}, ""},
+		{"ends inside the gap before", 15 * time.Minute, models.Booking{UserID: "u1", StartAtUTC: at(9, 20), EndAtUTC: at(9, 50)}, "candidate has another booking too close"},
+		{"starts exactly the gap after", 15 * time.Minute, models.Booking{UserID: "u1", StartAtUTC: at(10, 45), EndAtUTC: at(11, 15)}, ""},
+		{"starts inside the gap after", 15 * time.Minute, models.Booking{UserID: "u1", StartAtUTC: at(10, 40), EndAtUTC: at(11, 10)}, "candidate has another booking too close"},
+		{"well beyond the gap", 15 * time.Minute, models.Booking{UserID: "u1", StartAtUTC: at(8, 0), EndAtUTC: at(8, 30)}, ""},
+		{"same interviewer inside the gap", 15 * time.Minute, models.Booking{UserID: "u2", StartAtUTC: at(9, 30), EndAtUTC: at(9, 50)}, "candidate has another booking too close"},
+		{"cancelled booking inside the gap", 15 * time.Minute, models.Booking{UserID: "u1", Status: "cancelled", StartAtUTC: at(9, 20), EndAtUTC: at(9, 50)}, ""},
+		{"another candidate inside the gap", 15 * time.Minute, models.Booking{UserID: "u1", CandidateEmail: "d@example.com", StartAtUTC: at(9, 20), EndAtUTC: at(9, 50)}, ""},
+		{"gap disabled", 0, models.Booking{UserID: "u1", StartAtUTC: at(9, 20), EndAtUTC: at(9, 50)}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u2", time.Monday, "09:00", "12:00", 30)
+			s.MinCandidateGap = tc.gap
+			existing := tc.existing
+			existing.ID = "existing"
+			if existing.CandidateEmail == "" {
+				existing.CandidateEmail = "c@example.com"
+			}
+			if existing.Status == "" {
+				existing.Status = "confirmed"
+			}
+			s.Repo.(*fakeBookingRepo).bookings["existing"] = &existing
+
+			_, err := s.CreateBooking(context.Background(), "u2", CreateBookingParams{CandidateEmail: "c@example.com", Start: at(10, 0), End: at(10, 30)})
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
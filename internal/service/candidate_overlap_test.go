@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestCandidateDoubleBookingAcrossUsers(t *testing.T) {
+	cases := []struct {
+		name     string
+		block    bool
+		existing models.Booking
+		start    time.Time
+		email    string
+		wantErr  string
+	}{
+		{"overlap with another interviewer", true, models.Booking{UserID: "u1", CandidateEmail: "c@example.com", StartAtUTC: at(9, 15), EndAtUTC: at(9, 45)}, at(9, 0), "c@example.com", "candidate already booked"},
+		{"email differs only in case", true, models.Booking{UserID: "u1", CandidateEmail: "C@Example.com", StartAtUTC: at(9, 0), EndAtUTC: at(9, 30)}, at(9, 0), "c@example.com", "candidate already booked"},
+		{"check disabled", false, models.Booking{UserID: "u1", CandidateEmail: "c@example.com", StartAtUTC: at(9, 0), EndAtUTC: at(9, 30)}, at(9, 0), "c@example.com", ""},
+		{"back to back", true, models.Booking{UserID: "u1", CandidateEmail: "c@example.com", StartAtUTC: at(8, 30), EndAtUTC: at(9, 0)}, at(9, 0), "c@example.com", ""},
+		{"another candidate", true, models.Booking{UserID: "u1", CandidateEmail: "d@example.com", StartAtUTC: at(9, 0), EndAtUTC: at(9, 30)}, at(9, 0), "c@example.com", ""},
+		{"cancelled booking", true, models.Booking{UserID: "u1", CandidateEmail: "c@example.com", Status: "cancelled", StartAtUTC: at(9, 0), EndAtUTC: at(9, 30)}, at(9, 0), "c@example.com", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u2", time.Monday, "09:00", "10:00", 30)
+			s.BlockCandidateOverlap = tc.block
+			existing := tc.existing
+			existing.ID = "existing"
+			if existing.Status == "" {
+				existing.Status = "confirmed"
+			}
+			s.Repo.(*fakeBookingRepo).bookings["existing"] = &existing
+
+			_, err := s.CreateBooking(context.Background(), "u2", CreateBookingParams{CandidateEmail: tc.email, Start: tc.start, End: tc.start.Add(30 * time.Minute)})
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCandidateChecksCountPendingBookings(t *testing.T) {
+	cases := []struct {
+		name    string
+		block   bool
+		gap     time.Duration
+		start   time.Time
+		wantErr string
+	}{
+		{"overlap", true, 0, at(9, 0), "candidate already booked"},
+		{"within the gap", false, time.Hour, at(10, 0), "candidate has another booking too close"},
+		{"past the gap", false, time.Hour, at(10, 30), ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u1", time.Monday, "09:00", "11:00", 30)
+			addRule(t, avail, "u2", time.Monday, "09:00", "11:00", 30)
+			s.RequireApproval = true
+			s.BlockCandidateOverlap, s.MinCandidateGap = tc.block, tc.gap
+			first, err := s.CreateBooking(context.Background(), "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: at(9, 0), End: at(9, 30)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if first.Status != "pending" {
+				t.Fatalf("first booking is %s, want pending", first.Status)
+			}
+
+			_, err = s.CreateBooking(context.Background(), "u2", CreateBookingParams{CandidateEmail: "c@example.com", Start: tc.start, End: tc.start.Add(30 * time.Minute)})
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCandidateChecksOnRescheduleAndShift(t *testing.T) {
+	// the candidate is booked with u1 at 09:00 and with u2 at 10:30; the u1
+	// booking moves to 10:00, or to 10:30 onto the u2 one
+	cases := []struct {
+		name    string
+		block   bool
+		gap     time.Duration
+		to      time.Time
+		wantErr string
+	}{
+		{"onto the other booking", true, 0, at(10, 30), "candidate already booked"},
+		{"within the gap", false, time.Hour, at(10, 0), "candidate has another booking too close"},
+		{"checks disabled", false, 0, at(10, 30), ""},
+		{"own booking ignored", true, 30 * time.Minute, at(9, 30), ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			newService := func() (*BookingService, *fakeBookingRepo) {
+				avail, s := newFakeServices(monday)
+				addRule(t, avail, "u1", time.Monday, "09:00", "12:00", 30)
+				repo := newFakeBookingRepo(
+					models.Booking{ID: "moving", UserID: "u1", CandidateEmail: "c@example.com", StartAtUTC: at(9, 0), EndAtUTC: at(9, 30)},
+					models.Booking{ID: "other", UserID: "u2", CandidateEmail: "c@example.com", StartAtUTC: at(10, 30), EndAtUTC: at(11, 0)},
+				)
+				s.Repo, avail.Book = repo, repo
+				s.BlockCandidateOverlap, s.MinCandidateGap = tc.block, tc.gap
+				return s, repo
+			}
+
+			s, _ := newService()
+			_, err := s.RescheduleBooking(context.Background(), "u1", "moving", tc.to, tc.to.Add(30*time.Minute))
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("reschedule: err = %v, want %q", err, tc.wantErr)
+			}
+
+			s, repo := newService()
+			res, err := s.ShiftBookings(context.Background(), "u1", at(9, 0), tc.to.Sub(at(9, 0)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantStart := tc.to
+			if tc.wantErr != "" {
+				wantStart = at(9, 0)
+				if len(res.Skipped) != 1 || res.Skipped[0].Reason != ShiftSkipCandidate {
+					t.Errorf("shift skipped %+v, want the booking for %q", res.Skipped, ShiftSkipCandidate)
+				}
+			}
+			if got := repo.get("moving").StartAtUTC; !got.Equal(wantStart) {
+				t.Errorf("shifted booking starts at %s, want %s", got.Format("15:04"), wantStart.Format("15:04"))
+			}
+		})
+	}
+}
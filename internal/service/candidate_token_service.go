@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+// CandidateTokenService issues and resolves the tokens candidates use to view
+// their own bookings. A token is payload.signature, where the payload carries
+// the token row id and normalized email and the signature is an HMAC-SHA256
+// over the encoded payload. The row makes tokens revocable.
+type CandidateTokenService struct {
+	DB       repository.Querier
+	Repo     repository.CandidateTokenRepository
+	Bookings repository.BookingRepository
+	Secret   []byte
+
+	// Booker, when set, lets token holders book for themselves (Book).
+	Booker *BookingService
+
+	// EnforceEmail rejects a self-service booking whose candidate email is
+	// not the token's, so a token cannot book under someone else's name.
+	EnforceEmail bool
+}
+
+func NewCandidateTokenService(db repository.Querier, repo repository.CandidateTokenRepository, bookings repository.BookingRepository, secret []byte) *CandidateTokenService {
+	return &CandidateTokenService{DB: db, Repo: repo, Bookings: bookings, Secret: secret}
+}
+
+type candidateTokenPayload struct {
+	ID    string `json:"i"`
+	Email string `json:"e"`
+}
+
+// CandidateBooking is the read-only view of a booking shown to its candidate.
+type CandidateBooking struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	Title      string    `json:"title,omitempty"`
+	Status     string    `json:"status"`
+	StartAtUTC time.Time `json:"start_at_utc"`
+	EndAtUTC   time.Time `json:"end_at_utc"`
+}
+
+func normalizeCandidateEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Issue returns the candidate's live token, creating it when needed. created
+// reports whether it is new, i.e. whether this is the candidate's first.
+func (s *CandidateTokenService) Issue(ctx context.Context, email string) (token string, rec *models.CandidateToken, created bool, err error) {
+	email = normalizeCandidateEmail(email)
+	if email == "" || !strings.Contains(email, "@") {
+		return "", nil, false, errors.New("valid email required")
+	}
+	rec, created, err = s.Repo.EnsureCandidateToken(ctx, s.DB, email)
+	if err != nil {
+		return "", nil, false, err
+	}
+	token, err = s.sign(candidateTokenPayload{ID: rec.ID, Email: rec.Email})
+	return token, rec, created, err
+}
+
+// CandidateTokenHook issues a candidate's self-service token on their first
+// booking and hands it to Send, the channel that reaches the candidate. The
+// token never goes back to the interviewer API's caller, who may not be the
+// candidate; operators can otherwise issue one via POST /admin/candidate-tokens.
+type CandidateTokenHook struct {
+	NopBookingHook
+	Tokens *CandidateTokenService
+	Send   func(ctx context.Context, b models.Booking, token string) error
+}
+
+// OnCreated issues the token and sends it when it is the candidate's first.
+func (h *CandidateTokenHook) OnCreated(ctx context.Context, b models.Booking) error {
+	token, _, created, err := h.Tokens.Issue(ctx, b.CandidateEmail)
+	if err != nil || !created {
+		return err
+	}
+	return h.Send(ctx, b, token)
+}
+
+// Revoke invalidates a token by its id.
+func (s *CandidateTokenService) Revoke(ctx context.Context, id string) error {
+	n, err := s.Repo.RevokeCandidateToken(ctx, s.DB, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("token not found")
+	}
+	return nil
+}
+
+// ListBookings resolves token and returns the bookings of its candidate across
+// all users.
+func (s *CandidateTokenService) ListBookings(ctx context.Context, token string) (string, []CandidateBooking, error) {
+	rec, err := s.resolve(ctx, token)
+	if err != nil {
+		return "", nil, err
+	}
+	bookings, err := s.Bookings.ListBookingsByCandidate(ctx, s.DB, rec.Email)
+	if err != nil {
+		return "", nil, err
+	}
+	out := make([]CandidateBooking, 0, len(bookings))
+	for _, b := range bookings {
+		out = append(out, CandidateBooking{ID: b.ID, UserID: b.UserID, Title: b.Title, Status: b.Status, StartAtUTC: b.StartAtUTC.UTC(), EndAtUTC: b.EndAtUTC.UTC()})
+	}
+	return rec.Email, out, nil
+}
+
+// Book creates a booking with userID for the holder of token, acting as the
+// candidate. An empty candidate email defaults to the token's; with
+// EnforceEmail any other email is rejected.
+func (s *CandidateTokenService) Book(ctx context.Context, token, userID string, req CreateBookingParams) (models.Booking, error) {
+	if s.Booker == nil {
+		return models.Booking{}, errors.New("candidate booking not enabled")
+	}
+	rec, err := s.resolve(ctx, token)
+	if err != nil {
+		return models.Booking{}, err
+	}
+	if strings.TrimSpace(req.CandidateEmail) == "" {
+		req.CandidateEmail = rec.Email
+	} else if s.EnforceEmail && normalizeCandidateEmail(req.CandidateEmail) != rec.Email {
+		return models.Booking{}, errors.New("candidate email does not match token")
+	}
+	return s.Booker.CreateBooking(WithActor(ctx, rec.Email), userID, req)
+}
+
+// resolve verifies token and returns its live row.
+// TokenID returns the id of the token row token was issued for, checking its
+// signature but not whether it has been revoked.
+func (s *CandidateTokenService) TokenID(token string) (string, error) {
+	p, err := s.verify(token)
+	if err != nil {
+		return "", err
+	}
+	return p.ID, nil
+}
+
+func (s *CandidateTokenService) resolve(ctx context.Context, token string) (*models.CandidateToken, error) {
+	p, err := s.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := s.Repo.GetCandidateToken(ctx, s.DB, p.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("invalid token")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rec.RevokedAt != nil || rec.Email != p.Email {
+		return nil, errors.New("invalid token")
+	}
+	return rec, nil
+}
+
+func (s *CandidateTokenService) sign(p candidateTokenPayload) (string, error) {
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	return payload + "." + s.mac(payload), nil
+}
+
+func (s *CandidateTokenService) verify(token string) (candidateTokenPayload, error) {
+	var p candidateTokenPayload
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.mac(payload))) {
+		return p, errors.New("invalid token")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return p, errors.New("invalid token")
+	}
+	if err := json.Unmarshal(raw, &p); err != nil || p.ID == "" {
+		return p, errors.New("invalid token")
+	}
+	return p, nil
+}
+
+func (s *CandidateTokenService) mac(payload string) string {
+	m := hmac.New(sha256.New, s.Secret)
+	m.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
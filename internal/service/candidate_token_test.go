@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestCandidateTokenBookEnforcesEmail(t *testing.T) {
+	start := monday.Add(9 * time.Hour)
+	cases := []struct {
+		name    string
+		email   string
+		enforce bool
+		want    string // booked candidate email, or the error
+		wantErr bool
+	}{
+		{"matching email", "cand@example.com", true, "cand@example.com", false},
+		{"matching email, other case", " Cand@Example.com ", true, " Cand@Example.com ", false},
+		{"email left out", "", true, "cand@example.com", false},
+		{"mismatched email", "other@example.com", true, "candidate email does not match token", true},
+		{"mismatched email, not enforced", "other@example.com", false, "other@example.com", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, bookings := newFakeServices(monday)
+			addRule(t, avail, "u1", time.Monday, "09:00", "10:00", 30)
+			s := NewCandidateTokenService(fakeDB{}, &fakeCandidateTokenRepo{}, bookings.Repo, []byte("secret"))
+			s.Booker, s.EnforceEmail = bookings, tc.enforce
+			token, _, _, err := s.Issue(context.Background(), "cand@example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := s.Book(context.Background(), token, "u1", CreateBookingParams{CandidateEmail: tc.email, Start: start, End: start.Add(30 * time.Minute)})
+			if tc.wantErr {
+				if err == nil || err.Error() != tc.want {
+					t.Fatalf("err = %v, want %q", err, tc.want)
+				}
+				if n := len(bookings.Repo.(*fakeBookingRepo).bookings); n != 0 {
+					t.Errorf("%d bookings stored after a rejected request", n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.CandidateEmail != tc.want {
+				t.Errorf("candidate_email = %q, want %q", b.CandidateEmail, tc.want)
+			}
+			if tc.enforce && b.BookedBy != models.BookedByCandidate {
+				// the token holder booked under their own email
+				t.Errorf("booked_by = %q, want candidate", b.BookedBy)
+			}
+		})
+	}
+}
+
+func TestCandidateTokenBookRejectsBadTokens(t *testing.T) {
+	_, bookings := newFakeServices(monday)
+	repo := &fakeCandidateTokenRepo{}
+	s := NewCandidateTokenService(fakeDB{}, repo, bookings.Repo, []byte("secret"))
+	s.Booker, s.EnforceEmail = bookings, true
+	revoked, rec, _, err := s.Issue(context.Background(), "cand@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Revoke(context.Background(), rec.ID); err != nil {
+		t.Fatal(err)
+	}
+	forged := NewCandidateTokenService(fakeDB{}, repo, bookings.Repo, []byte("other"))
+	forgedToken, _, _, _ := forged.Issue(context.Background(), "cand@example.com")
+	for name, token := range map[string]string{"revoked": revoked, "forged": forgedToken, "garbage": "x.y"} {
+		if _, err := s.Book(context.Background(), token, "u1", CreateBookingParams{}); err == nil || err.Error() != "invalid token" {
+			t.Errorf("%s token: err = %v, want invalid token", name, err)
+		}
+	}
+}
+
+func TestCandidateTokenListBookingsResolvesToken(t *testing.T) {
+	repo := &fakeCandidateTokenRepo{}
+	s := NewCandidateTokenService(fakeDB{}, repo, newFakeBookingRepo(), []byte("secret"))
+	live, _, _, err := s.Issue(context.Background(), " Cand@Example.com ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, rec, _, err := s.Issue(context.Background(), "gone@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Revoke(context.Background(), rec.ID); err != nil {
+		t.Fatal(err)
+	}
+	forged, _, _, _ := NewCandidateTokenService(fakeDB{}, repo, nil, []byte("other")).Issue(context.Background(), "cand@example.com")
+	// a correctly signed token naming a row that belongs to someone else
+	swapped, _ := s.sign(candidateTokenPayload{ID: "token-1", Email: "gone@example.com"})
+	unknown, _ := s.sign(candidateTokenPayload{ID: "token-99", Email: "cand@example.com"})
+
+	cases := []struct {
+		name      string
+		token     string
+		wantEmail string
+		wantErr   string
+	}{
+		{"live token", live, "cand@example.com", ""},
+		{"revoked", revoked, "", "invalid token"},
+		{"wrong secret", forged, "", "invalid token"},
+		{"email does not match row", swapped, "", "invalid token"},
+		{"unknown row", unknown, "", "invalid token"},
+		{"no signature", "abc", "", "invalid token"},
+		{"garbage", "x.y", "", "invalid token"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			email, _, err := s.ListBookings(context.Background(), tc.token)
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+			if email != tc.wantEmail {
+				t.Errorf("email = %q, want %q", email, tc.wantEmail)
+			}
+		})
+	}
+}
+
+func TestCandidateTokenListBookingsOnlyOwn(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC) }
+	bookings := newFakeBookingRepo(
+		models.Booking{ID: "b1", UserID: "u1", CandidateEmail: "cand@example.com", StartAtUTC: at(3, 9), EndAtUTC: at(3, 10)},
+		models.Booking{ID: "b2", UserID: "u2", CandidateEmail: "CAND@example.com", StartAtUTC: at(2, 9), EndAtUTC: at(2, 10)},
+		models.Booking{ID: "b3", UserID: "u1", CandidateEmail: "other@example.com", StartAtUTC: at(2, 11), EndAtUTC: at(2, 12)},
+		models.Booking{ID: "b4", UserID: "u3", CandidateEmail: "cand@example.com", Status: "cancelled", StartAtUTC: at(4, 9), EndAtUTC: at(4, 10)},
+	)
+	s := NewCandidateTokenService(fakeDB{}, &fakeCandidateTokenRepo{}, bookings, []byte("secret"))
+
+	cases := []struct {
+		email string
+		want  []string
+	}{
+		{"cand@example.com", []string{"b2", "b1", "b4"}},
+		{"other@example.com", []string{"b3"}},
+		{"nobody@example.com", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.email, func(t *testing.T) {
+			token, _, _, err := s.Issue(context.Background(), tc.email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, got, err := s.ListBookings(context.Background(), token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d bookings, want %d", len(got), len(tc.want))
+			}
+			for i, b := range got {
+				if b.ID != tc.want[i] {
+					t.Errorf("booking %d = %s, want %s", i, b.ID, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCandidateTokenHookSendsFirstTokenOnly(t *testing.T) {
+	avail, bookings := newFakeServices(monday)
+	addRule(t, avail, "u1", time.Monday, "09:00", "11:00", 30)
+	tokens := NewCandidateTokenService(fakeDB{}, &fakeCandidateTokenRepo{}, bookings.Repo, []byte("secret"))
+	var sent []string
+	bookings.Hooks = &BookingHooks{}
+	bookings.Hooks.Register(&CandidateTokenHook{Tokens: tokens, Send: func(ctx context.Context, b models.Booking, token string) error {
+		sent = append(sent, b.CandidateEmail+" "+token)
+		return nil
+	}})
+
+	for _, h := range []int{9, 10} {
+		start := monday.Add(time.Duration(h) * time.Hour)
+		if _, err := bookings.CreateBooking(context.Background(), "u1", CreateBookingParams{CandidateEmail: "cand@example.com", Start: start, End: start.Add(30 * time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(sent) != 1 {
+		t.Fatalf("sent %d tokens, want 1 for the first booking", len(sent))
+	}
+	token, _, _, err := tokens.Issue(context.Background(), "cand@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent[0] != "cand@example.com "+token {
+		t.Errorf("sent %q, want the candidate's live token", sent[0])
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import "time"
+
+// Clock reports the current time to the services, so time-dependent logic
+// can be run against a fixed instant.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// FixedClock always reports the same instant.
+type FixedClock time.Time
+
+func (c FixedClock) Now() time.Time { return time.Time(c) }
+
+// nowUTC reads c in UTC, falling back to the wall clock when c is nil.
+func nowUTC(c Clock) time.Time {
+	if c == nil {
+		return time.Now().UTC()
+	}
+	return c.Now().UTC()
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestRollingHorizonFollowsClock(t *testing.T) {
+	cases := []struct {
+		name string
+		now  time.Time
+		want []string // slot starts over three weeks
+	}{
+		{"before the first slot", monday.Add(8 * time.Hour), []string{"2026-03-02 09:00", "2026-03-02 09:30"}},
+		{"horizon ends exactly at a slot start", monday.Add(9 * time.Hour), []string{"2026-03-02 09:00", "2026-03-02 09:30"}},
+		{"horizon reaches next week", monday.Add(10 * time.Hour), []string{"2026-03-09 09:00", "2026-03-09 09:30"}},
+		{"a week later", monday.AddDate(0, 0, 7).Add(8 * time.Hour), []string{"2026-03-09 09:00", "2026-03-09 09:30"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newFakeServices(tc.now)
+			s.Settings = fakeSettingsRepo{"u1": {UserID: "u1", RollingDays: 7}}
+			addRule(t, s, "u1", time.Monday, "09:00", "10:00", 30)
+
+			slots, err := s.GenerateAvailableSlots(context.Background(), "u1", monday, monday.AddDate(0, 0, 21))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, sl := range slots {
+				got = append(got, sl.StartUTC.Format("2006-01-02 15:04"))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("slots = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestServicesStampTimesFromClock(t *testing.T) {
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	avail, s := newFakeServices(now)
+
+	rules, err := avail.SetAvailability(ctx, "u1", []models.AvailabilityRule{{DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "10:00", SlotLengthMins: 30, Available: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 || !rules[0].CreatedAt.Equal(now) || !rules[0].UpdatedAt.Equal(now) {
+		t.Errorf("rule timestamps = %+v, want %s", rules, now)
+	}
+
+	start := now.Add(time.Hour)
+	b, err := s.CreateBooking(ctx, "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.CreatedAt.Equal(now) {
+		t.Errorf("booking created at %s, want %s", b.CreatedAt, now)
+	}
+
+	doc, err := avail.ExportAvailability(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.ExportedAt.Equal(now) {
+		t.Errorf("exported at %s, want %s", doc.ExportedAt, now)
+	}
+}
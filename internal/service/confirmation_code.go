@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/models"
+)
+
+// confirmationCodeAlphabet is Crockford's base32: digits and upper-case
+// letters without I, L, O and U, so codes read back unambiguously.
+const confirmationCodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// DefaultConfirmationCodeLength is used when BookingService.ConfirmationCodeLength
+// is not set. 8 characters give 2^40 codes.
+const DefaultConfirmationCodeLength = 8
+
+// maxConfirmationCodeAttempts bounds how many codes a booking tries before
+// giving up on collisions.
+const maxConfirmationCodeAttempts = 5
+
+// newConfirmationCode returns a random code of n base32 characters.
+func newConfirmationCode(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = confirmationCodeAlphabet[b%32]
+	}
+	return string(buf), nil
+}
+
+// normalizeConfirmationCode upper-cases a code as typed by a person, dropping
+// spaces and dashes and reading I, L and O as the digits they stand for.
+func normalizeConfirmationCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "", "I", "1", "L", "1", "O", "0").Replace(code)
+}
+
+func (s *BookingService) confirmationCodeLength() int {
+	if s.ConfirmationCodeLength > 0 {
+		return s.ConfirmationCodeLength
+	}
+	return DefaultConfirmationCodeLength
+}
+
+// GetBookingByConfirmationCode looks a booking up by its confirmation code.
+func (s *BookingService) GetBookingByConfirmationCode(ctx context.Context, code string) (*models.Booking, error) {
+	code = normalizeConfirmationCode(code)
+	if code == "" {
+		return nil, errors.New("booking not found")
+	}
+	b, err := s.Repo.GetBookingByConfirmationCode(ctx, s.DB, code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New("booking not found")
+	}
+	return b, err
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/repository"
+)
+
+func TestNewConfirmationCode(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 10000; i++ {
+		code, err := newConfirmationCode(DefaultConfirmationCodeLength)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(code) != DefaultConfirmationCodeLength {
+			t.Fatalf("len(%q) = %d", code, len(code))
+		}
+		if strings.Trim(code, confirmationCodeAlphabet) != "" {
+			t.Fatalf("%q has characters outside the alphabet", code)
+		}
+		if seen[code] {
+			t.Fatalf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestNormalizeConfirmationCode(t *testing.T) {
+	cases := map[string]string{
+		"ab3d-7fgh":   "AB3D7FGH",
+		" AB3D 7FGH ": "AB3D7FGH",
+		"oil0":        "0110",
+		"":            "",
+	}
+	for in, want := range cases {
+		if got := normalizeConfirmationCode(in); got != want {
+			t.Errorf("normalizeConfirmationCode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func newCodeTestServices(t *testing.T) (*BookingService, CreateBookingParams) {
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC) // a Sunday
+	avail, bookings := newFakeServices(now)
+	addRule(t, avail, "u1", time.Monday, "09:00", "12:00", 30)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	return bookings, CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute)}
+}
+
+func TestCreateBookingAssignsUniqueConfirmationCodes(t *testing.T) {
+	s, req := newCodeTestServices(t)
+	codes := map[string]bool{}
+	for i := 0; i < 6; i++ {
+		b, err := s.CreateBooking(context.Background(), "u1", req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b.ConfirmationCode) != DefaultConfirmationCodeLength || codes[b.ConfirmationCode] {
+			t.Fatalf("booking %d got code %q", i, b.ConfirmationCode)
+		}
+		codes[b.ConfirmationCode] = true
+		req.Start, req.End = req.End, req.End.Add(30*time.Minute)
+	}
+}
+
+func TestCreateBookingRetriesTakenConfirmationCode(t *testing.T) {
+	s, req := newCodeTestServices(t)
+	repo := s.Repo.(*fakeBookingRepo)
+
+	repo.codeCollisions = maxConfirmationCodeAttempts - 1
+	b, err := s.CreateBooking(context.Background(), "u1", req)
+	if err != nil {
+		t.Fatalf("with %d collisions: %v", maxConfirmationCodeAttempts-1, err)
+	}
+	if b.ConfirmationCode == "" {
+		t.Fatal("no confirmation code")
+	}
+
+	repo.codeCollisions = maxConfirmationCodeAttempts
+	req.Start, req.End = req.End, req.End.Add(30*time.Minute)
+	if _, err := s.CreateBooking(context.Background(), "u1", req); !errors.Is(err, repository.ErrConfirmationCodeTaken) {
+		t.Fatalf("with %d collisions: err = %v, want ErrConfirmationCodeTaken", maxConfirmationCodeAttempts, err)
+	}
+}
+
+func TestGetBookingByConfirmationCode(t *testing.T) {
+	s, req := newCodeTestServices(t)
+	s.ConfirmationCodeLength = 10
+	b, err := s.CreateBooking(context.Background(), "u1", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.ConfirmationCode) != 10 {
+		t.Fatalf("code %q, want 10 characters", b.ConfirmationCode)
+	}
+
+	typed := strings.ToLower(b.ConfirmationCode[:5] + "-" + b.ConfirmationCode[5:])
+	got, err := s.GetBookingByConfirmationCode(context.Background(), typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != b.ID {
+		t.Errorf("found %s, want %s", got.ID, b.ID)
+	}
+
+	for _, code := range []string{"", "  ", "ZZZZZZZZZZ"} {
+		if _, err := s.GetBookingByConfirmationCode(context.Background(), code); err == nil || err.Error() != "booking not found" {
+			t.Errorf("code %q: err = %v, want booking not found", code, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestAdvanceConfirmationState(t *testing.T) {
+	cases := []struct {
+		from, to string
+		ok       bool
+	}{
+		{models.ConfirmationStateConfirmed, models.ConfirmationStatePendingEvent, true},
+		{models.ConfirmationStatePendingEvent, models.ConfirmationStateEventCreated, true},
+		{models.ConfirmationStatePendingEvent, models.ConfirmationStateFailed, true},
+		{models.ConfirmationStateFailed, models.ConfirmationStatePendingEvent, true},
+		{models.ConfirmationStateConfirmed, models.ConfirmationStateEventCreated, false},
+		{models.ConfirmationStateEventCreated, models.ConfirmationStatePendingEvent, false},
+		{models.ConfirmationStateFailed, models.ConfirmationStateEventCreated, false},
+		{models.ConfirmationStateConfirmed, "bogus", false},
+	}
+	for _, tc := range cases {
+		repo := newFakeBookingRepo(models.Booking{ID: "b1", ConfirmationState: tc.from})
+		s := &BookingService{Repo: repo}
+		err := s.AdvanceConfirmationState(context.Background(), "b1", tc.to)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s -> %s: err = %v, want ok %v", tc.from, tc.to, err, tc.ok)
+		}
+		want := tc.from
+		if tc.ok {
+			want = tc.to
+		}
+		if got := repo.get("b1").ConfirmationState; got != want {
+			t.Errorf("%s -> %s: state = %s, want %s", tc.from, tc.to, got, want)
+		}
+	}
+}
+
+func TestReconcileWithCalendarAdvancesConfirmationState(t *testing.T) {
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	start := now.Add(2 * time.Hour)
+	end := start.Add(30 * time.Minute)
+	booking := func(id, event, state string) models.Booking {
+		return models.Booking{ID: id, UserID: "u1", StartAtUTC: start, EndAtUTC: end, GoogleEventID: event, ConfirmationState: state}
+	}
+	repo := newFakeBookingRepo(
+		booking("confirmed", "ev1", models.ConfirmationStateConfirmed),
+		booking("failed", "ev2", models.ConfirmationStateFailed),
+		booking("created", "ev3", models.ConfirmationStateEventCreated),
+		booking("pending-gone", "gone", models.ConfirmationStatePendingEvent),
+	)
+	src := fakeEventSource{
+		"ev1": {StartUTC: start, EndUTC: end},
+		"ev2": {StartUTC: start, EndUTC: end},
+		"ev3": {StartUTC: start, EndUTC: end},
+	}
+	s := &BookingService{Repo: repo, Clock: FixedClock(now)}
+
+	report, err := s.ReconcileWithCalendar(context.Background(), "u1", src, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.StatesAdvanced != 3 {
+		t.Errorf("StatesAdvanced = %d, want 3", report.StatesAdvanced)
+	}
+	want := map[string]string{
+		"confirmed":    models.ConfirmationStateEventCreated,
+		"failed":       models.ConfirmationStateEventCreated,
+		"created":      models.ConfirmationStateEventCreated,
+		"pending-gone": models.ConfirmationStateFailed,
+	}
+	for id, state := range want {
+		if got := repo.get(id).ConfirmationState; got != state {
+			t.Errorf("%s: state = %s, want %s", id, got, state)
+		}
+	}
+	if got := repo.get("pending-gone").Status; got != "cancelled" {
+		t.Errorf("pending-gone: status = %s, want cancelled", got)
+	}
+	if len(report.Items) != 1 || report.Items[0].Issue != ReconcileEventDeleted {
+		t.Errorf("items = %+v, want one event_deleted", report.Items)
+	}
+}
+
+func TestReconcileWithCalendarDryRunLeavesState(t *testing.T) {
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	start := now.Add(time.Hour)
+	repo := newFakeBookingRepo(models.Booking{ID: "b1", UserID: "u1", StartAtUTC: start, EndAtUTC: start.Add(time.Hour), GoogleEventID: "ev1", ConfirmationState: models.ConfirmationStateConfirmed})
+	src := fakeEventSource{"ev1": {StartUTC: start, EndUTC: start.Add(time.Hour)}}
+	s := &BookingService{Repo: repo, Clock: FixedClock(now)}
+
+	report, err := s.ReconcileWithCalendar(context.Background(), "u1", src, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.StatesAdvanced != 0 || repo.get("b1").ConfirmationState != models.ConfirmationStateConfirmed {
+		t.Errorf("dry run changed state: report %+v, state %s", report, repo.get("b1").ConfirmationState)
+	}
+}
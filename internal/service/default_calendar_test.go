@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCalendarIDPrefersRequestThenStoredDefault(t *testing.T) {
+	svc := NewUserSettingsService(nil, fakeSettingsRepo{
+		"u1": {UserID: "u1", DefaultCalendarID: "team@example.com"},
+		"u2": {UserID: "u2"},
+	})
+
+	cases := []struct {
+		name, userID, requested, want string
+	}{
+		{"stored default when the param is absent", "u1", "", "team@example.com"},
+		{"explicit param wins over the stored default", "u1", "other@example.com", "other@example.com"},
+		{"saved settings without a default", "u2", "", "primary"},
+		{"no saved settings", "u3", "", "primary"},
+		{"no user", "", "", "primary"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := svc.CalendarID(context.Background(), tc.userID, tc.requested)
+			if err != nil {
+				t.Fatalf("CalendarID: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("CalendarID(%q, %q) = %q, want %q", tc.userID, tc.requested, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateSettingsTrimsDefaultCalendar(t *testing.T) {
+	repo := fakeSettingsRepo{}
+	svc := NewUserSettingsService(nil, repo)
+	id := "  team@example.com "
+	if _, err := svc.UpdateSettings(context.Background(), "u1", UserSettingsUpdate{DefaultCalendarID: &id}); err != nil {
+		t.Fatalf("UpdateSettings: %v", err)
+	}
+	if got := repo["u1"].DefaultCalendarID; got != "team@example.com" {
+		t.Errorf("stored default_calendar_id = %q, want %q", got, "team@example.com")
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestSlotsAcrossUSDSTChanges(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	springForward := time.Date(2026, 3, 8, 0, 0, 0, 0, ny)
+	fallBack := time.Date(2026, 11, 1, 0, 0, 0, 0, ny)
+	cases := []struct {
+		name         string
+		day          time.Time
+		start, end   string
+		repeatedHour string
+		want         []string
+		note         bool
+	}{
+		{"window over the gap", springForward, "01:00", "04:00", "", []string{"01:00 EST", "01:30 EST", "03:00 EDT", "03:30 EDT"}, false},
+		{"window inside the gap", springForward, "02:00", "03:00", "", []string{}, true},
+		{"start in the gap", springForward, "02:30", "04:00", "", []string{"03:00 EDT", "03:30 EDT"}, true},
+		{"end in the gap", springForward, "01:00", "02:30", "", []string{"01:00 EST", "01:30 EST"}, true},
+		{"repeated hour, both", fallBack, "00:00", "03:00", DSTRepeatedHourBoth,
+			[]string{"00:00 EDT", "00:30 EDT", "01:00 EDT", "01:30 EDT", "01:00 EST", "01:30 EST", "02:00 EST", "02:30 EST"}, false},
+		{"repeated hour, default", fallBack, "00:00", "03:00", "",
+			[]string{"00:00 EDT", "00:30 EDT", "01:00 EDT", "01:30 EDT", "01:00 EST", "01:30 EST", "02:00 EST", "02:30 EST"}, false},
+		{"repeated hour, first", fallBack, "00:00", "03:00", DSTRepeatedHourFirst,
+			[]string{"00:00 EDT", "00:30 EDT", "01:00 EDT", "01:30 EDT", "02:00 EST", "02:30 EST"}, false},
+		{"end in the repeated hour, both", fallBack, "00:00", "01:30", DSTRepeatedHourBoth,
+			[]string{"00:00 EDT", "00:30 EDT", "01:00 EDT", "01:30 EDT", "01:00 EST"}, false},
+		{"end in the repeated hour, first", fallBack, "00:00", "01:30", DSTRepeatedHourFirst,
+			[]string{"00:00 EDT", "00:30 EDT", "01:00 EDT"}, false},
+		{"end at the repeated hour", fallBack, "00:00", "01:00", DSTRepeatedHourBoth, []string{"00:00 EDT", "00:30 EDT"}, false},
+		{"start in the repeated hour, first", fallBack, "01:30", "03:00", DSTRepeatedHourFirst, []string{"01:30 EDT", "02:00 EST", "02:30 EST"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dstGapNoted.Range(func(k, _ any) bool { dstGapNoted.Delete(k); return true })
+			var logged bytes.Buffer
+			orig := log.Writer()
+			log.SetOutput(&logged)
+			defer log.SetOutput(orig)
+
+			s, _ := newFakeServices(tc.day.AddDate(0, 0, -7))
+			s.DSTRepeatedHour = tc.repeatedHour
+			rule := models.AvailabilityRule{UserID: "u1", DayOfWeek: int(time.Sunday), StartTime: tc.start, EndTime: tc.end, SlotLengthMins: 30, Timezone: "America/New_York", Available: true}
+			if err := s.Avail.InsertAvailabilityRule(context.Background(), s.DB, &rule); err != nil {
+				t.Fatal(err)
+			}
+			slots, err := s.GenerateAvailableSlots(context.Background(), "u1", tc.day.UTC(), tc.day.AddDate(0, 0, 1).UTC())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, sl := range slots {
+				got = append(got, sl.StartUTC.In(ny).Format("15:04 MST"))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("slots = %v, want %v", got, tc.want)
+			}
+			if noted := strings.Contains(logged.String(), "does not exist"); noted != tc.note {
+				t.Errorf("gap noted = %v, want %v (log %q)", noted, tc.note, logged.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+// EffectiveWindow is a stretch of net availability after all rules and
+// overrides are merged and blocking sources are subtracted. Sources names what
+// contributed it, as "rule:<id>" or "override:<id>".
+type EffectiveWindow struct {
+	StartUTC time.Time `json:"start_utc"`
+	EndUTC   time.Time `json:"end_utc"`
+	Sources  []string  `json:"sources"`
+}
+
+// BlockedWindow is a stretch removed from availability, with the source that
+// removed it ("booking:<id>", "hold:<id>" or "override:<id>" for blackouts).
+type BlockedWindow struct {
+	StartUTC time.Time `json:"start_utc"`
+	EndUTC   time.Time `json:"end_utc"`
+	Source   string    `json:"source"`
+}
+
+// EffectiveDay groups effective and blocked windows by local calendar date.
+type EffectiveDay struct {
+	Date    string            `json:"date"`
+	Windows []EffectiveWindow `json:"windows"`
+	Blocked []BlockedWindow   `json:"blocked,omitempty"`
+}
+
+// EffectiveAvailability returns the user's merged availability windows over
+// [fromUTC, toUTC), grouped by local date in loc. It is a diagnostic view: it
+// shows windows rather than slots, and records which rules or overrides
+// produced each window and which blackouts, bookings or holds cut into it.
+func (s *AvailabilityService) EffectiveAvailability(ctx context.Context, userID string, fromUTC, toUTC time.Time, loc *time.Location) ([]EffectiveDay, error) {
+	rules, err := s.Avail.ListAvailabilityRules(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var windows []EffectiveWindow
+	var blocked []BlockedWindow
+	startDate, endDate := planningDates(fromUTC, toUTC)
+	overrides, err := s.listOverrides(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	planned, blocks, err := planRange(startDate, endDate, rules, overrides, s.DSTRepeatedHour)
+	if err != nil {
+		return nil, err
+	}
+	for _, sw := range planned {
+		w := EffectiveWindow{StartUTC: sw.start, EndUTC: sw.end, Sources: []string{sw.source}}
+		if w.StartUTC.Before(fromUTC) {
+			w.StartUTC = fromUTC
+		}
+		if w.EndUTC.After(toUTC) {
+			w.EndUTC = toUTC
+		}
+		if w.EndUTC.After(w.StartUTC) {
+			windows = append(windows, w)
+		}
+	}
+	for _, b := range blocks {
+		bw := BlockedWindow{StartUTC: b.start, EndUTC: b.end, Source: b.source}
+		if bw.StartUTC.Before(fromUTC) {
+			bw.StartUTC = fromUTC
+		}
+		if bw.EndUTC.After(toUTC) {
+			bw.EndUTC = toUTC
+		}
+		if bw.EndUTC.After(bw.StartUTC) {
+			blocked = append(blocked, bw)
+		}
+	}
+	windows = mergeWindows(windows)
+
+	bookings, err := s.Book.ListBookingsInRange(ctx, s.DB, userID, fromUTC.Add(-24*time.Hour), toUTC)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range bookings {
+		if b.EndAtUTC.After(fromUTC) && b.StartAtUTC.Before(toUTC) {
+			blocked = append(blocked, BlockedWindow{StartUTC: b.StartAtUTC, EndUTC: b.EndAtUTC, Source: "booking:" + b.ID})
+		}
+	}
+	if s.Holds != nil {
+		holds, err := s.Holds.ListActiveHolds(ctx, s.DB, userID, fromUTC, toUTC)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range holds {
+			blocked = append(blocked, BlockedWindow{StartUTC: h.StartAtUTC, EndUTC: h.EndAtUTC, Source: "hold:" + h.ID})
+		}
+	}
+	for _, b := range blocked {
+		windows = subtractWindow(windows, b.StartUTC, b.EndUTC)
+	}
+
+	days := map[string]*EffectiveDay{}
+	var order []string
+	dayFor := func(t time.Time) *EffectiveDay {
+		key := t.In(loc).Format("2006-01-02")
+		if d, ok := days[key]; ok {
+			return d
+		}
+		d := &EffectiveDay{Date: key, Windows: []EffectiveWindow{}}
+		days[key] = d
+		order = append(order, key)
+		return d
+	}
+	for _, w := range windows {
+		d := dayFor(w.StartUTC)
+		d.Windows = append(d.Windows, w)
+	}
+	for _, b := range blocked {
+		d := dayFor(b.StartUTC)
+		d.Blocked = append(d.Blocked, b)
+	}
+	sort.Strings(order)
+	out := make([]EffectiveDay, 0, len(order))
+	for _, key := range order {
+		out = append(out, *days[key])
+	}
+	return out, nil
+}
+
+// DateResolution explains one local date's availability: the weekly rules
+// and the overrides (including holiday blackouts) that shape it, and the net
+// windows and blocks that result.
+type DateResolution struct {
+	Date      string                    `json:"date"`
+	Timezone  string                    `json:"tz"`
+	FromUTC   time.Time                 `json:"from_utc"`
+	ToUTC     time.Time                 `json:"to_utc"`
+	Rules     []models.AvailabilityRule `json:"rules"`
+	Overrides []models.ScheduleOverride `json:"overrides"`
+	Windows   []EffectiveWindow         `json:"windows"`
+	Blocked   []BlockedWindow           `json:"blocked"`
+}
+
+// ResolveDate reports which rules and overrides apply on the YYYY-MM-DD date
+// in loc and the resulting availability. A rule or override applies when a
+// window or blackout it produces reaches into the date.
+func (s *AvailabilityService) ResolveDate(ctx context.Context, userID, date string, loc *time.Location) (*DateResolution, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		return nil, errors.New("date must be YYYY-MM-DD")
+	}
+	fromUTC, toUTC := day.UTC(), day.AddDate(0, 0, 1).UTC()
+	out := &DateResolution{Date: date, Timezone: loc.String(), FromUTC: fromUTC, ToUTC: toUTC,
+		Rules: []models.AvailabilityRule{}, Overrides: []models.ScheduleOverride{}, Windows: []EffectiveWindow{}, Blocked: []BlockedWindow{}}
+
+	rules, err := s.Avail.ListAvailabilityRules(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	startDate, endDate := planningDates(fromUTC, toUTC)
+	overrides, err := s.listOverrides(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	windows, blocks, err := planRange(startDate, endDate, rules, overrides, s.DSTRepeatedHour)
+	if err != nil {
+		return nil, err
+	}
+	applied := map[string]bool{}
+	for _, w := range windows {
+		if w.start.Before(toUTC) && w.end.After(fromUTC) {
+			applied[w.source] = true
+		}
+	}
+	for _, b := range blocks {
+		if b.start.Before(toUTC) && b.end.After(fromUTC) {
+			applied[b.source] = true
+		}
+	}
+	for _, r := range rules {
+		if applied["rule:"+r.ID] {
+			out.Rules = append(out.Rules, r)
+		}
+	}
+	for _, o := range overrides {
+		if applied["override:"+o.ID] {
+			out.Overrides = append(out.Overrides, o)
+		}
+	}
+	days, err := s.EffectiveAvailability(ctx, userID, fromUTC, toUTC, loc)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range days {
+		out.Windows = append(out.Windows, d.Windows...)
+		out.Blocked = append(out.Blocked, d.Blocked...)
+	}
+	return out, nil
+}
+
+// mergeWindows unions overlapping or touching windows, combining their sources.
+func mergeWindows(windows []EffectiveWindow) []EffectiveWindow {
+	if len(windows) == 0 {
+		return nil
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].StartUTC.Before(windows[j].StartUTC) })
+	merged := []EffectiveWindow{windows[0]}
+	for _, w := range windows[1:] {
+		last := &merged[len(merged)-1]
+		if !w.StartUTC.After(last.EndUTC) {
+			if w.EndUTC.After(last.EndUTC) {
+				last.EndUTC = w.EndUTC
+			}
+			last.Sources = append(last.Sources, w.Sources...)
+			continue
+		}
+		merged = append(merged, w)
+	}
+	return merged
+}
+
+// subtractWindow removes [start, end) from every window, splitting windows that
+// straddle it.
+func subtractWindow(windows []EffectiveWindow, start, end time.Time) []EffectiveWindow {
+	var out []EffectiveWindow
+	for _, w := range windows {
+		if !start.Before(w.EndUTC) || !end.After(w.StartUTC) {
+			out = append(out, w)
+			continue
+		}
+		if w.StartUTC.Before(start) {
+			out = append(out, EffectiveWindow{StartUTC: w.StartUTC, EndUTC: start, Sources: w.Sources})
+		}
+		if end.Before(w.EndUTC) {
+			out = append(out, EffectiveWindow{StartUTC: end, EndUTC: w.EndUTC, Sources: w.Sources})
+		}
+	}
+	return out
+}
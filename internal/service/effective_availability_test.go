@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+// describeDays renders effective days as "date: start-end sources" lines.
+func describeDays(days []EffectiveDay) []string {
+	out := []string{}
+	for _, d := range days {
+		for _, w := range d.Windows {
+			out = append(out, fmt.Sprintf("%s: %s-%s %v", d.Date, w.StartUTC.Format("15:04"), w.EndUTC.Format("15:04"), w.Sources))
+		}
+		for _, b := range d.Blocked {
+			out = append(out, fmt.Sprintf("%s: blocked %s-%s %s", d.Date, b.StartUTC.Format("15:04"), b.EndUTC.Format("15:04"), b.Source))
+		}
+	}
+	return out
+}
+
+func TestEffectiveAvailabilityWithException(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name      string
+		exception *models.AvailabilityException
+		want      []string
+	}{
+		{"weekly rule only", nil, []string{
+			"2026-03-02: 09:00-11:00 [rule:rule-1]",
+		}},
+		{"exception blocks part of the rule", &models.AvailabilityException{Date: "2026-03-02", StartTime: "09:30", EndTime: "10:30", Blocked: true}, []string{
+			"2026-03-02: 09:00-09:30 [rule:rule-1]",
+			"2026-03-02: 10:30-11:00 [rule:rule-1]",
+			"2026-03-02: blocked 09:30-10:30 override:exception-1",
+		}},
+		// midnight to midnight
+		{"exception blocks the day", &models.AvailabilityException{Date: "2026-03-02", Blocked: true}, []string{
+			"2026-03-02: blocked 00:00-00:00 override:exception-1",
+		}},
+		{"exception extends the rule", &models.AvailabilityException{Date: "2026-03-02", StartTime: "11:00", EndTime: "12:00", SlotLengthMins: 30}, []string{
+			"2026-03-02: 09:00-12:00 [rule:rule-1 override:exception-1]",
+		}},
+		{"exception on a ruleless day", &models.AvailabilityException{Date: "2026-03-03", StartTime: "14:00", EndTime: "15:00", SlotLengthMins: 30}, []string{
+			"2026-03-02: 09:00-11:00 [rule:rule-1]",
+			"2026-03-03: 14:00-15:00 [override:exception-1]",
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newExceptionTestService(t)
+			if tc.exception != nil {
+				if _, err := s.CreateException(ctx, "u1", tc.exception); err != nil {
+					t.Fatal(err)
+				}
+			}
+			days, err := s.EffectiveAvailability(ctx, "u1", monday, monday.AddDate(0, 0, 2), time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := describeDays(days); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("effective availability =\n%q\nwant\n%q", got, tc.want)
+			}
+		})
+	}
+}
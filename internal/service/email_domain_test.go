@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCreateBookingEmailDomainAllowlist(t *testing.T) {
+	cases := []struct {
+		name    string
+		allowed []string
+		email   string
+		wantErr string
+	}{
+		{"no allowlist", nil, "c@anywhere.io", ""},
+		{"allowed domain", []string{"partner.com"}, "c@partner.com", ""},
+		{"domain case differs", []string{" Partner.COM "}, "c@PARTNER.com", ""},
+		{"second allowed domain", []string{"partner.com", "example.org"}, "c@example.org", ""},
+		{"other domain", []string{"partner.com"}, "c@example.com", "candidate email domain not allowed"},
+		{"subdomain", []string{"partner.com"}, "c@eu.partner.com", "candidate email domain not allowed"},
+		{"lookalike suffix", []string{"partner.com"}, "c@notpartner.com", "candidate email domain not allowed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u1", time.Monday, "09:00", "10:00", 30)
+			s.AllowedEmailDomains = tc.allowed
+			start := monday.Add(9 * time.Hour)
+
+			_, err := s.CreateBooking(context.Background(), "u1", CreateBookingParams{CandidateEmail: tc.email, Start: start, End: start.Add(30 * time.Minute)})
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+			if n := len(s.Repo.(*fakeBookingRepo).bookings); tc.wantErr != "" && n != 0 {
+				t.Errorf("%d bookings stored for a rejected domain", n)
+			}
+		})
+	}
+}
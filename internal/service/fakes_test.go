@@ -0,0 +1,779 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+// fakeDB stands in for the pool: services only begin transactions on it and
+// hand the transaction to the fake repositories, which ignore it.
+type fakeDB struct {
+	repository.Querier
+}
+
+func (fakeDB) Begin(ctx context.Context) (pgx.Tx, error) { return fakeTx{}, nil }
+
+func (fakeDB) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	return fakeTx{}, nil
+}
+
+type fakeTx struct {
+	pgx.Tx
+}
+
+func (fakeTx) Commit(ctx context.Context) error   { return nil }
+func (fakeTx) Rollback(ctx context.Context) error { return nil }
+
+// fakeBookingRepo is an in-memory BookingRepository holding only what the
+// tests need; calling a method it does not implement panics.
+type fakeBookingRepo struct {
+	repository.BookingRepository
+
+	mu       sync.Mutex
+	bookings map[string]*models.Booking
+	nextID   int
+	// codeCollisions makes the next inserts fail as if their confirmation
+	// code were already taken.
+	codeCollisions int
+}
+
+func newFakeBookingRepo(bookings ...models.Booking) *fakeBookingRepo {
+	r := &fakeBookingRepo{bookings: map[string]*models.Booking{}}
+	for _, b := range bookings {
+		b := b
+		if b.Status == "" {
+			b.Status = "confirmed"
+		}
+		r.bookings[b.ID] = &b
+	}
+	return r
+}
+
+func (r *fakeBookingRepo) get(id string) models.Booking {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.bookings[id]
+}
+
+func (r *fakeBookingRepo) GetBooking(ctx context.Context, q repository.Querier, id string) (*models.Booking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.bookings[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	cp := *b
+	return &cp, nil
+}
+
+func (r *fakeBookingRepo) ListBookingsInRange(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime) ([]models.Booking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.Booking
+	for _, b := range r.bookings {
+		if b.UserID == userID && holdsSlot(b.Status) && !b.StartAtUTC.Before(from.(time.Time)) && b.StartAtUTC.Before(to.(time.Time)) {
+			out = append(out, *b)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeBookingRepo) ListBookings(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime, filtered bool, limit, offset int) ([]models.Booking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.Booking
+	for _, b := range r.bookings {
+		if b.UserID != userID || b.Status == "cancelled" {
+			continue
+		}
+		if filtered && (b.StartAtUTC.Before(from.(time.Time)) || !b.StartAtUTC.Before(to.(time.Time))) {
+			continue
+		}
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartAtUTC.Before(out[j].StartAtUTC) })
+	if offset > len(out) {
+		offset = len(out)
+	}
+	out = out[offset:]
+	if limit > 0 && limit < len(out) {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *fakeBookingRepo) ReassignBooking(ctx context.Context, q repository.Querier, id, toUserID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.bookings[id]
+	if !ok || !holdsSlot(b.Status) {
+		return 0, nil
+	}
+	b.UserID = toUserID
+	return 1, nil
+}
+
+func (r *fakeBookingRepo) CheckOverlappingBooking(ctx context.Context, q repository.Querier, userID string, start, end repository.AppTime, excludeID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.overlap(userID, start.(time.Time), end.(time.Time), excludeID), nil
+}
+
+// holdsSlot reports whether a booking in status blocks its time, as the
+// repository's queries treat it.
+func holdsSlot(status string) bool {
+	return status == "confirmed" || status == "pending"
+}
+
+func (r *fakeBookingRepo) overlap(userID string, start, end time.Time, excludeID string) string {
+	for _, b := range r.bookings {
+		if b.UserID == userID && b.ID != excludeID && holdsSlot(b.Status) && b.StartAtUTC.Before(end) && start.Before(b.EndAtUTC) {
+			return b.ID
+		}
+	}
+	return ""
+}
+
+// InsertBooking enforces what the database does: no overlapping live
+// bookings for a user (ErrConflict) and unique confirmation codes
+// (ErrConfirmationCodeTaken).
+func (r *fakeBookingRepo) InsertBooking(ctx context.Context, q repository.Querier, b *models.Booking) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.overlap(b.UserID, b.StartAtUTC, b.EndAtUTC, "") != "" {
+		return "", repository.ErrConflict
+	}
+	if r.codeCollisions > 0 {
+		r.codeCollisions--
+		return "", repository.ErrConfirmationCodeTaken
+	}
+	for _, o := range r.bookings {
+		if o.ConfirmationCode != "" && o.ConfirmationCode == b.ConfirmationCode {
+			return "", repository.ErrConfirmationCodeTaken
+		}
+	}
+	r.nextID++
+	cp := *b
+	cp.ID = fmt.Sprintf("booking-%d", r.nextID)
+	r.bookings[cp.ID] = &cp
+	return cp.ID, nil
+}
+
+func (r *fakeBookingRepo) FindCandidateOverlap(ctx context.Context, q repository.Querier, candidateEmail string, start, end repository.AppTime, excludeID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, b := range r.bookings {
+		if id != excludeID && strings.EqualFold(b.CandidateEmail, candidateEmail) && holdsSlot(b.Status) && b.StartAtUTC.Before(end.(time.Time)) && b.EndAtUTC.After(start.(time.Time)) {
+			return id, nil
+		}
+	}
+	return "", nil
+}
+
+func (r *fakeBookingRepo) ListBookingsByCandidate(ctx context.Context, q repository.Querier, candidateEmail string) ([]models.Booking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.Booking
+	for _, b := range r.bookings {
+		if strings.EqualFold(b.CandidateEmail, candidateEmail) {
+			out = append(out, *b)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].StartAtUTC.Equal(out[j].StartAtUTC) {
+			return out[i].StartAtUTC.Before(out[j].StartAtUTC)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+func (r *fakeBookingRepo) GetBookingByConfirmationCode(ctx context.Context, q repository.Querier, code string) (*models.Booking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.bookings {
+		if b.ConfirmationCode == code {
+			cp := *b
+			return &cp, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (r *fakeBookingRepo) GetBookingStatus(ctx context.Context, q repository.Querier, id string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.bookings[id]
+	if !ok {
+		return "", pgx.ErrNoRows
+	}
+	return b.Status, nil
+}
+
+func (r *fakeBookingRepo) ListUpcomingBookings(ctx context.Context, q repository.Querier, userID string, since, afterStart repository.AppTime, afterID string, limit int) ([]models.Booking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []models.Booking{}
+	for _, b := range r.bookings {
+		if b.UserID != userID || b.Status == "cancelled" || b.Status == "expired" || b.StartAtUTC.Before(since.(time.Time)) {
+			continue
+		}
+		if after, ok := afterStart.(time.Time); ok && (b.StartAtUTC.Before(after) || b.StartAtUTC.Equal(after) && b.ID <= afterID) {
+			continue
+		}
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].StartAtUTC.Equal(out[j].StartAtUTC) {
+			return out[i].StartAtUTC.Before(out[j].StartAtUTC)
+		}
+		return out[i].ID < out[j].ID
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// AggregateBookings mirrors the SQL aggregation over bookings starting in
+// [from, to): busiest weekday ties go to the earlier weekday.
+func (r *fakeBookingRepo) AggregateBookings(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime) (*models.BookingAggregates, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var a models.BookingAggregates
+	var mins float64
+	var perDay [7]int
+	for _, b := range r.bookings {
+		if b.UserID != userID || b.StartAtUTC.Before(from.(time.Time)) || !b.StartAtUTC.Before(to.(time.Time)) {
+			continue
+		}
+		a.Total++
+		switch b.Status {
+		case "confirmed":
+			a.Confirmed++
+			mins += b.EndAtUTC.Sub(b.StartAtUTC).Minutes()
+			perDay[b.StartAtUTC.UTC().Weekday()]++
+		case "cancelled":
+			a.Cancelled++
+		}
+	}
+	if a.Confirmed > 0 {
+		a.AvgDurationMins = mins / float64(a.Confirmed)
+	}
+	for day, n := range perDay {
+		if n > a.BusiestWeekdayCount {
+			day := day
+			a.BusiestWeekday, a.BusiestWeekdayCount = &day, n
+		}
+	}
+	return &a, nil
+}
+
+func (r *fakeBookingRepo) ListAllBookings(ctx context.Context, q repository.Querier, from, to repository.AppTime, status string, afterStart repository.AppTime, afterID string, limit int) ([]models.Booking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := []models.Booking{}
+	for _, b := range r.bookings {
+		if f, ok := from.(time.Time); ok && b.StartAtUTC.Before(f) {
+			continue
+		}
+		if t, ok := to.(time.Time); ok && !b.StartAtUTC.Before(t) {
+			continue
+		}
+		if status != "" && b.Status != status {
+			continue
+		}
+		if after, ok := afterStart.(time.Time); ok && (b.StartAtUTC.Before(after) || b.StartAtUTC.Equal(after) && b.ID <= afterID) {
+			continue
+		}
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].StartAtUTC.Equal(out[j].StartAtUTC) {
+			return out[i].StartAtUTC.Before(out[j].StartAtUTC)
+		}
+		return out[i].ID < out[j].ID
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *fakeBookingRepo) UpdateConfirmationState(ctx context.Context, q repository.Querier, id, from, to string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.bookings[id]
+	if !ok || b.ConfirmationState != from {
+		return 0, nil
+	}
+	b.ConfirmationState = to
+	return 1, nil
+}
+
+func (r *fakeBookingRepo) UpdateBookingTimes(ctx context.Context, q repository.Querier, id string, start, end repository.AppTime) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.bookings[id]
+	if !ok || !holdsSlot(b.Status) {
+		return 0, nil
+	}
+	if r.overlap(b.UserID, start.(time.Time), end.(time.Time), id) != "" {
+		return 0, repository.ErrConflict
+	}
+	b.StartAtUTC, b.EndAtUTC = start.(time.Time), end.(time.Time)
+	return 1, nil
+}
+
+func (r *fakeBookingRepo) CancelBooking(ctx context.Context, q repository.Querier, id, reason, cancelledBy string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.bookings[id]
+	if !ok || b.Status == "cancelled" {
+		return 0, nil
+	}
+	b.Status = "cancelled"
+	b.CancellationReason, b.CancelledBy = reason, cancelledBy
+	return 1, nil
+}
+
+func (r *fakeBookingRepo) PurgeCancelledBefore(ctx context.Context, q repository.Querier, cutoff repository.AppTime) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for id, b := range r.bookings {
+		if b.Status == "cancelled" && b.EndAtUTC.Before(cutoff.(time.Time)) {
+			ids = append(ids, id)
+			delete(r.bookings, id)
+		}
+	}
+	return ids, nil
+}
+
+// fakeEventSource serves calendar events from a map; a missing id is
+// ErrEventNotFound.
+type fakeEventSource map[string]CalendarEvent
+
+func (f fakeEventSource) GetEvent(ctx context.Context, eventID string) (*CalendarEvent, error) {
+	ev, ok := f[eventID]
+	if !ok {
+		return nil, ErrEventNotFound
+	}
+	return &ev, nil
+}
+
+// fakeAvailabilityRepo keeps availability rules in memory, keyed like the
+// table's unique index on (user_id, day_of_week, start_time, end_time, timezone).
+type fakeAvailabilityRepo struct {
+	repository.AvailabilityRepository
+
+	mu     sync.Mutex
+	rules  []models.AvailabilityRule
+	nextID int
+}
+
+func (r *fakeAvailabilityRepo) InsertAvailabilityRule(ctx context.Context, q repository.Querier, ar *models.AvailabilityRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.find(ar) >= 0 {
+		return repository.ErrConflict
+	}
+	r.nextID++
+	ar.ID = fmt.Sprintf("rule-%d", r.nextID)
+	r.rules = append(r.rules, *ar)
+	return nil
+}
+
+func (r *fakeAvailabilityRepo) UpsertAvailabilityRule(ctx context.Context, q repository.Querier, ar *models.AvailabilityRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.find(ar); i >= 0 {
+		ar.ID = r.rules[i].ID
+		r.rules[i] = *ar
+		return nil
+	}
+	r.nextID++
+	ar.ID = fmt.Sprintf("rule-%d", r.nextID)
+	r.rules = append(r.rules, *ar)
+	return nil
+}
+
+func (r *fakeAvailabilityRepo) find(ar *models.AvailabilityRule) int {
+	for i, o := range r.rules {
+		if o.UserID == ar.UserID && o.DayOfWeek == ar.DayOfWeek && o.StartTime == ar.StartTime && o.EndTime == ar.EndTime && o.Timezone == ar.Timezone {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *fakeAvailabilityRepo) ListAvailabilityRules(ctx context.Context, q repository.Querier, userID string) ([]models.AvailabilityRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.AvailabilityRule
+	for _, o := range r.rules {
+		if o.UserID == userID {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeAvailabilityRepo) CountAvailabilityRules(ctx context.Context, q repository.Querier, userID string) (int, error) {
+	rules, _ := r.ListAvailabilityRules(ctx, q, userID)
+	return len(rules), nil
+}
+
+func (r *fakeAvailabilityRepo) DeleteAvailabilityRules(ctx context.Context, q repository.Querier, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept, n := r.rules[:0], int64(0)
+	for _, o := range r.rules {
+		if o.UserID == userID {
+			n++
+			continue
+		}
+		kept = append(kept, o)
+	}
+	r.rules = kept
+	return n, nil
+}
+
+// monday is the Monday, 2 March 2026, that most tests schedule on.
+var monday = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+// at returns h:m UTC on monday.
+func at(h, m int) time.Time {
+	return monday.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+}
+
+// newFakeServices wires an availability and a booking service over fresh
+// in-memory repositories, with the clock fixed at now.
+func newFakeServices(now time.Time) (*AvailabilityService, *BookingService) {
+	books := newFakeBookingRepo()
+	avail := &AvailabilityService{DB: fakeDB{}, Avail: &fakeAvailabilityRepo{}, Book: books, Clock: FixedClock(now)}
+	bookings := NewBookingService(fakeDB{}, books, avail)
+	bookings.Clock = FixedClock(now)
+	return avail, bookings
+}
+
+// addRule gives userID a weekly rule on day between start and end with
+// slots of mins minutes.
+func addRule(t testing.TB, s *AvailabilityService, userID string, day time.Weekday, start, end string, mins int) models.AvailabilityRule {
+	t.Helper()
+	r := models.AvailabilityRule{UserID: userID, DayOfWeek: int(day), StartTime: start, EndTime: end, SlotLengthMins: mins, Available: true}
+	if err := s.Avail.InsertAvailabilityRule(context.Background(), s.DB, &r); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// fakeExceptionRepo keeps availability exceptions in memory.
+type fakeExceptionRepo struct {
+	mu         sync.Mutex
+	exceptions []models.AvailabilityException
+	nextID     int
+}
+
+func (r *fakeExceptionRepo) InsertException(ctx context.Context, q repository.Querier, e *models.AvailabilityException) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	e.ID = fmt.Sprintf("exception-%d", r.nextID)
+	r.exceptions = append(r.exceptions, *e)
+	return nil
+}
+
+func (r *fakeExceptionRepo) ListExceptionsInRange(ctx context.Context, q repository.Querier, userID string, fromDate, toDate string) ([]models.AvailabilityException, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.AvailabilityException
+	for _, e := range r.exceptions {
+		if e.UserID == userID && fromDate <= e.Date && e.Date <= toDate {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeExceptionRepo) DeleteException(ctx context.Context, q repository.Querier, userID, id string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, e := range r.exceptions {
+		if e.UserID == userID && e.ID == id {
+			r.exceptions = append(r.exceptions[:i], r.exceptions[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// fakeOverrideRepo keeps schedule overrides in memory.
+type fakeOverrideRepo struct {
+	mu        sync.Mutex
+	overrides []models.ScheduleOverride
+	nextID    int
+}
+
+func (r *fakeOverrideRepo) InsertOverride(ctx context.Context, q repository.Querier, o *models.ScheduleOverride) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	o.ID = fmt.Sprintf("override-%d", r.nextID)
+	r.overrides = append(r.overrides, *o)
+	return nil
+}
+
+func (r *fakeOverrideRepo) ListOverridesInRange(ctx context.Context, q repository.Querier, userID string, fromDate, toDate string) ([]models.ScheduleOverride, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.ScheduleOverride
+	for _, o := range r.overrides {
+		if o.UserID == userID && o.StartDate <= toDate && o.EndDate >= fromDate {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeOverrideRepo) DeleteOverride(ctx context.Context, q repository.Querier, userID, id string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, o := range r.overrides {
+		if o.UserID == userID && o.ID == id {
+			r.overrides = append(r.overrides[:i], r.overrides[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// slotStarts formats the slots' starts as 15:04 UTC, in order.
+func slotStarts(slots []Slot) []string {
+	out := make([]string, len(slots))
+	for i, sl := range slots {
+		out[i] = sl.StartUTC.Format("15:04")
+	}
+	return out
+}
+
+// fakeHoldRepo keeps holds in memory. A hold is active until now passes its
+// expiry; with now nil every hold stays active.
+type fakeHoldRepo struct {
+	repository.SlotHoldRepository
+	holds []models.SlotHold
+	now   func() time.Time
+}
+
+func (r *fakeHoldRepo) active(h models.SlotHold) bool {
+	return r.now == nil || h.ExpiresAt.After(r.now())
+}
+
+func (r *fakeHoldRepo) InsertHold(ctx context.Context, q repository.Querier, h *models.SlotHold) error {
+	h.ID = fmt.Sprintf("hold-%d", len(r.holds)+1)
+	r.holds = append(r.holds, *h)
+	return nil
+}
+
+func (r *fakeHoldRepo) ListActiveHolds(ctx context.Context, q repository.Querier, userID string, from, to repository.AppTime) ([]models.SlotHold, error) {
+	var out []models.SlotHold
+	for _, h := range r.holds {
+		if h.UserID == userID && r.active(h) && h.StartAtUTC.Before(to.(time.Time)) && h.EndAtUTC.After(from.(time.Time)) {
+			out = append(out, h)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeHoldRepo) FindActiveHoldOverlap(ctx context.Context, q repository.Querier, userID string, start, end repository.AppTime) (string, error) {
+	holds, _ := r.ListActiveHolds(ctx, q, userID, start, end)
+	if len(holds) == 0 {
+		return "", nil
+	}
+	return holds[0].ID, nil
+}
+
+func (r *fakeHoldRepo) ConsumeHold(ctx context.Context, q repository.Querier, userID, token string, start, end repository.AppTime) (int64, error) {
+	for i, h := range r.holds {
+		if h.Token == token && h.UserID == userID && h.StartAtUTC.Equal(start.(time.Time)) && h.EndAtUTC.Equal(end.(time.Time)) && r.active(h) {
+			r.holds = append(r.holds[:i], r.holds[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (r *fakeHoldRepo) DeleteExpiredHolds(ctx context.Context, q repository.Querier, userID string) (int64, error) {
+	kept, n := r.holds[:0], int64(0)
+	for _, h := range r.holds {
+		if h.UserID == userID && !r.active(h) {
+			n++
+			continue
+		}
+		kept = append(kept, h)
+	}
+	r.holds = kept
+	return n, nil
+}
+
+// fakeAuditRepo keeps audit entries in memory.
+type fakeAuditRepo struct {
+	mu      sync.Mutex
+	entries []models.BookingAuditEntry
+}
+
+func (r *fakeAuditRepo) InsertAuditEntry(ctx context.Context, q repository.Querier, e *models.BookingAuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.ID = fmt.Sprintf("audit-%d", len(r.entries)+1)
+	r.entries = append(r.entries, *e)
+	return nil
+}
+
+func (r *fakeAuditRepo) ListAuditEntries(ctx context.Context, q repository.Querier, bookingID string) ([]models.BookingAuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.BookingAuditEntry
+	for _, e := range r.entries {
+		if e.BookingID == bookingID {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeAuditRepo) DeleteAuditEntries(ctx context.Context, q repository.Querier, bookingIDs []string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	purged := map[string]bool{}
+	for _, id := range bookingIDs {
+		purged[id] = true
+	}
+	kept, n := r.entries[:0], int64(0)
+	for _, e := range r.entries {
+		if purged[e.BookingID] {
+			n++
+			continue
+		}
+		kept = append(kept, e)
+	}
+	r.entries = kept
+	return n, nil
+}
+
+func (r *fakeBookingRepo) CancelRecurrenceGroup(ctx context.Context, q repository.Querier, groupID string, from repository.AppTime, reason, cancelledBy string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for id, b := range r.bookings {
+		if b.RecurrenceGroupID != groupID || b.Status == "cancelled" {
+			continue
+		}
+		if from != nil && b.StartAtUTC.Before(from.(time.Time)) {
+			continue
+		}
+		b.Status = "cancelled"
+		b.CancellationReason, b.CancelledBy = reason, cancelledBy
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *fakeBookingRepo) GetBookingByGoogleEventID(ctx context.Context, q repository.Querier, eventID string) (*models.Booking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.bookings {
+		if b.GoogleEventID == eventID {
+			cp := *b
+			return &cp, nil
+		}
+	}
+	return nil, pgx.ErrNoRows
+}
+
+// fakeSettingsRepo serves fixed user settings by user id.
+type fakeSettingsRepo map[string]models.UserSettings
+
+func (r fakeSettingsRepo) GetUserSettings(ctx context.Context, q repository.Querier, userID string) (*models.UserSettings, error) {
+	st, ok := r[userID]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return &st, nil
+}
+
+func (r fakeSettingsRepo) UpsertUserSettings(ctx context.Context, q repository.Querier, st *models.UserSettings) error {
+	r[st.UserID] = *st
+	return nil
+}
+
+func (r *fakeBookingRepo) ApproveBooking(ctx context.Context, q repository.Querier, id string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.bookings[id]
+	if !ok || b.Status != "pending" {
+		return 0, nil
+	}
+	b.Status, b.PendingSince = "confirmed", nil
+	return 1, nil
+}
+
+func (r *fakeBookingRepo) ExpirePendingBefore(ctx context.Context, q repository.Querier, cutoff repository.AppTime) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for id, b := range r.bookings {
+		if b.Status == "pending" && b.PendingSince.Before(cutoff.(time.Time)) {
+			b.Status = "expired"
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// fakeCandidateTokenRepo keeps one live token per email.
+type fakeCandidateTokenRepo struct {
+	tokens map[string]*models.CandidateToken // by id
+}
+
+func (r *fakeCandidateTokenRepo) EnsureCandidateToken(ctx context.Context, q repository.Querier, email string) (*models.CandidateToken, bool, error) {
+	if r.tokens == nil {
+		r.tokens = map[string]*models.CandidateToken{}
+	}
+	for _, t := range r.tokens {
+		if t.Email == email && t.RevokedAt == nil {
+			return t, false, nil
+		}
+	}
+	t := &models.CandidateToken{ID: fmt.Sprintf("token-%d", len(r.tokens)+1), Email: email}
+	r.tokens[t.ID] = t
+	return t, true, nil
+}
+
+func (r *fakeCandidateTokenRepo) GetCandidateToken(ctx context.Context, q repository.Querier, id string) (*models.CandidateToken, error) {
+	t, ok := r.tokens[id]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+	return t, nil
+}
+
+func (r *fakeCandidateTokenRepo) RevokeCandidateToken(ctx context.Context, q repository.Querier, id string) (int64, error) {
+	t, ok := r.tokens[id]
+	if !ok || t.RevokedAt != nil {
+		return 0, nil
+	}
+	now := time.Now()
+	t.RevokedAt = &now
+	return 1, nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import "context"
+
+// Flags are per-request behaviour switches, resolved once from the request by
+// app.FeatureFlagsMiddleware and read back with FlagsFrom.
+type Flags struct {
+	// StrictUTC rejects *_utc timestamps that carry an offset instead of a
+	// Z suffix.
+	StrictUTC bool
+	// IncludeHeld keeps slots under another caller's hold in generated
+	// availability instead of hiding them.
+	IncludeHeld bool
+	// IncludePast keeps slots that have already ended when the service is
+	// configured to skip them.
+	IncludePast bool
+	// Locale selects localized slot titles, e.g. "de" or "pt-BR"; empty
+	// uses the default titles.
+	Locale string
+}
+
+type flagsKey struct{}
+
+// WithFlags returns a copy of ctx carrying f.
+func WithFlags(ctx context.Context, f Flags) context.Context {
+	return context.WithValue(ctx, flagsKey{}, f)
+}
+
+// FlagsFrom returns the flags stored in ctx, or the zero Flags when none
+// were resolved.
+func FlagsFrom(ctx context.Context) Flags {
+	f, _ := ctx.Value(flagsKey{}).(Flags)
+	return f
+}
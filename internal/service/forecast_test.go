@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestForecastForKnownSchedule(t *testing.T) {
+	at := func(days, h, m int) time.Time {
+		return monday.AddDate(0, 0, days).Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	booking := func(id string, start time.Time, mins int, status string) models.Booking {
+		return models.Booking{ID: id, UserID: "u1", StartAtUTC: start, EndAtUTC: start.Add(time.Duration(mins) * time.Minute), Status: status}
+	}
+
+	// Monday 09:00–12:00 in 30-min slots and Wednesday 13:00–15:00 in 60-min
+	// slots: 8 slots a week
+	cases := []struct {
+		name     string
+		bookings []models.Booking
+		from, to time.Time
+		want     UtilizationForecast
+	}{
+		{"no bookings", nil, monday, monday.AddDate(0, 0, 7), UtilizationForecast{TotalSlots: 8, RemainingSlots: 8}},
+		{"three booked slots", []models.Booking{
+			booking("mon-0900", at(0, 9, 0), 30, ""),
+			booking("mon-1000", at(0, 10, 0), 30, ""),
+			booking("wed-1300", at(2, 13, 0), 60, ""),
+			booking("mon-1100", at(0, 11, 0), 30, "cancelled"),
+			booking("next-mon", at(7, 9, 0), 30, ""),
+		}, monday, monday.AddDate(0, 0, 7), UtilizationForecast{TotalSlots: 8, BookedSlots: 3, RemainingSlots: 5, UtilizationPct: 37.5}},
+		{"booking spanning two slots", []models.Booking{
+			booking("mon-1015", at(0, 10, 15), 30, ""),
+		}, monday, monday.AddDate(0, 0, 7), UtilizationForecast{TotalSlots: 8, BookedSlots: 2, RemainingSlots: 6, UtilizationPct: 25}},
+		{"uneven percentage", []models.Booking{
+			booking("mon-0900", at(0, 9, 0), 30, ""),
+		}, at(0, 9, 0), at(0, 10, 30), UtilizationForecast{TotalSlots: 3, BookedSlots: 1, RemainingSlots: 2, UtilizationPct: 33.33}},
+		{"two weeks", []models.Booking{
+			booking("mon-0900", at(0, 9, 0), 30, ""),
+			booking("next-wed", at(9, 14, 0), 60, ""),
+		}, monday, monday.AddDate(0, 0, 14), UtilizationForecast{TotalSlots: 16, BookedSlots: 2, RemainingSlots: 14, UtilizationPct: 12.5}},
+		{"no availability", nil, at(1, 0, 0), at(2, 0, 0), UtilizationForecast{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			addRule(t, avail, "u1", time.Monday, "09:00", "12:00", 30)
+			addRule(t, avail, "u1", time.Wednesday, "13:00", "15:00", 60)
+			repo := newFakeBookingRepo(tc.bookings...)
+			s.Repo, avail.Book = repo, repo
+
+			got, err := s.Forecast(context.Background(), "u1", tc.from, tc.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tc.want
+			want.UserID, want.From, want.To = "u1", tc.from, tc.to
+			if got != want {
+				t.Errorf("forecast = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
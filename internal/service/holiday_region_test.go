@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHolidayRegionSuppressesSlots(t *testing.T) {
+	monday := time.Date(2026, 6, 29, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name   string
+		region string
+		want   []string // slot days
+	}{
+		{"US observes July 4th on Friday", "US", []string{"06-29", "06-30", "07-01", "07-02"}},
+		{"region without a holiday that week", "DE", []string{"06-29", "06-30", "07-01", "07-02", "07-03"}},
+		{"no region", "", []string{"06-29", "06-30", "07-01", "07-02", "07-03"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newFakeServices(monday)
+			s.Settings = fakeSettingsRepo{"u1": {UserID: "u1", HolidayRegion: tc.region}}
+			for day := time.Monday; day <= time.Friday; day++ {
+				addRule(t, s, "u1", day, "09:00", "10:00", 60)
+			}
+
+			slots, err := s.GenerateAvailableSlots(context.Background(), "u1", monday, monday.AddDate(0, 0, 7))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, sl := range slots {
+				got = append(got, sl.StartUTC.Format("01-02"))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("slot days = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateSettingsValidatesHolidayRegion(t *testing.T) {
+	cases := []struct {
+		region  string
+		want    string
+		wantErr string
+	}{
+		{" gb ", "GB", ""},
+		{"", "", ""},
+		{"XX", "", "holiday_region must be one of DE, FR, GB, US"},
+	}
+	for _, tc := range cases {
+		repo := fakeSettingsRepo{}
+		region := tc.region
+		_, err := NewUserSettingsService(nil, repo).UpdateSettings(context.Background(), "u1", UserSettingsUpdate{HolidayRegion: &region})
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+			t.Errorf("%q: err = %v, want %q", tc.region, err, tc.wantErr)
+			continue
+		}
+		if got := repo["u1"].HolidayRegion; got != tc.want {
+			t.Errorf("%q: stored holiday_region = %q, want %q", tc.region, got, tc.want)
+		}
+	}
+}
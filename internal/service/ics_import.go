@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"scheduler-service/internal/ics"
+	"scheduler-service/internal/models"
+)
+
+// maxICSBlackouts caps how many blackout overrides one ICS import may create.
+const maxICSBlackouts = 2000
+
+// ImportICSBlackouts turns the busy time of events within the dates [from,
+// to) into blackout overrides, one per day an occurrence touches, and stores
+// them in one transaction. Days and times are those of overrideLocation, the
+// zone the user's overrides are read in, so a blackout covers the instants the
+// event is busy; all-day events keep their date. Free, cancelled and
+// zero-length events are skipped.
+func (s *AvailabilityService) ImportICSBlackouts(ctx context.Context, userID string, events []ics.Event, from, to time.Time) ([]models.ScheduleOverride, error) {
+	if s.Overrides == nil {
+		return nil, errors.New("schedule overrides not enabled")
+	}
+	rules, err := s.Avail.ListAvailabilityRules(ctx, s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := overrideLocation(rules)
+	localFrom, localTo := inLocationDate(from, loc), inLocationDate(to, loc)
+	var out []models.ScheduleOverride
+	for _, e := range events {
+		if e.Free || e.Cancelled || !e.End.After(e.Start) {
+			continue
+		}
+		rangeFrom, rangeTo := localFrom, localTo
+		if e.AllDay {
+			// All-day events are dated, not timed, like from and to
+			rangeFrom, rangeTo = from, to
+		}
+		for _, occ := range e.Occurrences(rangeFrom, rangeTo) {
+			start, end := occ.Start.In(loc), occ.End.In(loc)
+			if e.AllDay {
+				start, end = inLocationDate(occ.Start, loc), inLocationDate(occ.End, loc)
+			}
+			if start.Before(localFrom) {
+				start = localFrom
+			}
+			if end.After(localTo) {
+				end = localTo
+			}
+			out = append(out, blackoutsForSpan(userID, start, end, e.Summary)...)
+			if len(out) > maxICSBlackouts {
+				return nil, fmt.Errorf("ics file produces more than %d blackouts in the range", maxICSBlackouts)
+			}
+		}
+	}
+
+	trx, err := beginTx(ctx, s.DB)
+	if err != nil {
+		return nil, err
+	}
+	defer trx.Rollback(ctx)
+	for i := range out {
+		if err := validateOverride(&out[i]); err != nil {
+			return nil, err
+		}
+		if err := s.Overrides.InsertOverride(ctx, trx, &out[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := trx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// inLocationDate returns midnight in loc of t's calendar date.
+func inLocationDate(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// blackoutsForSpan splits [start, end) at midnights of start's location into
+// blackouts. Whole days get a blackout without times; a part-day running to
+// midnight ends at 23:59, the last time of day an override can name.
+func blackoutsForSpan(userID string, start, end time.Time, title string) []models.ScheduleOverride {
+	var out []models.ScheduleOverride
+	for day := inLocationDate(start, start.Location()); day.Before(end); day = day.AddDate(0, 0, 1) {
+		segStart, segEnd := start, end
+		if segStart.Before(day) {
+			segStart = day
+		}
+		next := day.AddDate(0, 0, 1)
+		if segEnd.After(next) {
+			segEnd = next
+		}
+		o := models.ScheduleOverride{
+			UserID:    userID,
+			Type:      models.OverrideBlackout,
+			StartDate: day.Format("2006-01-02"),
+			Title:     title,
+		}
+		if !segStart.Equal(day) || !segEnd.Equal(next) {
+			o.StartTime = segStart.Truncate(time.Minute).Format("15:04")
+			o.EndTime = "23:59"
+			if segEnd.Before(next) {
+				// Round up so a partial minute stays blocked
+				o.EndTime = segEnd.Add(time.Minute - time.Nanosecond).Truncate(time.Minute).Format("15:04")
+			}
+			if o.EndTime <= o.StartTime {
+				continue
+			}
+		}
+		out = append(out, o)
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/ics"
+	"scheduler-service/internal/models"
+)
+
+func TestImportICSBlackouts(t *testing.T) {
+	const calendar = "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\nUID:standup\r\nSUMMARY:Standup\r\nDTSTART:20260302T090000Z\r\nDTEND:20260302T093000Z\r\nRRULE:FREQ=DAILY;COUNT=3\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:trip\r\nSUMMARY:Trip\r\nDTSTART;VALUE=DATE:20260305\r\nDTEND;VALUE=DATE:20260306\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:lunch\r\nSUMMARY:Lunch\r\nTRANSP:TRANSPARENT\r\nDTSTART:20260302T120000Z\r\nDTEND:20260302T130000Z\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	events, err := ics.Parse(strings.NewReader(calendar))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		from, to time.Time
+		want     []string // "date start-end title"
+	}{
+		{"whole week", monday, monday.AddDate(0, 0, 7), []string{
+			"2026-03-02 09:00-09:30 Standup",
+			"2026-03-03 09:00-09:30 Standup",
+			"2026-03-04 09:00-09:30 Standup",
+			"2026-03-05 - Trip",
+		}},
+		{"range clips the series", monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 3), []string{
+			"2026-03-03 09:00-09:30 Standup",
+			"2026-03-04 09:00-09:30 Standup",
+		}},
+		{"range after everything", monday.AddDate(0, 0, 7), monday.AddDate(0, 0, 14), nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newFakeServices(monday)
+			s.Overrides = &fakeOverrideRepo{}
+			got, err := s.ImportICSBlackouts(context.Background(), "u1", events, tc.from, tc.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var desc []string
+			for _, o := range got {
+				desc = append(desc, o.StartDate+" "+o.StartTime+"-"+o.EndTime+" "+o.Title)
+			}
+			if strings.Join(desc, "|") != strings.Join(tc.want, "|") {
+				t.Errorf("blackouts = %q, want %q", desc, tc.want)
+			}
+			if stored := len(s.Overrides.(*fakeOverrideRepo).overrides); stored != len(tc.want) {
+				t.Errorf("stored %d overrides, want %d", stored, len(tc.want))
+			}
+		})
+	}
+}
+
+func TestImportICSBlackoutsInRuleTimezone(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	const calendar = "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\nUID:sync\r\nSUMMARY:Sync\r\nDTSTART:20260302T140000Z\r\nDTEND:20260302T150000Z\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:late\r\nSUMMARY:Late\r\nDTSTART:20260303T030000Z\r\nDTEND:20260303T040000Z\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:trip\r\nSUMMARY:Trip\r\nDTSTART;VALUE=DATE:20260304\r\nDTEND;VALUE=DATE:20260305\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+	events, err := ics.Parse(strings.NewReader(calendar))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	s, _ := newFakeServices(monday)
+	s.Overrides = &fakeOverrideRepo{}
+	rule := models.AvailabilityRule{UserID: "u1", DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "11:00", SlotLengthMins: 30, Timezone: "America/New_York", Available: true}
+	if err := s.Avail.InsertAvailabilityRule(ctx, s.DB, &rule); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.ImportICSBlackouts(ctx, "u1", events, monday, monday.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var desc []string
+	for _, o := range got {
+		desc = append(desc, o.StartDate+" "+o.StartTime+"-"+o.EndTime+" "+o.Title)
+	}
+	want := []string{
+		"2026-03-02 09:00-10:00 Sync",
+		"2026-03-02 22:00-23:00 Late",
+		"2026-03-04 - Trip",
+	}
+	if strings.Join(desc, "|") != strings.Join(want, "|") {
+		t.Errorf("blackouts = %q, want %q", desc, want)
+	}
+
+	// The Sync blackout covers the instants the event is busy
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, ny)
+	slots, err := s.GenerateAvailableSlots(ctx, "u1", day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if starts := slotStarts(slots); strings.Join(starts, ",") != "15:00,15:30" {
+		t.Errorf("slots = %v, want 15:00,15:30 UTC", starts)
+	}
+}
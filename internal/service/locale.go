@@ -0,0 +1,46 @@
+package service
+
+import "strings"
+
+// normalizeLocale lowercases a locale tag and uses "-" as its separator, so
+// "pt_BR" and "pt-BR" both become "pt-br".
+func normalizeLocale(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// normalizeTranslations normalizes translation keys and drops blank entries.
+func normalizeTranslations(in map[string]string) map[string]string {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for tag, text := range in {
+		tag, text = normalizeLocale(tag), strings.TrimSpace(text)
+		if tag == "" || text == "" {
+			continue
+		}
+		out[tag] = text
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// localizedTitle picks the translation for locale, trying the full tag then
+// its base language ("pt-br", then "pt"), and falls back to title.
+func localizedTitle(title string, translations map[string]string, locale string) string {
+	locale = normalizeLocale(locale)
+	if locale == "" || len(translations) == 0 {
+		return title
+	}
+	if t, ok := translations[locale]; ok {
+		return t
+	}
+	if base, _, found := strings.Cut(locale, "-"); found {
+		if t, ok := translations[base]; ok {
+			return t
+		}
+	}
+	return title
+}
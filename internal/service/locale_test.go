@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestSlotTitlesFollowLocale(t *testing.T) {
+	cases := []struct {
+		locale string
+		want   string
+	}{
+		{"de", "Vorstellungsgespräch"},
+		{"de-AT", "Vorstellungsgespräch"},
+		{"pt-BR", "Entrevista"},
+		{"pt_br", "Entrevista"},
+		{"pt", "Entrevista técnica"},
+		{"fr", "Interview"},
+		{"", "Interview"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.locale, func(t *testing.T) {
+			s, _ := newFakeServices(monday)
+			rule := models.AvailabilityRule{UserID: "u1", DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "10:00", SlotLengthMins: 60, Available: true,
+				Title: "Interview", TitleTranslations: map[string]string{"DE": "Vorstellungsgespräch", "pt_BR": "Entrevista", "pt": "Entrevista técnica", "es": " "}}
+			if err := validateAvailabilityRule(&rule); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Avail.InsertAvailabilityRule(context.Background(), s.DB, &rule); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := WithFlags(context.Background(), Flags{Locale: tc.locale})
+			slots, err := s.GenerateAvailableSlots(ctx, "u1", monday, monday.Add(24*time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(slots) != 1 || slots[0].Title != tc.want {
+				t.Errorf("slots = %+v, want one titled %q", slots, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTranslations(t *testing.T) {
+	cases := []struct {
+		name string
+		in   map[string]string
+		want map[string]string
+	}{
+		{"keys lowercased with dashes", map[string]string{"pt_BR": " Entrevista ", "DE": "Gespräch"}, map[string]string{"pt-br": "Entrevista", "de": "Gespräch"}},
+		{"blank entries dropped", map[string]string{"fr": " ", " ": "x", "es": "Entrevista"}, map[string]string{"es": "Entrevista"}},
+		{"nothing left", map[string]string{"fr": ""}, nil},
+		{"empty", nil, nil},
+	}
+	for _, tc := range cases {
+		if got := normalizeTranslations(tc.in); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestMergeFreeWindows(t *testing.T) {
+	cases := []struct {
+		name        string
+		booked      []string // starts of 30-min bookings
+		minDuration time.Duration
+		want        []string
+	}{
+		{"three adjacent slots make one window", nil, 90 * time.Minute, []string{"09:00-10:30"}},
+		{"shorter minimum still returns the whole window", nil, 60 * time.Minute, []string{"09:00-10:30"}},
+		{"longer minimum than the window", nil, 120 * time.Minute, []string{}},
+		{"booked middle slot breaks the window", []string{"09:30"}, 90 * time.Minute, []string{}},
+		{"pieces either side of the booking", []string{"09:30"}, 30 * time.Minute, []string{"09:00-09:30", "10:00-10:30"}},
+		{"booked first slot leaves the tail", []string{"09:00"}, 60 * time.Minute, []string{"09:30-10:30"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newFakeServices(monday.Add(8 * time.Hour))
+			addRule(t, s, "u1", time.Monday, "09:00", "10:30", 30)
+			var bookings []models.Booking
+			for i, hm := range tc.booked {
+				start, _ := time.Parse("15:04", hm)
+				at := monday.Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute)
+				bookings = append(bookings, models.Booking{ID: fmt.Sprintf("b%d", i+1), UserID: "u1", StartAtUTC: at, EndAtUTC: at.Add(30 * time.Minute)})
+			}
+			s.Book = newFakeBookingRepo(bookings...)
+
+			slots, err := s.GenerateAvailableSlots(context.Background(), "u1", monday, monday.Add(24*time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, w := range MergeFreeWindows(slots, tc.minDuration) {
+				got = append(got, w.StartUTC.Format("15:04")+"-"+w.EndUTC.Format("15:04"))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("windows = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMergeFreeWindowsJoinsOverlappingSlots(t *testing.T) {
+	// Out of order and overlapping, as two rules on the same day can produce
+	slots := []Slot{
+		{StartUTC: at(10, 0), EndUTC: at(11, 0)},
+		{StartUTC: at(9, 0), EndUTC: at(10, 0)},
+		{StartUTC: at(9, 30), EndUTC: at(10, 0)},
+		{StartUTC: at(12, 0), EndUTC: at(13, 0)},
+	}
+	want := []Slot{{StartUTC: at(9, 0), EndUTC: at(11, 0)}}
+	if got := MergeFreeWindows(slots, 90*time.Minute); !reflect.DeepEqual(got, want) {
+		t.Errorf("windows = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOneOffAvailability(t *testing.T) {
+	type oneOff struct {
+		date, start, end string
+		mins             int
+	}
+	cases := []struct {
+		name    string
+		oneOff  oneOff
+		want    []string
+		wantErr string
+	}{
+		{"Saturday with no weekly rule", oneOff{"2026-03-07", "10:00", "12:00", 60},
+			[]string{"03-02 09:00", "03-07 10:00 Saturday session", "03-07 11:00 Saturday session"}, ""},
+		{"added to a day with a rule", oneOff{"2026-03-02", "14:00", "15:00", 60},
+			[]string{"03-02 09:00", "03-02 14:00 Saturday session"}, ""},
+		{"only that date", oneOff{"2026-02-28", "10:00", "12:00", 60}, []string{"03-02 09:00"}, ""},
+		{"end before start", oneOff{"2026-03-07", "12:00", "10:00", 60}, nil, "end_time must be after start_time"},
+		{"bad date", oneOff{"07/03/2026", "10:00", "12:00", 60}, nil, "start_date must be YYYY-MM-DD"},
+		{"no slot length", oneOff{"2026-03-07", "10:00", "12:00", 0}, nil, "slot_length_minutes must be positive"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, _ := newFakeServices(monday)
+			s.Overrides = &fakeOverrideRepo{}
+			addRule(t, s, "u1", time.Monday, "09:00", "10:00", 60)
+
+			_, err := s.CreateOneOffAvailability(ctx, "u1", tc.oneOff.date, tc.oneOff.start, tc.oneOff.end, tc.oneOff.mins, "Saturday session")
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+			if tc.wantErr != "" {
+				return
+			}
+			slots, err := s.GenerateAvailableSlots(ctx, "u1", monday, monday.AddDate(0, 0, 7))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, sl := range slots {
+				label := sl.StartUTC.Format("01-02 15:04")
+				if sl.Title != "" {
+					label += " " + sl.Title
+				}
+				got = append(got, label)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("slots = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
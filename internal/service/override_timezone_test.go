@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestDateOverridesInRuleTimezone(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	ctx := context.Background()
+	christmasEve := time.Date(2026, 12, 24, 0, 0, 0, 0, la) // a Thursday
+	cases := []struct {
+		name  string
+		setup func(t *testing.T, s *AvailabilityService)
+		// slots per local date, Dec 24 to Dec 26
+		want [3]int
+	}{
+		{"no override", func(t *testing.T, s *AvailabilityService) {}, [3]int{16, 16, 16}},
+		{"blackout override", func(t *testing.T, s *AvailabilityService) {
+			if _, err := s.CreateOverride(ctx, "u1", &models.ScheduleOverride{Type: models.OverrideBlackout, StartDate: "2026-12-25"}); err != nil {
+				t.Fatal(err)
+			}
+		}, [3]int{16, 0, 16}},
+		{"blocked exception", func(t *testing.T, s *AvailabilityService) {
+			if _, err := s.CreateException(ctx, "u1", &models.AvailabilityException{Date: "2026-12-25", Blocked: true}); err != nil {
+				t.Fatal(err)
+			}
+		}, [3]int{16, 0, 16}},
+		{"holiday", func(t *testing.T, s *AvailabilityService) {
+			s.Settings = fakeSettingsRepo{"u1": {UserID: "u1", HolidayRegion: "US"}}
+		}, [3]int{16, 0, 16}},
+		{"evening blackout", func(t *testing.T, s *AvailabilityService) {
+			if _, err := s.CreateOverride(ctx, "u1", &models.ScheduleOverride{Type: models.OverrideBlackout, StartDate: "2026-12-25", StartTime: "16:00", EndTime: "17:00"}); err != nil {
+				t.Fatal(err)
+			}
+		}, [3]int{16, 14, 16}},
+		{"extra hours", func(t *testing.T, s *AvailabilityService) {
+			if _, err := s.CreateOverride(ctx, "u1", &models.ScheduleOverride{Type: models.OverrideExtraHours, StartDate: "2026-12-26", StartTime: "18:00", EndTime: "19:00", SlotLengthMins: 30}); err != nil {
+				t.Fatal(err)
+			}
+		}, [3]int{16, 16, 18}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newFakeServices(christmasEve.AddDate(0, 0, -7))
+			s.Overrides = &fakeOverrideRepo{}
+			s.Exceptions = &fakeExceptionRepo{}
+			for _, day := range []time.Weekday{time.Thursday, time.Friday, time.Saturday} {
+				r := models.AvailabilityRule{UserID: "u1", DayOfWeek: int(day), StartTime: "09:00", EndTime: "17:00", SlotLengthMins: 30, Timezone: "America/Los_Angeles", Available: true}
+				if err := s.Avail.InsertAvailabilityRule(ctx, s.DB, &r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			tc.setup(t, s)
+
+			slots, err := s.GenerateAvailableSlots(ctx, "u1", christmasEve.UTC(), christmasEve.AddDate(0, 0, 3).UTC())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got [3]int
+			for _, sl := range slots {
+				got[sl.StartUTC.In(la).Day()-24]++
+			}
+			if got != tc.want {
+				t.Errorf("slots on Dec 24-26 = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// EarliestCommonSlot returns the earliest start in [fromUTC, toUTC) at which
+// every user in userIDs is free for duration, starting on one of their own
+// slot boundaries. A user is free for duration from a slot's start when that
+// slot and the back-to-back slots after it cover the whole duration. It
+// returns nil when there is no such start.
+func (s *AvailabilityService) EarliestCommonSlot(ctx context.Context, userIDs []string, duration time.Duration, fromUTC, toUTC time.Time) (*Slot, error) {
+	var common map[int64]bool
+	for _, res := range s.GenerateAvailableSlotsBatch(ctx, userIDs, fromUTC, toUTC) {
+		if res.Error != "" {
+			return nil, fmt.Errorf("user %s: %s", res.UserID, res.Error)
+		}
+		starts := freeStarts(res.Slots, duration)
+		if common == nil {
+			common = starts
+			continue
+		}
+		for t := range common {
+			if !starts[t] {
+				delete(common, t)
+			}
+		}
+	}
+
+	var best *time.Time
+	for t := range common {
+		start := time.Unix(t, 0).UTC()
+		if start.Before(fromUTC) || start.Add(duration).After(toUTC) {
+			continue
+		}
+		if best == nil || start.Before(*best) {
+			best = &start
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	return &Slot{StartUTC: *best, EndUTC: best.Add(duration)}, nil
+}
+
+// freeStarts returns the slot starts (unix seconds) from which slots run
+// without a gap for at least duration.
+func freeStarts(slots []Slot, duration time.Duration) map[int64]bool {
+	sorted := append([]Slot(nil), slots...)
+	SortSlots(sorted)
+	out := map[int64]bool{}
+	for i, sl := range sorted {
+		reach := sl.EndUTC
+		for j := i + 1; j < len(sorted) && reach.Sub(sl.StartUTC) < duration; j++ {
+			if sorted[j].StartUTC.After(reach) {
+				break
+			}
+			if sorted[j].EndUTC.After(reach) {
+				reach = sorted[j].EndUTC
+			}
+		}
+		if reach.Sub(sl.StartUTC) >= duration {
+			out[sl.StartUTC.Unix()] = true
+		}
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEarliestCommonSlotOnDayThree(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(monday)
+	for _, d := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday} {
+		addRule(t, s, "u1", d, "09:00", "12:00", 30)
+	}
+	addRule(t, s, "u2", time.Monday, "13:00", "15:00", 30)
+	addRule(t, s, "u2", time.Tuesday, "14:00", "16:00", 30)
+	addRule(t, s, "u2", time.Wednesday, "10:00", "12:00", 30)
+	addRule(t, s, "u3", time.Monday, "09:00", "15:00", 30)
+	addRule(t, s, "u3", time.Tuesday, "09:00", "16:00", 30)
+	addRule(t, s, "u3", time.Wednesday, "10:30", "12:00", 30)
+	users := []string{"u1", "u2", "u3"}
+	wednesday := monday.AddDate(0, 0, 2)
+
+	cases := []struct {
+		duration time.Duration
+		to       time.Time
+		want     time.Time // zero for none
+	}{
+		{time.Hour, monday.AddDate(0, 0, 7), wednesday.Add(10*time.Hour + 30*time.Minute)},
+		{90 * time.Minute, monday.AddDate(0, 0, 7), wednesday.Add(10*time.Hour + 30*time.Minute)},
+		{2 * time.Hour, monday.AddDate(0, 0, 7), time.Time{}},
+		{time.Hour, wednesday.Add(11 * time.Hour), time.Time{}}, // window closes mid-slot
+	}
+	for _, tc := range cases {
+		slot, err := s.EarliestCommonSlot(ctx, users, tc.duration, monday, tc.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case tc.want.IsZero() && slot != nil:
+			t.Errorf("%s until %s: got %s, want none", tc.duration, tc.to, slot.StartUTC)
+		case !tc.want.IsZero() && slot == nil:
+			t.Errorf("%s until %s: got none, want %s", tc.duration, tc.to, tc.want)
+		case slot != nil && (!slot.StartUTC.Equal(tc.want) || !slot.EndUTC.Equal(tc.want.Add(tc.duration))):
+			t.Errorf("%s until %s: got %s-%s, want start %s", tc.duration, tc.to, slot.StartUTC, slot.EndUTC, tc.want)
+		}
+	}
+}
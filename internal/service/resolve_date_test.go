@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestResolveDateWithBlackout(t *testing.T) {
+	cases := []struct {
+		name          string
+		date          string
+		blackout      models.ScheduleOverride
+		wantRules     []string
+		wantOverrides []string
+		wantWindows   []string
+		wantBlocked   []string
+	}{
+		{"partial blackout splits the rule",
+			"2026-03-02", models.ScheduleOverride{StartDate: "2026-03-02", EndDate: "2026-03-02", StartTime: "10:00", EndTime: "11:00"},
+			[]string{"rule-1"}, []string{"override-1"},
+			[]string{"09:00-10:00", "11:00-12:00"}, []string{"10:00-11:00 override:override-1"}},
+		{"whole-day blackout leaves nothing",
+			"2026-03-02", models.ScheduleOverride{StartDate: "2026-03-02", EndDate: "2026-03-02"},
+			[]string{"rule-1"}, []string{"override-1"},
+			[]string{}, []string{"00:00-00:00 override:override-1"}},
+		{"blackout on another date is not reported",
+			"2026-03-02", models.ScheduleOverride{StartDate: "2026-03-09", EndDate: "2026-03-09"},
+			[]string{"rule-1"}, []string{},
+			[]string{"09:00-12:00"}, []string{}},
+		{"weekday without a rule",
+			"2026-03-03", models.ScheduleOverride{StartDate: "2026-03-03", EndDate: "2026-03-03", StartTime: "10:00", EndTime: "11:00"},
+			[]string{}, []string{"override-1"},
+			[]string{}, []string{"10:00-11:00 override:override-1"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, _ := newFakeServices(time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC))
+			overrides := &fakeOverrideRepo{}
+			s.Overrides = overrides
+			addRule(t, s, "u1", time.Monday, "09:00", "12:00", 30)
+			o := tc.blackout
+			o.UserID, o.Type = "u1", models.OverrideBlackout
+			if err := overrides.InsertOverride(ctx, nil, &o); err != nil {
+				t.Fatal(err)
+			}
+
+			res, err := s.ResolveDate(ctx, "u1", tc.date, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rules := []string{}
+			for _, r := range res.Rules {
+				rules = append(rules, r.ID)
+			}
+			ids := []string{}
+			for _, o := range res.Overrides {
+				ids = append(ids, o.ID)
+			}
+			windows := []string{}
+			for _, w := range res.Windows {
+				windows = append(windows, w.StartUTC.Format("15:04")+"-"+w.EndUTC.Format("15:04"))
+			}
+			blocked := []string{}
+			for _, b := range res.Blocked {
+				blocked = append(blocked, b.StartUTC.Format("15:04")+"-"+b.EndUTC.Format("15:04")+" "+b.Source)
+			}
+			if !reflect.DeepEqual(rules, tc.wantRules) {
+				t.Errorf("rules = %v, want %v", rules, tc.wantRules)
+			}
+			if !reflect.DeepEqual(ids, tc.wantOverrides) {
+				t.Errorf("overrides = %v, want %v", ids, tc.wantOverrides)
+			}
+			if !reflect.DeepEqual(windows, tc.wantWindows) {
+				t.Errorf("windows = %v, want %v", windows, tc.wantWindows)
+			}
+			if !reflect.DeepEqual(blocked, tc.wantBlocked) {
+				t.Errorf("blocked = %v, want %v", blocked, tc.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestResolveDateRejectsBadDate(t *testing.T) {
+	s, _ := newFakeServices(time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC))
+	if _, err := s.ResolveDate(context.Background(), "u1", "03/02/2026", time.UTC); err == nil || err.Error() != "date must be YYYY-MM-DD" {
+		t.Errorf("err = %v, want date must be YYYY-MM-DD", err)
+	}
+}
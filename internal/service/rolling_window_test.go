@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRollingWindowClampsQueriedRange(t *testing.T) {
+	now := monday.Add(12 * time.Hour)
+	cases := []struct {
+		name      string
+		days      int
+		from, to  time.Time
+		wantCount int
+		wantFirst string
+		wantLast  string
+	}{
+		{"much larger range", 14, monday.AddDate(0, 0, -7), monday.AddDate(0, 2, 0), 8, "2026-03-03 09:00", "2026-03-16 09:30"},
+		{"range inside the window", 14, monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 2), 2, "2026-03-03 09:00", "2026-03-03 09:30"},
+		{"range beyond the window", 14, monday.AddDate(0, 0, 21), monday.AddDate(0, 0, 42), 0, "", ""},
+		{"range in the past", 14, monday.AddDate(0, 0, -14), monday, 0, "", ""},
+		{"window disabled", 0, monday, monday.AddDate(0, 0, 28), 16, "2026-03-02 09:00", "2026-03-24 09:30"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newFakeServices(now)
+			s.Settings = fakeSettingsRepo{"u1": {UserID: "u1", RollingDays: tc.days}}
+			addRule(t, s, "u1", time.Monday, "09:00", "10:00", 30)
+			addRule(t, s, "u1", time.Tuesday, "09:00", "10:00", 30)
+
+			slots, err := s.GenerateAvailableSlots(context.Background(), "u1", tc.from, tc.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(slots) != tc.wantCount {
+				t.Fatalf("got %d slots, want %d", len(slots), tc.wantCount)
+			}
+			if tc.days > 0 {
+				for _, sl := range slots {
+					if sl.StartUTC.Before(now) || !sl.EndUTC.Before(now.AddDate(0, 0, tc.days)) {
+						t.Errorf("slot %s falls outside the rolling window", sl.StartUTC)
+					}
+				}
+			}
+			if len(slots) == 0 {
+				return
+			}
+			if got := slots[0].StartUTC.Format("2006-01-02 15:04"); got != tc.wantFirst {
+				t.Errorf("first slot = %s, want %s", got, tc.wantFirst)
+			}
+			if got := slots[len(slots)-1].StartUTC.Format("2006-01-02 15:04"); got != tc.wantLast {
+				t.Errorf("last slot = %s, want %s", got, tc.wantLast)
+			}
+		})
+	}
+}
+
+func TestRollingWindowRejectsBookingBeyondIt(t *testing.T) {
+	cases := []struct {
+		name    string
+		start   time.Time
+		wantErr string
+	}{
+		{"inside the window", monday.AddDate(0, 0, 7).Add(9 * time.Hour), ""},
+		{"beyond the window", monday.AddDate(0, 0, 21).Add(9 * time.Hour), "slot not available"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			avail.Settings = fakeSettingsRepo{"u1": {UserID: "u1", RollingDays: 14}}
+			addRule(t, avail, "u1", time.Monday, "09:00", "10:00", 30)
+
+			_, err := s.CreateBooking(context.Background(), "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: tc.start, End: tc.start.Add(30 * time.Minute)})
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateSettingsValidatesRollingDays(t *testing.T) {
+	cases := []struct {
+		days    int
+		wantErr string
+	}{
+		{0, ""},
+		{14, ""},
+		{365, ""},
+		{-1, "rolling_days must be between 0 and 365"},
+		{366, "rolling_days must be between 0 and 365"},
+	}
+	for _, tc := range cases {
+		repo := fakeSettingsRepo{}
+		days := tc.days
+		_, err := NewUserSettingsService(nil, repo).UpdateSettings(context.Background(), "u1", UserSettingsUpdate{RollingDays: &days})
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+			t.Errorf("rolling_days %d: err = %v, want %q", tc.days, err, tc.wantErr)
+			continue
+		}
+		if tc.wantErr == "" && repo["u1"].RollingDays != tc.days {
+			t.Errorf("stored rolling_days = %d, want %d", repo["u1"].RollingDays, tc.days)
+		}
+	}
+}
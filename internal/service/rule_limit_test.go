@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func weeklyRule(day time.Weekday, start, end string) models.AvailabilityRule {
+	return models.AvailabilityRule{DayOfWeek: int(day), StartTime: start, EndTime: end, SlotLengthMins: 30, Available: true}
+}
+
+func TestSetAvailabilityRuleLimit(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(time.Now())
+	s.MaxRulesPerUser = 2
+
+	if _, err := s.SetAvailability(ctx, "u1", []models.AvailabilityRule{weeklyRule(time.Monday, "09:00", "10:00"), weeklyRule(time.Tuesday, "09:00", "10:00")}); err != nil {
+		t.Fatalf("at the limit: %v", err)
+	}
+	if _, err := s.SetAvailability(ctx, "u1", []models.AvailabilityRule{weeklyRule(time.Wednesday, "09:00", "10:00")}); err == nil || err.Error() != "availability rule limit exceeded" {
+		t.Fatalf("over the limit: err = %v", err)
+	}
+}
+
+func TestUpsertAvailabilityRuleLimit(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(time.Now())
+	s.MaxRulesPerUser = 3
+	if _, err := s.SetAvailability(ctx, "u1", []models.AvailabilityRule{weeklyRule(time.Monday, "09:00", "10:00"), weeklyRule(time.Tuesday, "09:00", "10:00")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// One update and one insert reach the limit exactly
+	if _, err := s.UpsertAvailability(ctx, "u1", []models.AvailabilityRule{weeklyRule(time.Monday, "09:00", "10:00"), weeklyRule(time.Wednesday, "09:00", "10:00")}); err != nil {
+		t.Fatalf("reaching the limit: %v", err)
+	}
+	// At the limit, updates still go through
+	updated := weeklyRule(time.Tuesday, "09:00", "10:00")
+	updated.Title = "renamed"
+	if _, err := s.UpsertAvailability(ctx, "u1", []models.AvailabilityRule{updated}); err != nil {
+		t.Fatalf("update at the limit: %v", err)
+	}
+	// but any new rule is refused
+	if _, err := s.UpsertAvailability(ctx, "u1", []models.AvailabilityRule{weeklyRule(time.Thursday, "09:00", "10:00")}); err == nil || err.Error() != "availability rule limit exceeded" {
+		t.Fatalf("insert over the limit: err = %v", err)
+	}
+	if n, _ := s.Avail.CountAvailabilityRules(ctx, nil, "u1"); n != 3 {
+		t.Errorf("user has %d rules, want 3", n)
+	}
+}
+
+func TestUpsertAvailabilityCountsRepeatedKeysOnce(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(time.Now())
+	s.MaxRulesPerUser = 1
+	rules := []models.AvailabilityRule{weeklyRule(time.Monday, "09:00", "10:00"), weeklyRule(time.Monday, "09:00", "10:00")}
+	if _, err := s.UpsertAvailability(ctx, "u1", rules); err != nil {
+		t.Fatalf("same key twice: %v", err)
+	}
+}
+
+func TestNewRuleCountIgnoresSeconds(t *testing.T) {
+	s, _ := newFakeServices(time.Now())
+	stored := weeklyRule(time.Monday, "09:00:00", "10:00:00")
+	stored.UserID = "u1"
+	s.Avail.(*fakeAvailabilityRepo).rules = []models.AvailabilityRule{stored}
+	n, err := s.newRuleCount(context.Background(), nil, "u1", []models.AvailabilityRule{weeklyRule(time.Monday, "09:00", "10:00")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("newRuleCount = %d, want 0", n)
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestBookingKeepsRuleSnapshot(t *testing.T) {
+	ctx := context.Background()
+	avail, s := newFakeServices(monday)
+	avail.Overrides = &fakeOverrideRepo{}
+	rule := models.AvailabilityRule{UserID: "u1", DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "10:00", SlotLengthMins: 30, Available: true, Title: "Phone screen", Tags: []string{"screen"}}
+	if err := avail.Avail.InsertAvailabilityRule(ctx, avail.DB, &rule); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := avail.CreateOverride(ctx, "u1", &models.ScheduleOverride{Type: models.OverrideExtraHours, StartDate: "2026-03-02", StartTime: "16:00", EndTime: "17:00", SlotLengthMins: 30}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name  string
+		start time.Time
+		want  *models.BookingRuleSnapshot
+	}{
+		{"slot from a rule", monday.Add(9 * time.Hour), &models.BookingRuleSnapshot{RuleID: rule.ID, Title: "Phone screen", Tags: []string{"screen"}}},
+		{"slot from extra hours", monday.Add(16 * time.Hour), nil},
+	}
+	ids := make([]string, len(cases))
+	for i, tc := range cases {
+		b, err := s.CreateBooking(ctx, "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: tc.start, End: tc.start.Add(30 * time.Minute)})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		ids[i] = b.ID
+	}
+
+	// Edit the rule after booking; the bookings keep what was booked
+	repo := avail.Avail.(*fakeAvailabilityRepo)
+	repo.rules[0].Title, repo.rules[0].Tags = "Onsite loop", []string{"onsite"}
+
+	for i, tc := range cases {
+		got := s.Repo.(*fakeBookingRepo).get(ids[i]).RuleSnapshot
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: rule_snapshot = %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestSlotsFilteredByRuleTag(t *testing.T) {
+	s, _ := newFakeServices(monday)
+	for _, r := range []models.AvailabilityRule{
+		{StartTime: "09:00", EndTime: "10:00", Tags: []string{" Screen ", "screen"}},
+		{StartTime: "13:00", EndTime: "14:00", Tags: []string{"onsite", "system-design"}},
+		{StartTime: "16:00", EndTime: "16:30"},
+	} {
+		r.UserID, r.DayOfWeek, r.SlotLengthMins, r.Available = "u1", int(time.Monday), 30, true
+		if err := validateAvailabilityRule(&r); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Avail.InsertAvailabilityRule(context.Background(), s.DB, &r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	slots, err := s.GenerateAvailableSlots(context.Background(), "u1", monday, monday.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := slots[0].Tags; !reflect.DeepEqual(got, []string{"screen"}) {
+		t.Errorf("09:00 slot tags = %v, want the rule's normalized tags", got)
+	}
+
+	cases := []struct {
+		tag  string
+		want []string
+	}{
+		{"screen", []string{"09:00", "09:30"}},
+		{"SCREEN", []string{"09:00", "09:30"}},
+		{"system-design", []string{"13:00", "13:30"}},
+		{"onsite", []string{"13:00", "13:30"}},
+		{"behavioural", []string{}},
+	}
+	for _, tc := range cases {
+		if got := slotStarts(FilterSlotsByTag(slots, tc.tag)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("tag %q: slots = %v, want %v", tc.tag, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"scheduler-service/internal/models"
+)
+
+func TestAvailabilityRuleWarnings(t *testing.T) {
+	cases := []struct {
+		name string
+		rule models.AvailabilityRule
+		want []string
+	}{
+		{"slots divide the window", models.AvailabilityRule{StartTime: "09:00", EndTime: "10:00", SlotLengthMins: 30}, nil},
+		{"gap", models.AvailabilityRule{StartTime: "09:00", EndTime: "10:00", SlotLengthMins: 45},
+			[]string{"rule 09:00–10:00 with 45-min slots leaves a 15-min gap"}},
+		{"no slots", models.AvailabilityRule{StartTime: "09:00", EndTime: "09:30", SlotLengthMins: 45},
+			[]string{"rule 09:00–09:30 with 45-min slots produces no slots"}},
+		{"start offset leaves a gap", models.AvailabilityRule{StartTime: "09:00", EndTime: "10:00", SlotLengthMins: 30, StartOffsetMins: 15},
+			[]string{"rule 09:00–10:00 with 30-min slots leaves a 15-min gap"}},
+		{"seconds in the times", models.AvailabilityRule{StartTime: "09:00:00", EndTime: "10:00:00", SlotLengthMins: 45},
+			[]string{"rule 09:00–10:00 with 45-min slots leaves a 15-min gap"}},
+		{"one window per warning", models.AvailabilityRule{SlotLengthMins: 45, Windows: []models.TimeWindow{
+			{StartTime: "09:00", EndTime: "10:30"},
+			{StartTime: "13:00", EndTime: "14:00"},
+		}}, []string{"rule 13:00–14:00 with 45-min slots leaves a 15-min gap"}},
+		{"no slot length", models.AvailabilityRule{StartTime: "09:00", EndTime: "10:00"}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := AvailabilityRuleWarnings(tc.rule); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("warnings = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
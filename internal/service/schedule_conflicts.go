@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+// Schedule issue types reported by ScheduleConflicts.
+const (
+	IssueOverlappingRules    = "overlapping_rules"
+	IssueRuleWithoutSlots    = "rule_without_slots"
+	IssueBookingOutsideHours = "booking_outside_availability"
+	IssueOverlappingBookings = "overlapping_bookings"
+)
+
+// ScheduleIssue is one problem found in a user's schedule.
+type ScheduleIssue struct {
+	Type       string   `json:"type"`
+	Message    string   `json:"message"`
+	RuleIDs    []string `json:"rule_ids,omitempty"`
+	BookingIDs []string `json:"booking_ids,omitempty"`
+}
+
+// ScheduleReport lists the issues found by ScheduleConflicts. Rule issues
+// concern the weekly rules as a whole; booking issues cover confirmed
+// bookings starting in [From, To).
+type ScheduleReport struct {
+	UserID string          `json:"user_id"`
+	From   time.Time       `json:"from"`
+	To     time.Time       `json:"to"`
+	Issues []ScheduleIssue `json:"issues"`
+}
+
+// ScheduleConflicts checks a user's schedule for overlapping available rules,
+// rules that cannot produce a slot, confirmed bookings that fall outside the
+// availability of their day, and confirmed bookings that overlap each other.
+func (s *AvailabilityService) ScheduleConflicts(ctx context.Context, userID string, fromUTC, toUTC time.Time) (ScheduleReport, error) {
+	out := ScheduleReport{UserID: userID, From: fromUTC, To: toUTC, Issues: []ScheduleIssue{}}
+	rules, err := s.Avail.ListAvailabilityRules(ctx, s.DB, userID)
+	if err != nil {
+		return out, err
+	}
+	out.Issues = append(out.Issues, ruleOverlapIssues(rules)...)
+	out.Issues = append(out.Issues, emptyRuleIssues(rules)...)
+
+	bookings, err := s.Book.ListBookingsInRange(ctx, s.DB, userID, fromUTC, toUTC)
+	if err != nil {
+		return out, err
+	}
+	sort.SliceStable(bookings, func(i, j int) bool { return bookings[i].StartAtUTC.Before(bookings[j].StartAtUTC) })
+	firstDate, lastDate := planningDates(fromUTC, toUTC)
+	overrides, err := s.listOverrides(ctx, userID, firstDate, lastDate)
+	if err != nil {
+		return out, err
+	}
+	for _, b := range bookings {
+		inside, err := withinAvailability(b.StartAtUTC.UTC(), b.EndAtUTC.UTC(), rules, overrides, s.DSTRepeatedHour)
+		if err != nil {
+			return out, err
+		}
+		if !inside {
+			out.Issues = append(out.Issues, ScheduleIssue{
+				Type:       IssueBookingOutsideHours,
+				Message:    fmt.Sprintf("booking at %s is outside the availability for that day", b.StartAtUTC.UTC().Format(time.RFC3339)),
+				BookingIDs: []string{b.ID},
+			})
+		}
+	}
+	out.Issues = append(out.Issues, bookingOverlapIssues(bookings)...)
+	return out, nil
+}
+
+type ruleSpan struct {
+	start, end time.Time
+	ruleID     string
+}
+
+// ruleOverlapIssues reports each pair of available rules whose windows overlap
+// on the same weekday.
+func ruleOverlapIssues(rules []models.AvailabilityRule) []ScheduleIssue {
+	byDay := map[int][]ruleSpan{}
+	for _, r := range rules {
+		if !r.Available {
+			continue
+		}
+		for _, w := range ruleWindows(r) {
+			start, err1 := parseHHMM(w.StartTime)
+			end, err2 := parseHHMM(w.EndTime)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			byDay[r.DayOfWeek] = append(byDay[r.DayOfWeek], ruleSpan{start: start, end: end, ruleID: r.ID})
+		}
+	}
+	var issues []ScheduleIssue
+	seen := map[[2]string]bool{}
+	for day := 0; day < 7; day++ {
+		spans := byDay[day]
+		sort.Slice(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+		for i := range spans {
+			for j := i + 1; j < len(spans) && spans[j].start.Before(spans[i].end); j++ {
+				if spans[i].ruleID == spans[j].ruleID {
+					continue
+				}
+				key := [2]string{spans[i].ruleID, spans[j].ruleID}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				issues = append(issues, ScheduleIssue{
+					Type:    IssueOverlappingRules,
+					Message: fmt.Sprintf("rules overlap on %s from %s", time.Weekday(day), spans[j].start.Format("15:04")),
+					RuleIDs: []string{spans[i].ruleID, spans[j].ruleID},
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// emptyRuleIssues reports available rules none of whose windows fits a slot.
+func emptyRuleIssues(rules []models.AvailabilityRule) []ScheduleIssue {
+	var issues []ScheduleIssue
+	for _, r := range rules {
+		if !r.Available {
+			continue
+		}
+		fits := false
+		for _, w := range ruleWindows(r) {
+			start, err1 := parseHHMM(w.StartTime)
+			end, err2 := parseHHMM(w.EndTime)
+			if err1 != nil || err2 != nil || r.SlotLengthMins <= 0 {
+				continue
+			}
+			if int(end.Sub(start).Minutes())-r.StartOffsetMins >= r.SlotLengthMins {
+				fits = true
+				break
+			}
+		}
+		if !fits {
+			issues = append(issues, ScheduleIssue{
+				Type:    IssueRuleWithoutSlots,
+				Message: fmt.Sprintf("rule on %s produces no %d-min slots", time.Weekday(r.DayOfWeek), r.SlotLengthMins),
+				RuleIDs: []string{r.ID},
+			})
+		}
+	}
+	return issues
+}
+
+// withinAvailability reports whether [start, end) lies inside one of the slot
+// windows planned around it and clear of blackouts.
+func withinAvailability(start, end time.Time, rules []models.AvailabilityRule, overrides []models.ScheduleOverride, repeatedHour string) (bool, error) {
+	firstDate, lastDate := planningDates(start, end)
+	windows, blocks, err := planRange(firstDate, lastDate, rules, overrides, repeatedHour)
+	if err != nil {
+		return false, err
+	}
+	if overlapsBlock(start, end, blocks) {
+		return false, nil
+	}
+	for _, w := range windows {
+		if !start.Before(w.start) && !end.After(w.end) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// bookingOverlapIssues reports bookings, sorted by start, that begin before
+// an earlier booking has ended.
+func bookingOverlapIssues(bookings []models.Booking) []ScheduleIssue {
+	var issues []ScheduleIssue
+	var latest *models.Booking
+	for i := range bookings {
+		b := &bookings[i]
+		if latest != nil && b.StartAtUTC.Before(latest.EndAtUTC) {
+			issues = append(issues, ScheduleIssue{
+				Type:       IssueOverlappingBookings,
+				Message:    fmt.Sprintf("booking at %s overlaps the booking at %s", b.StartAtUTC.UTC().Format(time.RFC3339), latest.StartAtUTC.UTC().Format(time.RFC3339)),
+				BookingIDs: []string{latest.ID, b.ID},
+			})
+		}
+		if latest == nil || b.EndAtUTC.After(latest.EndAtUTC) {
+			latest = b
+		}
+	}
+	return issues
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestScheduleConflictsReportsEachType(t *testing.T) {
+	type rule struct {
+		start, end string
+		mins       int
+	}
+	nineToNoon := []rule{{"09:00", "12:00", 30}}
+	cases := []struct {
+		name     string
+		rules    []rule // all on Monday
+		bookings []models.Booking
+		want     []string // issue types in report order
+	}{
+		{"clean schedule", nineToNoon, []models.Booking{{ID: "b1", StartAtUTC: at(9, 0), EndAtUTC: at(9, 30)}, {ID: "b2", StartAtUTC: at(9, 30), EndAtUTC: at(10, 0)}}, nil},
+		{"overlapping rules", []rule{{"09:00", "11:00", 30}, {"10:00", "12:00", 30}}, nil, []string{IssueOverlappingRules}},
+		{"adjacent rules", []rule{{"09:00", "10:00", 30}, {"10:00", "11:00", 30}}, nil, nil},
+		{"rule too short for a slot", []rule{{"09:00", "12:00", 30}, {"13:00", "13:20", 30}}, nil, []string{IssueRuleWithoutSlots}},
+		{"booking outside availability", nineToNoon, []models.Booking{{ID: "b1", StartAtUTC: at(13, 0), EndAtUTC: at(13, 30)}}, []string{IssueBookingOutsideHours}},
+		{"booking running past the rule", nineToNoon, []models.Booking{{ID: "b1", StartAtUTC: at(11, 30), EndAtUTC: at(12, 30)}}, []string{IssueBookingOutsideHours}},
+		{"overlapping bookings", nineToNoon, []models.Booking{{ID: "b1", StartAtUTC: at(9, 0), EndAtUTC: at(10, 0)}, {ID: "b2", StartAtUTC: at(9, 30), EndAtUTC: at(10, 0)}}, []string{IssueOverlappingBookings}},
+		{"cancelled booking ignored", nineToNoon, []models.Booking{{ID: "b1", Status: "cancelled", StartAtUTC: at(13, 0), EndAtUTC: at(13, 30)}}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newFakeServices(monday)
+			for _, r := range tc.rules {
+				addRule(t, s, "u1", time.Monday, r.start, r.end, r.mins)
+			}
+			for _, b := range tc.bookings {
+				b.UserID = "u1"
+				if b.Status == "" {
+					b.Status = "confirmed"
+				}
+				s.Book.(*fakeBookingRepo).bookings[b.ID] = &b
+			}
+
+			report, err := s.ScheduleConflicts(context.Background(), "u1", monday, monday.AddDate(0, 0, 7))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, is := range report.Issues {
+				got = append(got, is.Type)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("issues = %+v, want types %v", report.Issues, tc.want)
+			}
+		})
+	}
+}
+
+func TestScheduleConflictsNamesTheParties(t *testing.T) {
+	s, _ := newFakeServices(monday)
+	first := addRule(t, s, "u1", time.Monday, "09:00", "11:00", 30)
+	second := addRule(t, s, "u1", time.Monday, "10:30", "12:00", 30)
+	s.Book.(*fakeBookingRepo).bookings["b1"] = &models.Booking{ID: "b1", UserID: "u1", Status: "confirmed", StartAtUTC: monday.Add(9 * time.Hour), EndAtUTC: monday.Add(10 * time.Hour)}
+	s.Book.(*fakeBookingRepo).bookings["b2"] = &models.Booking{ID: "b2", UserID: "u1", Status: "confirmed", StartAtUTC: monday.Add(9*time.Hour + 30*time.Minute), EndAtUTC: monday.Add(10 * time.Hour)}
+
+	report, err := s.ScheduleConflicts(context.Background(), "u1", monday, monday.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ScheduleIssue{
+		{Type: IssueOverlappingRules, Message: "rules overlap on Monday from 10:30", RuleIDs: []string{first.ID, second.ID}},
+		{Type: IssueOverlappingBookings, Message: "booking at 2026-03-02T09:30:00Z overlaps the booking at 2026-03-02T09:00:00Z", BookingIDs: []string{"b1", "b2"}},
+	}
+	if !reflect.DeepEqual(report.Issues, want) {
+		t.Errorf("issues = %+v, want %+v", report.Issues, want)
+	}
+}
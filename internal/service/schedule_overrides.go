@@ -0,0 +1,458 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"scheduler-service/internal/holidays"
+	"scheduler-service/internal/models"
+)
+
+// CreateOverride validates and stores a schedule override for the user.
+func (s *AvailabilityService) CreateOverride(ctx context.Context, userID string, o *models.ScheduleOverride) (*models.ScheduleOverride, error) {
+	if s.Overrides == nil {
+		return nil, errors.New("schedule overrides not enabled")
+	}
+	o.UserID = userID
+	if err := validateOverride(o); err != nil {
+		return nil, err
+	}
+	if err := s.Overrides.InsertOverride(ctx, s.DB, o); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// CreateOneOffAvailability adds bookable time on a single date, whether or
+// not any weekly rule covers that weekday. It is stored as a one-day
+// extra_hours override.
+func (s *AvailabilityService) CreateOneOffAvailability(ctx context.Context, userID, date, startTime, endTime string, slotLengthMins int, title string) (*models.ScheduleOverride, error) {
+	return s.CreateOverride(ctx, userID, &models.ScheduleOverride{
+		Type:           models.OverrideExtraHours,
+		StartDate:      date,
+		EndDate:        date,
+		StartTime:      startTime,
+		EndTime:        endTime,
+		SlotLengthMins: slotLengthMins,
+		Title:          title,
+	})
+}
+
+// ListOverrides returns the user's overrides intersecting [fromDate, toDate].
+func (s *AvailabilityService) ListOverrides(ctx context.Context, userID, fromDate, toDate string) ([]models.ScheduleOverride, error) {
+	if s.Overrides == nil {
+		return nil, errors.New("schedule overrides not enabled")
+	}
+	return s.Overrides.ListOverridesInRange(ctx, s.DB, userID, fromDate, toDate)
+}
+
+func (s *AvailabilityService) DeleteOverride(ctx context.Context, userID, id string) error {
+	if s.Overrides == nil {
+		return errors.New("schedule overrides not enabled")
+	}
+	n, err := s.Overrides.DeleteOverride(ctx, s.DB, userID, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("override not found")
+	}
+	return nil
+}
+
+func validateOverride(o *models.ScheduleOverride) error {
+	switch o.Type {
+	case models.OverrideBlackout, models.OverrideCustomHours, models.OverrideExtraHours:
+	default:
+		return errors.New("type must be blackout, custom_hours or extra_hours")
+	}
+	start, err := time.Parse("2006-01-02", o.StartDate)
+	if err != nil {
+		return errors.New("start_date must be YYYY-MM-DD")
+	}
+	if o.EndDate == "" {
+		o.EndDate = o.StartDate
+	}
+	end, err := time.Parse("2006-01-02", o.EndDate)
+	if err != nil {
+		return errors.New("end_date must be YYYY-MM-DD")
+	}
+	if end.Before(start) {
+		return errors.New("end_date must not be before start_date")
+	}
+	if (o.StartTime == "") != (o.EndTime == "") {
+		return errors.New("start_time and end_time must be given together")
+	}
+	if o.StartTime != "" {
+		st, err := time.Parse("15:04", o.StartTime)
+		if err != nil {
+			return err
+		}
+		et, err := time.Parse("15:04", o.EndTime)
+		if err != nil {
+			return err
+		}
+		if !et.After(st) {
+			return errors.New("end_time must be after start_time")
+		}
+	}
+	if o.Type == models.OverrideBlackout {
+		o.SlotLengthMins = 0
+		return nil
+	}
+	if o.StartTime == "" {
+		return errors.New("custom_hours and extra_hours need start_time and end_time")
+	}
+	if o.SlotLengthMins <= 0 {
+		return errors.New("slot_length_minutes must be positive")
+	}
+	return nil
+}
+
+// slotWindow is a stretch of one UTC day that is cut into slots of slotLen.
+type slotWindow struct {
+	start, end time.Time
+	slotLen    time.Duration
+	buffer     time.Duration // kept free around bookings
+	tags       []string
+	title      string
+	titles     map[string]string // localized titles by locale
+	ruleID     string            // empty for override windows
+	source     string            // "rule:<id>" or "override:<id>"
+	tzFallback bool              // rule's zone failed to load; laid out in UTC
+}
+
+// blockWindow is a stretch removed from a day by a blackout override.
+type blockWindow struct {
+	start, end time.Time
+	source     string
+}
+
+// planDay resolves the weekly rules and overrides that apply to the date of
+// day into slot windows and blackouts. Precedence: custom_hours replaces the
+// weekly rules, extra_hours adds to whatever remains, and blackouts are
+// subtracted from the result by the caller, so a blackout always wins.
+// day is a calendar date: each rule is laid out on it in the rule's Timezone,
+// and the date-keyed overrides, exceptions and holidays in the zone of the
+// user's rules (see overrideLocation), so either may fall on a neighbouring
+// UTC date. repeatedHour is the DSTRepeatedHour policy for rules whose hours
+// cross a fall-back change.
+func planDay(day time.Time, rules []models.AvailabilityRule, overrides []models.ScheduleOverride, repeatedHour string) ([]slotWindow, []blockWindow, error) {
+	key := day.Format("2006-01-02")
+	var todays []models.ScheduleOverride
+	custom := false
+	for _, o := range overrides {
+		if o.StartDate <= key && key <= o.EndDate {
+			todays = append(todays, o)
+			if o.Type == models.OverrideCustomHours {
+				custom = true
+			}
+		}
+	}
+
+	var windows []slotWindow
+	var blocks []blockWindow
+	if !custom {
+		for _, r := range rules {
+			if !r.Available || int(day.Weekday()) != r.DayOfWeek {
+				continue
+			}
+			loc, fallback := planLocation(r)
+			for _, tw := range ruleWindows(r) {
+				startTOD, err := parseHHMM(tw.StartTime)
+				if err != nil {
+					return nil, nil, err
+				}
+				endTOD, err := parseHHMM(tw.EndTime)
+				if err != nil {
+					return nil, nil, err
+				}
+				if !endTOD.After(startTOD) {
+					return nil, nil, errors.New("end_time must be after start_time for rule " + r.ID)
+				}
+				start := atTimeOfDay(day, startTOD, loc, false, "rule "+r.ID).Add(time.Duration(r.StartOffsetMins) * time.Minute)
+				end := atTimeOfDay(day, endTOD, loc, repeatedHour != DSTRepeatedHourFirst, "rule "+r.ID)
+				for _, sp := range localSpans(start, end, loc, repeatedHour) {
+					windows = append(windows, slotWindow{
+						start:      sp[0],
+						end:        sp[1],
+						slotLen:    time.Duration(r.SlotLengthMins) * time.Minute,
+						buffer:     time.Duration(r.BufferMins) * time.Minute,
+						tags:       r.Tags,
+						title:      r.Title,
+						titles:     r.TitleTranslations,
+						ruleID:     r.ID,
+						source:     "rule:" + r.ID,
+						tzFallback: fallback,
+					})
+				}
+			}
+		}
+	}
+	if len(todays) == 0 {
+		return windows, blocks, nil
+	}
+	loc := overrideLocation(rules)
+	for _, o := range todays {
+		var start, end time.Time
+		if o.StartTime == "" {
+			midnight := time.Time{}
+			start = atTimeOfDay(day, midnight, loc, false, "override "+o.ID)
+			end = atTimeOfDay(day.Add(24*time.Hour), midnight, loc, false, "override "+o.ID)
+		} else {
+			startTOD, err := parseHHMM(o.StartTime)
+			if err != nil {
+				return nil, nil, err
+			}
+			endTOD, err := parseHHMM(o.EndTime)
+			if err != nil {
+				return nil, nil, err
+			}
+			// A blackout always covers both passes of a repeated hour
+			later := o.Type == models.OverrideBlackout || repeatedHour != DSTRepeatedHourFirst
+			start = atTimeOfDay(day, startTOD, loc, false, "override "+o.ID)
+			end = atTimeOfDay(day, endTOD, loc, later, "override "+o.ID)
+		}
+		if o.Type == models.OverrideBlackout {
+			blocks = append(blocks, blockWindow{start: start, end: end, source: "override:" + o.ID})
+			continue
+		}
+		for _, sp := range localSpans(start, end, loc, repeatedHour) {
+			windows = append(windows, slotWindow{
+				start:   sp[0],
+				end:     sp[1],
+				slotLen: time.Duration(o.SlotLengthMins) * time.Minute,
+				title:   o.Title,
+				source:  "override:" + o.ID,
+			})
+		}
+	}
+	return windows, blocks, nil
+}
+
+// Policies for the hour a fall-back DST change repeats, chosen by
+// AvailabilityService.DSTRepeatedHour. A spring-forward gap has no policy:
+// its wall-clock times do not exist, so windows skip it (see atTimeOfDay).
+const (
+	// DSTRepeatedHourBoth offers slots in both passes of the repeated hour,
+	// so the day has an hour's more slots. It is the default.
+	DSTRepeatedHourBoth = "both"
+	// DSTRepeatedHourFirst offers slots in the first pass only, so every
+	// wall-clock slot time occurs once.
+	DSTRepeatedHourFirst = "first"
+)
+
+// dstGapNoted records the (subject, date, time) gaps already logged.
+var dstGapNoted sync.Map
+
+// atTimeOfDay returns the instant at wall-clock tod on day's date in loc, in
+// UTC. A time in a spring-forward gap does not exist: it resolves to the end
+// of the gap, so a window with an edge inside it skips the missing part, and
+// a note naming subject is logged once. A time in the repeated fall-back hour
+// resolves to its first occurrence, or its second when later is set, so a
+// window ending in that hour can cover both passes.
+func atTimeOfDay(day, tod time.Time, loc *time.Location, later bool, subject string) time.Time {
+	y, m, d := day.Date()
+	t := time.Date(y, m, d, tod.Hour(), tod.Minute(), 0, 0, loc)
+	if t.Hour() != tod.Hour() || t.Minute() != tod.Minute() {
+		// time.Date moves a nonexistent time by the gap, in either direction
+		start, end := t.ZoneBounds()
+		gapEnd := end
+		if t.Day() == d && t.Hour()*60+t.Minute() > tod.Hour()*60+tod.Minute() {
+			gapEnd = start
+		}
+		key := fmt.Sprintf("%s %s %s %s", subject, loc, t.Format("2006-01-02"), tod.Format("15:04"))
+		if _, noted := dstGapNoted.LoadOrStore(key, true); !noted && subject != "" {
+			log.Printf("%s: %s on %04d-%02d-%02d does not exist in %s, skipping to %s",
+				subject, tod.Format("15:04"), y, m, d, loc, gapEnd.In(loc).Format("15:04 MST"))
+		}
+		return gapEnd.UTC()
+	}
+	_, off := t.Zone()
+	start, end := t.ZoneBounds()
+	if later && !end.IsZero() {
+		if _, next := end.Zone(); next < off {
+			// the same wall clock comes round again after the change; at the
+			// change itself the repeated hour has only just begun
+			if u := t.Add(time.Duration(off-next) * time.Second); u.After(end) {
+				return u.UTC()
+			}
+		}
+	}
+	if !later && !start.IsZero() {
+		if _, prev := start.Add(-time.Second).Zone(); prev > off {
+			if u := t.Add(-time.Duration(prev-off) * time.Second); u.Before(start) {
+				return u.UTC()
+			}
+		}
+	}
+	return t.UTC()
+}
+
+// localSpans returns [start, end) in loc as the spans slots are cut from.
+// Under DSTRepeatedHourFirst the second pass of a repeated hour is cut out;
+// otherwise the window is kept whole and cut in elapsed time, so it loses any
+// hour skipped inside it and gains any repeated one.
+func localSpans(start, end time.Time, loc *time.Location, repeatedHour string) [][2]time.Time {
+	if !start.Before(end) {
+		return nil
+	}
+	if repeatedHour != DSTRepeatedHourFirst {
+		return [][2]time.Time{{start, end}}
+	}
+	var spans [][2]time.Time
+	from := start
+	for t := start.In(loc); ; {
+		_, off := t.Zone()
+		_, change := t.ZoneBounds()
+		if change.IsZero() || !change.Before(end) {
+			break
+		}
+		if _, next := change.Zone(); next < off {
+			if from.Before(change) {
+				spans = append(spans, [2]time.Time{from, change.UTC()})
+			}
+			if repeatEnd := change.Add(time.Duration(off-next) * time.Second); repeatEnd.After(from) {
+				from = repeatEnd.UTC()
+			}
+		}
+		t = change
+	}
+	if from.Before(end) {
+		spans = append(spans, [2]time.Time{from, end})
+	}
+	return spans
+}
+
+// planningDates returns the first and last UTC dates to plan for
+// [fromUTC, toUTC): one day either side of the range, since a rule in a
+// timezone away from UTC can reach into it from a neighbouring date.
+func planningDates(fromUTC, toUTC time.Time) (time.Time, time.Time) {
+	return fromUTC.Truncate(24 * time.Hour).Add(-24 * time.Hour), toUTC.Truncate(24 * time.Hour).Add(24 * time.Hour)
+}
+
+// planRange runs planDay for each date from startDate to endDate inclusive
+// and collects the windows and blackouts of all of them.
+func planRange(startDate, endDate time.Time, rules []models.AvailabilityRule, overrides []models.ScheduleOverride, repeatedHour string) ([]slotWindow, []blockWindow, error) {
+	var windows []slotWindow
+	var blocks []blockWindow
+	for day := startDate; !day.After(endDate); day = day.Add(24 * time.Hour) {
+		w, b, err := planDay(day, rules, overrides, repeatedHour)
+		if err != nil {
+			return nil, nil, err
+		}
+		windows = append(windows, w...)
+		blocks = append(blocks, b...)
+	}
+	return windows, blocks, nil
+}
+
+// overrideLocation returns the zone date-keyed overrides are laid out in: the
+// Timezone all of the user's rules share, so a Dec 25 blackout covers Dec 25
+// where the user works. Without rules, with rules in several zones, or when
+// the zone fails to load, overrides stay on UTC dates.
+func overrideLocation(rules []models.AvailabilityRule) *time.Location {
+	if len(rules) == 0 {
+		return time.UTC
+	}
+	for _, r := range rules[1:] {
+		if r.Timezone != rules[0].Timezone {
+			return time.UTC
+		}
+	}
+	loc, fallback := planLocation(rules[0])
+	if fallback {
+		return time.UTC
+	}
+	return loc
+}
+
+var ruleLocations sync.Map // IANA name -> *time.Location
+
+// loadLocation is time.LoadLocation, replaced in tests to simulate a zone
+// missing from the host's tzdata.
+var loadLocation = time.LoadLocation
+
+// tzFallbackWarned records the zones a fallback was already logged for.
+var tzFallbackWarned sync.Map
+
+// planLocation returns the location r's times are laid out in. Zones are
+// validated when rules are written, but one can still be missing from the
+// host's tzdata at generation time; the rule then falls back to UTC, with a
+// warning logged once per zone, and fallback reports it so its slots can be
+// marked rather than failing the whole request.
+func planLocation(r models.AvailabilityRule) (*time.Location, bool) {
+	loc, err := ruleLocation(r.Timezone)
+	if err == nil {
+		return loc, false
+	}
+	if _, warned := tzFallbackWarned.LoadOrStore(r.Timezone, true); !warned {
+		log.Printf("availability rule %s: %v, laying out its slots in UTC", r.ID, err)
+	}
+	return time.UTC, true
+}
+
+// ruleLocation loads a rule's timezone, caching the result; empty is UTC.
+func ruleLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	if loc, ok := ruleLocations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	// "Local" would depend on the server's zone
+	loc, err := loadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	ruleLocations.Store(name, loc)
+	return loc, nil
+}
+
+func overlapsBlock(start, end time.Time, blocks []blockWindow) bool {
+	for _, b := range blocks {
+		if start.Before(b.end) && end.After(b.start) {
+			return true
+		}
+	}
+	return false
+}
+
+// listOverrides loads the overrides intersecting the UTC dates of
+// [fromUTC, toUTC], plus a whole-day blackout for each public holiday of the
+// user's holiday region. Stored overrides are skipped when not enabled.
+func (s *AvailabilityService) listOverrides(ctx context.Context, userID string, fromUTC, toUTC time.Time) ([]models.ScheduleOverride, error) {
+	var out []models.ScheduleOverride
+	if s.Overrides != nil {
+		stored, err := s.Overrides.ListOverridesInRange(ctx, s.DB, userID, fromUTC.Format("2006-01-02"), toUTC.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		out = stored
+	}
+	if s.Exceptions != nil {
+		exceptions, err := s.Exceptions.ListExceptionsInRange(ctx, s.DB, userID, fromUTC.Format("2006-01-02"), toUTC.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		for i := range exceptions {
+			out = append(out, *exceptionOverride(&exceptions[i]))
+		}
+	}
+	st, err := s.userSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if st != nil && st.HolidayRegion != "" {
+		// Holidays of the user's region black out the whole day
+		for _, h := range holidays.InRange(st.HolidayRegion, fromUTC, toUTC) {
+			d := h.Date.Format("2006-01-02")
+			out = append(out, models.ScheduleOverride{ID: "holiday:" + st.HolidayRegion + ":" + d, UserID: userID, Type: models.OverrideBlackout, StartDate: d, EndDate: d, Title: h.Name})
+		}
+	}
+	return out, nil
+}
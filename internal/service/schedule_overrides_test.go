@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestScheduleOverridePrecedence(t *testing.T) {
+	custom := func(start, end string, mins int) models.ScheduleOverride {
+		return models.ScheduleOverride{Type: models.OverrideCustomHours, StartDate: "2026-03-02", StartTime: start, EndTime: end, SlotLengthMins: mins}
+	}
+	extra := func(start, end string) models.ScheduleOverride {
+		return models.ScheduleOverride{Type: models.OverrideExtraHours, StartDate: "2026-03-02", StartTime: start, EndTime: end, SlotLengthMins: 30}
+	}
+	blackout := func(startDate, endDate, start, end string) models.ScheduleOverride {
+		return models.ScheduleOverride{Type: models.OverrideBlackout, StartDate: startDate, EndDate: endDate, StartTime: start, EndTime: end}
+	}
+	cases := []struct {
+		name      string
+		overrides []models.ScheduleOverride
+		want      []string
+	}{
+		{"weekly rule only", nil, []string{"09:00", "09:30", "10:00", "10:30", "11:00", "11:30"}},
+		{"custom hours replace the rule", []models.ScheduleOverride{custom("14:00", "15:00", 30)}, []string{"14:00", "14:30"}},
+		{"extra hours add to the rule", []models.ScheduleOverride{extra("16:00", "17:00")},
+			[]string{"09:00", "09:30", "10:00", "10:30", "11:00", "11:30", "16:00", "16:30"}},
+		{"extra hours add to custom hours", []models.ScheduleOverride{custom("14:00", "15:00", 30), extra("16:00", "17:00")},
+			[]string{"14:00", "14:30", "16:00", "16:30"}},
+		{"partial blackout cuts the rule", []models.ScheduleOverride{blackout("2026-03-02", "", "10:00", "11:00")},
+			[]string{"09:00", "09:30", "11:00", "11:30"}},
+		{"partial blackout cuts custom hours", []models.ScheduleOverride{custom("09:00", "12:00", 60), blackout("2026-03-02", "", "10:00", "11:00")},
+			[]string{"09:00", "11:00"}},
+		{"blackout wins over extra hours", []models.ScheduleOverride{extra("16:00", "17:00"), blackout("2026-03-02", "", "", "")}, nil},
+		{"blackout range wins over overlapping custom hours", []models.ScheduleOverride{blackout("2026-03-01", "2026-03-03", "", ""), custom("14:00", "15:00", 30)}, nil},
+		{"blackout range on other days", []models.ScheduleOverride{blackout("2026-03-03", "2026-03-05", "", "")},
+			[]string{"09:00", "09:30", "10:00", "10:30", "11:00", "11:30"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, _ := newFakeServices(monday)
+			s.Overrides = &fakeOverrideRepo{}
+			addRule(t, s, "u1", time.Monday, "09:00", "12:00", 30)
+			for _, o := range tc.overrides {
+				o := o
+				if _, err := s.CreateOverride(ctx, "u1", &o); err != nil {
+					t.Fatal(err)
+				}
+			}
+			slots, err := s.GenerateAvailableSlots(ctx, "u1", monday, monday.Add(24*time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := slotStarts(slots); len(got)+len(tc.want) > 0 && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("slots = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
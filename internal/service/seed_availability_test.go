@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"scheduler-service/internal/models"
+)
+
+func TestGenerateAPIKeySeedsDefaultAvailability(t *testing.T) {
+	cases := []struct {
+		name      string
+		seed      bool
+		keys      []models.APIKey
+		rules     []models.AvailabilityRule
+		wantRules int
+	}{
+		{"brand-new user", true, nil, nil, 5},
+		{"seeding disabled", false, nil, nil, 0},
+		{"regenerated key", true, []models.APIKey{{ID: "key-1", Email: "a@example.com", Scopes: []string{ScopeAll}}}, nil, 0},
+		{"new key, user already has rules", true, nil, []models.AvailabilityRule{{ID: "rule-0", UserID: "key-1", DayOfWeek: 6, StartTime: "10:00", EndTime: "11:00", SlotLengthMins: 60, Available: true}}, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail := &fakeAvailabilityRepo{rules: tc.rules}
+			s := &APIKeyService{DB: fakeDB{}, Repo: &fakeAPIKeyRepo{keys: tc.keys}}
+			if tc.seed {
+				s.Avail, s.DefaultAvailability = avail, DefaultWeeklyAvailability()
+			}
+
+			_, rec, err := s.GenerateAPIKey(context.Background(), "a@example.com", "pw", nil, nil, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rules, err := avail.ListAvailabilityRules(context.Background(), nil, rec.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rules) != tc.wantRules {
+				t.Fatalf("user has %d rules, want %d", len(rules), tc.wantRules)
+			}
+			if tc.wantRules != 5 {
+				return
+			}
+			for i, r := range rules {
+				if r.DayOfWeek != i+1 || r.StartTime != "09:00" || r.EndTime != "17:00" || r.SlotLengthMins != 30 || !r.Available {
+					t.Errorf("seeded rule %d = %+v, want Mon-Fri 09:00-17:00 in 30-min slots", i, r)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+// newSkipPastService builds a user with weekday rules, a zoned weekend rule
+// with a buffer, and bookings and holds spread over [from, from+days).
+func newSkipPastService(t testing.TB, from time.Time, days int) *AvailabilityService {
+	s, _ := newFakeServices(from)
+	for d := time.Monday; d <= time.Friday; d++ {
+		addRule(t, s, "u1", d, "09:00", "17:00", 30)
+	}
+	zoned := models.AvailabilityRule{UserID: "u1", DayOfWeek: int(time.Saturday), StartTime: "10:00", EndTime: "12:00", SlotLengthMins: 45, BufferMins: 10, Timezone: "America/New_York", Available: true}
+	if err := s.Avail.InsertAvailabilityRule(context.Background(), nil, &zoned); err != nil {
+		t.Fatal(err)
+	}
+	books := s.Book.(*fakeBookingRepo)
+	holds := &fakeHoldRepo{}
+	for d := 0; d < days; d += 3 {
+		start := from.AddDate(0, 0, d).Add(10*time.Hour + 15*time.Minute)
+		books.bookings[start.String()] = &models.Booking{ID: start.String(), UserID: "u1", Status: "confirmed", StartAtUTC: start, EndAtUTC: start.Add(40 * time.Minute)}
+		hold := from.AddDate(0, 0, d+1).Add(14 * time.Hour)
+		holds.holds = append(holds.holds, models.SlotHold{UserID: "u1", StartAtUTC: hold, EndAtUTC: hold.Add(5 * time.Minute)})
+	}
+	s.Holds = holds
+	return s
+}
+
+func TestSkipPastSlotsMatchesFilteredListing(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 28)
+	s := newSkipPastService(t, from, 28)
+
+	nows := []time.Time{
+		from.Add(-time.Hour),                                      // before the range
+		from.AddDate(0, 0, 9).Add(10 * time.Hour),                 // on a slot boundary
+		from.AddDate(0, 0, 10).Add(14*time.Hour + 10*time.Minute), // inside a slot, just after a hold
+		from.AddDate(0, 0, 13).Add(15*time.Hour + 20*time.Minute), // inside a zoned slot
+		from.AddDate(0, 0, 15),                                    // midnight
+		to.Add(time.Hour),                                         // after the range
+	}
+	for _, now := range nows {
+		s.Clock = FixedClock(now)
+		s.SkipPastSlots = false
+		all, err := s.GenerateAvailableSlots(ctx, "u1", from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want []Slot
+		for _, sl := range all {
+			if sl.EndUTC.After(now) {
+				want = append(want, sl)
+			}
+		}
+
+		s.SkipPastSlots = true
+		got, err := s.GenerateAvailableSlots(ctx, "u1", from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("now %s: skipping gave %d slots, filtering %d", now, len(got), len(want))
+		}
+
+		past, err := s.GenerateAvailableSlots(WithFlags(ctx, Flags{IncludePast: true}), "u1", from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(past, all) {
+			t.Errorf("now %s: include_past gave %d slots, want all %d", now, len(past), len(all))
+		}
+	}
+}
+
+// BenchmarkGenerateAvailableSlotsPastRange lists 90 days of which all but the
+// last week are over, with and without skipping the past.
+func BenchmarkGenerateAvailableSlotsPastRange(b *testing.B) {
+	ctx := context.Background()
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 90)
+	s := newSkipPastService(b, from, 90)
+	s.Clock = FixedClock(to.AddDate(0, 0, -7))
+	for _, skip := range []bool{false, true} {
+		name := "full"
+		if skip {
+			name = "skip_past"
+		}
+		b.Run(name, func(b *testing.B) {
+			s.SkipPastSlots = skip
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := s.GenerateAvailableSlots(ctx, "u1", from, to); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
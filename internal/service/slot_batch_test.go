@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+// countingAvailabilityRepo tracks how many rule lookups run at once, holding
+// each open briefly so overlapping generations show up.
+type countingAvailabilityRepo struct {
+	*fakeAvailabilityRepo
+	inFlight, peak atomic.Int32
+}
+
+func (r *countingAvailabilityRepo) ListAvailabilityRules(ctx context.Context, q repository.Querier, userID string) ([]models.AvailabilityRule, error) {
+	n := r.inFlight.Add(1)
+	defer r.inFlight.Add(-1)
+	for {
+		p := r.peak.Load()
+		if n <= p || r.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	if userID == "broken" {
+		return nil, errors.New("lookup failed")
+	}
+	return r.fakeAvailabilityRepo.ListAvailabilityRules(ctx, q, userID)
+}
+
+func TestBatchSlotsMatchSerialAndBoundConcurrency(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(monday)
+	repo := &countingAvailabilityRepo{fakeAvailabilityRepo: s.Avail.(*fakeAvailabilityRepo)}
+	s.Avail = repo
+
+	var userIDs []string
+	for i := 0; i < 12; i++ {
+		id := fmt.Sprintf("u%d", i)
+		userIDs = append(userIDs, id)
+		addRule(t, s, id, time.Monday, fmt.Sprintf("%02d:00", 8+i%6), "16:00", 15+15*(i%3))
+	}
+	userIDs = append(userIDs[:5], append([]string{"broken"}, userIDs[5:]...)...)
+	from, to := monday, monday.AddDate(0, 0, 7)
+
+	var serial []UserSlots
+	for _, id := range userIDs {
+		slots, err := s.GenerateAvailableSlots(ctx, id, from, to)
+		us := UserSlots{UserID: id, Slots: slots}
+		if err != nil {
+			us = UserSlots{UserID: id, Error: err.Error()}
+		}
+		serial = append(serial, us)
+	}
+
+	s.BatchConcurrency = 3
+	repo.peak.Store(0)
+	got := s.GenerateAvailableSlotsBatch(ctx, userIDs, from, to)
+	if !reflect.DeepEqual(got, serial) {
+		t.Errorf("batch results differ from serial generation")
+	}
+	if got[5].UserID != "broken" || got[5].Error == "" {
+		t.Errorf("failed user reported as %+v", got[5])
+	}
+	if p := repo.peak.Load(); p > 3 || p < 2 {
+		t.Errorf("peak concurrency %d, want 2..3", p)
+	}
+}
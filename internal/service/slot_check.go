@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// Reasons reported by CheckSlots for an unavailable slot.
+const (
+	SlotReasonInvalid    = "invalid_range"
+	SlotReasonNotOffered = "outside_availability"
+	SlotReasonBooked     = "booked"
+	SlotReasonHeld       = "held"
+)
+
+// slotCheckBookingMargin widens the booking lookup backwards so bookings that
+// start before the checked span but run into it are still seen.
+const slotCheckBookingMargin = 24 * time.Hour
+
+// SlotCheck is one slot to check in CheckSlots.
+type SlotCheck struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// SlotCheckResult reports whether the slot at the same index is bookable.
+type SlotCheckResult struct {
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// CheckSlots reports bookability for each requested slot using one slot
+// generation and one booking/hold lookup over the span of all checks. A slot
+// is bookable when it exactly matches a generated slot and overlaps no
+// confirmed booking or active hold.
+func (s *AvailabilityService) CheckSlots(ctx context.Context, userID string, checks []SlotCheck) ([]SlotCheckResult, error) {
+	out := make([]SlotCheckResult, len(checks))
+	var from, to time.Time
+	for _, ch := range checks {
+		if !ch.End.After(ch.Start) {
+			continue
+		}
+		start, end := ch.Start.UTC(), ch.End.UTC()
+		if from.IsZero() || start.Before(from) {
+			from = start
+		}
+		if to.IsZero() || end.After(to) {
+			to = end
+		}
+	}
+	if from.IsZero() {
+		for i := range out {
+			out[i].Reason = SlotReasonInvalid
+		}
+		return out, nil
+	}
+
+	slots, err := s.generateSlots(ctx, userID, from, to, slotOptions{ignoreHolds: true, ignoreBookings: true})
+	if err != nil {
+		return nil, err
+	}
+	offered := map[[2]int64]struct{}{}
+	for _, sl := range slots {
+		offered[[2]int64{sl.StartUTC.Unix(), sl.EndUTC.Unix()}] = struct{}{}
+	}
+	bookings, err := s.Book.ListBookingsInRange(ctx, s.DB, userID, from.Add(-slotCheckBookingMargin), to)
+	if err != nil {
+		return nil, err
+	}
+	var holdWindows [][2]time.Time
+	if s.Holds != nil {
+		holds, err := s.Holds.ListActiveHolds(ctx, s.DB, userID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range holds {
+			holdWindows = append(holdWindows, [2]time.Time{h.StartAtUTC, h.EndAtUTC})
+		}
+	}
+
+	for i, ch := range checks {
+		start, end := ch.Start.UTC(), ch.End.UTC()
+		if !end.After(start) {
+			out[i].Reason = SlotReasonInvalid
+			continue
+		}
+		if _, ok := offered[[2]int64{start.Unix(), end.Unix()}]; !ok {
+			out[i].Reason = SlotReasonNotOffered
+			continue
+		}
+		reason := ""
+		for _, b := range bookings {
+			if start.Before(b.EndAtUTC) && end.After(b.StartAtUTC) {
+				reason = SlotReasonBooked
+				break
+			}
+		}
+		if reason == "" {
+			for _, w := range holdWindows {
+				if start.Before(w[1]) && end.After(w[0]) {
+					reason = SlotReasonHeld
+					break
+				}
+			}
+		}
+		out[i] = SlotCheckResult{Available: reason == "", Reason: reason}
+	}
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestCheckSlotsMixOfAvailableAndTaken(t *testing.T) {
+	avail, _ := newFakeServices(monday)
+	addRule(t, avail, "u1", time.Monday, "09:00", "11:00", 30)
+	avail.Book = newFakeBookingRepo(models.Booking{ID: "b1", UserID: "u1", StartAtUTC: at(9, 30), EndAtUTC: at(10, 0)})
+	avail.Holds = &fakeHoldRepo{holds: []models.SlotHold{{UserID: "u1", StartAtUTC: at(10, 0), EndAtUTC: at(10, 30), ExpiresAt: monday.Add(defaultHoldTTL)}}}
+
+	cases := []struct {
+		name  string
+		check SlotCheck
+		want  SlotCheckResult
+	}{
+		{"free slot", SlotCheck{at(9, 0), at(9, 30)}, SlotCheckResult{Available: true}},
+		{"booked slot", SlotCheck{at(9, 30), at(10, 0)}, SlotCheckResult{Reason: SlotReasonBooked}},
+		{"held slot", SlotCheck{at(10, 0), at(10, 30)}, SlotCheckResult{Reason: SlotReasonHeld}},
+		{"last free slot", SlotCheck{at(10, 30), at(11, 0)}, SlotCheckResult{Available: true}},
+		{"misaligned slot", SlotCheck{at(9, 15), at(9, 45)}, SlotCheckResult{Reason: SlotReasonNotOffered}},
+		{"outside the rule", SlotCheck{at(12, 0), at(12, 30)}, SlotCheckResult{Reason: SlotReasonNotOffered}},
+		{"end before start", SlotCheck{at(9, 30), at(9, 0)}, SlotCheckResult{Reason: SlotReasonInvalid}},
+	}
+	checks := make([]SlotCheck, len(cases))
+	for i, tc := range cases {
+		checks[i] = tc.check
+	}
+	got, err := avail.CheckSlots(context.Background(), "u1", checks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(cases) {
+		t.Fatalf("got %d results for %d checks", len(got), len(cases))
+	}
+	for i, tc := range cases {
+		if got[i] != tc.want {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got[i], tc.want)
+		}
+	}
+}
+
+func TestCheckSlotsAllInvalid(t *testing.T) {
+	avail, _ := newFakeServices(monday)
+	got, err := avail.CheckSlots(context.Background(), "u1", []SlotCheck{{monday, monday}, {monday.Add(time.Hour), monday}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range got {
+		if r != (SlotCheckResult{Reason: SlotReasonInvalid}) {
+			t.Errorf("check %d = %+v, want %s", i, r, SlotReasonInvalid)
+		}
+	}
+}
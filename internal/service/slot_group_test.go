@@ -0,0 +1,44 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGroupSlotsByDay(t *testing.T) {
+	utc := func(day, h, m int) Slot {
+		start := time.Date(2026, 3, day, h, m, 0, 0, time.UTC)
+		return Slot{StartUTC: start, EndUTC: start.Add(30 * time.Minute)}
+	}
+	// 23:30 on the 2nd to 01:00 on the 3rd UTC
+	slots := []Slot{utc(2, 23, 30), utc(3, 0, 0), utc(3, 0, 30)}
+	cases := []struct {
+		tz   string
+		want map[string][]string
+	}{
+		{"UTC", map[string][]string{"2026-03-02": {"23:30"}, "2026-03-03": {"00:00", "00:30"}}},
+		// UTC-8: all three are the evening of the 2nd
+		{"America/Los_Angeles", map[string][]string{"2026-03-02": {"23:30", "00:00", "00:30"}}},
+		// UTC+5:30: all three are the morning of the 3rd
+		{"Asia/Kolkata", map[string][]string{"2026-03-03": {"23:30", "00:00", "00:30"}}},
+		// UTC+1: 23:30 UTC is already 00:30 on the 3rd
+		{"Europe/Berlin", map[string][]string{"2026-03-03": {"23:30", "00:00", "00:30"}}},
+	}
+	for _, tc := range cases {
+		loc, err := time.LoadLocation(tc.tz)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string][]string{}
+		for day, daySlots := range GroupSlotsByDay(slots, loc) {
+			got[day] = slotStarts(daySlots)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: groups = %v, want %v", tc.tz, got, tc.want)
+		}
+	}
+	if got := GroupSlotsByDay(nil, time.UTC); len(got) != 0 {
+		t.Errorf("no slots grouped into %v", got)
+	}
+}
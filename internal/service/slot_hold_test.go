@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newHoldServices gives u1 one Monday slot at 09:00 and holds that expire by
+// the returned clock, which tests advance to age them.
+func newHoldServices(t *testing.T, monday time.Time) (*AvailabilityService, *BookingService, *time.Time) {
+	avail, s := newFakeServices(monday)
+	addRule(t, avail, "u1", time.Monday, "09:00", "09:30", 30)
+	now := monday
+	holds := &fakeHoldRepo{now: func() time.Time { return now }}
+	avail.Holds, s.Holds = holds, holds
+	return avail, s, &now
+}
+
+func TestSlotHoldConvertAndExpiry(t *testing.T) {
+	start := monday.Add(9 * time.Hour)
+	cases := []struct {
+		name       string
+		elapsed    time.Duration
+		token      string // "hold" uses the hold's token
+		wantListed bool
+		wantErr    string
+	}{
+		{"convert the hold", time.Minute, "hold", false, ""},
+		{"book without the token", time.Minute, "", false, "slot is held"},
+		{"book with another token", time.Minute, "hold_other", false, "invalid or expired hold"},
+		{"expired hold frees the slot", 6 * time.Minute, "", true, ""},
+		{"convert an expired hold", 6 * time.Minute, "hold", true, "invalid or expired hold"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			avail, s, now := newHoldServices(t, monday)
+			hold, err := s.HoldSlot(ctx, "u1", start, start.Add(30*time.Minute))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !hold.ExpiresAt.Equal(monday.Add(defaultHoldTTL)) || hold.Token == "" {
+				t.Fatalf("hold = %+v, want a token expiring after %s", hold, defaultHoldTTL)
+			}
+			*now = monday.Add(tc.elapsed)
+
+			slots, err := avail.GenerateAvailableSlots(ctx, "u1", monday, monday.Add(24*time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if listed := len(slots) == 1; listed != tc.wantListed {
+				t.Errorf("slot listed = %v, want %v", listed, tc.wantListed)
+			}
+
+			token := tc.token
+			if token == "hold" {
+				token = hold.Token
+			}
+			b, err := s.CreateBooking(ctx, "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: start, End: start.Add(30 * time.Minute), HoldToken: token})
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+			if err == nil && b.Status != "confirmed" {
+				t.Errorf("booking status = %q, want confirmed", b.Status)
+			}
+		})
+	}
+}
+
+func TestHoldSlotTwice(t *testing.T) {
+	start := monday.Add(9 * time.Hour)
+	cases := []struct {
+		name    string
+		elapsed time.Duration
+		wantErr string
+	}{
+		{"while the first is active", 4 * time.Minute, "slot is held"},
+		{"after the first expired", 5 * time.Minute, ""},
+	}
+	for _, tc := range cases {
+		ctx := context.Background()
+		_, s, now := newHoldServices(t, monday)
+		if _, err := s.HoldSlot(ctx, "u1", start, start.Add(30*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		*now = monday.Add(tc.elapsed)
+		_, err := s.HoldSlot(ctx, "u1", start, start.Add(30*time.Minute))
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.wantErr)
+		}
+		if n := len(s.Holds.(*fakeHoldRepo).holds); n != 1 {
+			t.Errorf("%s: %d holds stored, want 1", tc.name, n)
+		}
+	}
+}
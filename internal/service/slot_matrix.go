@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SlotMatrix is a team availability grid: one row per step-long cell, with
+// Available[i] reporting whether UserIDs[i] is free for the whole cell.
+type SlotMatrix struct {
+	UserIDs  []string         `json:"user_ids"`
+	StepMins int              `json:"step_minutes"`
+	Timezone string           `json:"tz"`
+	Cells    []SlotMatrixCell `json:"cells"`
+}
+
+// SlotMatrixCell is one row of a SlotMatrix.
+type SlotMatrixCell struct {
+	StartUTC   time.Time `json:"start_utc"`
+	EndUTC     time.Time `json:"end_utc"`
+	StartLocal string    `json:"start_local"`
+	Available  []bool    `json:"available"`
+	FreeCount  int       `json:"free_count"`
+}
+
+// AvailabilityMatrix generates each user's slots over [fromUTC, toUTC) and
+// lays them on a common grid of step-long cells. Cells are aligned to step
+// boundaries counted from local midnight in loc, so a 30 minute grid in
+// Asia/Kolkata starts on :00 and :30 local time. A user is free in a cell
+// when their slots cover it without a gap.
+func (s *AvailabilityService) AvailabilityMatrix(ctx context.Context, userIDs []string, fromUTC, toUTC time.Time, step time.Duration, loc *time.Location) (*SlotMatrix, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	free := make([][]Slot, len(userIDs))
+	for i, res := range s.GenerateAvailableSlotsBatch(ctx, userIDs, fromUTC, toUTC) {
+		if res.Error != "" {
+			return nil, fmt.Errorf("user %s: %s", res.UserID, res.Error)
+		}
+		free[i] = mergeSlotSpans(res.Slots)
+	}
+
+	out := &SlotMatrix{UserIDs: userIDs, StepMins: int(step / time.Minute), Timezone: loc.String(), Cells: []SlotMatrixCell{}}
+	for start := alignToStep(fromUTC, step, loc); start.Before(toUTC); start = start.Add(step) {
+		end := start.Add(step)
+		if start.Before(fromUTC) || end.After(toUTC) {
+			continue
+		}
+		cell := SlotMatrixCell{StartUTC: start, EndUTC: end, StartLocal: start.In(loc).Format(time.RFC3339), Available: make([]bool, len(userIDs))}
+		for i, spans := range free {
+			if spansCover(spans, start, end) {
+				cell.Available[i] = true
+				cell.FreeCount++
+			}
+		}
+		out.Cells = append(out.Cells, cell)
+	}
+	return out, nil
+}
+
+// alignToStep returns the latest step boundary, counted from local midnight
+// in loc, at or before t.
+func alignToStep(t time.Time, step time.Duration, loc *time.Location) time.Time {
+	local := t.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	return midnight.Add(t.Sub(midnight) / step * step).UTC()
+}
+
+// mergeSlotSpans sorts slots and joins those that touch or overlap into
+// continuous spans.
+func mergeSlotSpans(slots []Slot) []Slot {
+	sorted := append([]Slot(nil), slots...)
+	SortSlots(sorted)
+	var spans []Slot
+	for _, sl := range sorted {
+		if n := len(spans); n > 0 && !sl.StartUTC.After(spans[n-1].EndUTC) {
+			if sl.EndUTC.After(spans[n-1].EndUTC) {
+				spans[n-1].EndUTC = sl.EndUTC
+			}
+			continue
+		}
+		spans = append(spans, Slot{StartUTC: sl.StartUTC, EndUTC: sl.EndUTC})
+	}
+	return spans
+}
+
+// spansCover reports whether one of the sorted, merged spans contains
+// [start, end).
+func spansCover(spans []Slot, start, end time.Time) bool {
+	for _, sp := range spans {
+		if sp.StartUTC.After(start) {
+			return false
+		}
+		if !sp.EndUTC.Before(end) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestAvailabilityMatrixMarksFreeUsers(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(monday)
+	addRule(t, s, "u1", time.Monday, "09:00", "10:00", 30)
+	addRule(t, s, "u2", time.Monday, "09:30", "11:00", 30)
+	booked := monday.Add(10 * time.Hour)
+	s.Book.(*fakeBookingRepo).bookings["b1"] = &models.Booking{ID: "b1", UserID: "u2", Status: "confirmed", StartAtUTC: booked, EndAtUTC: booked.Add(30 * time.Minute)}
+
+	m, err := s.AvailabilityMatrix(ctx, []string{"u1", "u2"}, monday.Add(9*time.Hour), monday.Add(11*time.Hour), 30*time.Minute, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		start string
+		free  []bool
+	}{
+		{"09:00", []bool{true, false}},
+		{"09:30", []bool{true, true}},
+		{"10:00", []bool{false, false}},
+		{"10:30", []bool{false, true}},
+	}
+	if len(m.Cells) != len(want) {
+		t.Fatalf("%d cells, want %d", len(m.Cells), len(want))
+	}
+	for i, w := range want {
+		cell := m.Cells[i]
+		if got := cell.StartUTC.Format("15:04"); got != w.start {
+			t.Errorf("cell %d starts %s, want %s", i, got, w.start)
+		}
+		if !reflect.DeepEqual(cell.Available, w.free) {
+			t.Errorf("cell %s: available %v, want %v", w.start, cell.Available, w.free)
+		}
+		n := 0
+		for _, f := range w.free {
+			if f {
+				n++
+			}
+		}
+		if cell.FreeCount != n {
+			t.Errorf("cell %s: free_count %d, want %d", w.start, cell.FreeCount, n)
+		}
+	}
+
+	// An hourly grid in Kolkata (UTC+5:30) starts cells on the local hour
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skip(err)
+	}
+	m, err = s.AvailabilityMatrix(ctx, []string{"u1", "u2"}, monday.Add(9*time.Hour), monday.Add(11*time.Hour), time.Hour, kolkata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Cells) != 1 || m.Cells[0].StartLocal != "2026-03-02T15:00:00+05:30" {
+		t.Fatalf("cells = %+v, want one starting 15:00 local", m.Cells)
+	}
+	if !reflect.DeepEqual(m.Cells[0].Available, []bool{false, false}) {
+		t.Errorf("09:30-10:30 UTC: available %v, want neither", m.Cells[0].Available)
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestSlotsWithStartOffset(t *testing.T) {
+	cases := []struct {
+		name       string
+		start, end string
+		mins       int
+		offset     int
+		want       []string
+	}{
+		{"no offset", "09:00", "10:00", 30, 0, []string{"09:00", "09:30"}},
+		{"5-minute offset", "09:00", "10:00", 30, 5, []string{"09:05"}},
+		{"5-minute offset, last slot fits", "09:00", "10:05", 30, 5, []string{"09:05", "09:35"}},
+		{"5-minute offset, 15-minute slots", "09:00", "10:00", 15, 5, []string{"09:05", "09:20", "09:35"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newFakeServices(monday)
+			rule := models.AvailabilityRule{UserID: "u1", DayOfWeek: int(time.Monday), StartTime: tc.start, EndTime: tc.end, SlotLengthMins: tc.mins, StartOffsetMins: tc.offset, Available: true}
+			if err := s.Avail.InsertAvailabilityRule(context.Background(), s.DB, &rule); err != nil {
+				t.Fatal(err)
+			}
+			slots, err := s.GenerateAvailableSlots(context.Background(), "u1", monday, monday.Add(24*time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := slotStarts(slots); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("slots = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateStartOffset(t *testing.T) {
+	cases := []struct {
+		name    string
+		offset  int
+		wantErr string
+	}{
+		{"no offset", 0, ""},
+		{"5 minutes", 5, ""},
+		{"exactly one slot left", 30, ""},
+		{"no room for a slot", 31, "start_offset_minutes leaves no room for a slot before end_time"},
+		{"negative", -5, "start_offset_minutes must not be negative"},
+	}
+	for _, tc := range cases {
+		rule := models.AvailabilityRule{DayOfWeek: 1, StartTime: "09:00", EndTime: "10:00", SlotLengthMins: 30, StartOffsetMins: tc.offset, Available: true}
+		err := validateAvailabilityRule(&rule)
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.wantErr)
+		}
+	}
+}
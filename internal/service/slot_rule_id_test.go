@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestSlotsCarrySourceRuleID(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newFakeServices(monday)
+	s.Overrides = &fakeOverrideRepo{}
+	morning := addRule(t, s, "u1", time.Monday, "09:00", "10:00", 30)
+	afternoon := addRule(t, s, "u1", time.Monday, "14:00", "15:00", 60)
+	split := models.AvailabilityRule{UserID: "u1", DayOfWeek: int(time.Tuesday), SlotLengthMins: 60, Available: true,
+		Windows: []models.TimeWindow{{StartTime: "09:00", EndTime: "10:00"}, {StartTime: "13:00", EndTime: "14:00"}}}
+	if err := validateAvailabilityRule(&split); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Avail.InsertAvailabilityRule(ctx, s.DB, &split); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateOverride(ctx, "u1", &models.ScheduleOverride{Type: models.OverrideExtraHours, StartDate: "2026-03-02", StartTime: "16:00", EndTime: "17:00", SlotLengthMins: 60}); err != nil {
+		t.Fatal(err)
+	}
+
+	slots, err := s.GenerateAvailableSlots(ctx, "u1", monday, monday.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct{ start, ruleID string }{
+		{"2026-03-02 09:00", morning.ID},
+		{"2026-03-02 09:30", morning.ID},
+		{"2026-03-02 14:00", afternoon.ID},
+		{"2026-03-02 16:00", ""}, // extra hours override
+		{"2026-03-03 09:00", split.ID},
+		{"2026-03-03 13:00", split.ID},
+	}
+	var got []struct{ start, ruleID string }
+	for _, sl := range slots {
+		got = append(got, struct{ start, ruleID string }{sl.StartUTC.Format("2006-01-02 15:04"), sl.RuleID})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("slots = %v, want %v", got, want)
+	}
+
+	for _, sl := range slots {
+		raw, err := json.Marshal(sl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if has := strings.Contains(string(raw), `"rule_id"`); has != (sl.RuleID != "") {
+			t.Errorf("%s: rule_id present = %v in %s", sl.StartUTC, has, raw)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newStreamService builds a user with 15-minute slots from 08:00 to 18:00
+// every day.
+func newStreamService(t testing.TB, from time.Time) *AvailabilityService {
+	s, _ := newFakeServices(from)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		addRule(t, s, "u1", d, "08:00", "18:00", 15)
+	}
+	return s
+}
+
+func TestStreamAvailableSlotsMatchesSlice(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 90)
+	s := newStreamService(t, from)
+
+	all, err := s.GenerateAvailableSlots(context.Background(), "u1", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var streamed []Slot
+	days := 0
+	err = s.StreamAvailableSlots(context.Background(), "u1", from, to, func(day []Slot) error {
+		days++
+		streamed = append(streamed, day...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if days != 90 {
+		t.Errorf("yielded %d days, want 90", days)
+	}
+	if !reflect.DeepEqual(streamed, all) {
+		t.Errorf("streamed %d slots, slice has %d", len(streamed), len(all))
+	}
+}
+
+// BenchmarkSlots90Days compares building a 90-day range of 15-minute slots
+// as one slice with streaming it a day at a time; the stream never holds more
+// than a day's slots.
+func BenchmarkSlots90Days(b *testing.B) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 90)
+	s := newStreamService(b, from)
+	ctx := context.Background()
+
+	b.Run("slice", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := s.GenerateAvailableSlots(ctx, "u1", from, to); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := s.StreamAvailableSlots(ctx, "u1", from, to, func(day []Slot) error { return nil }); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestSplitDayRuleSlots(t *testing.T) {
+	cases := []struct {
+		name    string
+		windows []models.TimeWindow
+		want    []string
+	}{
+		{"morning and afternoon", []models.TimeWindow{{StartTime: "09:00", EndTime: "10:00"}, {StartTime: "13:00", EndTime: "14:00"}},
+			[]string{"09:00", "09:30", "13:00", "13:30"}},
+		{"windows given out of order", []models.TimeWindow{{StartTime: "13:00", EndTime: "14:00"}, {StartTime: "09:00", EndTime: "10:00"}},
+			[]string{"09:00", "09:30", "13:00", "13:30"}},
+		{"back to back windows", []models.TimeWindow{{StartTime: "09:00", EndTime: "10:00"}, {StartTime: "10:00", EndTime: "10:30"}},
+			[]string{"09:00", "09:30", "10:00"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newFakeServices(monday)
+			rule := models.AvailabilityRule{UserID: "u1", DayOfWeek: int(time.Monday), SlotLengthMins: 30, Available: true, Windows: tc.windows}
+			if err := validateAvailabilityRule(&rule); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Avail.InsertAvailabilityRule(context.Background(), s.DB, &rule); err != nil {
+				t.Fatal(err)
+			}
+			slots, err := s.GenerateAvailableSlots(context.Background(), "u1", monday, monday.Add(24*time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := slotStarts(slots); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("slots = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateSplitRuleWindows(t *testing.T) {
+	cases := []struct {
+		name               string
+		windows            []models.TimeWindow
+		wantErr            string
+		wantStart, wantEnd string
+	}{
+		{"disjoint", []models.TimeWindow{{StartTime: "13:00", EndTime: "17:00"}, {StartTime: "09:00", EndTime: "12:00"}}, "", "09:00", "17:00"},
+		{"overlapping", []models.TimeWindow{{StartTime: "09:00", EndTime: "12:00"}, {StartTime: "11:00", EndTime: "13:00"}}, "windows must not overlap", "", ""},
+		{"nested", []models.TimeWindow{{StartTime: "09:00", EndTime: "17:00"}, {StartTime: "12:00", EndTime: "13:00"}}, "windows must not overlap", "", ""},
+		{"inverted window", []models.TimeWindow{{StartTime: "09:00", EndTime: "10:00"}, {StartTime: "14:00", EndTime: "13:00"}}, "end_time must be after start_time", "", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := models.AvailabilityRule{DayOfWeek: int(time.Monday), SlotLengthMins: 30, Available: true, Windows: tc.windows}
+			err := validateAvailabilityRule(&rule)
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+			if tc.wantErr == "" && (rule.StartTime != tc.wantStart || rule.EndTime != tc.wantEnd) {
+				t.Errorf("envelope = %s-%s, want %s-%s", rule.StartTime, rule.EndTime, tc.wantStart, tc.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"scheduler-service/internal/ics"
+	"scheduler-service/internal/repository"
+)
+
+// Team feed limits: bookings included per user, and how long a rendered feed
+// is reused before being rebuilt.
+const (
+	teamFeedMaxPerUser = 500
+	defaultTeamFeedTTL = time.Minute
+)
+
+// TeamFeedService renders a subscribable ICS feed of upcoming bookings for a
+// set of users. Feed tokens are an HMAC-SHA256 over the sorted user ids, so a
+// token grants exactly the set it was issued for and needs no storage.
+type TeamFeedService struct {
+	DB       repository.Querier
+	Bookings repository.BookingRepository
+	Secret   []byte
+	// TTL is how long a rendered feed is cached (defaultTeamFeedTTL when zero).
+	TTL   time.Duration
+	Clock Clock
+
+	mu    sync.Mutex
+	cache map[string]cachedFeed
+}
+
+type cachedFeed struct {
+	body    []byte
+	expires time.Time
+}
+
+func NewTeamFeedService(db repository.Querier, bookings repository.BookingRepository, secret []byte) *TeamFeedService {
+	return &TeamFeedService{DB: db, Bookings: bookings, Secret: secret}
+}
+
+// feedKey canonicalizes a user id set: sorted, comma-joined.
+func feedKey(userIDs []string) string {
+	ids := append([]string(nil), userIDs...)
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// Token returns the feed token for userIDs, in any order.
+func (s *TeamFeedService) Token(userIDs []string) string {
+	mac := hmac.New(sha256.New, s.Secret)
+	mac.Write([]byte("team-feed:" + feedKey(userIDs)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ValidToken reports whether token was issued for exactly userIDs.
+func (s *TeamFeedService) ValidToken(userIDs []string, token string) bool {
+	return hmac.Equal([]byte(token), []byte(s.Token(userIDs)))
+}
+
+// Feed returns the ICS feed of the users' live bookings that have not ended,
+// serving a cached copy when one is still fresh.
+func (s *TeamFeedService) Feed(ctx context.Context, userIDs []string) ([]byte, error) {
+	key := feedKey(userIDs)
+	now := nowUTC(s.Clock)
+	s.mu.Lock()
+	if c, ok := s.cache[key]; ok && now.Before(c.expires) {
+		s.mu.Unlock()
+		return c.body, nil
+	}
+	s.mu.Unlock()
+
+	var events []ics.Event
+	for _, userID := range userIDs {
+		// Start a day back so interviews in progress stay in the feed
+		bookings, err := s.Bookings.ListUpcomingBookings(ctx, s.DB, userID, now.Add(-24*time.Hour), nil, "", teamFeedMaxPerUser)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range bookings {
+			if !b.EndAtUTC.After(now) {
+				continue
+			}
+			title := b.Title
+			if title == "" {
+				title = "Interview with " + b.CandidateEmail
+			}
+			events = append(events, ics.Event{
+				UID:         b.ID + "@scheduler-service",
+				Summary:     title,
+				Description: "Candidate: " + b.CandidateEmail + "\nInterviewer: " + b.UserID,
+				Start:       b.StartAtUTC,
+				End:         b.EndAtUTC,
+			})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+
+	var buf bytes.Buffer
+	if err := ics.Write(&buf, "Team interviews", events, now); err != nil {
+		return nil, err
+	}
+
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = defaultTeamFeedTTL
+	}
+	s.mu.Lock()
+	if s.cache == nil {
+		s.cache = map[string]cachedFeed{}
+	}
+	for k, c := range s.cache {
+		if !now.Before(c.expires) {
+			delete(s.cache, k)
+		}
+	}
+	s.cache[key] = cachedFeed{body: buf.Bytes(), expires: now.Add(ttl)}
+	s.mu.Unlock()
+	return buf.Bytes(), nil
+}
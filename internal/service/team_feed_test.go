@@ -0,0 +1,106 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/ics"
+	"scheduler-service/internal/models"
+)
+
+func TestTeamFeedCombinesUsers(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return now.Add(time.Duration(h) * time.Hour) }
+	repo := newFakeBookingRepo(
+		models.Booking{ID: "u1-later", UserID: "u1", CandidateEmail: "a@example.com", Title: "Onsite", StartAtUTC: at(26), EndAtUTC: at(27)},
+		models.Booking{ID: "u2-soon", UserID: "u2", CandidateEmail: "b@example.com", StartAtUTC: at(2), EndAtUTC: at(3)},
+		models.Booking{ID: "u1-running", UserID: "u1", CandidateEmail: "c@example.com", StartAtUTC: at(-1), EndAtUTC: at(1)},
+		models.Booking{ID: "u1-ended", UserID: "u1", CandidateEmail: "d@example.com", StartAtUTC: at(-3), EndAtUTC: at(-2)},
+		models.Booking{ID: "u2-cancelled", UserID: "u2", CandidateEmail: "e@example.com", Status: "cancelled", StartAtUTC: at(4), EndAtUTC: at(5)},
+		models.Booking{ID: "u3-other", UserID: "u3", CandidateEmail: "f@example.com", StartAtUTC: at(2), EndAtUTC: at(3)},
+	)
+	s := NewTeamFeedService(fakeDB{}, repo, []byte("secret"))
+	s.Clock = FixedClock(now)
+
+	body, err := s.Feed(context.Background(), []string{"u1", "u2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := ics.Parse(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("feed does not parse: %v\n%s", err, body)
+	}
+	var got []string
+	for _, e := range events {
+		got = append(got, e.UID)
+	}
+	want := []string{"u1-running@scheduler-service", "u2-soon@scheduler-service", "u1-later@scheduler-service"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	if events[1].Summary != "Interview with b@example.com" || events[2].Summary != "Onsite" {
+		t.Errorf("summaries = %q, %q", events[1].Summary, events[2].Summary)
+	}
+	if !strings.Contains(string(body), "X-WR-CALNAME:Team interviews\r\n") {
+		t.Errorf("feed has no calendar name:\n%s", body)
+	}
+}
+
+func TestTeamFeedCachesBriefly(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	repo := newFakeBookingRepo(models.Booking{ID: "b1", UserID: "u1", CandidateEmail: "a@example.com", StartAtUTC: now.Add(time.Hour), EndAtUTC: now.Add(2 * time.Hour)})
+	s := NewTeamFeedService(fakeDB{}, repo, []byte("secret"))
+	count := func(clock time.Time) int {
+		s.Clock = FixedClock(clock)
+		body, err := s.Feed(context.Background(), []string{"u1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Count(string(body), "BEGIN:VEVENT")
+	}
+
+	if n := count(now); n != 1 {
+		t.Fatalf("first feed has %d events, want 1", n)
+	}
+	repo.bookings["b2"] = &models.Booking{ID: "b2", UserID: "u1", CandidateEmail: "b@example.com", Status: "confirmed", StartAtUTC: now.Add(3 * time.Hour), EndAtUTC: now.Add(4 * time.Hour)}
+	cases := []struct {
+		name  string
+		clock time.Time
+		want  int
+	}{
+		{"within the TTL", now.Add(30 * time.Second), 1},
+		{"after the TTL", now.Add(time.Minute), 2},
+	}
+	for _, tc := range cases {
+		if n := count(tc.clock); n != tc.want {
+			t.Errorf("%s: feed has %d events, want %d", tc.name, n, tc.want)
+		}
+	}
+}
+
+func TestTeamFeedToken(t *testing.T) {
+	s := NewTeamFeedService(nil, nil, []byte("secret"))
+	token := s.Token([]string{"u1", "u2"})
+	cases := []struct {
+		name    string
+		userIDs []string
+		token   string
+		want    bool
+	}{
+		{"same set", []string{"u1", "u2"}, token, true},
+		{"other order", []string{"u2", "u1"}, token, true},
+		{"subset", []string{"u1"}, token, false},
+		{"superset", []string{"u1", "u2", "u3"}, token, false},
+		{"other secret", []string{"u1", "u2"}, NewTeamFeedService(nil, nil, []byte("other")).Token([]string{"u1", "u2"}), false},
+		{"empty token", []string{"u1", "u2"}, "", false},
+	}
+	for _, tc := range cases {
+		if got := s.ValidToken(tc.userIDs, tc.token); got != tc.want {
+			t.Errorf("%s: ValidToken = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+// failLoadLocation makes zone fail to load for the rest of the test, as if
+// it had been removed from the host's tzdata.
+func failLoadLocation(t *testing.T, zone string) {
+	orig := loadLocation
+	loadLocation = func(name string) (*time.Location, error) {
+		if name == zone {
+			return nil, errors.New("unknown time zone " + name)
+		}
+		return orig(name)
+	}
+	ruleLocations.Delete(zone)
+	t.Cleanup(func() {
+		loadLocation = orig
+		ruleLocations.Delete(zone)
+	})
+}
+
+func TestTimezoneFallbackToUTC(t *testing.T) {
+	failLoadLocation(t, "Europe/Vienna")
+	ctx := context.Background()
+	s, _ := newFakeServices(monday)
+	addRule(t, s, "u1", time.Monday, "14:00", "15:00", 30)
+	// stored while the zone still loaded
+	zoned := models.AvailabilityRule{UserID: "u1", DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "10:00", SlotLengthMins: 30, Timezone: "Europe/Vienna", Available: true}
+	if err := s.Avail.InsertAvailabilityRule(ctx, s.DB, &zoned); err != nil {
+		t.Fatal(err)
+	}
+
+	slots, err := s.GenerateAvailableSlots(ctx, "u1", monday, monday.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("generation failed instead of falling back: %v", err)
+	}
+	want := map[string]bool{"09:00": true, "09:30": true, "14:00": false, "14:30": false}
+	if len(slots) != len(want) {
+		t.Fatalf("slots = %v, want %d", slotStarts(slots), len(want))
+	}
+	for _, sl := range slots {
+		fallback, ok := want[sl.StartUTC.Format("15:04")]
+		if !ok {
+			t.Fatalf("unexpected slot at %s", sl.StartUTC)
+		}
+		if sl.TimezoneFallback != fallback {
+			t.Errorf("slot at %s: timezone_fallback = %v, want %v", sl.StartUTC.Format("15:04"), sl.TimezoneFallback, fallback)
+		}
+	}
+}
+
+func TestTimezoneValidatedOnWrite(t *testing.T) {
+	failLoadLocation(t, "Europe/Vienna")
+	s, _ := newFakeServices(monday)
+	rule := models.AvailabilityRule{DayOfWeek: int(time.Monday), StartTime: "09:00", EndTime: "10:00", SlotLengthMins: 30, Timezone: "Europe/Vienna", Available: true}
+	if _, err := s.UpsertAvailability(context.Background(), "u1", []models.AvailabilityRule{rule}); err == nil {
+		t.Fatal("rule with an unloadable timezone accepted")
+	}
+	if rules, _ := s.ListAvailability(context.Background(), "u1"); len(rules) != 0 {
+		t.Errorf("rules stored: %+v", rules)
+	}
+}
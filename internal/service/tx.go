@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"scheduler-service/internal/repository"
+)
+
+// beginTx starts a transaction on db when the underlying querier supports it
+// (a pool does; an existing transaction does not).
+func beginTx(ctx context.Context, db repository.Querier) (pgx.Tx, error) {
+	tx, ok := db.(interface {
+		Begin(context.Context) (pgx.Tx, error)
+	})
+	if !ok {
+		return nil, errors.New("db does not support transactions")
+	}
+	return tx.Begin(ctx)
+}
+
+// beginTxWithIsolation is beginTx with an explicit isolation level; an empty
+// level leaves the server default.
+func beginTxWithIsolation(ctx context.Context, db repository.Querier, iso pgx.TxIsoLevel) (pgx.Tx, error) {
+	if iso == "" {
+		return beginTx(ctx, db)
+	}
+	tx, ok := db.(interface {
+		BeginTx(context.Context, pgx.TxOptions) (pgx.Tx, error)
+	})
+	if !ok {
+		return nil, errors.New("db does not support transactions")
+	}
+	return tx.BeginTx(ctx, pgx.TxOptions{IsoLevel: iso})
+}
+
+// maxSerializationRetries bounds how often a transaction aborted by a
+// serialization failure or deadlock is re-run from the start.
+const maxSerializationRetries = 3
+
+// isSerializationFailure reports whether err aborted the transaction in a way
+// that is safe to retry (SQLSTATE 40001 serialization_failure or 40P01
+// deadlock_detected).
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestListUpcomingBookingsExcludesPast(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	booking := func(id string, offset time.Duration, status string) models.Booking {
+		return models.Booking{ID: id, UserID: "u1", StartAtUTC: now.Add(offset), EndAtUTC: now.Add(offset + 30*time.Minute), Status: status}
+	}
+	repo := newFakeBookingRepo(
+		booking("past", -48*time.Hour, ""),
+		booking("started", -15*time.Minute, ""),
+		booking("later", 48*time.Hour, ""),
+		booking("soon", time.Hour, ""),
+		booking("now", 0, ""),
+		booking("cancelled", 2*time.Hour, "cancelled"),
+		booking("pending", 3*time.Hour, "pending"),
+		models.Booking{ID: "other-user", UserID: "u2", StartAtUTC: now.Add(time.Hour), EndAtUTC: now.Add(90 * time.Minute)},
+	)
+	s := NewBookingService(fakeDB{}, repo, nil)
+
+	cases := []struct {
+		name  string
+		limit int
+		want  [][]string // ids per page
+	}{
+		{"one page", 10, [][]string{{"now", "soon", "pending", "later"}}},
+		{"paged", 3, [][]string{{"now", "soon", "pending"}, {"later"}}},
+		{"page per booking", 1, [][]string{{"now"}, {"soon"}, {"pending"}, {"later"}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var cursor *BookingCursor
+			for page, want := range tc.want {
+				got, next, err := s.ListUpcomingBookings(context.Background(), "u1", now, cursor, tc.limit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var ids []string
+				for _, b := range got {
+					ids = append(ids, b.ID)
+				}
+				if len(ids) != len(want) {
+					t.Fatalf("page %d = %v, want %v", page, ids, want)
+				}
+				for i := range want {
+					if ids[i] != want[i] {
+						t.Fatalf("page %d = %v, want %v", page, ids, want)
+					}
+				}
+				if last := page == len(tc.want)-1; last != (next == nil) {
+					t.Fatalf("page %d: next cursor %v, want one only before the last page", page, next)
+				}
+				cursor = next
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"scheduler-service/internal/holidays"
+	"scheduler-service/internal/models"
+	"scheduler-service/internal/repository"
+)
+
+// defaultCalendarID is used for Google Calendar calls when neither the request
+// nor the user's settings name a calendar.
+const defaultCalendarID = "primary"
+
+type UserSettingsService struct {
+	DB   repository.Querier
+	Repo repository.UserSettingsRepository
+}
+
+func NewUserSettingsService(db repository.Querier, repo repository.UserSettingsRepository) *UserSettingsService {
+	return &UserSettingsService{DB: db, Repo: repo}
+}
+
+// UserSettingsUpdate is a partial update; nil fields are left unchanged.
+type UserSettingsUpdate struct {
+	DefaultCalendarID *string `json:"default_calendar_id"`
+	RollingDays       *int    `json:"rolling_days"`
+	HolidayRegion     *string `json:"holiday_region"`
+}
+
+// maxRollingDays bounds the rolling availability window.
+const maxRollingDays = 365
+
+// GetSettings returns the user's settings, or defaults when none were saved.
+func (s *UserSettingsService) GetSettings(ctx context.Context, userID string) (models.UserSettings, error) {
+	st, err := s.Repo.GetUserSettings(ctx, s.DB, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.UserSettings{UserID: userID}, nil
+	}
+	if err != nil {
+		return models.UserSettings{}, err
+	}
+	return *st, nil
+}
+
+func (s *UserSettingsService) UpdateSettings(ctx context.Context, userID string, upd UserSettingsUpdate) (models.UserSettings, error) {
+	st, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return st, err
+	}
+	if upd.DefaultCalendarID != nil {
+		st.DefaultCalendarID = strings.TrimSpace(*upd.DefaultCalendarID)
+	}
+	if upd.RollingDays != nil {
+		if *upd.RollingDays < 0 || *upd.RollingDays > maxRollingDays {
+			return st, fmt.Errorf("rolling_days must be between 0 and %d", maxRollingDays)
+		}
+		st.RollingDays = *upd.RollingDays
+	}
+	if upd.HolidayRegion != nil {
+		region := strings.ToUpper(strings.TrimSpace(*upd.HolidayRegion))
+		if region != "" && !holidays.Supported(region) {
+			return st, fmt.Errorf("holiday_region must be one of %s", strings.Join(holidays.Regions(), ", "))
+		}
+		st.HolidayRegion = region
+	}
+	if err := s.Repo.UpsertUserSettings(ctx, s.DB, &st); err != nil {
+		return st, err
+	}
+	return st, nil
+}
+
+// SetVacation marks the user unavailable until the given instant, or clears
+// vacation mode when until is nil. Existing bookings are left untouched.
+func (s *UserSettingsService) SetVacation(ctx context.Context, userID string, until *time.Time) (models.UserSettings, error) {
+	st, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return st, err
+	}
+	if until != nil {
+		if !until.After(time.Now()) {
+			return st, errors.New("until must be in the future")
+		}
+		u := until.UTC()
+		until = &u
+	}
+	st.UnavailableUntil = until
+	if err := s.Repo.UpsertUserSettings(ctx, s.DB, &st); err != nil {
+		return st, err
+	}
+	return st, nil
+}
+
+// CalendarID resolves the calendar to use for a Google Calendar call: the
+// explicit request value, then the user's stored default, then "primary".
+func (s *UserSettingsService) CalendarID(ctx context.Context, userID, requested string) (string, error) {
+	if requested != "" {
+		return requested, nil
+	}
+	if userID == "" {
+		return defaultCalendarID, nil
+	}
+	st, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if st.DefaultCalendarID != "" {
+		return st.DefaultCalendarID, nil
+	}
+	return defaultCalendarID, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestVacationHidesSlots(t *testing.T) {
+	at := func(days, hours int) *time.Time {
+		t := monday.AddDate(0, 0, days).Add(time.Duration(hours) * time.Hour)
+		return &t
+	}
+	cases := []struct {
+		name  string
+		until *time.Time
+		want  []string // slot days
+	}{
+		{"no vacation", nil, []string{"03-02", "03-03", "03-04", "03-05", "03-06"}},
+		{"until mid-week", at(2, 12), []string{"03-05", "03-06"}},
+		{"until exactly a slot start", at(3, 9), []string{"03-05", "03-06"}},
+		{"covering the whole range", at(14, 0), nil},
+		{"already over", at(-7, 0), []string{"03-02", "03-03", "03-04", "03-05", "03-06"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, _ := newFakeServices(monday)
+			s.Settings = fakeSettingsRepo{"u1": {UserID: "u1", UnavailableUntil: tc.until}}
+			for day := time.Monday; day <= time.Friday; day++ {
+				addRule(t, s, "u1", day, "09:00", "10:00", 60)
+			}
+
+			slots, err := s.GenerateAvailableSlots(context.Background(), "u1", monday, monday.AddDate(0, 0, 7))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, sl := range slots {
+				got = append(got, sl.StartUTC.Format("01-02"))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("slot days = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestVacationRejectsBookings(t *testing.T) {
+	until := monday.AddDate(0, 0, 2).Add(12 * time.Hour)
+	cases := []struct {
+		name    string
+		start   time.Time
+		wantErr string
+	}{
+		{"during vacation", monday.AddDate(0, 0, 1).Add(9 * time.Hour), "user is unavailable"},
+		{"after vacation", monday.AddDate(0, 0, 3).Add(9 * time.Hour), ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, s := newFakeServices(monday)
+			avail.Settings = fakeSettingsRepo{"u1": {UserID: "u1", UnavailableUntil: &until}}
+			for day := time.Monday; day <= time.Friday; day++ {
+				addRule(t, avail, "u1", day, "09:00", "10:00", 60)
+			}
+
+			_, err := s.CreateBooking(context.Background(), "u1", CreateBookingParams{CandidateEmail: "c@example.com", Start: tc.start, End: tc.start.Add(time.Hour)})
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetVacation(t *testing.T) {
+	future := time.Now().Add(48 * time.Hour).In(time.FixedZone("CET", 3600))
+	past := time.Now().Add(-time.Hour)
+	cases := []struct {
+		name    string
+		start   *time.Time
+		until   *time.Time
+		want    *time.Time
+		wantErr string
+	}{
+		{"set", nil, &future, &future, ""},
+		{"clear", &future, nil, nil, ""},
+		{"in the past", nil, &past, nil, "until must be in the future"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := fakeSettingsRepo{"u1": {UserID: "u1", RollingDays: 14, UnavailableUntil: tc.start}}
+			_, err := NewUserSettingsService(nil, repo).SetVacation(context.Background(), "u1", tc.until)
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || err.Error() != tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+			got := repo["u1"].UnavailableUntil
+			if (got == nil) != (tc.want == nil) || got != nil && (!got.Equal(*tc.want) || got.Location() != time.UTC) {
+				t.Errorf("stored unavailable_until = %v, want %v in UTC", got, tc.want)
+			}
+			if repo["u1"].RollingDays != 14 {
+				t.Errorf("rolling_days = %d, want it left at 14", repo["u1"].RollingDays)
+			}
+		})
+	}
+}
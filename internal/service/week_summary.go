@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WeekSummary counts a user's open slots and confirmed bookings in one ISO
+// week, Monday to Sunday in the requested timezone.
+type WeekSummary struct {
+	Week           string `json:"week"` // ISO year-week, e.g. "2026-W01"
+	StartDate      string `json:"start_date"`
+	EndDate        string `json:"end_date"`
+	AvailableSlots int    `json:"available_slots"`
+	BookedSlots    int    `json:"booked_slots"`
+}
+
+// isoWeekStart returns local midnight on the Monday of t's ISO week in loc.
+func isoWeekStart(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	offset := (int(local.Weekday()) + 6) % 7 // days since Monday
+	return time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, loc)
+}
+
+// WeekSummaries returns count consecutive ISO week summaries in loc, starting
+// with the week containing from. Slots are counted as generation offers them
+// with bookings subtracted; bookings count when they start in the week.
+func (s *AvailabilityService) WeekSummaries(ctx context.Context, userID string, from time.Time, count int, loc *time.Location) ([]WeekSummary, error) {
+	first := isoWeekStart(from, loc)
+	weeks := make([]WeekSummary, count)
+	bounds := make([]time.Time, count+1)
+	for i := 0; i <= count; i++ {
+		// Built from the date so DST shifts don't drift the boundary
+		bounds[i] = time.Date(first.Year(), first.Month(), first.Day()+7*i, 0, 0, 0, 0, loc)
+	}
+	for i := range weeks {
+		year, week := bounds[i].ISOWeek()
+		weeks[i] = WeekSummary{
+			Week:      fmt.Sprintf("%04d-W%02d", year, week),
+			StartDate: bounds[i].Format("2006-01-02"),
+			EndDate:   bounds[i].AddDate(0, 0, 6).Format("2006-01-02"),
+		}
+	}
+	fromUTC, toUTC := bounds[0].UTC(), bounds[count].UTC()
+
+	slots, err := s.generateSlots(ctx, userID, fromUTC, toUTC, slotOptions{ignoreHolds: true})
+	if err != nil {
+		return nil, err
+	}
+	for _, sl := range slots {
+		if i := weekIndex(bounds, sl.StartUTC); i >= 0 {
+			weeks[i].AvailableSlots++
+		}
+	}
+	bookings, err := s.Book.ListBookingsInRange(ctx, s.DB, userID, fromUTC, toUTC)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range bookings {
+		if i := weekIndex(bounds, b.StartAtUTC); i >= 0 {
+			weeks[i].BookedSlots++
+		}
+	}
+	return weeks, nil
+}
+
+// weekIndex returns the index of the week in bounds that t falls in, or -1.
+func weekIndex(bounds []time.Time, t time.Time) int {
+	for i := 0; i+1 < len(bounds); i++ {
+		if !t.Before(bounds[i]) && t.Before(bounds[i+1]) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"scheduler-service/internal/models"
+)
+
+func TestWeekSummariesAcrossYearEnd(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name  string
+		from  time.Time
+		count int
+		loc   *time.Location
+		want  []string // week, start date, end date
+	}{
+		{"53-week year rolls into W01",
+			time.Date(2026, 12, 30, 12, 0, 0, 0, time.UTC), 3, time.UTC,
+			[]string{"2026-W53 2026-12-28 2027-01-03", "2027-W01 2027-01-04 2027-01-10", "2027-W02 2027-01-11 2027-01-17"}},
+		{"W01 starting in the previous December",
+			time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC), 1, time.UTC,
+			[]string{"2025-W01 2024-12-30 2025-01-05"}},
+		{"January days belonging to the last week of the old year",
+			time.Date(2021, 1, 2, 12, 0, 0, 0, time.UTC), 2, time.UTC,
+			[]string{"2020-W53 2020-12-28 2021-01-03", "2021-W01 2021-01-04 2021-01-10"}},
+		{"Sunday night UTC is already Monday in Tokyo",
+			time.Date(2027, 1, 3, 20, 0, 0, 0, time.UTC), 1, tokyo,
+			[]string{"2027-W01 2027-01-04 2027-01-10"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			avail, _ := newFakeServices(tc.from.AddDate(0, 0, -30))
+			weeks, err := avail.WeekSummaries(context.Background(), "u1", tc.from, tc.count, tc.loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, w := range weeks {
+				got = append(got, w.Week+" "+w.StartDate+" "+w.EndDate)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("weeks = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWeekSummariesCountSlotsPerWeek(t *testing.T) {
+	avail, _ := newFakeServices(time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC))
+	addRule(t, avail, "u1", time.Monday, "09:00", "10:00", 30)
+	repo := newFakeBookingRepo(models.Booking{
+		ID: "b1", UserID: "u1", CandidateEmail: "c@example.com",
+		StartAtUTC: time.Date(2027, 1, 4, 9, 0, 0, 0, time.UTC),
+		EndAtUTC:   time.Date(2027, 1, 4, 9, 30, 0, 0, time.UTC),
+	})
+	avail.Book = repo
+
+	weeks, err := avail.WeekSummaries(context.Background(), "u1", time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), 2, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []WeekSummary{
+		{Week: "2026-W53", StartDate: "2026-12-28", EndDate: "2027-01-03", AvailableSlots: 2},
+		{Week: "2027-W01", StartDate: "2027-01-04", EndDate: "2027-01-10", AvailableSlots: 1, BookedSlots: 1},
+	}
+	if !reflect.DeepEqual(weeks, want) {
+		t.Errorf("weeks = %+v, want %+v", weeks, want)
+	}
+}
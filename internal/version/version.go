@@ -0,0 +1,12 @@
+package version
+
+// Build metadata, injected at link time:
+//
+//	go build -ldflags "-X scheduler-service/internal/version.Version=1.2.0 \
+//	  -X scheduler-service/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X scheduler-service/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)